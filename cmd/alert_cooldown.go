@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/state"
+)
+
+// alertCooldown applies notification.cooldown_per_container: a container alerted within
+// the cooldown period is not alerted again unless its severity increased. Sent alerts
+// are recorded in the scan state, which is keyed by container ID. A nil *alertCooldown
+// never suppresses an alert.
+type alertCooldown struct {
+	st       state.Backend
+	period   time.Duration
+	ids      map[string]string // Container name -> state ID
+	now      time.Time
+	recorded bool
+}
+
+// newAlertCooldown creates the cooldown for one scan. Returns nil when period is not
+// positive or there is no state to keep the alert times in.
+func newAlertCooldown(st state.Backend, period time.Duration) *alertCooldown {
+	if st == nil || period <= 0 {
+		return nil
+	}
+	return &alertCooldown{st: st, period: period, ids: stateIDsByName(st), now: time.Now()}
+}
+
+// stateIDsByName maps container names to their state IDs. A name tracked under several
+// IDs (a recreated container) maps to the most recently scanned one.
+func stateIDsByName(st state.Backend) map[string]string {
+	containers := st.GetAllContainers()
+	ids := make(map[string]string, len(containers))
+	for id, ctr := range containers {
+		if current, ok := ids[ctr.Name]; ok && !ctr.LastScan.After(containers[current].LastScan) {
+			continue
+		}
+		ids[ctr.Name] = id
+	}
+	return ids
+}
+
+// Active reports whether an alert of severity for the named container falls within the
+// cooldown of its previous alert, and how long ago that alert was sent. A severity
+// above the previous alert's always passes.
+func (c *alertCooldown) Active(name string, severity knowledge.Severity) (time.Duration, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	id, ok := c.ids[name]
+	if !ok {
+		return 0, false
+	}
+	notifiedAt, notified, ok := c.st.GetLastNotified(id)
+	if !ok {
+		return 0, false
+	}
+	previous, err := knowledge.ParseSeverity(notified)
+	if err != nil || severity > previous {
+		return 0, false
+	}
+
+	since := c.now.Sub(notifiedAt)
+	return since, since < c.period
+}
+
+// Record remembers that an alert of severity was sent for the named container.
+func (c *alertCooldown) Record(name string, severity knowledge.Severity) {
+	if c == nil {
+		return
+	}
+
+	if id, ok := c.ids[name]; ok {
+		c.st.RecordNotification(id, c.now, severity.String())
+		c.recorded = true
+	}
+}
+
+// Save persists the recorded alerts.
+func (c *alertCooldown) Save() error {
+	if c == nil || !c.recorded {
+		return nil
+	}
+
+	if err := c.st.Save(); err != nil {
+		return fmt.Errorf("failed to save notification times: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/notification"
+	"github.com/zorak1103/dlia/internal/state"
+)
+
+func TestStateIDsByName(t *testing.T) {
+	st, err := state.Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	now := time.Now()
+	st.UpdateContainer("old123", "web", now.Add(-time.Hour), "")
+	st.UpdateContainer("new456", "web", now, "")
+	st.UpdateContainer("db7890", "db", now, "")
+
+	ids := stateIDsByName(st)
+	if ids["web"] != "new456" || ids["db"] != "db7890" {
+		t.Errorf("stateIDsByName() = %v, want the most recently scanned ID per name", ids)
+	}
+}
+
+func TestAlertCooldown_Active(t *testing.T) {
+	st, err := state.Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	st.UpdateContainer("abc123", "web", time.Now(), "")
+
+	if newAlertCooldown(st, 0) != nil || newAlertCooldown(nil, time.Hour) != nil {
+		t.Fatal("Expected no cooldown without a period or state")
+	}
+
+	cooldown := newAlertCooldown(st, time.Hour)
+	if _, ok := cooldown.Active("web", knowledge.SeverityWarning); ok {
+		t.Error("A container that was never alerted should not be in its cooldown")
+	}
+
+	cooldown.Record("web", knowledge.SeverityWarning)
+	cooldown.now = cooldown.now.Add(30 * time.Minute)
+	if since, ok := cooldown.Active("web", knowledge.SeverityWarning); !ok || since != 30*time.Minute {
+		t.Errorf("Active(warning) = %s, %v; want 30m0s, true", since, ok)
+	}
+	if _, ok := cooldown.Active("web", knowledge.SeverityCritical); ok {
+		t.Error("An escalation from warning to critical should bypass the cooldown")
+	}
+	if _, ok := cooldown.Active("db", knowledge.SeverityWarning); ok {
+		t.Error("An untracked container should not be in a cooldown")
+	}
+
+	cooldown.now = cooldown.now.Add(time.Hour)
+	if _, ok := cooldown.Active("web", knowledge.SeverityWarning); ok {
+		t.Error("The cooldown should end after its period")
+	}
+}
+
+func TestSendContainerAlerts_Cooldown(t *testing.T) {
+	var sent atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		sent.Add(1)
+	}))
+	defer server.Close()
+
+	notifier, err := notification.NewNotifier(&config.Config{
+		Notification: config.NotificationConfig{Enabled: true, ShoutrrURL: "generic+" + server.URL + "/webhook"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	st, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	st.UpdateContainer("abc123", "web", time.Now(), "")
+
+	scanCfg := newTestScanConfig()
+	send := func(analysis string) {
+		t.Helper()
+		if err := sendContainerAlerts(notifier, notification.ContainerStatuses(map[string]string{"web": analysis}), newAlertCooldown(st, time.Hour), scanCfg); err != nil {
+			t.Fatalf("sendContainerAlerts() error = %v", err)
+		}
+	}
+
+	send("Warning: slow queries")
+	send("Warning: slow queries")
+	if got := sent.Load(); got != 1 {
+		t.Fatalf("Expected the repeated warning to be suppressed, got %d alerts", got)
+	}
+
+	send("Critical: database down")
+	if got := sent.Load(); got != 2 {
+		t.Fatalf("Expected the escalation to critical to be sent, got %d alerts", got)
+	}
+
+	reloaded, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, severity, ok := reloaded.GetLastNotified("abc123"); !ok || severity != "critical" {
+		t.Errorf("Expected the critical alert to be saved, got %q, %v", severity, ok)
+	}
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/prompts"
+	"github.com/zorak1103/dlia/internal/ui"
+)
+
+var (
+	analyzeFile        string
+	analyzeName        string
+	analyzeLLMLog      bool
+	analyzeFilterStats bool
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   cmdAnalyze,
+	Short: "Analyze a captured log file without Docker",
+	Long: `Analyze reads log lines from a file and runs them through the same
+LLM pipeline and reporting path as scan, without contacting the Docker daemon.
+
+Lines in "docker logs --timestamps" format keep their timestamps; other lines
+are analyzed as plain messages. The report and knowledge base entry are stored
+under the name given with --name (defaults to the file name without extension
+and rotation suffix). No scan state is read or written.
+
+--file also accepts a directory or a glob pattern. Gzip-compressed files are
+decompressed transparently, and rotated files (app.log.2.gz, app.log.1, app.log)
+are read oldest first so the logs are analyzed in chronological order.
+
+Useful for testing prompts and debugging analysis of a known log capture.`,
+	Example: `  # Analyze a captured log file
+  docker logs --timestamps my-app > my-app.log
+  dlia analyze --file my-app.log --name my-app
+
+  # Analyze a log file together with its rotated, compressed predecessors
+  dlia analyze --file '/var/log/app/app.log*'
+
+  # Show filter statistics and log LLM requests
+  dlia analyze --file crash.log --filter-stats --llmlog`,
+	Args: cobra.NoArgs,
+	RunE: runAnalyze,
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+
+	analyzeCmd.Flags().StringVar(&analyzeFile, "file", "", "log file, directory or glob pattern to analyze; .gz files are decompressed (required)")
+	analyzeCmd.Flags().StringVar(&analyzeName, "name", "", "container name used for reports and knowledge base (default: file name)")
+	analyzeCmd.Flags().BoolVar(&analyzeLLMLog, "llmlog", false, "enable logging of all LLM requests and responses to markdown files")
+	analyzeCmd.Flags().BoolVar(&analyzeFilterStats, "filter-stats", false, "display filter statistics showing how many log lines were filtered")
+	_ = analyzeCmd.MarkFlagRequired("file") //nolint:errcheck // Flag is defined above
+}
+
+func runAnalyze(_ *cobra.Command, _ []string) error {
+	cfg = GetConfig()
+	if err := validateConfigOrExit(cfg, cmdAnalyze); err != nil {
+		return err
+	}
+
+	timestamps, err := docker.NewTimestampParser(cfg.Docker.TimestampFormat, cfg.Docker.TimestampPattern, cfg.DisplayLocation())
+	if err != nil {
+		return fmt.Errorf("invalid docker timestamp settings: %w", err)
+	}
+
+	files, err := resolveLogFiles(analyzeFile)
+	if err != nil {
+		return err
+	}
+
+	var logs []docker.LogEntry
+	for _, file := range files {
+		entries, err := readLogFile(file, timestamps)
+		if err != nil {
+			return err
+		}
+		logs = append(logs, entries...)
+	}
+
+	containerName := analyzeName
+	if containerName == "" {
+		containerName = defaultAnalyzeName(files[len(files)-1])
+	}
+
+	scanCfg := &scanConfig{
+		llmLog:      analyzeLLMLog,
+		filterStats: analyzeFilterStats,
+		verbose:     verbose,
+	}
+
+	// Custom prompt overrides must be loaded before the pipeline is created
+	prompts.InitPrompts(cfg)
+
+	ui.Printf("📄 Analyzing %s as %s\n", analyzeFile, containerName)
+	if len(files) > 1 {
+		ui.Printf("        📚 Reading %d files oldest first: %s\n", len(files), strings.Join(files, ", "))
+	}
+	ui.Printf("        📝 Found %d log entries\n", len(logs))
+
+	if len(logs) == 0 {
+		ui.Printf("        ℹ️  Nothing to analyze\n")
+		return nil
+	}
+
+	displayLogsPreview(logs, scanCfg)
+
+	pipeline, err := initializeLLMPipeline(cfg, scanCfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize LLM: %w", err)
+	}
+
+	ui.Printf("        🤖 Analyzing logs with LLM...\n")
+	result, err := pipeline.AnalyzeLogs(context.Background(), containerName, logs)
+	if err != nil {
+		return fmt.Errorf("LLM analysis failed for %s: %w", analyzeFile, err)
+	}
+
+	displayAnalysisResults(result, scanCfg)
+	handleReportingAndKnowledge(containerName, result, logs, cfg, scanCfg)
+
+	ui.Printf("✅ Analysis complete (%d tokens, %d chunk(s))\n", result.TokensUsed, result.ChunksUsed)
+	return nil
+}
+
+// rotationSuffix matches the suffix logrotate appends to rotated files: a date
+// (app.log-20250101, group 1) or a generation number (app.log.1, group 2).
+var rotationSuffix = regexp.MustCompile(`(?:[-_.](\d{4}-?\d{2}-?\d{2}(?:[-_]?\d+)?)|\.(\d+))$`)
+
+// resolveLogFiles expands a file, directory or glob pattern into the list of log
+// files to read, ordered so rotated files come before the files that replaced them.
+func resolveLogFiles(pattern string) ([]string, error) {
+	var files []string
+
+	info, err := os.Stat(pattern)
+	switch {
+	case err == nil && info.IsDir():
+		dirEntries, err := os.ReadDir(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read log directory %s: %w", pattern, err)
+		}
+		for _, e := range dirEntries {
+			if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+				files = append(files, filepath.Join(pattern, e.Name()))
+			}
+		}
+	case err == nil:
+		files = []string{pattern}
+	default:
+		matches, globErr := filepath.Glob(pattern)
+		if globErr != nil {
+			return nil, fmt.Errorf("invalid log file pattern %s: %w", pattern, globErr)
+		}
+		if len(matches) == 0 {
+			// Surface the original stat error for plain paths ("no such file")
+			return nil, fmt.Errorf("failed to open log file %s: %w", pattern, err)
+		}
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err == nil && fi.Mode().IsRegular() {
+				files = append(files, m)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no log files found in %s", pattern)
+	}
+
+	sortRotatedFiles(files)
+	return files, nil
+}
+
+// sortRotatedFiles orders log files chronologically per log: dated rotations
+// ascending, then numbered rotations from the highest (oldest) number down, then
+// the active file. Different logs are ordered by name.
+func sortRotatedFiles(files []string) {
+	type rotation struct {
+		stem   string
+		rank   int    // 0 = dated, 1 = numbered, 2 = active
+		number int    // Generation for numbered rotations
+		date   string // Suffix for dated rotations
+	}
+
+	keys := make(map[string]rotation, len(files))
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".gz")
+		key := rotation{stem: name, rank: 2}
+		if m := rotationSuffix.FindStringSubmatchIndex(name); m != nil {
+			key.stem = name[:m[0]]
+			if m[2] >= 0 {
+				key.rank = 0
+				key.date = name[m[2]:m[3]]
+			} else {
+				key.rank = 1
+				key.number, _ = strconv.Atoi(name[m[4]:m[5]]) //nolint:errcheck // Regex guarantees digits
+			}
+		}
+		keys[f] = key
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := keys[files[i]], keys[files[j]]
+		switch {
+		case filepath.Dir(files[i]) != filepath.Dir(files[j]):
+			return filepath.Dir(files[i]) < filepath.Dir(files[j])
+		case a.stem != b.stem:
+			return a.stem < b.stem
+		case a.rank != b.rank:
+			return a.rank < b.rank
+		case a.rank == 1:
+			return a.number > b.number
+		default:
+			return a.date < b.date
+		}
+	})
+}
+
+// readLogFile opens path and parses its lines into log entries. Gzip-compressed
+// files are recognized by their magic bytes and decompressed. A nil timestamps
+// parser only recognizes Docker's RFC3339 prefix.
+func readLogFile(path string, timestamps *docker.TimestampParser) ([]docker.LogEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // Path is provided by the user on the command line
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck // Read-only file, close error not actionable
+
+	br := bufio.NewReader(f)
+	var reader io.Reader = br
+
+	magic, _ := br.Peek(2) //nolint:errcheck // Short files are simply not gzip
+	compressed := len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b
+	if !compressed && strings.HasSuffix(path, ".gz") {
+		return nil, fmt.Errorf("corrupt gzip stream in %s: missing gzip header", path)
+	}
+	if compressed {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("corrupt gzip stream in %s: %w", path, err)
+		}
+		defer gz.Close() //nolint:errcheck // Read-only stream, close error not actionable
+		reader = gz
+	}
+
+	logs, err := timestamps.ParseLogFile(reader)
+	if err != nil {
+		if compressed {
+			return nil, fmt.Errorf("corrupt gzip stream in %s: %w", path, err)
+		}
+		return nil, fmt.Errorf("failed to parse log file %s: %w", path, err)
+	}
+
+	return logs, nil
+}
+
+// defaultAnalyzeName derives a container name from a log file path
+// ("logs/my-app.log" -> "my-app", "app.log.1.gz" -> "app").
+func defaultAnalyzeName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), ".gz")
+	if m := rotationSuffix.FindStringIndex(base); m != nil && m[0] > 0 {
+		base = base[:m[0]]
+	}
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
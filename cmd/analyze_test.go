@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeCmd_Structure(t *testing.T) {
+	t.Parallel()
+
+	if analyzeCmd.Use != cmdAnalyze {
+		t.Errorf("Expected command use '%s', got '%s'", cmdAnalyze, analyzeCmd.Use)
+	}
+
+	for _, flag := range []string{"file", "name", "llmlog", "filter-stats"} {
+		if analyzeCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Expected --%s flag to be defined", flag)
+		}
+	}
+}
+
+func TestReadLogFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	content := "2025-01-01T10:00:00Z starting\n2025-01-01T10:00:01Z ERROR failed to connect\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write log file: %v", err)
+	}
+
+	logs, err := readLogFile(path, nil)
+	if err != nil {
+		t.Fatalf("readLogFile() error = %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(logs))
+	}
+	if logs[1].Message != "ERROR failed to connect" {
+		t.Errorf("Unexpected message: %q", logs[1].Message)
+	}
+}
+
+func TestReadLogFile_Missing(t *testing.T) {
+	t.Parallel()
+
+	_, err := readLogFile(filepath.Join(t.TempDir(), "missing.log"), nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to open log file") {
+		t.Errorf("Expected open error, got %v", err)
+	}
+}
+
+func TestDefaultAnalyzeName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"my-app.log":          "my-app",
+		"logs/web.server.txt": "web.server",
+		"/tmp/capture":        "capture",
+		"app.log.1.gz":        "app",
+		"app.log-20250101.gz": "app",
+	}
+
+	for path, want := range tests {
+		if got := defaultAnalyzeName(path); got != want {
+			t.Errorf("defaultAnalyzeName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// writeGzip writes content gzip-compressed to path.
+func writeGzip(t *testing.T, path, content string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestReadLogFile_Gzip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log.1.gz")
+	writeGzip(t, path, "2025-01-01T10:00:00Z starting\n2025-01-01T10:00:01Z ERROR failed\n")
+
+	logs, err := readLogFile(path, nil)
+	if err != nil {
+		t.Fatalf("readLogFile() error = %v", err)
+	}
+	if len(logs) != 2 || logs[1].Message != "ERROR failed" {
+		t.Errorf("Unexpected entries: %+v", logs)
+	}
+}
+
+func TestReadLogFile_CorruptGzip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// Valid header, corrupted checksum trailer
+	checksum := filepath.Join(dir, "checksum.log.gz")
+	writeGzip(t, checksum, "2025-01-01T10:00:00Z starting\n")
+	data, err := os.ReadFile(checksum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-8] ^= 0xff
+	if err := os.WriteFile(checksum, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Truncated stream
+	truncated := filepath.Join(dir, "truncated.log.gz")
+	if err := os.WriteFile(truncated, data[:len(data)/2], 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// .gz name without gzip content
+	plain := filepath.Join(dir, "plain.log.gz")
+	if err := os.WriteFile(plain, []byte("not compressed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{checksum, truncated, plain} {
+		_, err := readLogFile(path, nil)
+		if err == nil || !strings.Contains(err.Error(), "corrupt gzip stream") {
+			t.Errorf("readLogFile(%s) error = %v, want corrupt gzip error", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestResolveLogFiles_RotatedOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"app.log", "app.log.1", "app.log.2.gz", "app.log.10.gz", "other.log", ".hidden"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"app.log.10.gz", "app.log.2.gz", "app.log.1", "app.log"}
+
+	files, err := resolveLogFiles(filepath.Join(dir, "app.log*"))
+	if err != nil {
+		t.Fatalf("resolveLogFiles() error = %v", err)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, filepath.Base(f))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("glob order = %v, want %v", got, want)
+	}
+
+	files, err = resolveLogFiles(dir)
+	if err != nil {
+		t.Fatalf("resolveLogFiles(dir) error = %v", err)
+	}
+	got = nil
+	for _, f := range files {
+		got = append(got, filepath.Base(f))
+	}
+	if want := append(want, "other.log"); !reflect.DeepEqual(got, want) {
+		t.Errorf("directory order = %v, want %v", got, want)
+	}
+}
+
+func TestSortRotatedFiles_Dated(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"app.log", "app.log-20250103", "app.log-20250101.gz", "app.log-20250102.gz"}
+	sortRotatedFiles(files)
+
+	want := []string{"app.log-20250101.gz", "app.log-20250102.gz", "app.log-20250103", "app.log"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("sortRotatedFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestResolveLogFiles_NoMatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if _, err := resolveLogFiles(filepath.Join(dir, "*.log")); err == nil {
+		t.Error("Expected error when the pattern matches nothing")
+	}
+	if _, err := resolveLogFiles(dir); err == nil || !strings.Contains(err.Error(), "no log files found") {
+		t.Errorf("Expected empty directory error, got %v", err)
+	}
+}
@@ -4,12 +4,14 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/config"
 	"github.com/zorak1103/dlia/internal/state"
 )
 
@@ -18,8 +20,9 @@ const (
 )
 
 var (
-	cleanupDryRun bool
-	cleanupForce  bool
+	cleanupDryRun           bool
+	cleanupForce            bool
+	cleanupReportsOlderThan string
 )
 
 var cleanupCmd = &cobra.Command{
@@ -31,11 +34,26 @@ The cleanup command scans all storage locations (state file, knowledge base,
 reports, and LLM logs) for references to containers that have been removed
 from Docker. It can list obsolete data or remove it with confirmation.
 
+With --dry-run alone, it previews a cleanup: each obsolete container with the
+exact state entry, knowledge base files, reports and LLM log directories that
+would be removed, and their sizes. Nothing is deleted.
+
+With --reports-older-than, it instead deletes report files older than the given
+age for all containers, including containers that still exist. Deletion requires
+--force; without it the files are only listed.
+
 Note: This command requires DLIA to be initialized. Run 'dlia init' first if 
 you encounter configuration errors.`,
 	Example: `  # List obsolete container data
   dlia cleanup list
 
+  # Preview which files and directories a cleanup would remove, with sizes
+  dlia cleanup --dry-run
+
+  # List reports older than 30 days, then delete them
+  dlia cleanup --reports-older-than 30d
+  dlia cleanup --reports-older-than 30d --force
+
   # Preview what would be deleted (dry-run)
   dlia cleanup execute --dry-run
 
@@ -44,6 +62,136 @@ you encounter configuration errors.`,
 
   # Delete without confirmation
   dlia cleanup execute --force`,
+	RunE: runCleanupReports,
+}
+
+// runCleanupReports deletes report files older than --reports-older-than across all
+// containers. Without the flag it previews the obsolete container cleanup with
+// --dry-run, and otherwise shows the help for the cleanup subcommands.
+func runCleanupReports(cmd *cobra.Command, _ []string) error {
+	if cleanupReportsOlderThan == "" {
+		if cleanupDryRun {
+			return runCleanupPreview(cmd)
+		}
+		return cmd.Help()
+	}
+
+	cfg := GetConfig()
+	if err := validateConfigOrExit(cfg, "cleanup"); err != nil {
+		return err
+	}
+
+	age, err := parseRetention(cleanupReportsOlderThan)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-age)
+
+	oldReports, err := findOldReports(cfg, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to find old reports: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(oldReports) == 0 {
+		_, _ = fmt.Fprintf(out, "%s No reports older than %s found\n", checkmark, cleanupReportsOlderThan)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "⚠️  Found %d report file(s) older than %s (before %s):\n", len(oldReports), cleanupReportsOlderThan, cutoff.Format("2006-01-02 15:04"))
+	_, _ = fmt.Fprintln(out, "")
+	for _, path := range oldReports {
+		_, _ = fmt.Fprintf(out, "  • %s\n", path)
+	}
+	_, _ = fmt.Fprintln(out, "")
+
+	if cleanupDryRun {
+		_, _ = fmt.Fprintln(out, "🔍 DRY RUN - No changes made")
+		return nil
+	}
+	if !cleanupForce {
+		_, _ = fmt.Fprintln(out, "❌ Aborted (use --force to confirm)")
+		return nil
+	}
+
+	deleted, errs := deleteReportFiles(oldReports)
+	_, _ = fmt.Fprintln(out, "✅ Report cleanup complete")
+	_, _ = fmt.Fprintf(out, "   Removed: %d file(s)\n", deleted)
+	if len(errs) > 0 {
+		_, _ = fmt.Fprintf(out, "   Failed: %d file(s)\n", len(errs))
+		_, _ = fmt.Fprintln(out, "")
+		_, _ = fmt.Fprintln(out, "⚠️  Errors encountered:")
+		for _, errMsg := range errs {
+			_, _ = fmt.Fprintf(out, "   - %s\n", errMsg)
+		}
+	}
+
+	return nil
+}
+
+// runCleanupPreview lists what cleanup execute would remove, with paths and sizes,
+// without deleting anything.
+func runCleanupPreview(cmd *cobra.Command) error {
+	cfg := GetConfig()
+	if err := validateConfigOrExit(cfg, "cleanup"); err != nil {
+		return err
+	}
+
+	// Connect to the log source (Docker or Kubernetes)
+	ctx := context.Background()
+	dockerClient, err := connectLogSource(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dockerClient.Close() }() // Close client; error not actionable in defer context
+
+	obsolete, err := findObsoleteContainers(ctx, dockerClient, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to find obsolete containers: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(obsolete) == 0 {
+		_, _ = fmt.Fprintf(out, "%s No obsolete container data found\n", checkmark)
+		_, _ = fmt.Fprintln(out, "  All storage is clean!")
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "🔍 DRY RUN - Cleanup would remove data of %d obsolete container(s):\n", len(obsolete))
+	_, _ = fmt.Fprintln(out, "")
+	writeCleanupPreview(out, obsolete, cfg)
+	_, _ = fmt.Fprintln(out, "")
+	_, _ = fmt.Fprintln(out, "No changes made. Run 'dlia cleanup execute' to remove this data")
+	return nil
+}
+
+// writeCleanupPreview lists each obsolete container with the files and directories
+// cleanup would remove and their sizes, followed by the total size.
+func writeCleanupPreview(w io.Writer, obsolete []ObsoleteContainer, cfg *config.Config) {
+	var total int64
+	for _, obs := range obsolete {
+		shortID := obs.ID
+		if !strings.HasPrefix(shortID, "orphaned-") {
+			shortID = shortContainerID(shortID, cfg.Output.ShortIDLength)
+		}
+
+		_, _ = fmt.Fprintf(w, "  • %s", shortID)
+		if obs.Name != "" {
+			_, _ = fmt.Fprintf(w, " (%s)", obs.Name)
+		}
+		_, _ = fmt.Fprintln(w, "")
+
+		for _, target := range cleanupTargets(obs, cfg) {
+			if target.Kind == stateEntryTarget {
+				_, _ = fmt.Fprintf(w, "    - %s in %s\n", target.Kind, target.Path)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "    - %s %s (%s)\n", target.Kind, target.Path, formatSize(target.Size))
+			total += target.Size
+		}
+	}
+	_, _ = fmt.Fprintln(w, "")
+	_, _ = fmt.Fprintf(w, "Total: %s\n", formatSize(total))
 }
 
 var cleanupListCmd = &cobra.Command{
@@ -64,19 +212,14 @@ contain data for each obsolete container.`,
 			return err
 		}
 
-		// Initialize Docker client
+		// Connect to the log source (Docker or Kubernetes)
 		ctx := context.Background()
-		dockerClient, err := docker.NewClient(cfg.Docker.SocketPath)
+		dockerClient, err := connectLogSource(ctx, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create Docker client: %w", err)
+			return err
 		}
 		defer func() { _ = dockerClient.Close() }() // Close client; error not actionable in defer context
 
-		// Ping Docker to verify connection
-		if pingErr := dockerClient.Ping(ctx); pingErr != nil {
-			return fmt.Errorf("failed to connect to Docker: %w", pingErr)
-		}
-
 		// Find obsolete containers
 		obsolete, err := findObsoleteContainers(ctx, dockerClient, cfg)
 		if err != nil {
@@ -101,8 +244,8 @@ contain data for each obsolete container.`,
 		for _, obs := range obsolete {
 			// Truncate container ID to 12 characters
 			shortID := obs.ID
-			if len(shortID) > 12 && !strings.HasPrefix(shortID, "orphaned-") {
-				shortID = shortID[:12]
+			if !strings.HasPrefix(shortID, "orphaned-") {
+				shortID = shortContainerID(shortID, cfg.Output.ShortIDLength)
 			}
 
 			// Format name
@@ -163,19 +306,14 @@ Use --dry-run to preview without deleting, or --force to skip confirmation.`,
 			return err
 		}
 
-		// Initialize Docker client
+		// Connect to the log source (Docker or Kubernetes)
 		ctx := context.Background()
-		dockerClient, err := docker.NewClient(cfg.Docker.SocketPath)
+		dockerClient, err := connectLogSource(ctx, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create Docker client: %w", err)
+			return err
 		}
 		defer func() { _ = dockerClient.Close() }() // Close client; error not actionable in defer context
 
-		// Ping Docker to verify connection
-		if pingErr := dockerClient.Ping(ctx); pingErr != nil {
-			return fmt.Errorf("failed to connect to Docker: %w", pingErr)
-		}
-
 		// Find obsolete containers
 		obsolete, err := findObsoleteContainers(ctx, dockerClient, cfg)
 		if err != nil {
@@ -192,32 +330,7 @@ Use --dry-run to preview without deleting, or --force to skip confirmation.`,
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⚠️  Found %d obsolete container(s):\n", len(obsolete))
 		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
 
-		for _, obs := range obsolete {
-			shortID := obs.ID
-			if len(shortID) > 12 && !strings.HasPrefix(shortID, "orphaned-") {
-				shortID = shortID[:12]
-			}
-
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  • %s", shortID)
-			if obs.Name != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), " (%s)", obs.Name)
-			}
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
-
-			// Show what will be deleted
-			if obs.InState {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "    - State entry")
-			}
-			if obs.InKB {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "    - Knowledge base file")
-			}
-			if obs.InReports {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "    - Reports directory")
-			}
-			if obs.InLLMLogs {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "    - LLM logs directory")
-			}
-		}
+		writeCleanupPreview(cmd.OutOrStdout(), obsolete, cfg)
 		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
 
 		// Dry-run mode - exit without deleting
@@ -249,10 +362,13 @@ Use --dry-run to preview without deleting, or --force to skip confirmation.`,
 		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
 
 		// Load state once for all deletions
-		st, err := state.Load(cfg.Output.StateFile)
+		st, err := state.Open(cfg.Output.StateBackend, cfg.Output.StateFile)
 		if err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("failed to load state: %w", err)
 		}
+		if st != nil {
+			defer st.Close() //nolint:errcheck // Close error not actionable in defer context
+		}
 
 		// Track results
 		successCount := 0
@@ -263,8 +379,8 @@ Use --dry-run to preview without deleting, or --force to skip confirmation.`,
 		for _, obs := range obsolete {
 			hasErrors := false
 			shortID := obs.ID
-			if len(shortID) > 12 && !strings.HasPrefix(shortID, "orphaned-") {
-				shortID = shortID[:12]
+			if !strings.HasPrefix(shortID, "orphaned-") {
+				shortID = shortContainerID(shortID, cfg.Output.ShortIDLength)
 			}
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Removing %s...", shortID)
@@ -336,4 +452,5 @@ func init() {
 	// Global cleanup flags
 	cleanupCmd.PersistentFlags().BoolVar(&cleanupDryRun, "dry-run", false, "show what would be deleted without actually deleting")
 	cleanupCmd.PersistentFlags().BoolVar(&cleanupForce, "force", false, "skip confirmation prompt")
+	cleanupCmd.Flags().StringVar(&cleanupReportsOlderThan, "reports-older-than", "", "delete report files older than this age for all containers (e.g. 30d, 12h); requires --force")
 }
@@ -4,13 +4,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zorak1103/dlia/internal/config"
 	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/knowledge"
 	"github.com/zorak1103/dlia/internal/sanitize"
 	"github.com/zorak1103/dlia/internal/state"
 )
@@ -34,7 +39,7 @@ func getDockerContainerIDs(ctx context.Context, dockerClient docker.Client) (map
 // scanStateFile reads the state file and extracts all tracked container IDs.
 // Returns an empty list if the state file doesn't exist (not an error condition).
 func scanStateFile(cfg *config.Config) ([]string, error) {
-	st, err := state.Load(cfg.Output.StateFile)
+	st, err := state.OpenReadOnly(cfg.Output.StateBackend, cfg.Output.StateFile)
 	if err != nil {
 		// If state file doesn't exist, return empty list (not an error)
 		if os.IsNotExist(err) {
@@ -42,6 +47,7 @@ func scanStateFile(cfg *config.Config) ([]string, error) {
 		}
 		return nil, fmt.Errorf("failed to load state file: %w", err)
 	}
+	defer st.Close() //nolint:errcheck // Close error not actionable in defer context
 
 	containers := st.GetAllContainers()
 	containerIDs := make([]string, 0, len(containers))
@@ -52,7 +58,7 @@ func scanStateFile(cfg *config.Config) ([]string, error) {
 	return containerIDs, nil
 }
 
-// scanKnowledgeBase returns container names found in knowledge_base/services/*.md files
+// scanKnowledgeBase returns container names found in knowledge_base/services/*.md and *.json files
 func scanKnowledgeBase(cfg *config.Config) ([]string, error) {
 	kbServicesDir := filepath.Join(cfg.Output.KnowledgeBaseDir, "services")
 
@@ -67,14 +73,20 @@ func scanKnowledgeBase(cfg *config.Config) ([]string, error) {
 	}
 
 	containerNames := make([]string, 0)
+	seen := make(map[string]bool)
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
-		// Extract container name from .md files
-		if strings.HasSuffix(entry.Name(), ".md") {
-			// Remove .md extension and un-sanitize (replace _ with /)
-			name := strings.TrimSuffix(entry.Name(), ".md")
+		// Extract container name from KB files of either format
+		ext := filepath.Ext(entry.Name())
+		if !slices.Contains(knowledge.ServiceFileExtensions, ext) {
+			continue
+		}
+		// Remove the extension; the result is the sanitized container name
+		name := strings.TrimSuffix(entry.Name(), ext)
+		if !seen[name] {
+			seen[name] = true
 			containerNames = append(containerNames, name)
 		}
 	}
@@ -139,6 +151,82 @@ func scanLLMLogs(cfg *config.Config) ([]string, error) {
 	return containerNames, nil
 }
 
+// parseRetention parses a retention age such as "30d", "12h" or "90m". A "d" suffix
+// counts days; any other value must be a Go duration. The age must be positive.
+func parseRetention(value string) (time.Duration, error) {
+	var age time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention %q (use e.g. 30d or 12h)", value)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention %q (use e.g. 30d or 12h)", value)
+		}
+		age = d
+	}
+
+	if age <= 0 {
+		return 0, fmt.Errorf("invalid retention %q (must be greater than 0)", value)
+	}
+	return age, nil
+}
+
+// findOldReports returns the files in the report directories (reports and their raw
+// log files) last modified before cutoff, sorted by path. Subdirectories are searched
+// as well, for layouts from output.report_path_template.
+func findOldReports(cfg *config.Config, cutoff time.Time) ([]string, error) {
+	reportDirs, err := scanReports(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var old []string
+	for _, name := range reportDirs {
+		dir := filepath.Join(cfg.Output.ReportsDir, name)
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil // Removed concurrently
+			}
+			if info.ModTime().Before(cutoff) {
+				old = append(old, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reports directory %s: %w", dir, err)
+		}
+	}
+
+	sort.Strings(old)
+	return old, nil
+}
+
+// deleteReportFiles removes the given report files. It returns the number of
+// deleted files and an error message for each file that could not be removed.
+func deleteReportFiles(paths []string) (int, []string) {
+	deleted := 0
+	var errs []string
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		deleted++
+	}
+	return deleted, errs
+}
+
 // ObsoleteContainer represents a container that exists in storage but not in Docker
 type ObsoleteContainer struct {
 	ID        string // Container ID (from state file)
@@ -201,9 +289,13 @@ func scanAllStorageLocations(cfg *config.Config) (*storageMaps, error) {
 		return nil, fmt.Errorf("failed to scan knowledge base: %w", err)
 	}
 
-	reportNames, err := scanReports(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to scan reports: %w", err)
+	// Custom report layouts do not name directories after containers
+	var reportNames []string
+	if cfg.Output.FlatReportLayout() {
+		reportNames, err = scanReports(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reports: %w", err)
+		}
 	}
 
 	llmLogNames, err := scanLLMLogs(cfg)
@@ -240,10 +332,11 @@ func findObsoleteFromState(cfg *config.Config, dockerIDs map[string]bool) map[st
 	obsoleteMap := make(map[string]*ObsoleteContainer)
 
 	// Load state to get container info
-	st, err := state.Load(cfg.Output.StateFile)
+	st, err := state.OpenReadOnly(cfg.Output.StateBackend, cfg.Output.StateFile)
 	if err != nil {
 		return obsoleteMap
 	}
+	defer st.Close() //nolint:errcheck // Close error not actionable in defer context
 
 	containers := st.GetAllContainers()
 	for id, ctr := range containers {
@@ -293,10 +386,11 @@ func findOrphanedEntries(cfg *config.Config, dockerIDs map[string]bool, storageM
 	}
 
 	// Load state to check against
-	st, err := state.Load(cfg.Output.StateFile)
+	st, err := state.OpenReadOnly(cfg.Output.StateBackend, cfg.Output.StateFile)
 	if err != nil {
 		return
 	}
+	defer st.Close() //nolint:errcheck // Close error not actionable in defer context
 
 	containers := st.GetAllContainers()
 	for name := range allNames {
@@ -306,10 +400,12 @@ func findOrphanedEntries(cfg *config.Config, dockerIDs map[string]bool, storageM
 	}
 }
 
-// isOrphanedEntry checks if a storage name is orphaned (no corresponding Docker container)
+// isOrphanedEntry checks if a storage name is orphaned (no corresponding Docker container).
+// Names written by older versions (sanitize.LegacyName) still belong to their container;
+// their knowledge base file is renamed on the container's next scan.
 func isOrphanedEntry(name string, containers map[string]*state.Container, dockerIDs map[string]bool) bool {
 	for id, ctr := range containers {
-		if sanitize.Name(ctr.Name) == name && dockerIDs[id] {
+		if (sanitize.Name(ctr.Name) == name || sanitize.LegacyName(ctr.Name) == name) && dockerIDs[id] {
 			return false
 		}
 	}
@@ -322,7 +418,7 @@ func addOrphanedEntry(name string, storageMaps *storageMaps, obsoleteMap map[str
 	if _, exists := obsoleteMap[pseudoID]; !exists {
 		obsoleteMap[pseudoID] = &ObsoleteContainer{
 			ID:        pseudoID,
-			Name:      strings.ReplaceAll(name, "_", "/"), // Un-sanitize for display
+			Name:      sanitize.Original(name), // Un-sanitize; round-trips through sanitize.Name on delete
 			InState:   false,
 			InKB:      storageMaps.kbMap[name],
 			InReports: storageMaps.reportsMap[name],
@@ -348,8 +444,8 @@ func convertToSortedList(obsoleteMap map[string]*ObsoleteContainer) []ObsoleteCo
 	return obsoleteList
 }
 
-// deleteFromState removes a container entry from state.json
-func deleteFromState(containerID string, st *state.State) error {
+// deleteFromState removes a container entry from the state backend
+func deleteFromState(containerID string, st state.Backend) error {
 	st.RemoveContainer(containerID)
 	if err := st.Save(); err != nil {
 		return fmt.Errorf("failed to save state after removing container: %w", err)
@@ -357,27 +453,29 @@ func deleteFromState(containerID string, st *state.State) error {
 	return nil
 }
 
-// deleteKnowledgeBase removes a container's knowledge base file
+// deleteKnowledgeBase removes a container's knowledge base files (Markdown and JSON)
 func deleteKnowledgeBase(containerName string, cfg *config.Config) error {
 	if containerName == "" {
 		return nil // No name, nothing to delete
 	}
 
 	sanitized := sanitize.Name(containerName)
-	kbFile := filepath.Join(cfg.Output.KnowledgeBaseDir, "services", sanitized+".md")
+	for _, ext := range knowledge.ServiceFileExtensions {
+		kbFile := filepath.Join(cfg.Output.KnowledgeBaseDir, "services", sanitized+ext)
 
-	// Check if file exists
-	if _, err := os.Stat(kbFile); os.IsNotExist(err) {
-		return nil // File doesn't exist, nothing to delete
-	}
+		// Check if file exists
+		if _, err := os.Stat(kbFile); os.IsNotExist(err) {
+			continue // File doesn't exist, nothing to delete
+		}
 
-	// Delete the file
-	if err := os.Remove(kbFile); err != nil {
-		// Check for permission errors
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied deleting %s. Check file permissions", kbFile)
+		// Delete the file
+		if err := os.Remove(kbFile); err != nil {
+			// Check for permission errors
+			if os.IsPermission(err) {
+				return fmt.Errorf("permission denied deleting %s. Check file permissions", kbFile)
+			}
+			return fmt.Errorf("failed to delete knowledge base file %s: %w", kbFile, err)
 		}
-		return fmt.Errorf("failed to delete knowledge base file %s: %w", kbFile, err)
 	}
 
 	return nil
@@ -439,3 +537,83 @@ func deleteLLMLogsDir(containerName string, cfg *config.Config) error {
 
 	return nil
 }
+
+// stateEntryTarget is the kind of the cleanupTarget for a container's state entry.
+const stateEntryTarget = "State entry"
+
+// cleanupTarget is one item that cleanup removes for an obsolete container.
+type cleanupTarget struct {
+	Kind string // e.g. "State entry" or "Reports directory"
+	Path string // File or directory removed; for a state entry, the state file it is removed from
+	Size int64  // Bytes on disk; 0 for a state entry
+}
+
+// cleanupTargets lists what cleanup execute removes for obs, following the same rules
+// as deleteFromState, deleteKnowledgeBase, deleteReportsDir and deleteLLMLogsDir.
+// Files and directories that no longer exist are left out.
+func cleanupTargets(obs ObsoleteContainer, cfg *config.Config) []cleanupTarget {
+	var targets []cleanupTarget
+	if obs.InState {
+		targets = append(targets, cleanupTarget{Kind: stateEntryTarget, Path: cfg.Output.StateFile})
+	}
+	if obs.Name == "" {
+		return targets
+	}
+
+	add := func(kind, path string) {
+		size, err := diskUsage(path)
+		if err != nil {
+			return // Missing or unreadable; the delete helpers skip it as well
+		}
+		targets = append(targets, cleanupTarget{Kind: kind, Path: path, Size: size})
+	}
+
+	sanitized := sanitize.Name(obs.Name)
+	if obs.InKB {
+		for _, ext := range knowledge.ServiceFileExtensions {
+			add("Knowledge base file", filepath.Join(cfg.Output.KnowledgeBaseDir, "services", sanitized+ext))
+		}
+	}
+	if obs.InReports {
+		add("Reports directory", filepath.Join(cfg.Output.ReportsDir, sanitized))
+	}
+	if obs.InLLMLogs && cfg.Output.LLMLogEnabled {
+		add("LLM logs directory", filepath.Join(cfg.Output.LLMLogDir, sanitized))
+	}
+
+	return targets
+}
+
+// diskUsage returns the size of a file, or the total size of the files below a directory.
+func diskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// formatSize formats a byte count for display, e.g. "512 B" or "14.2 KiB".
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exp])
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"github.com/zorak1103/dlia/internal/config"
 	"github.com/zorak1103/dlia/internal/docker"
 	"github.com/zorak1103/dlia/internal/sanitize"
+	"github.com/zorak1103/dlia/internal/state"
 )
 
 func TestSanitizeName(t *testing.T) {
@@ -28,12 +30,12 @@ func TestSanitizeName(t *testing.T) {
 		{
 			name:     "name with single slash",
 			input:    "project/nginx",
-			expected: "project_nginx",
+			expected: "project~nginx",
 		},
 		{
 			name:     "name with multiple slashes",
 			input:    "company/project/nginx",
-			expected: "company_project_nginx",
+			expected: "company~project~nginx",
 		},
 		{
 			name:     "empty string",
@@ -50,6 +52,53 @@ func TestSanitizeName(t *testing.T) {
 	}
 }
 
+func TestAddOrphanedEntry_CollidingNamesRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Output: config.OutputConfig{KnowledgeBaseDir: tempDir}}
+
+	servicesDir := filepath.Join(tempDir, "services")
+	require.NoError(t, os.MkdirAll(servicesDir, 0o750))
+	for _, name := range []string{"org/app", "org_app"} {
+		file := filepath.Join(servicesDir, sanitize.Name(name)+".md")
+		require.NoError(t, os.WriteFile(file, []byte("# Knowledge Base: "+name+"\n"), 0o600))
+	}
+
+	kbNames, err := scanKnowledgeBase(cfg)
+	require.NoError(t, err)
+	require.Len(t, kbNames, 2, "colliding container names must map to distinct files")
+
+	storage := &storageMaps{kbMap: map[string]bool{}}
+	for _, name := range kbNames {
+		storage.kbMap[name] = true
+	}
+
+	obsoleteMap := make(map[string]*ObsoleteContainer)
+	for _, name := range kbNames {
+		addOrphanedEntry(name, storage, obsoleteMap)
+	}
+
+	var names []string
+	for _, obsolete := range obsoleteMap {
+		names = append(names, obsolete.Name)
+	}
+	assert.ElementsMatch(t, []string{"org/app", "org_app"}, names)
+
+	// Deleting by the un-sanitized name removes exactly the matching file
+	require.NoError(t, deleteKnowledgeBase("org/app", cfg))
+	remaining, err := scanKnowledgeBase(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org_app"}, remaining)
+}
+
+func TestIsOrphanedEntry_LegacyName(t *testing.T) {
+	containers := map[string]*state.Container{"abc123": {Name: "org/app"}}
+	dockerIDs := map[string]bool{"abc123": true}
+
+	assert.False(t, isOrphanedEntry("org~app", containers, dockerIDs))
+	assert.False(t, isOrphanedEntry("org_app", containers, dockerIDs), "files named by older versions belong to the running container")
+	assert.True(t, isOrphanedEntry("org_app", containers, map[string]bool{}))
+}
+
 func TestScanStateFile(t *testing.T) {
 	t.Run("empty state file", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -284,10 +333,28 @@ func (m *testMockDockerClient) ReadLogs(_ context.Context, _ string, _ docker.Lo
 	return []docker.LogEntry{}, nil
 }
 
+func (m *testMockDockerClient) ReadLogsAfter(_ context.Context, _ string, _ docker.LogCursor) ([]docker.LogEntry, error) {
+	return []docker.LogEntry{}, nil
+}
+
 func (m *testMockDockerClient) ReadLogsLookback(_ context.Context, _ string, _ time.Duration) ([]docker.LogEntry, error) {
 	return []docker.LogEntry{}, nil
 }
 
+func (m *testMockDockerClient) ReadLogsTail(_ context.Context, _ string, _ int) ([]docker.LogEntry, error) {
+	return []docker.LogEntry{}, nil
+}
+
+func (m *testMockDockerClient) FollowLogs(_ context.Context, _ string) (<-chan docker.LogEntry, error) {
+	entries := make(chan docker.LogEntry)
+	close(entries)
+	return entries, nil
+}
+
+func (m *testMockDockerClient) ReadEventsSince(_ context.Context, _ string, _ time.Time) ([]docker.Event, error) {
+	return nil, nil
+}
+
 func TestFindObsoleteContainers(t *testing.T) {
 	t.Run("no obsolete containers", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -372,3 +439,140 @@ func TestFindObsoleteContainers(t *testing.T) {
 		assert.True(t, obsolete[0].InState)
 	})
 }
+
+func TestParseRetention(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "30d", want: 30 * 24 * time.Hour},
+		{input: "12h", want: 12 * time.Hour},
+		{input: "90m", want: 90 * time.Minute},
+		{input: "0d", wantErr: true},
+		{input: "-1h", wantErr: true},
+		{input: "xd", wantErr: true},
+		{input: "month", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseRetention(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFindAndDeleteOldReports(t *testing.T) {
+	reportsDir := t.TempDir()
+	cfg := &config.Config{Output: config.OutputConfig{ReportsDir: reportsDir}}
+
+	now := time.Now()
+	writeReport := func(container, name string, age time.Duration) string {
+		t.Helper()
+		dir := filepath.Join(reportsDir, container)
+		require.NoError(t, os.MkdirAll(dir, 0o750))
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("report"), 0o600))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+		return path
+	}
+
+	oldWeb := writeReport("web", "2025-01-01_10-00-00.md", 40*24*time.Hour)
+	oldWebLogs := writeReport("web", "2025-01-01_10-00-00.logs.txt", 40*24*time.Hour)
+	writeReport("web", "2025-02-09_10-00-00.md", 24*time.Hour)
+	oldDB := writeReport("db", "2025-01-02_10-00-00.html", 31*24*time.Hour)
+
+	old, err := findOldReports(cfg, now.Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldDB, oldWebLogs, oldWeb}, old)
+
+	deleted, errs := deleteReportFiles(old)
+	assert.Equal(t, 3, deleted)
+	assert.Empty(t, errs)
+
+	remaining, err := os.ReadDir(filepath.Join(reportsDir, "web"))
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1, "recent reports must be kept")
+}
+
+func TestFindOldReports_NestedLayout(t *testing.T) {
+	reportsDir := t.TempDir()
+	cfg := &config.Config{Output: config.OutputConfig{ReportsDir: reportsDir, ReportPathTemplate: "{{.Project}}/{{.Container}}"}}
+
+	dir := filepath.Join(reportsDir, "shop", "web")
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	old := filepath.Join(dir, "2025-01-01_10-00-00.md")
+	require.NoError(t, os.WriteFile(old, []byte("report"), 0o600))
+	past := time.Now().Add(-40 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(old, past, past))
+
+	found, err := findOldReports(cfg, time.Now().Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{old}, found)
+
+	maps, err := scanAllStorageLocations(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, maps.reportsMap, "project directories must not be taken for containers")
+}
+
+func TestCleanupTargets(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			StateFile:        filepath.Join(tempDir, "state.json"),
+			KnowledgeBaseDir: filepath.Join(tempDir, "kb"),
+			ReportsDir:       filepath.Join(tempDir, "reports"),
+			LLMLogDir:        filepath.Join(tempDir, "llm"),
+			LLMLogEnabled:    false,
+		},
+	}
+
+	kbFile := filepath.Join(tempDir, "kb", "services", "old-nginx.md")
+	reportsDir := filepath.Join(tempDir, "reports", "old-nginx")
+	require.NoError(t, os.MkdirAll(filepath.Dir(kbFile), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(reportsDir, "2024"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "llm", "old-nginx"), 0o750))
+	require.NoError(t, os.WriteFile(kbFile, make([]byte, 100), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(reportsDir, "a.md"), make([]byte, 300), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(reportsDir, "2024", "b.md"), make([]byte, 700), 0o600))
+
+	obs := ObsoleteContainer{ID: "removed123", Name: "old-nginx", InState: true, InKB: true, InReports: true, InLLMLogs: true}
+	targets := cleanupTargets(obs, cfg)
+
+	// LLM logs are only deleted with LLM logging enabled, so the preview leaves them out
+	want := []cleanupTarget{
+		{Kind: stateEntryTarget, Path: cfg.Output.StateFile},
+		{Kind: "Knowledge base file", Path: kbFile, Size: 100},
+		{Kind: "Reports directory", Path: reportsDir, Size: 1000},
+	}
+	assert.Equal(t, want, targets)
+
+	var out bytes.Buffer
+	writeCleanupPreview(&out, []ObsoleteContainer{obs}, cfg)
+	assert.Contains(t, out.String(), "• removed123 (old-nginx)")
+	assert.Contains(t, out.String(), "Reports directory "+reportsDir+" (1000 B)")
+	assert.Contains(t, out.String(), "Total: 1.1 KiB")
+
+	_, err := os.Stat(kbFile)
+	assert.NoError(t, err, "the preview must not delete anything")
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:                "0 B",
+		1023:             "1023 B",
+		1536:             "1.5 KiB",
+		5 * 1024 * 1024:  "5.0 MiB",
+		3 << 40:          "3.0 TiB",
+		2048 * (1 << 40): "2048.0 TiB",
+	}
+	for size, want := range tests {
+		assert.Equal(t, want, formatSize(size), "formatSize(%d)", size)
+	}
+}
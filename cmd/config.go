@@ -9,7 +9,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/llm"
 	"github.com/zorak1103/dlia/internal/prompts"
+	"github.com/zorak1103/dlia/internal/ui"
 )
 
 // validateConfigOrExit validates that the configuration is properly initialized
@@ -17,12 +19,12 @@ import (
 func validateConfigOrExit(cfg *config.Config, _ string) error {
 	// Check if config was loaded
 	if cfg == nil {
-		return fmt.Errorf("configuration not loaded\n\nDLIA has not been initialized in this directory.\nRun 'dlia init' to set up DLIA and create the necessary configuration")
+		return configError(fmt.Errorf("configuration not loaded\n\nDLIA has not been initialized in this directory.\nRun 'dlia init' to set up DLIA and create the necessary configuration"))
 	}
 
 	// Check if config file exists (using DI approach - config file path stored in Config struct)
 	if cfg.ConfigFilePath == "" {
-		return fmt.Errorf("no configuration file found\n\nDLIA requires a configuration file to run.\nRun 'dlia init' to create config.yaml in the current directory")
+		return configError(fmt.Errorf("no configuration file found\n\nDLIA requires a configuration file to run.\nRun 'dlia init' to create config.yaml in the current directory"))
 	}
 
 	// Validate required directories exist
@@ -60,7 +62,7 @@ func validateConfigOrExit(cfg *config.Config, _ string) error {
 			errMsg += fmt.Sprintf("  - %s\n", dir)
 		}
 		errMsg += "\nRun 'dlia init' to create the required directory structure"
-		return fmt.Errorf("%s", errMsg)
+		return configError(fmt.Errorf("%s", errMsg))
 	}
 
 	return nil
@@ -74,60 +76,216 @@ var configCmd = &cobra.Command{
 This shows the merged configuration from:
   1. Default values
   2. Configuration file (config.yaml)
-  3. Environment variables (highest priority)
+  3. The selected profile (--profile or DLIA_PROFILE)
+  4. Environment variables (highest priority)
 
 Sensitive values like API keys are masked for security.`,
 	Example: `  # Show current configuration
   dlia config
 
   # Show with custom config file
-  dlia config --config /etc/dlia/config.yaml`,
+  dlia config --config /etc/dlia/config.yaml
+
+  # Show the settings of the "ci" profile
+  dlia config --profile ci`,
 	RunE: func(_ *cobra.Command, _ []string) error {
 		cfg := GetConfig()
 		if cfg == nil {
 			return fmt.Errorf("configuration not loaded\n\nTo get started, run: dlia init")
 		}
 
-		fmt.Println("=== DLIA Effective Configuration ===")
-		fmt.Println()
+		ui.Println("=== DLIA Effective Configuration ===")
+		ui.Println()
+
+		configFile := cfg.ConfigFilePath
+		if configFile == "" {
+			configFile = "(none, using defaults and environment variables)"
+		}
+		ui.Printf("📄 Config File:    %s\n", configFile)
+		profile := cfg.Profile
+		if profile == "" {
+			profile = "(none)"
+		}
+		ui.Printf("🏷️  Profile:        %s\n", profile)
+		ui.Println()
 
 		// LLM Configuration
-		fmt.Println("🤖 LLM Configuration:")
-		fmt.Printf("   Base URL:       %s\n", cfg.LLM.BaseURL)
-		fmt.Printf("   Model:          %s\n", cfg.LLM.Model)
-		fmt.Printf("   Max Tokens:     %d\n", cfg.LLM.MaxTokens)
-		fmt.Printf("   API Key:        %s\n", maskAPIKey(cfg.LLM.APIKey))
-		fmt.Println()
+		ui.Println("🤖 LLM Configuration:")
+		ui.Printf("   Base URL:       %s\n", cfg.LLM.BaseURL)
+		ui.Printf("   Provider:       %s\n", cfg.LLM.Provider)
+		if cfg.LLM.Provider == config.ProviderAzure {
+			ui.Printf("   Azure Deployment: %s\n", cfg.LLM.Azure.Deployment)
+			ui.Printf("   Azure API Version: %s\n", cfg.LLM.Azure.APIVersion)
+		}
+		ui.Printf("   Model:          %s\n", cfg.LLM.Model)
+		if cfg.LLM.MaxTokens > 0 {
+			ui.Printf("   Max Tokens:     %d\n", cfg.LLM.MaxTokens)
+		} else {
+			window, _ := llm.ContextWindow(cfg.LLM.Model)
+			ui.Printf("   Max Tokens:     %d (auto-detected)\n", window)
+		}
+		ui.Printf("   Max Log Lines:  %d\n", cfg.LLM.MaxLogLines)
+		ui.Printf("   Max Log Bytes:  %d\n", cfg.LLM.MaxLogBytes)
+		ui.Printf("   Structured:     %v\n", cfg.LLM.StructuredOutput)
+		if cfg.LLM.StructuredOutput {
+			ui.Printf("   Structured Via: %s\n", cfg.LLM.StructuredMethod)
+		}
+		ui.Printf("   Timeout:        %s\n", cfg.LLM.RequestTimeout)
+		if cfg.LLM.CircuitBreakerThreshold > 0 {
+			ui.Printf("   Circuit Breaker: %d failures, %s cooldown\n", cfg.LLM.CircuitBreakerThreshold, cfg.LLM.CircuitBreakerCooldown)
+		} else {
+			ui.Printf("   Circuit Breaker: disabled\n")
+		}
+		ui.Printf("   Dedup Scans:    %d\n", cfg.LLM.DedupAcrossScans)
+		ui.Printf("   Dedup Mode:     %s (min %d repeats)\n", cfg.LLM.DedupMode, cfg.LLM.DedupMinRepeats)
+		ui.Printf("   Dedup Times:    %v\n", cfg.LLM.DedupTimestamps)
+		if cfg.LLM.SkipCleanLogs {
+			ui.Printf("   Skip Clean Logs: up to %d lines without %s\n", cfg.LLM.SkipCleanMaxLines, strings.Join(cfg.LLM.SkipCleanKeywords, ", "))
+		}
+		if cfg.LLM.MinLogLines > 0 {
+			ui.Printf("   Min Log Lines:  %d\n", cfg.LLM.MinLogLines)
+		}
+		if cfg.LLM.IncludePreviousAnalysis {
+			ui.Printf("   Previous Analysis: included for trend detection\n")
+		}
+		if cfg.LLM.CacheEnabled {
+			ui.Printf("   Result Cache:   %s (TTL %s)\n", cfg.LLM.CacheDir, cfg.LLM.CacheTTL)
+		}
+		ui.Printf("   Response Reserve: %d tokens\n", cfg.LLM.ResponseReserveTokens)
+		ui.Printf("   System Prompt Reserve: %d tokens\n", cfg.LLM.SystemPromptReserveTokens)
+		ui.Printf("   Chunk Summary Max: %d tokens\n", cfg.LLM.ChunkSummaryMaxTokens)
+		ui.Printf("   Temperature:    %g (summaries %g)\n", cfg.LLM.Temperature, cfg.LLM.SummaryTemperature)
+		if cfg.LLM.TopP > 0 {
+			ui.Printf("   Top P:          %g\n", cfg.LLM.TopP)
+		}
+		if cfg.LLM.FrequencyPenalty != 0 {
+			ui.Printf("   Frequency Penalty: %g\n", cfg.LLM.FrequencyPenalty)
+		}
+		if cfg.LLM.RequestsPerMinute > 0 {
+			ui.Printf("   Rate Limit:     %d requests/minute\n", cfg.LLM.RequestsPerMinute)
+		} else {
+			ui.Printf("   Rate Limit:     unlimited\n")
+		}
+		ui.Printf("   Chunk Concurrency: %d\n", max(cfg.LLM.ChunkConcurrency, 1))
+		if cfg.LLM.ChunkOverlapLines > 0 {
+			ui.Printf("   Chunk Overlap:  %d lines\n", cfg.LLM.ChunkOverlapLines)
+		}
+		if cfg.LLM.SynthesisCriticalLines > 0 {
+			ui.Printf("   Synthesis Lines: %d critical lines per chunk\n", cfg.LLM.SynthesisCriticalLines)
+		}
+		displayAPIKeys(cfg.LLM.Keys())
+		ui.Println()
+
+		// Log source
+		if cfg.Source == config.SourceKubernetes {
+			ui.Println("☸️  Kubernetes Configuration:")
+			ui.Printf("   Target:         %s\n", kubernetesTarget(cfg))
+			if cfg.Kubernetes.TokenFile != "" {
+				ui.Printf("   Token File:     %s\n", cfg.Kubernetes.TokenFile)
+			}
+			if cfg.Kubernetes.CAFile != "" {
+				ui.Printf("   CA File:        %s\n", cfg.Kubernetes.CAFile)
+			}
+			ui.Println()
+		}
 
 		// Docker Configuration
-		fmt.Println("🐳 Docker Configuration:")
-		fmt.Printf("   Socket Path:    %s\n", cfg.Docker.SocketPath)
-		fmt.Println()
+		ui.Println("🐳 Docker Configuration:")
+		ui.Printf("   Socket Path:    %s\n", cfg.Docker.SocketPath)
+		ui.Printf("   Stream:         %s\n", cfg.Docker.Stream)
+		if cfg.Docker.MaxContainersPerScan > 0 {
+			ui.Printf("   Max Containers: %d per scan\n", cfg.Docker.MaxContainersPerScan)
+		} else {
+			ui.Printf("   Max Containers: unlimited\n")
+		}
+		if len(cfg.Docker.IncludeContainers) > 0 {
+			ui.Printf("   Include:        %s\n", strings.Join(cfg.Docker.IncludeContainers, ", "))
+		}
+		if len(cfg.Docker.ExcludeContainers) > 0 {
+			ui.Printf("   Exclude:        %s\n", strings.Join(cfg.Docker.ExcludeContainers, ", "))
+		}
+		if cfg.Docker.MaxCatchupWindow > 0 {
+			ui.Printf("   Max Catch-up:   %s\n", cfg.Docker.MaxCatchupWindow)
+		}
+		if cfg.Docker.TimestampFormat != "" || cfg.Docker.TimestampPattern != "" {
+			ui.Printf("   Timestamp Format:  %s\n", cfg.Docker.TimestampFormat)
+			ui.Printf("   Timestamp Pattern: %s\n", cfg.Docker.TimestampPattern)
+		}
+		if cfg.Docker.MultilinePattern != "" {
+			ui.Printf("   Multi-line:     %s\n", cfg.Docker.MultilinePattern)
+		}
+		ui.Printf("   Include Events: %v\n", cfg.Docker.IncludeEvents)
+		ui.Printf("   Invalid UTF-8:  %s\n", cfg.Docker.InvalidUTF8)
+		ui.Println()
 
 		// Notification Configuration
-		fmt.Println("🔔 Notification Configuration:")
-		fmt.Printf("   Enabled:        %v\n", cfg.Notification.Enabled)
-		fmt.Printf("   Shoutrrr URL:   %s\n", maskShoutrrrURL(cfg.Notification.ShoutrrURL))
-		fmt.Println()
+		ui.Println("🔔 Notification Configuration:")
+		ui.Printf("   Enabled:        %v\n", cfg.Notification.Enabled)
+		ui.Printf("   Shoutrrr URL:   %s\n", maskShoutrrrURL(cfg.Notification.ShoutrrURL))
+		ui.Printf("   Min Severity:   %s\n", cfg.Notification.MinSeverity)
+		ui.Printf("   Per Container:  %v\n", cfg.Notification.PerContainer)
+		if cfg.Notification.CooldownPerContainer > 0 {
+			ui.Printf("   Cooldown:       %s per container\n", cfg.Notification.CooldownPerContainer)
+		}
+		if cfg.Notification.PendingDir != "" {
+			ui.Printf("   Pending Dir:    %s (max %d)\n", cfg.Notification.PendingDir, cfg.Notification.MaxPending)
+		}
+		ui.Println()
 
 		// Output Configuration
-		fmt.Println("📁 Output Configuration:")
-		fmt.Printf("   Reports Dir:    %s\n", cfg.Output.ReportsDir)
-		fmt.Printf("   KB Dir:         %s\n", cfg.Output.KnowledgeBaseDir)
-		fmt.Printf("   State File:     %s\n", cfg.Output.StateFile)
-		fmt.Printf("   Knowledge Retention: %d days\n", cfg.Output.KnowledgeRetentionDays)
-		fmt.Println()
+		ui.Println("📁 Output Configuration:")
+		ui.Printf("   Reports Dir:    %s\n", cfg.Output.ReportsDir)
+		ui.Printf("   KB Dir:         %s\n", cfg.Output.KnowledgeBaseDir)
+		ui.Printf("   State File:     %s\n", cfg.Output.StateFile)
+		ui.Printf("   State Backend:  %s\n", cfg.Output.StateBackend)
+		ui.Printf("   Knowledge Retention: %d days\n", cfg.Output.KnowledgeRetentionDays)
+		ui.Printf("   Knowledge Max Entries: %d\n", cfg.Output.KnowledgeMaxEntries)
+		ui.Printf("   Knowledge Format: %s\n", cfg.Output.KnowledgeFormat)
+		ui.Printf("   Knowledge Skip Unchanged: %t\n", cfg.Output.KnowledgeSkipUnchanged)
+		ui.Printf("   Report Format:  %s\n", cfg.Output.ReportFormat)
+		ui.Printf("   Max Report Bytes: %d\n", cfg.Output.MaxReportBytes)
+		ui.Printf("   Short ID Length: %d\n", cfg.Output.ShortIDLength)
+		ui.Printf("   Group By Compose Project: %v\n", cfg.Output.GroupByComposeProject)
+		ui.Printf("   Save Raw Logs:  %v\n", cfg.Output.SaveRawLogs)
+		ui.Printf("   Report Path:    %s\n", cfg.Output.ReportPathTemplate)
+		ui.Printf("   Display Zone:   %s\n", cfg.DisplayLocation())
+		ui.Println()
 
 		// Privacy Configuration
-		fmt.Println("🔒 Privacy Configuration:")
-		fmt.Printf("   Anonymize IPs:  %v\n", cfg.Privacy.AnonymizeIPs)
-		fmt.Printf("   Anonymize Keys: %v\n", cfg.Privacy.AnonymizeSecrets)
-		fmt.Println()
+		ui.Println("🔒 Privacy Configuration:")
+		ui.Printf("   Anonymize IPs:  %v\n", cfg.Privacy.AnonymizeIPs)
+		ui.Printf("   Anonymize Keys: %v\n", cfg.Privacy.AnonymizeSecrets)
+		ui.Printf("   Anonymize Emails: %v\n", cfg.Privacy.AnonymizeEmails)
+		ui.Printf("   Anonymize Card Numbers: %v\n", cfg.Privacy.AnonymizeCardNumbers)
+		ui.Println()
+
+		// Logging Configuration
+		ui.Println("🪵 Logging Configuration:")
+		ui.Printf("   Level:          %s\n", cfg.Logging.Level)
+		ui.Printf("   Format:         %s\n", cfg.Logging.Format)
+		ui.Println()
+
+		ui.Println("🔍 Regexp Filters:")
+		ui.Printf("   Containers:     %d\n", len(cfg.RegexpFilters))
+		if cfg.RegexpFiltersFile != "" {
+			ui.Printf("   Filters File:   %s\n", cfg.RegexpFiltersFile)
+		} else {
+			ui.Printf("   Filters File:   %s (if present)\n", config.DefaultRegexpFiltersFile)
+		}
+		ui.Println()
+
+		if len(cfg.ContainerInstructions) > 0 {
+			ui.Println("🎯 Container Instructions (first match wins):")
+			for _, ci := range cfg.ContainerInstructions {
+				ui.Printf("   %s: %s\n", ci.Pattern, ci.Instructions)
+			}
+			ui.Println()
+		}
 
 		// Prompts Configuration (Phase 8)
-		fmt.Println("📝 Prompts Configuration:")
+		ui.Println("📝 Prompts Configuration:")
 		displayPromptPaths(cfg)
-		fmt.Println()
+		ui.Println()
 
 		return nil
 	},
@@ -138,6 +296,23 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 }
 
+// displayAPIKeys prints the masked API key, or all keys in failover order when
+// llm.api_keys adds more.
+func displayAPIKeys(keys []string) {
+	if len(keys) <= 1 {
+		var key string
+		if len(keys) == 1 {
+			key = keys[0]
+		}
+		ui.Printf("   API Key:        %s\n", maskAPIKey(key))
+		return
+	}
+	ui.Printf("   API Keys:       %d (failover order)\n", len(keys))
+	for i, key := range keys {
+		ui.Printf("     %d. %s\n", i+1, maskAPIKey(key))
+	}
+}
+
 // maskAPIKey obscures API keys for secure display in config output.
 // Shows first 4 and last 4 characters (e.g., "sk-1***abc2") to allow key identification
 // without exposing the full secret. This 4/4 split follows OpenAI's display convention
@@ -184,13 +359,14 @@ func displayPromptPaths(cfg *config.Config) {
 		{"Chunk Summary Prompt", cfg.Prompts.ChunkSummaryPrompt},
 		{"Synthesis Prompt", cfg.Prompts.SynthesisPrompt},
 		{"Executive Summary Prompt", cfg.Prompts.ExecutiveSummaryPrompt},
+		{"Notification Prompt", cfg.Prompts.NotificationPrompt},
 	}
 
 	for _, pc := range promptConfigs {
 		if pc.configuredPath != "" {
-			fmt.Printf("   %-25s [EXTERNAL] %s\n", pc.name+":", pc.configuredPath)
+			ui.Printf("   %-25s [EXTERNAL] %s\n", pc.name+":", pc.configuredPath)
 		} else {
-			fmt.Printf("   %-25s [INTERNAL DEFAULT]\n", pc.name+":")
+			ui.Printf("   %-25s [INTERNAL DEFAULT]\n", pc.name+":")
 		}
 	}
 }
@@ -601,3 +601,58 @@ func TestConfigCmd_OutputsKnowledgeRetentionDays(t *testing.T) {
 	assert.Contains(t, output, "Knowledge Retention:", "Output should contain 'Knowledge Retention:' label")
 	assert.Contains(t, output, "45 days", "Output should contain the configured value '45 days'")
 }
+
+func TestConfigCmd_MasksExpandedAPIKey(t *testing.T) {
+	t.Setenv("DLIA_TEST_API_KEY", "sk-expanded-secret-9876")
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+	content := "llm:\n  api_key: ${DLIA_TEST_API_KEY}\noutput:\n  reports_dir: " + tmpDir + "\n  knowledge_base_dir: " + tmpDir + "\n"
+	assert.NoError(t, os.WriteFile(configFile, []byte(content), 0600))
+
+	loaded, err := config.Load(configFile)
+	assert.NoError(t, err)
+
+	originalCfg := cfg
+	cfg = loaded
+	defer func() { cfg = originalCfg }()
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, configCmd.RunE(configCmd, []string{}))
+	})
+
+	assert.Contains(t, output, maskAPIKey("sk-expanded-secret-9876"))
+	assert.NotContains(t, output, "sk-expanded-secret-9876")
+	assert.NotContains(t, output, "${DLIA_TEST_API_KEY}")
+}
+
+func TestConfigCmd_MasksAPIKeyList(t *testing.T) {
+	originalCfg := cfg
+	cfg = &config.Config{LLM: config.LLMConfig{APIKey: "sk-primary-secret-1111", APIKeys: []string{"sk-backup-secret-2222"}}}
+	defer func() { cfg = originalCfg }()
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, configCmd.RunE(configCmd, []string{}))
+	})
+
+	assert.Contains(t, output, "API Keys:       2 (failover order)")
+	assert.Contains(t, output, "1. "+maskAPIKey("sk-primary-secret-1111"))
+	assert.Contains(t, output, "2. "+maskAPIKey("sk-backup-secret-2222"))
+	assert.NotContains(t, output, "sk-backup-secret-2222")
+}
+
+func TestConfigCmd_ShowsProfile(t *testing.T) {
+	originalCfg := cfg
+	defer func() { cfg = originalCfg }()
+
+	cfg = &config.Config{Profile: "ci"}
+	output := captureStdout(t, func() {
+		assert.NoError(t, configCmd.RunE(configCmd, []string{}))
+	})
+	assert.Contains(t, output, "Profile:        ci")
+
+	cfg = &config.Config{}
+	output = captureStdout(t, func() {
+		assert.NoError(t, configCmd.RunE(configCmd, []string{}))
+	})
+	assert.Contains(t, output, "Profile:        (none)")
+}
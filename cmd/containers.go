@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/state"
+)
+
+var (
+	containersFilter         string
+	containersFilterLabels   []string
+	containersIncludeStopped bool
+	containersJSON           bool
+)
+
+// containerInventoryEntry is one row of dlia containers list.
+type containerInventoryEntry struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	State   string            `json:"state"`
+	Image   string            `json:"image"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Tracked bool              `json:"tracked"`
+	Skipped bool              `json:"skipped"`
+}
+
+var containersCmd = &cobra.Command{
+	Use:   cmdContainers,
+	Short: "Inspect the containers DLIA sees",
+	Long:  `Container commands for previewing which Docker containers a scan would select.`,
+}
+
+var containersListCmd = &cobra.Command{
+	Use:   cmdList,
+	Short: "List the containers a scan would select",
+	Long: `List the containers that match the same filters as dlia scan, without reading
+logs or calling the LLM.
+
+Each container is shown with its short ID, name, state, image, its dlia.* labels
+and the labels matched by --filter-label, and whether it is tracked in the state
+file. Containers labeled dlia.skip=true are listed but marked as skipped.`,
+	Example: `  # Containers a plain scan would select
+  dlia containers list
+
+  # Check a name regex before using it with dlia scan
+  dlia containers list --filter "^web-"
+
+  # Machine-readable output including stopped containers
+  dlia containers list --include-stopped --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, cmdContainers); err != nil {
+			return err
+		}
+
+		labels, err := parseLabelFilters(containersFilterLabels)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		dockerClient, err := connectLogSource(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = dockerClient.Close() }() // Close client; error not actionable in defer context
+
+		st, err := state.Open(cfg.Output.StateBackend, cfg.Output.StateFile)
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+		defer st.Close() //nolint:errcheck // Close error not actionable in defer context
+
+		entries, err := containerInventory(ctx, dockerClient, st, docker.FilterOptions{
+			NamePattern: containersFilter,
+			IncludeAll:  containersIncludeStopped,
+			Labels:      labels,
+		}, cfg.Docker, cfg.Output.ShortIDLength)
+		if err != nil {
+			return err
+		}
+
+		if containersJSON {
+			return writeContainerInventoryJSON(cmd.OutOrStdout(), entries)
+		}
+		writeContainerInventory(cmd.OutOrStdout(), entries)
+		return nil
+	},
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(containersCmd)
+	containersCmd.AddCommand(containersListCmd)
+
+	containersListCmd.Flags().StringVar(&containersFilter, "filter", "", "regex pattern to filter container names")
+	containersListCmd.Flags().StringArrayVar(&containersFilterLabels, "filter-label", nil, "only list containers with this label (key=value, repeatable; all must match)")
+	containersListCmd.Flags().BoolVar(&containersIncludeStopped, "include-stopped", false, "also list stopped/exited containers")
+	containersListCmd.Flags().BoolVar(&containersJSON, "json", false, "print the containers as JSON")
+}
+
+// containerInventory lists the containers matching filterOpts and the name lists of
+// dockerCfg, sorted by name, with their relevant labels and whether they are tracked
+// in st.
+func containerInventory(ctx context.Context, dockerClient docker.Client, st state.Backend, filterOpts docker.FilterOptions, dockerCfg config.DockerConfig, shortIDLength int) ([]containerInventoryEntry, error) {
+	containers, err := validateAndFilterContainers(ctx, dockerClient, filterOpts, dockerCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]containerInventoryEntry, 0, len(containers))
+	for _, c := range containers {
+		_, tracked := st.GetLastScan(c.ID)
+		overrides, _ := docker.ParseLabelOverrides(c.Labels)
+		entries = append(entries, containerInventoryEntry{
+			ID:      shortContainerID(c.ID, shortIDLength),
+			Name:    c.Name,
+			State:   c.State,
+			Image:   c.Image,
+			Labels:  matchedLabels(c.Labels, filterOpts.Labels),
+			Tracked: tracked,
+			Skipped: overrides.Skip,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// matchedLabels returns the dlia.* labels and the labels named in filter.
+func matchedLabels(labels, filter map[string]string) map[string]string {
+	matched := make(map[string]string)
+	for key, value := range labels {
+		if _, filtered := filter[key]; filtered || strings.HasPrefix(key, docker.LabelPrefix) {
+			matched[key] = value
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	return matched
+}
+
+// shortContainerID truncates a container ID to length characters
+// (output.short_id_length), or to config.DefaultShortIDLength for 0. Shorter IDs,
+// e.g. of test fixtures, are returned unchanged.
+func shortContainerID(id string, length int) string {
+	if length <= 0 {
+		length = config.DefaultShortIDLength
+	}
+	if len(id) > length {
+		return id[:length]
+	}
+	return id
+}
+
+// writeContainerInventory prints the containers as a table.
+func writeContainerInventory(w io.Writer, entries []containerInventoryEntry) {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(w, "ℹ️  No containers match the filters")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Container ID\tName\tState\tImage\tTracked\tLabels")
+	_, _ = fmt.Fprintln(tw, "------------\t----\t-----\t-----\t-------\t------")
+	for _, e := range entries {
+		tracked := " "
+		if e.Tracked {
+			tracked = checkmark
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.ID, e.Name, e.State, e.Image, tracked, formatLabels(e.Labels))
+	}
+	_ = tw.Flush() // Flush buffered output; error not actionable in CLI display context
+
+	skipped := 0
+	for _, e := range entries {
+		if e.Skipped {
+			skipped++
+		}
+	}
+	_, _ = fmt.Fprintln(w, "")
+	_, _ = fmt.Fprintf(w, "Total: %d container(s)", len(entries))
+	if skipped > 0 {
+		_, _ = fmt.Fprintf(w, ", %d skipped by %s=true", skipped, docker.LabelSkip)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// formatLabels renders labels as sorted key=value pairs, or "-" when there are none.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// writeContainerInventoryJSON prints the containers as a JSON array.
+func writeContainerInventoryJSON(w io.Writer, entries []containerInventoryEntry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(entries); err != nil {
+		return fmt.Errorf("failed to encode containers as JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/state"
+)
+
+func TestContainerInventory(t *testing.T) {
+	client := &testMockDockerClient{containers: []docker.Container{
+		{ID: "bbbbbbbbbbbbbbbbbbbb", Name: "web", State: "running", Image: "nginx:1.27", Labels: map[string]string{"dlia.scan": "true", "team": "frontend", "other": "x"}},
+		{ID: "aaaaaaaaaaaaaaaaaaaa", Name: "db", State: "running", Image: "postgres:16", Labels: map[string]string{"dlia.skip": "true"}},
+	}}
+	st, err := state.Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.UpdateContainer("bbbbbbbbbbbbbbbbbbbb", "web", time.Now(), "")
+
+	entries, err := containerInventory(context.Background(), client, st, docker.FilterOptions{Labels: map[string]string{"team": "frontend"}}, config.DockerConfig{}, 0)
+	if err != nil {
+		t.Fatalf("containerInventory() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != "db" || entries[1].Name != "web" {
+		t.Fatalf("expected db and web sorted by name, got %+v", entries)
+	}
+
+	web := entries[1]
+	if web.ID != "bbbbbbbbbbbb" || !web.Tracked || web.Skipped {
+		t.Errorf("unexpected web entry: %+v", web)
+	}
+	if len(web.Labels) != 2 || web.Labels["team"] != "frontend" || web.Labels["dlia.scan"] != "true" {
+		t.Errorf("expected dlia.* and filter labels only, got %v", web.Labels)
+	}
+	if db := entries[0]; db.Tracked || !db.Skipped {
+		t.Errorf("unexpected db entry: %+v", db)
+	}
+}
+
+func TestShortContainerID(t *testing.T) {
+	id := "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		id     string
+		length int
+		want   string
+	}{
+		{id, 0, "0123456789ab"},
+		{id, 16, "0123456789abcdef"},
+		{id, 64, id},
+		{"abc", 0, "abc"},
+		{"", 12, ""},
+	}
+	for _, tt := range tests {
+		if got := shortContainerID(tt.id, tt.length); got != tt.want {
+			t.Errorf("shortContainerID(%q, %d) = %q, want %q", tt.id, tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestWriteContainerInventory(t *testing.T) {
+	entries := []containerInventoryEntry{
+		{ID: "aaaaaaaaaaaa", Name: "db", State: "exited", Image: "postgres:16", Skipped: true, Labels: map[string]string{"dlia.skip": "true"}},
+		{ID: "bbbbbbbbbbbb", Name: "web", State: "running", Image: "nginx:1.27", Tracked: true},
+	}
+
+	var buf bytes.Buffer
+	writeContainerInventory(&buf, entries)
+	output := buf.String()
+	for _, want := range []string{"aaaaaaaaaaaa", "postgres:16", "dlia.skip=true", "Total: 2 container(s), 1 skipped by dlia.skip=true"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	buf.Reset()
+	writeContainerInventory(&buf, nil)
+	if !strings.Contains(buf.String(), "No containers match") {
+		t.Errorf("unexpected empty output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeContainerInventoryJSON(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if len(decoded) != 2 || decoded[1]["tracked"] != true || decoded[0]["skipped"] != true {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/knowledge"
+)
+
+// diffTimeLayouts are the absolute time formats accepted by --from and --to.
+var diffTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var (
+	diffFrom string
+	diffTo   string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   cmdDiff + " <container>",
+	Short: "Compare two scans of a container",
+	Long: `Diff compares two knowledge base scan entries of a container and prints the
+status transition together with a line diff of the analysis text.
+
+By default the latest scan is compared with the one before it. --from and --to
+select the scans nearest to the given times; both accept an absolute time
+(RFC3339, "2006-01-02 15:04" or "2006-01-02") or a duration ago (e.g. 24h).`,
+	Example: `  # What changed since the previous scan
+  dlia diff my-app
+
+  # Compare the scan from a day ago with the latest one
+  dlia diff my-app --from 24h
+
+  # Compare two specific points in time
+  dlia diff my-app --from 2025-01-01 --to "2025-01-02 12:00"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, cmdDiff); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		from, err := parseDiffTime(diffFrom, now)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+		to, err := parseDiffTime(diffTo, now)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+
+		entries, err := knowledge.ContainerEntries(cfg.Output.KnowledgeBaseDir, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read knowledge base: %w", err)
+		}
+
+		w := cmd.OutOrStdout()
+		if len(entries) < 2 {
+			_, _ = fmt.Fprintf(w, "ℹ️  Need at least two scans to diff; found %d for %s\n", len(entries), args[0])
+			return nil
+		}
+
+		fromIdx, toIdx := selectDiffEntries(entries, from, to)
+		displayDiff(w, args[0], entries[fromIdx], entries[toIdx])
+		return nil
+	},
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "compare from the scan nearest to this time (default: scan before --to)")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "compare to the scan nearest to this time (default: latest scan)")
+}
+
+// parseDiffTime parses an absolute time or a duration ago relative to now.
+// An empty value yields the zero time.
+func parseDiffTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range diffTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q is neither a duration (e.g. 24h) nor a time (e.g. 2006-01-02 15:04)", value)
+}
+
+// selectDiffEntries picks the entries to compare from entries sorted oldest-first
+// (at least two). A zero to selects the latest entry; a zero from selects the entry
+// before to. The result is always two distinct entries in chronological order.
+func selectDiffEntries(entries []knowledge.Entry, from, to time.Time) (int, int) {
+	toIdx := len(entries) - 1
+	if !to.IsZero() {
+		toIdx = knowledge.NearestEntry(entries, to)
+	}
+
+	fromIdx := toIdx - 1
+	if !from.IsZero() {
+		fromIdx = knowledge.NearestEntry(entries, from)
+	}
+
+	if fromIdx == toIdx || fromIdx < 0 {
+		if toIdx > 0 {
+			fromIdx = toIdx - 1
+		} else {
+			fromIdx, toIdx = 0, 1
+		}
+	}
+
+	if fromIdx > toIdx {
+		fromIdx, toIdx = toIdx, fromIdx
+	}
+
+	return fromIdx, toIdx
+}
+
+// displayDiff prints the status transition and the analysis diff of two entries.
+func displayDiff(w io.Writer, container string, from, to knowledge.Entry) {
+	_, _ = fmt.Fprintf(w, "🔀 Diff for %s\n", container)
+	_, _ = fmt.Fprintf(w, "   From: %s  %s\n", from.Timestamp.Format("2006-01-02 15:04:05"), from.Status)
+	_, _ = fmt.Fprintf(w, "   To:   %s  %s\n\n", to.Timestamp.Format("2006-01-02 15:04:05"), to.Status)
+
+	if from.Status == to.Status {
+		_, _ = fmt.Fprintf(w, "Status: %s (unchanged)\n\n", to.Status)
+	} else {
+		_, _ = fmt.Fprintf(w, "Status: %s → %s\n\n", from.Status, to.Status)
+	}
+
+	diff := knowledge.DiffLines(from.Analysis(), to.Analysis())
+	changed := false
+	for _, line := range diff {
+		if line[0] != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		_, _ = fmt.Fprintln(w, "ℹ️  Analysis text is identical")
+		return
+	}
+
+	for _, line := range diff {
+		_, _ = fmt.Fprintf(w, "   %s\n", line)
+	}
+}
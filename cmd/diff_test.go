@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/knowledge"
+)
+
+func TestParseDiffTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseDiffTime("", now)
+	if err != nil || !got.IsZero() {
+		t.Errorf("parseDiffTime(\"\") = %v, %v; want zero time", got, err)
+	}
+
+	got, err = parseDiffTime("24h", now)
+	if err != nil || !got.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("parseDiffTime(\"24h\") = %v, %v", got, err)
+	}
+
+	got, err = parseDiffTime("2025-01-01T08:00:00Z", now)
+	if err != nil || !got.Equal(time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseDiffTime(RFC3339) = %v, %v", got, err)
+	}
+
+	if _, err := parseDiffTime("yesterday", now); err == nil {
+		t.Error("Expected error for unparseable time")
+	}
+}
+
+func TestSelectDiffEntries(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []knowledge.Entry{
+		{Timestamp: base},
+		{Timestamp: base.Add(time.Hour)},
+		{Timestamp: base.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		wantFrom int
+		wantTo   int
+	}{
+		{"defaults to latest two", time.Time{}, time.Time{}, 1, 2},
+		{"from only", base.Add(10 * time.Minute), time.Time{}, 0, 2},
+		{"to only", time.Time{}, base.Add(70 * time.Minute), 0, 1},
+		{"same nearest entry", base.Add(2 * time.Hour), base.Add(2 * time.Hour), 1, 2},
+		{"both at oldest", base, base, 0, 1},
+		{"reversed range", base.Add(2 * time.Hour), base, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fromIdx, toIdx := selectDiffEntries(entries, tt.from, tt.to)
+			if fromIdx != tt.wantFrom || toIdx != tt.wantTo {
+				t.Errorf("selectDiffEntries() = %d, %d; want %d, %d", fromIdx, toIdx, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestDiffCmd_Run(t *testing.T) {
+	tmpDir := t.TempDir()
+	servicesDir := filepath.Join(tmpDir, "services")
+	if err := os.MkdirAll(servicesDir, 0o750); err != nil {
+		t.Fatalf("Failed to create services dir: %v", err)
+	}
+
+	older := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	newer := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	content := "# Knowledge Base: web\n\n## Service History\n" +
+		"\n### Scan: " + older + "\n**Status:** 🟢 Healthy\n\nAll requests served.\n\n---\n" +
+		"\n### Scan: " + newer + "\n**Status:** 🔴 Issues Detected\n\nError: upstream timeout\n\n---\n"
+	if err := os.WriteFile(filepath.Join(servicesDir, "web.md"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write KB file: %v", err)
+	}
+
+	originalCfg := cfg
+	cfg = &config.Config{
+		ConfigFilePath: filepath.Join(tmpDir, "config.yaml"),
+		Output: config.OutputConfig{
+			ReportsDir:       tmpDir,
+			KnowledgeBaseDir: tmpDir,
+			StateFile:        filepath.Join(tmpDir, "state.json"),
+		},
+	}
+	defer func() { cfg = originalCfg }()
+
+	var buf bytes.Buffer
+	diffCmd.SetOut(&buf)
+
+	if err := diffCmd.RunE(diffCmd, []string{"web"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"🟢 Healthy → 🔴 Issues Detected", "- All requests served.", "+ Error: upstream timeout"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+
+	buf.Reset()
+	if err := diffCmd.RunE(diffCmd, []string{"unknown"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Need at least two scans") {
+		t.Errorf("Expected not-enough-scans message, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import "errors"
+
+// Process exit codes returned by Execute.
+// 0 = success, 1 = general error, 2 = config error, 3 = issues found,
+// 4 = scan in progress, 75 = LLM quota exhausted (see main.go).
+const (
+	exitCodeError = 1
+	// exitCodeConfig signals a missing, unreadable or invalid configuration.
+	exitCodeConfig = 2
+	// exitCodeIssuesFound signals a successful scan whose findings reached the
+	// --fail-on-issues severity.
+	exitCodeIssuesFound = 3
+	// exitCodeScanInProgress signals that another scan holds the scan lock.
+	exitCodeScanInProgress = 4
+	// exitCodeQuotaExhausted signals that the LLM quota or rate limit ran out mid-scan.
+	// Matches EX_TEMPFAIL from sysexits.h so schedulers can retry later.
+	exitCodeQuotaExhausted = 75
+)
+
+// exitError carries a specific process exit code through cobra's RunE.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// configError marks err as a configuration error (exitCodeConfig).
+func configError(err error) error {
+	return &exitError{code: exitCodeConfig, err: err}
+}
+
+// exitCodeFor returns the exit code for an error returned by a command.
+func exitCodeFor(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitCodeError
+}
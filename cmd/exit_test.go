@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/chunking"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/llm"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"plain error", errors.New("boom"), exitCodeError},
+		{"exit error", &exitError{code: exitCodeQuotaExhausted, err: errors.New("quota")}, exitCodeQuotaExhausted},
+		{"issues found", &exitError{code: exitCodeIssuesFound, err: errors.New("issues")}, exitCodeIssuesFound},
+		{"config error", configError(errors.New("missing config")), exitCodeConfig},
+		{"wrapped exit error", fmt.Errorf("scan: %w", &exitError{code: exitCodeQuotaExhausted, err: errors.New("quota")}), exitCodeQuotaExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.want {
+				t.Errorf("exitCodeFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitError_Unwrap(t *testing.T) {
+	inner := errors.New("inner")
+	err := &exitError{code: 2, err: inner}
+
+	if err.Error() != "inner" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "inner")
+	}
+	if !errors.Is(err, inner) {
+		t.Error("exitError should unwrap to the inner error")
+	}
+}
+
+func TestParseFailOnIssues(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    knowledge.Severity
+		wantErr bool
+	}{
+		{"", knowledge.SeverityHealthy, false},
+		{"critical", knowledge.SeverityCritical, false},
+		{"Warning", knowledge.SeverityWarning, false},
+		{"healthy", knowledge.SeverityHealthy, true},
+		{"sometimes", knowledge.SeverityHealthy, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFailOnIssues(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFailOnIssues(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("parseFailOnIssues(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFailOnIssuesFlag_RequiresValue(t *testing.T) {
+	// Without an optional value, "--fail-on-issues warning" consumes "warning" instead
+	// of leaving it as the container argument
+	if flag := scanCmd.Flags().Lookup("fail-on-issues"); flag.NoOptDefVal != "" {
+		t.Errorf("--fail-on-issues must require a value, NoOptDefVal = %q", flag.NoOptDefVal)
+	}
+}
+
+func TestIssuesFoundError(t *testing.T) {
+	results := map[string]*chunking.AnalyzeResult{
+		"web":   {Analysis: "All good"},
+		"cache": {Analysis: "Warning: eviction rate rising"},
+	}
+
+	if err := issuesFoundError(results, knowledge.SeverityHealthy); err != nil {
+		t.Errorf("disabled check returned %v", err)
+	}
+	if err := issuesFoundError(results, knowledge.SeverityCritical); err != nil {
+		t.Errorf("warnings only, critical threshold: got %v, want nil", err)
+	}
+
+	err := issuesFoundError(results, knowledge.SeverityWarning)
+	if got := exitCodeFor(err); got != exitCodeIssuesFound {
+		t.Fatalf("exitCodeFor() = %d, want %d", got, exitCodeIssuesFound)
+	}
+	if !strings.Contains(err.Error(), "cache") || strings.Contains(err.Error(), "web") {
+		t.Errorf("error should list only affected containers, got %q", err.Error())
+	}
+
+	// A structured healthy verdict wins over keywords in the rendered text
+	results["cache"] = &chunking.AnalyzeResult{
+		Analysis:   "**Summary**: Healthy\n**Errors**: no errors",
+		Structured: &llm.StructuredAnalysis{Severity: "healthy"},
+	}
+	if err := issuesFoundError(results, knowledge.SeverityWarning); err != nil {
+		t.Errorf("structured healthy analysis: got %v, want nil", err)
+	}
+
+	results["db"] = &chunking.AnalyzeResult{Analysis: "Critical: connection pool exhausted"}
+	err = issuesFoundError(results, knowledge.SeverityCritical)
+	if got := exitCodeFor(err); got != exitCodeIssuesFound {
+		t.Errorf("exitCodeFor() = %d, want %d", got, exitCodeIssuesFound)
+	}
+}
@@ -7,10 +7,14 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zorak1103/dlia/internal/templates"
+	"github.com/zorak1103/dlia/internal/ui"
+	"golang.org/x/term"
 )
 
 var (
-	force bool
+	force          bool
+	nonInteractive bool
+	initFlags      initAnswers
 )
 
 var initCmd = &cobra.Command{
@@ -25,14 +29,25 @@ This command will create:
   - knowledge_base/ (directory for accumulated knowledge)
   - knowledge_base/services/ (directory for per-service summaries)
 
+When run in a terminal, init asks for the LLM base URL, API key (input is
+hidden), model, Docker socket and notification settings, validates them and
+writes them into config.yaml and .env. Secrets (API key, Shoutrrr URL) go to
+.env only. Use --non-interactive with the value flags for scripted setup.
+Without a terminal and without --non-interactive, the sample files are written
+unchanged.
+
 Run this once when setting up DLIA for the first time.`,
-	Example: `  # Initialize in current directory
+	Example: `  # Initialize in current directory (interactive wizard in a terminal)
   dlia init
 
   # Force overwrite existing files
-  dlia init --force`,
+  dlia init --force
+
+  # Scripted setup
+  dlia init --non-interactive --api-key "$KEY" --model gpt-4o-mini \
+    --notifications --shoutrrr-url "discord://token@webhookid"`,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		fmt.Println("🔧 Initializing DLIA...")
+		ui.Println("🔧 Initializing DLIA...")
 
 		dirs := []string{
 			"reports",
@@ -44,25 +59,25 @@ Run this once when setting up DLIA for the first time.`,
 			if err := os.MkdirAll(dir, 0o750); err != nil {
 				return fmt.Errorf("failed to create directory %s: %w", dir, err)
 			}
-			fmt.Printf("✅ Created directory: %s\n", dir)
+			ui.Printf("✅ Created directory: %s\n", dir)
 		}
 
-		files := map[string][]byte{
-			"config.yaml": templates.ConfigYAML,
-			".env":        templates.EnvFile,
+		files, configured, err := initFileContents()
+		if err != nil {
+			return err
 		}
 
-		for filename, content := range files {
+		for _, filename := range []string{"config.yaml", ".env"} {
 			if _, err := os.Stat(filename); err == nil && !force {
-				fmt.Printf("⚠️  Skipping %s (already exists, use --force to overwrite)\n", filename)
+				ui.Printf("⚠️  Skipping %s (already exists, use --force to overwrite)\n", filename)
 				continue
 			}
 
-			if err := os.WriteFile(filename, content, 0o600); err != nil {
+			if err := os.WriteFile(filename, files[filename], 0o600); err != nil {
 				return fmt.Errorf("failed to write %s: %w", filename, err)
 			}
 
-			fmt.Printf("✅ Created %s\n", filename)
+			ui.Printf("✅ Created %s\n", filename)
 		}
 
 		globalSummaryPath := filepath.Join("knowledge_base", "global_summary.md")
@@ -77,15 +92,21 @@ No scans performed yet.
 			if err := os.WriteFile(globalSummaryPath, []byte(initialContent), 0o600); err != nil {
 				return fmt.Errorf("failed to create global_summary.md: %w", err)
 			}
-			fmt.Printf("✅ Created %s\n", globalSummaryPath)
+			ui.Printf("✅ Created %s\n", globalSummaryPath)
 		}
 
-		fmt.Println("\n🎉 Initialization complete!")
-		fmt.Println("\n📝 Next steps:")
-		fmt.Println("   1. Edit config.yaml to configure your LLM API")
-		fmt.Println("   2. Edit .env to add your API key and other secrets")
-		fmt.Println("   3. Run 'dlia scan --dry-run' to test your setup")
-		fmt.Println("   4. Run 'dlia scan' to perform your first analysis")
+		ui.Println("\n🎉 Initialization complete!")
+		ui.Println("\n📝 Next steps:")
+		if configured {
+			ui.Println("   1. Review config.yaml for optional settings (filters, retention, prompts)")
+			ui.Println("   2. Run 'dlia scan --dry-run' to test your setup")
+			ui.Println("   3. Run 'dlia scan' to perform your first analysis")
+			return nil
+		}
+		ui.Println("   1. Edit config.yaml to configure your LLM API")
+		ui.Println("   2. Edit .env to add your API key and other secrets")
+		ui.Println("   3. Run 'dlia scan --dry-run' to test your setup")
+		ui.Println("   4. Run 'dlia scan' to perform your first analysis")
 
 		return nil
 	},
@@ -96,4 +117,53 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 
 	initCmd.Flags().BoolVar(&force, "force", false, "overwrite existing configuration files")
+	initCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt; take settings from the flags below")
+	initCmd.Flags().StringVar(&initFlags.BaseURL, "base-url", defaultInitBaseURL, "LLM API base URL (with --non-interactive)")
+	initCmd.Flags().StringVar(&initFlags.APIKey, "api-key", "", "LLM API key, written to .env (with --non-interactive)")
+	initCmd.Flags().StringVar(&initFlags.Model, "model", defaultInitModel, "LLM model (with --non-interactive)")
+	initCmd.Flags().StringVar(&initFlags.DockerSocket, "docker-socket", "", "Docker socket, empty = auto-detect (with --non-interactive)")
+	initCmd.Flags().BoolVar(&initFlags.NotificationsEnabled, "notifications", false, "enable notifications (with --non-interactive)")
+	initCmd.Flags().StringVar(&initFlags.ShoutrrrURL, "shoutrrr-url", "", "Shoutrrr notification URL, written to .env (with --non-interactive)")
+}
+
+// initFileContents returns the contents of config.yaml and .env and whether they
+// carry user settings. Settings come from the flags with --non-interactive, from the
+// wizard when stdin is a terminal, and otherwise the sample templates are used.
+func initFileContents() (map[string][]byte, bool, error) {
+	samples := map[string][]byte{
+		"config.yaml": templates.ConfigYAML,
+		".env":        templates.EnvFile,
+	}
+
+	var answers initAnswers
+	switch {
+	case nonInteractive:
+		answers = initFlags
+		if err := answers.validate(); err != nil {
+			return nil, false, fmt.Errorf("invalid init settings: %w", err)
+		}
+	case term.IsTerminal(int(os.Stdin.Fd())):
+		if _, err := os.Stat("config.yaml"); err == nil && !force {
+			// Nothing would be written; don't ask for settings that are thrown away
+			return samples, false, nil
+		}
+		prompter := newInitPrompter(os.Stdin, ui.Writer(os.Stdout), func() (string, error) {
+			secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+			ui.Println()
+			return string(secret), err
+		})
+		var err error
+		answers, err = prompter.ask()
+		if err != nil {
+			return nil, false, err
+		}
+	default:
+		return samples, false, nil
+	}
+
+	files, err := renderInitFiles(answers)
+	if err != nil {
+		return nil, false, err
+	}
+	return files, true, nil
 }
@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/joho/godotenv"
+	"github.com/zorak1103/dlia/internal/config"
+
 	"github.com/zorak1103/dlia/internal/templates"
 )
 
@@ -520,3 +524,220 @@ func TestInitCmd_IdempotentDirectoryCreation(t *testing.T) {
 		}
 	}
 }
+
+func TestInitPrompter_Ask(t *testing.T) {
+	t.Parallel()
+
+	// Invalid base URL is asked again, empty model takes the default
+	input := strings.Join([]string{
+		"not-a-url",
+		"https://openrouter.ai/api/v1",
+		"",
+		"unix:///var/run/docker.sock",
+		"maybe",
+		"y",
+		"discord://token@webhookid",
+	}, "\n") + "\n"
+
+	secrets := []string{"", "sk-secret-key-1234"}
+	var out bytes.Buffer
+	p := newInitPrompter(strings.NewReader(input), &out, func() (string, error) {
+		s := secrets[0]
+		secrets = secrets[1:]
+		return s, nil
+	})
+
+	answers, err := p.ask()
+	if err != nil {
+		t.Fatalf("ask() error = %v", err)
+	}
+
+	want := initAnswers{
+		BaseURL:              "https://openrouter.ai/api/v1",
+		APIKey:               "sk-secret-key-1234",
+		Model:                defaultInitModel,
+		DockerSocket:         "unix:///var/run/docker.sock",
+		NotificationsEnabled: true,
+		ShoutrrrURL:          "discord://token@webhookid",
+	}
+	if answers != want {
+		t.Errorf("ask() = %+v, want %+v", answers, want)
+	}
+
+	if strings.Contains(out.String(), "sk-secret-key-1234") {
+		t.Error("API key must never be written to the terminal")
+	}
+	if got := strings.Count(out.String(), "❌"); got != 3 {
+		t.Errorf("expected 3 re-prompts (base URL, API key, yes/no), got %d:\n%s", got, out.String())
+	}
+}
+
+func TestInitPrompter_AskEOF(t *testing.T) {
+	t.Parallel()
+
+	p := newInitPrompter(strings.NewReader(""), &bytes.Buffer{}, func() (string, error) { return "key", nil })
+	if _, err := p.ask(); err == nil {
+		t.Error("expected error when input ends before all questions are answered")
+	}
+}
+
+func TestInitAnswers_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := initAnswers{BaseURL: defaultInitBaseURL, APIKey: "key", Model: defaultInitModel}
+
+	tests := []struct {
+		name    string
+		modify  func(*initAnswers)
+		wantErr string
+	}{
+		{"valid", func(*initAnswers) {}, ""},
+		{"missing api key", func(a *initAnswers) { a.APIKey = "" }, "API key"},
+		{"bad base url", func(a *initAnswers) { a.BaseURL = "ftp://example.com" }, "base URL"},
+		{"empty model", func(a *initAnswers) { a.Model = " " }, "model"},
+		{"socket without scheme", func(a *initAnswers) { a.DockerSocket = "/var/run/docker.sock" }, "docker socket"},
+		{"notifications without url", func(a *initAnswers) { a.NotificationsEnabled = true }, "shoutrrr URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := valid
+			tt.modify(&a)
+			err := a.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderInitFiles(t *testing.T) {
+	t.Parallel()
+
+	answers := initAnswers{
+		BaseURL:              "http://localhost:11434/v1",
+		APIKey:               "sk-abc#123",
+		Model:                "llama3.1",
+		DockerSocket:         "tcp://10.0.0.5:2375",
+		NotificationsEnabled: true,
+		ShoutrrrURL:          "discord://token@webhookid",
+	}
+
+	files, err := renderInitFiles(answers)
+	if err != nil {
+		t.Fatalf("renderInitFiles() error = %v", err)
+	}
+
+	cfg, err := config.Parse(files["config.yaml"])
+	if err != nil {
+		t.Fatalf("config.Parse() error = %v", err)
+	}
+	if cfg.LLM.BaseURL != answers.BaseURL || cfg.LLM.Model != answers.Model {
+		t.Errorf("LLM settings = %q/%q, want %q/%q", cfg.LLM.BaseURL, cfg.LLM.Model, answers.BaseURL, answers.Model)
+	}
+	if cfg.Docker.SocketPath != answers.DockerSocket {
+		t.Errorf("socket_path = %q, want %q", cfg.Docker.SocketPath, answers.DockerSocket)
+	}
+	if !cfg.Notification.Enabled {
+		t.Error("notification.enabled should be true")
+	}
+	if cfg.LLM.APIKey != "" || cfg.Notification.ShoutrrURL != "" {
+		t.Error("secrets must not be written to config.yaml")
+	}
+
+	env, err := godotenv.Unmarshal(string(files[".env"]))
+	if err != nil {
+		t.Fatalf("godotenv.Unmarshal() error = %v", err)
+	}
+	wantEnv := map[string]string{
+		"DLIA_LLM_API_KEY":               answers.APIKey,
+		"DLIA_LLM_BASE_URL":              answers.BaseURL,
+		"DLIA_LLM_MODEL":                 answers.Model,
+		"DLIA_NOTIFICATION_SHOUTRRR_URL": answers.ShoutrrrURL,
+		"DLIA_DOCKER_SOCKET_PATH":        answers.DockerSocket,
+	}
+	for key, want := range wantEnv {
+		if env[key] != want {
+			t.Errorf(".env %s = %q, want %q", key, env[key], want)
+		}
+	}
+}
+
+func TestRenderInitFiles_CommentsOutUnusedEnv(t *testing.T) {
+	t.Parallel()
+
+	files, err := renderInitFiles(initAnswers{BaseURL: defaultInitBaseURL, APIKey: "key", Model: defaultInitModel})
+	if err != nil {
+		t.Fatalf("renderInitFiles() error = %v", err)
+	}
+
+	env, err := godotenv.Unmarshal(string(files[".env"]))
+	if err != nil {
+		t.Fatalf("godotenv.Unmarshal() error = %v", err)
+	}
+	for _, key := range []string{"DLIA_NOTIFICATION_SHOUTRRR_URL", "DLIA_DOCKER_SOCKET_PATH"} {
+		if _, ok := env[key]; ok {
+			t.Errorf(".env should not set %s when no value was given", key)
+		}
+	}
+}
+
+func TestInitCmd_NonInteractive(t *testing.T) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to change to temp directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(origDir); err != nil {
+			t.Errorf("Failed to restore original directory: %v", err)
+		}
+	}()
+
+	force = false
+	nonInteractive = true
+	initFlags = initAnswers{BaseURL: defaultInitBaseURL, Model: defaultInitModel}
+	defer func() {
+		nonInteractive = false
+		initFlags = initAnswers{BaseURL: defaultInitBaseURL, Model: defaultInitModel}
+	}()
+
+	if err := initCmd.RunE(initCmd, []string{}); err == nil {
+		t.Fatal("expected error without --api-key")
+	}
+	if _, err := os.Stat("config.yaml"); !os.IsNotExist(err) {
+		t.Error("config.yaml must not be written when settings are invalid")
+	}
+
+	initFlags.APIKey = "sk-non-interactive"
+	initFlags.Model = "gpt-4o"
+	if err := initCmd.RunE(initCmd, []string{}); err != nil {
+		t.Fatalf("initCmd.RunE() error = %v", err)
+	}
+
+	content, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read config.yaml: %v", err)
+	}
+	if !strings.Contains(string(content), `model: "gpt-4o"`) {
+		t.Error("config.yaml should contain the model from --model")
+	}
+
+	env, err := godotenv.Read(".env")
+	if err != nil {
+		t.Fatalf("Failed to read .env: %v", err)
+	}
+	if env["DLIA_LLM_API_KEY"] != "sk-non-interactive" {
+		t.Errorf(".env DLIA_LLM_API_KEY = %q", env["DLIA_LLM_API_KEY"])
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/templates"
+)
+
+const (
+	defaultInitBaseURL = "https://api.openai.com/v1"
+	defaultInitModel   = "gpt-4o-mini"
+)
+
+// initAnswers holds the settings collected by dlia init.
+type initAnswers struct {
+	BaseURL              string
+	APIKey               string
+	Model                string
+	DockerSocket         string // Empty = auto-detect
+	NotificationsEnabled bool
+	ShoutrrrURL          string
+}
+
+// validate checks every field and reports all problems found.
+func (a initAnswers) validate() error {
+	validators := []error{
+		validateInitBaseURL(a.BaseURL),
+		validateInitRequired("API key", a.APIKey),
+		validateInitRequired("model", a.Model),
+		validateInitDockerSocket(a.DockerSocket),
+	}
+	if a.NotificationsEnabled {
+		validators = append(validators, validateInitShoutrrrURL(a.ShoutrrrURL))
+	}
+	return errors.Join(validators...)
+}
+
+func validateInitBaseURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("base URL %q must be an http(s) URL such as %s", value, defaultInitBaseURL)
+	}
+	return nil
+}
+
+func validateInitRequired(name, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%s must not be empty", name)
+	}
+	return nil
+}
+
+func validateInitDockerSocket(value string) error {
+	if value != "" && !strings.Contains(value, "://") {
+		return fmt.Errorf("docker socket %q must include a scheme, e.g. unix:///var/run/docker.sock", value)
+	}
+	return nil
+}
+
+func validateInitShoutrrrURL(value string) error {
+	if !strings.Contains(value, "://") {
+		return fmt.Errorf("shoutrrr URL %q must look like service://..., e.g. discord://token@webhookid", value)
+	}
+	return nil
+}
+
+// initPrompter asks for the init settings on a line-based terminal. Invalid answers
+// are reported and asked again. The API key is read through readSecret so it is
+// never echoed.
+type initPrompter struct {
+	in         *bufio.Reader
+	out        io.Writer
+	readSecret func() (string, error)
+}
+
+func newInitPrompter(in io.Reader, out io.Writer, readSecret func() (string, error)) *initPrompter {
+	return &initPrompter{in: bufio.NewReader(in), out: out, readSecret: readSecret}
+}
+
+// ask runs the wizard and returns the validated answers.
+func (p *initPrompter) ask() (initAnswers, error) {
+	var a initAnswers
+	var err error
+
+	_, _ = fmt.Fprintln(p.out, "\n📝 Answer a few questions to create your configuration (press Enter for the default).")
+
+	if a.BaseURL, err = p.askLine("LLM base URL", defaultInitBaseURL, validateInitBaseURL); err != nil {
+		return a, err
+	}
+	if a.APIKey, err = p.askSecret("LLM API key"); err != nil {
+		return a, err
+	}
+	if a.Model, err = p.askLine("LLM model", defaultInitModel, func(v string) error {
+		return validateInitRequired("model", v)
+	}); err != nil {
+		return a, err
+	}
+	if a.DockerSocket, err = p.askLine("Docker socket (empty = auto-detect)", "", validateInitDockerSocket); err != nil {
+		return a, err
+	}
+	if a.NotificationsEnabled, err = p.askYesNo("Enable notifications?", false); err != nil {
+		return a, err
+	}
+	if a.NotificationsEnabled {
+		if a.ShoutrrrURL, err = p.askLine("Shoutrrr URL", "", validateInitShoutrrrURL); err != nil {
+			return a, err
+		}
+	}
+
+	return a, nil
+}
+
+// readLine reads one line without its line ending. EOF without input is an error
+// so a closed stdin does not loop forever.
+func (p *initPrompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (p *initPrompter) askLine(question, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			_, _ = fmt.Fprintf(p.out, "%s: ", question)
+		}
+
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+
+		if err := validate(answer); err != nil {
+			_, _ = fmt.Fprintf(p.out, "❌ %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+func (p *initPrompter) askSecret(question string) (string, error) {
+	for {
+		_, _ = fmt.Fprintf(p.out, "%s (input hidden): ", question)
+
+		answer, err := p.readSecret()
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", question, err)
+		}
+		answer = strings.TrimSpace(answer)
+
+		if err := validateInitRequired(question, answer); err != nil {
+			_, _ = fmt.Fprintf(p.out, "❌ %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+func (p *initPrompter) askYesNo(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	for {
+		_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, hint)
+
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		_, _ = fmt.Fprintln(p.out, "❌ Please answer y or n")
+	}
+}
+
+// renderInitFiles fills the config.yaml and .env templates with the answers and
+// validates the result. Secrets only go to .env, which is read on startup and
+// overrides the empty values in config.yaml.
+func renderInitFiles(a initAnswers) (map[string][]byte, error) {
+	configYAML := string(templates.ConfigYAML)
+	configYAML = setYAMLValue(configYAML, "llm", "base_url", strconv.Quote(a.BaseURL))
+	configYAML = setYAMLValue(configYAML, "llm", "model", strconv.Quote(a.Model))
+	configYAML = setYAMLValue(configYAML, "docker", "socket_path", strconv.Quote(a.DockerSocket))
+	configYAML = setYAMLValue(configYAML, "notification", "enabled", strconv.FormatBool(a.NotificationsEnabled))
+
+	cfg, err := config.Parse([]byte(configYAML))
+	if err != nil {
+		return nil, fmt.Errorf("generated config.yaml is invalid: %w", err)
+	}
+	cfg.LLM.APIKey = a.APIKey
+	cfg.Notification.ShoutrrURL = a.ShoutrrrURL
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("generated config.yaml is invalid: %w", err)
+	}
+
+	env := string(templates.EnvFile)
+	env = setEnvValue(env, "DLIA_LLM_API_KEY", a.APIKey)
+	env = setEnvValue(env, "DLIA_LLM_BASE_URL", a.BaseURL)
+	env = setEnvValue(env, "DLIA_LLM_MODEL", a.Model)
+	env = setEnvValue(env, "DLIA_NOTIFICATION_SHOUTRRR_URL", a.ShoutrrrURL)
+	env = setEnvValue(env, "DLIA_DOCKER_SOCKET_PATH", a.DockerSocket)
+
+	return map[string][]byte{
+		"config.yaml": []byte(configYAML),
+		".env":        []byte(env),
+	}, nil
+}
+
+// setYAMLValue replaces the value of the first uncommented key within the given
+// top-level section, keeping the template's comments and layout intact.
+func setYAMLValue(content, section, key, value string) string {
+	lines := strings.Split(content, "\n")
+	current := ""
+
+	for i, line := range lines {
+		if line != "" && line[0] != ' ' && line[0] != '#' {
+			current = strings.TrimSuffix(strings.TrimSpace(line), ":")
+			continue
+		}
+		if current != section {
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, key+":") {
+			indent := line[:len(line)-len(trimmed)]
+			lines[i] = indent + key + ": " + value
+			break
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// setEnvValue sets name in a .env template. A non-empty value replaces the line
+// (uncommenting it if needed); an empty value comments the line out so the sample
+// value does not override config.yaml.
+func setEnvValue(content, name, value string) string {
+	lines := strings.Split(content, "\n")
+
+	for i, line := range lines {
+		bare := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if !strings.HasPrefix(bare, name+"=") {
+			continue
+		}
+
+		if value == "" {
+			lines[i] = "# " + bare
+		} else {
+			lines[i] = name + "=" + quoteEnvValue(value)
+		}
+		break
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// quoteEnvValue single-quotes values that godotenv would otherwise cut short or
+// interpret.
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, " #'\"\\$") {
+		return value
+	}
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(value) + `"`
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/knowledge"
+)
+
+// kbMaxExcerptLines limits how many matching lines are shown per search result.
+const kbMaxExcerptLines = 3
+
+var (
+	kbSearchStatus    string
+	kbSearchSince     string
+	kbSearchContainer string
+)
+
+var kbCmd = &cobra.Command{
+	Use:   cmdKB,
+	Short: "Query the knowledge base",
+	Long: `Knowledge base commands for inspecting and exporting the accumulated
+per-container scan history stored under knowledge_base/services/.`,
+}
+
+var kbSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search knowledge base scan entries",
+	Long: `Search all per-container knowledge base files for scan entries containing
+the query (case-insensitive). Matching entries are listed newest-first with
+their container name, scan timestamp and status.
+
+Use an empty query ("") to list all entries that match the other filters.`,
+	Example: `  # Find all scans mentioning timeouts
+  dlia kb search timeout
+
+  # Only entries with detected issues from the last 24 hours
+  dlia kb search "connection refused" --status issues --since 24h
+
+  # Restrict the search to one container
+  dlia kb search oom --container my-app`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, "kb"); err != nil {
+			return err
+		}
+
+		opts := knowledge.SearchOptions{
+			Query:     args[0],
+			Status:    kbSearchStatus,
+			Container: kbSearchContainer,
+		}
+
+		if kbSearchSince != "" {
+			duration, err := time.ParseDuration(kbSearchSince)
+			if err != nil {
+				return fmt.Errorf("invalid since duration '%s': %w (use format like: 1h, 24h, 30m)", kbSearchSince, err)
+			}
+			opts.Since = time.Now().Add(-duration)
+		}
+
+		entries, err := knowledge.Search(cfg.Output.KnowledgeBaseDir, opts)
+		if err != nil {
+			return fmt.Errorf("failed to search knowledge base: %w", err)
+		}
+
+		displaySearchResults(cmd.OutOrStdout(), entries, opts.Query)
+		return nil
+	},
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(kbCmd)
+	kbCmd.AddCommand(kbSearchCmd)
+
+	kbSearchCmd.Flags().StringVar(&kbSearchStatus, "status", "", "filter by status: healthy, warnings, issues")
+	kbSearchCmd.Flags().StringVar(&kbSearchSince, "since", "", "only entries from within this duration (e.g., 1h, 24h, 168h)")
+	kbSearchCmd.Flags().StringVar(&kbSearchContainer, "container", "", "only search the knowledge base of this container")
+}
+
+// displaySearchResults prints matching entries with a short excerpt of matching lines.
+func displaySearchResults(w io.Writer, entries []knowledge.Entry, query string) {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(w, "ℹ️  No matching knowledge base entries found")
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "🔎 Found %d matching entry(ies):\n\n", len(entries))
+
+	for _, entry := range entries {
+		status := entry.Status
+		if status == "" {
+			status = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s  %s  %s\n", entry.Timestamp.Format("2006-01-02 15:04:05"), status, entry.ContainerName)
+
+		for _, line := range excerptLines(entry.Content, query) {
+			_, _ = fmt.Fprintf(w, "   │ %s\n", line)
+		}
+		_, _ = fmt.Fprintln(w, "")
+	}
+}
+
+// excerptLines returns up to kbMaxExcerptLines analysis lines containing the query.
+func excerptLines(content, query string) []string {
+	if query == "" {
+		return nil
+	}
+
+	lowerQuery := strings.ToLower(query)
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "### Scan:") || strings.HasPrefix(trimmed, "**Status:**") {
+			continue
+		}
+		if strings.Contains(strings.ToLower(trimmed), lowerQuery) {
+			lines = append(lines, trimmed)
+			if len(lines) == kbMaxExcerptLines {
+				break
+			}
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/ui"
+	"github.com/zorak1103/dlia/internal/version"
+)
+
+// Supported kb export archive formats
+const (
+	exportFormatZip   = "zip"
+	exportFormatTarGz = "tar.gz"
+)
+
+// exportManifestName is the manifest file written at the archive root.
+const exportManifestName = "manifest.json"
+
+var (
+	kbExportOutput         string
+	kbExportFormat         string
+	kbExportIncludeReports bool
+)
+
+var kbExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Bundle the knowledge base into an archive",
+	Long: `Export writes the knowledge base (per-container service files and the global
+summary) into a zip or tar.gz archive for archiving or hand-off. Reports can be
+included with --include-reports.
+
+The archive contains a manifest.json listing every container with its number of
+knowledge base entries and the date range they cover.`,
+	Example: `  # Export the knowledge base as a zip file
+  dlia kb export --output bundle.zip
+
+  # Include reports and write a tar.gz archive
+  dlia kb export --output bundle.tar.gz --format tar.gz --include-reports`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, "kb"); err != nil {
+			return err
+		}
+
+		if kbExportFormat != exportFormatZip && kbExportFormat != exportFormatTarGz {
+			return fmt.Errorf("invalid export format %q (expected %s or %s)", kbExportFormat, exportFormatZip, exportFormatTarGz)
+		}
+
+		manifest, err := exportKnowledgeBase(cfg, kbExportOutput, kbExportFormat, kbExportIncludeReports)
+		if err != nil {
+			return err
+		}
+
+		ui.Printf("📦 Exported %d container(s) to %s\n", len(manifest.Containers), kbExportOutput)
+		return nil
+	},
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	kbCmd.AddCommand(kbExportCmd)
+
+	kbExportCmd.Flags().StringVarP(&kbExportOutput, "output", "o", "", "archive file to write (required)")
+	kbExportCmd.Flags().StringVar(&kbExportFormat, "format", exportFormatZip, "archive format: zip or tar.gz")
+	kbExportCmd.Flags().BoolVar(&kbExportIncludeReports, "include-reports", false, "also include report files")
+	_ = kbExportCmd.MarkFlagRequired("output") //nolint:errcheck // Flag is defined above
+}
+
+// exportManifest describes the contents of an export archive.
+type exportManifest struct {
+	GeneratedAt     time.Time             `json:"generated_at"`
+	DLIAVersion     string                `json:"dlia_version"`
+	IncludesReports bool                  `json:"includes_reports"`
+	Containers      []exportManifestEntry `json:"containers"`
+	Files           []string              `json:"files"`
+	byName          map[string]*exportManifestEntry
+}
+
+// exportManifestEntry summarizes the exported data of one container.
+type exportManifestEntry struct {
+	Name       string     `json:"name"`
+	KBEntries  int        `json:"kb_entries"`
+	FirstEntry *time.Time `json:"first_entry,omitempty"`
+	LastEntry  *time.Time `json:"last_entry,omitempty"`
+	Reports    int        `json:"reports,omitempty"`
+}
+
+// entry returns the manifest entry for a (sanitized) container name, creating it if needed.
+func (m *exportManifest) entry(name string) *exportManifestEntry {
+	if e, ok := m.byName[name]; ok {
+		return e
+	}
+	e := &exportManifestEntry{Name: name}
+	m.byName[name] = e
+	return e
+}
+
+// archiveWriter adds files to a zip or tar.gz archive.
+type archiveWriter interface {
+	addFile(name string, size int64, modTime time.Time, r io.Reader) error
+	Close() error
+}
+
+type zipArchive struct {
+	zw *zip.Writer
+}
+
+func (a *zipArchive) addFile(name string, _ int64, modTime time.Time, r io.Reader) error {
+	w, err := a.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func (a *zipArchive) Close() error {
+	return a.zw.Close()
+}
+
+type tarGzArchive struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (a *tarGzArchive) addFile(name string, size int64, modTime time.Time, r io.Reader) error {
+	if err := a.tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: size, ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err := io.Copy(a.tw, r)
+	return err
+}
+
+func (a *tarGzArchive) Close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
+
+func newArchiveWriter(w io.Writer, format string) archiveWriter {
+	if format == exportFormatTarGz {
+		gz := gzip.NewWriter(w)
+		return &tarGzArchive{gz: gz, tw: tar.NewWriter(gz)}
+	}
+	return &zipArchive{zw: zip.NewWriter(w)}
+}
+
+// exportKnowledgeBase writes the knowledge base (and optionally reports) to an archive at output.
+// Discovery uses the same helpers as cleanup so both commands see the same containers.
+func exportKnowledgeBase(cfg *config.Config, output, format string, includeReports bool) (*exportManifest, error) {
+	f, err := os.Create(output) //nolint:gosec // Output path is provided by the user on the command line
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file %s: %w", output, err)
+	}
+
+	archive := newArchiveWriter(f, format)
+	manifest, err := writeExportArchive(archive, cfg, includeReports)
+	if closeErr := archive.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to finalize archive %s: %w", output, closeErr)
+	}
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close export file %s: %w", output, closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(output) // Best effort cleanup of partial archive
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func writeExportArchive(archive archiveWriter, cfg *config.Config, includeReports bool) (*exportManifest, error) {
+	manifest := &exportManifest{
+		GeneratedAt:     time.Now().UTC(),
+		DLIAVersion:     version.GetVersion(),
+		IncludesReports: includeReports,
+		Containers:      []exportManifestEntry{},
+		Files:           []string{},
+		byName:          make(map[string]*exportManifestEntry),
+	}
+
+	kbNames, err := scanKnowledgeBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	kbDir := cfg.Output.KnowledgeBaseDir
+	for _, name := range kbNames {
+		e := manifest.entry(name)
+		for _, ext := range knowledge.ServiceFileExtensions {
+			file := filepath.Join(kbDir, "services", name+ext)
+			if _, err := os.Stat(file); err != nil {
+				continue // This container has no KB file in this format
+			}
+			_, entries, err := knowledge.ReadServiceEntries(file)
+			if err != nil {
+				return nil, err
+			}
+
+			e.KBEntries += len(entries)
+			for i := range entries {
+				ts := entries[i].Timestamp
+				if e.FirstEntry == nil || ts.Before(*e.FirstEntry) {
+					e.FirstEntry = &ts
+				}
+				if e.LastEntry == nil || ts.After(*e.LastEntry) {
+					e.LastEntry = &ts
+				}
+			}
+
+			if err := addExportFile(archive, manifest, file, path.Join("knowledge_base", "services", name+ext)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	globalSummary := filepath.Join(kbDir, "global_summary.md")
+	if _, err := os.Stat(globalSummary); err == nil {
+		if err := addExportFile(archive, manifest, globalSummary, path.Join("knowledge_base", "global_summary.md")); err != nil {
+			return nil, err
+		}
+	}
+
+	if includeReports {
+		if err := addExportReports(archive, manifest, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range manifest.byName {
+		manifest.Containers = append(manifest.Containers, *e)
+	}
+	sort.Slice(manifest.Containers, func(i, j int) bool {
+		return manifest.Containers[i].Name < manifest.Containers[j].Name
+	})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export manifest: %w", err)
+	}
+	if err := archive.addFile(exportManifestName, int64(len(data)), manifest.GeneratedAt, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", exportManifestName, err)
+	}
+
+	return manifest, nil
+}
+
+// addExportReports adds every file below each report directory found by scanReports.
+func addExportReports(archive archiveWriter, manifest *exportManifest, cfg *config.Config) error {
+	reportNames, err := scanReports(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range reportNames {
+		root := filepath.Join(cfg.Output.ReportsDir, name)
+		e := manifest.entry(name)
+
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(cfg.Output.ReportsDir, p)
+			if err != nil {
+				return err
+			}
+
+			e.Reports++
+			return addExportFile(archive, manifest, p, path.Join("reports", filepath.ToSlash(rel)))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to export reports for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// addExportFile copies a file from disk into the archive under archiveName.
+func addExportFile(archive archiveWriter, manifest *exportManifest, src, archiveName string) error {
+	f, err := os.Open(src) //nolint:gosec // Paths come from the configured output directories
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer f.Close() //nolint:errcheck // Read-only file, close error not actionable
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+
+	if err := archive.addFile(archiveName, info.Size(), info.ModTime(), f); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", src, err)
+	}
+
+	manifest.Files = append(manifest.Files, archiveName)
+	return nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+// setupExportFixture creates a knowledge base with two entries for "web",
+// a global summary and one report file.
+func setupExportFixture(t *testing.T) *config.Config {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	kbDir := filepath.Join(tmpDir, "kb")
+	reportsDir := filepath.Join(tmpDir, "reports")
+
+	for _, dir := range []string{filepath.Join(kbDir, "services"), filepath.Join(reportsDir, "web")} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	kbContent := "# Knowledge Base: web\n\n## Service History\n\n" +
+		"### Scan: 2025-01-01T10:00:00Z\n**Status:** ✅ Healthy\n\nAll good\n\n---\n" +
+		"### Scan: 2025-01-03T10:00:00Z\n**Status:** 🔴 Issues Detected\n\nError\n\n---\n"
+	files := map[string]string{
+		filepath.Join(kbDir, "services", "web.md"):        kbContent,
+		filepath.Join(kbDir, "global_summary.md"):         "# Global Summary\n",
+		filepath.Join(reportsDir, "web", "2025-01-03.md"): "# Report\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	return &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir: kbDir,
+			ReportsDir:       reportsDir,
+		},
+	}
+}
+
+func TestExportKnowledgeBase_Zip(t *testing.T) {
+	t.Parallel()
+
+	testCfg := setupExportFixture(t)
+	output := filepath.Join(t.TempDir(), "bundle.zip")
+
+	manifest, err := exportKnowledgeBase(testCfg, output, exportFormatZip, true)
+	if err != nil {
+		t.Fatalf("exportKnowledgeBase() error = %v", err)
+	}
+
+	if len(manifest.Containers) != 1 {
+		t.Fatalf("Expected 1 container in manifest, got %d", len(manifest.Containers))
+	}
+	web := manifest.Containers[0]
+	if web.Name != "web" || web.KBEntries != 2 || web.Reports != 1 {
+		t.Errorf("Unexpected manifest entry: %+v", web)
+	}
+	if web.FirstEntry == nil || web.LastEntry == nil || !web.FirstEntry.Before(*web.LastEntry) {
+		t.Errorf("Expected date range, got %v - %v", web.FirstEntry, web.LastEntry)
+	}
+
+	zr, err := zip.OpenReader(output)
+	if err != nil {
+		t.Fatalf("Failed to open zip: %v", err)
+	}
+	defer zr.Close() //nolint:errcheck // Test cleanup
+
+	names := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		names[f.Name] = f
+	}
+	for _, want := range []string{"knowledge_base/services/web.md", "knowledge_base/global_summary.md", "reports/web/2025-01-03.md", exportManifestName} {
+		if names[want] == nil {
+			t.Errorf("Expected %s in archive", want)
+		}
+	}
+
+	rc, err := names[exportManifestName].Open()
+	if err != nil {
+		t.Fatalf("Failed to open manifest: %v", err)
+	}
+	defer rc.Close() //nolint:errcheck // Test cleanup
+
+	var decoded exportManifest
+	if err := json.NewDecoder(rc).Decode(&decoded); err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	if !decoded.IncludesReports || len(decoded.Containers) != 1 {
+		t.Errorf("Unexpected decoded manifest: %+v", decoded)
+	}
+}
+
+func TestExportKnowledgeBase_TarGzWithoutReports(t *testing.T) {
+	t.Parallel()
+
+	testCfg := setupExportFixture(t)
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+
+	if _, err := exportKnowledgeBase(testCfg, output, exportFormatTarGz, false); err != nil {
+		t.Fatalf("exportKnowledgeBase() error = %v", err)
+	}
+
+	f, err := os.Open(output) //nolint:gosec // Test file
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	defer f.Close() //nolint:errcheck // Test cleanup
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Failed to open gzip stream: %v", err)
+	}
+
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read tar: %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+
+	if len(names) != 3 {
+		t.Errorf("Expected KB file, global summary and manifest (no reports), got %v", names)
+	}
+}
+
+func TestExportKnowledgeBase_EmptyKB(t *testing.T) {
+	t.Parallel()
+
+	testCfg := &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir: filepath.Join(t.TempDir(), "missing"),
+			ReportsDir:       filepath.Join(t.TempDir(), "missing"),
+		},
+	}
+
+	manifest, err := exportKnowledgeBase(testCfg, filepath.Join(t.TempDir(), "empty.zip"), exportFormatZip, true)
+	if err != nil {
+		t.Fatalf("exportKnowledgeBase() error = %v", err)
+	}
+	if len(manifest.Containers) != 0 {
+		t.Errorf("Expected empty manifest, got %+v", manifest.Containers)
+	}
+}
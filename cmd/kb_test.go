@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/knowledge"
+)
+
+func TestKBSearchCmd_Structure(t *testing.T) {
+	t.Parallel()
+
+	if kbCmd.Use != "kb" {
+		t.Errorf("Expected command use 'kb', got '%s'", kbCmd.Use)
+	}
+
+	if !strings.HasPrefix(kbSearchCmd.Use, "search") {
+		t.Errorf("Expected command use to start with 'search', got '%s'", kbSearchCmd.Use)
+	}
+
+	for _, flag := range []string{"status", "since", "container"} {
+		if kbSearchCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Expected --%s flag to be defined", flag)
+		}
+	}
+}
+
+func TestKBSearchCmd_Run(t *testing.T) {
+	tmpDir := t.TempDir()
+	servicesDir := filepath.Join(tmpDir, "services")
+	if err := os.MkdirAll(servicesDir, 0o750); err != nil {
+		t.Fatalf("Failed to create services dir: %v", err)
+	}
+
+	ts := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	content := "# Knowledge Base: web\n\n## Service History\n\n### Scan: " + ts +
+		"\n**Status:** 🔴 Issues Detected\n\nError: upstream timeout\n\n---\n"
+	if err := os.WriteFile(filepath.Join(servicesDir, "web.md"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write KB file: %v", err)
+	}
+
+	originalCfg := cfg
+	cfg = &config.Config{
+		ConfigFilePath: filepath.Join(tmpDir, "config.yaml"),
+		Output: config.OutputConfig{
+			ReportsDir:       tmpDir,
+			KnowledgeBaseDir: tmpDir,
+			StateFile:        filepath.Join(tmpDir, "state.json"),
+		},
+	}
+	defer func() { cfg = originalCfg }()
+
+	var buf bytes.Buffer
+	kbSearchCmd.SetOut(&buf)
+	kbSearchCmd.SetErr(&buf)
+
+	if err := kbSearchCmd.RunE(kbSearchCmd, []string{"timeout"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Found 1 matching", "Issues Detected", "web", "upstream timeout"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got: %s", want, output)
+		}
+	}
+
+	buf.Reset()
+	if err := kbSearchCmd.RunE(kbSearchCmd, []string{"nothing-like-this"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No matching knowledge base entries") {
+		t.Errorf("Expected no-match message, got: %s", buf.String())
+	}
+}
+
+func TestExcerptLines(t *testing.T) {
+	t.Parallel()
+
+	content := "### Scan: 2025-01-01T00:00:00Z\n**Status:** 🟢 Healthy\n\ntimeout one\nother\nTIMEOUT two\ntimeout three\ntimeout four"
+
+	lines := excerptLines(content, "timeout")
+	if len(lines) != kbMaxExcerptLines {
+		t.Fatalf("Expected %d excerpt lines, got %d: %v", kbMaxExcerptLines, len(lines), lines)
+	}
+	if lines[1] != "TIMEOUT two" {
+		t.Errorf("Expected case-insensitive match, got %v", lines)
+	}
+
+	if excerptLines(content, "") != nil {
+		t.Error("Expected no excerpt for empty query")
+	}
+}
+
+func TestDisplaySearchResults_Empty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	displaySearchResults(&buf, []knowledge.Entry{}, "x")
+
+	if !strings.Contains(buf.String(), "No matching") {
+		t.Errorf("Expected empty message, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/notification"
+	"github.com/zorak1103/dlia/internal/ui"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   cmdNotify,
+	Short: "Manage notifications",
+	Long: `Notification commands. Notifications that fail to send are queued in
+notification.pending_dir when it is configured.`,
+}
+
+var notifyFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Resend queued notifications",
+	Long: `Flush retries every notification in notification.pending_dir, oldest first,
+and deletes each one that is delivered. It stops at the first delivery failure
+and leaves the rest queued. Scans also flush the queue before they start.`,
+	Example: `  # Resend notifications that failed during earlier scans
+  dlia notify flush`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, cmdNotify); err != nil {
+			return err
+		}
+		if cfg.Notification.PendingDir == "" {
+			return fmt.Errorf("notification queue is not configured: set notification.pending_dir")
+		}
+
+		result, err := notification.FlushPending(cfg.Notification.PendingDir)
+		if result.Sent == 0 && result.Remaining == 0 && result.Discarded == 0 && err == nil {
+			ui.Println("✅ No queued notifications")
+			return nil
+		}
+		ui.Printf("📧 Sent %d queued notification(s), %d remaining\n", result.Sent, result.Remaining)
+		if result.Discarded > 0 {
+			ui.Printf("⚠️  Moved %d unreadable queued notification(s) aside\n", result.Discarded)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to flush notification queue: %w", err)
+		}
+		return nil
+	},
+}
+
+// defaultTestMessage is the body of dlia notify test without --message.
+const defaultTestMessage = "This is a test notification from DLIA. If you can read this, notification.shoutrrr_url works."
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test notification",
+	Long: `Test sends a message through notification.shoutrrr_url so the URL can be
+verified without running a scan that finds issues. The minimum severity does not
+apply, and a failed test message is not queued.
+
+Notifications must be enabled; --force sends the test message even when
+notification.enabled is false, to validate a URL before turning notifications on.`,
+	Example: `  # Check that the configured notification URL works
+  dlia notify test
+
+  # Validate the URL before setting notification.enabled: true
+  dlia notify test --force --message "Hello from DLIA"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, cmdNotify); err != nil {
+			return err
+		}
+		message, _ := cmd.Flags().GetString("message")
+		force, _ := cmd.Flags().GetBool("force")
+
+		return sendTestNotification(cfg, message, force)
+	},
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.AddCommand(notifyFlushCmd)
+	notifyCmd.AddCommand(notifyTestCmd)
+
+	notifyTestCmd.Flags().String("message", defaultTestMessage, "text of the test notification")
+	notifyTestCmd.Flags().Bool("force", false, "send even when notification.enabled is false")
+}
+
+// sendTestNotification sends message through the configured notification URL. With
+// force, disabled notifications are enabled for this message only.
+func sendTestNotification(cfg *config.Config, message string, force bool) error {
+	if !cfg.Notification.Enabled && !force {
+		return configError(fmt.Errorf("notifications are disabled (notification.enabled: false); use --force to send the test message anyway"))
+	}
+
+	testCfg := *cfg
+	testCfg.Notification.Enabled = true
+	notifier, err := notification.NewNotifier(&testCfg)
+	if err != nil {
+		return configError(err)
+	}
+
+	if err := notifier.SendTest(message); err != nil {
+		return err
+	}
+	ui.Println("✅ Test notification sent")
+	return nil
+}
+
+// flushPendingNotifications resends notifications queued by earlier scans. Failures
+// are only reported: the queue is retried again by the next scan or dlia notify flush.
+func flushPendingNotifications(cfg *config.Config, scanCfg *scanConfig) {
+	if cfg.Notification.PendingDir == "" || scanCfg.dryRun {
+		return
+	}
+
+	result, err := notification.FlushPending(cfg.Notification.PendingDir)
+	if result.Sent > 0 {
+		scanCfg.out.Printf("📧 Sent %d queued notification(s)\n", result.Sent)
+	}
+	if result.Discarded > 0 {
+		scanCfg.out.Warnf("⚠️  Moved %d unreadable queued notification(s) aside\n", result.Discarded)
+	}
+	switch {
+	case err != nil && result.Remaining > 0:
+		scanCfg.out.Warnf("⚠️  %d queued notification(s) could not be sent: %v\n", result.Remaining, err)
+	case err != nil:
+		scanCfg.out.Warnf("⚠️  Notification queue: %v\n", err)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/notification"
+)
+
+func TestFlushPendingNotifications(t *testing.T) {
+	dir := t.TempDir()
+	pending := notification.PendingNotification{URL: "generic+http://127.0.0.1:1/webhook", Message: "m", CreatedAt: time.Now()}
+	if _, err := notification.Enqueue(dir, 10, pending); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Notification: config.NotificationConfig{PendingDir: dir, MaxPending: 10}}
+
+	t.Run("reports failures and keeps the queue", func(t *testing.T) {
+		output := captureStdout(t, func() { flushPendingNotifications(cfg, newTestScanConfig()) })
+		if !strings.Contains(output, "1 queued notification(s) could not be sent") {
+			t.Errorf("expected failure warning, got %q", output)
+		}
+		if paths, _ := notification.ListPending(dir); len(paths) != 1 {
+			t.Errorf("expected notification to stay queued, got %d", len(paths))
+		}
+	})
+
+	t.Run("dry run does not flush", func(t *testing.T) {
+		scanCfg := newTestScanConfig()
+		scanCfg.dryRun = true
+		if output := captureStdout(t, func() { flushPendingNotifications(cfg, scanCfg) }); output != "" {
+			t.Errorf("expected no output in dry run, got %q", output)
+		}
+	})
+}
+
+func TestSendTestNotification(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	url := "generic+" + server.URL + "/webhook"
+
+	t.Run("disabled without force", func(t *testing.T) {
+		cfg := &config.Config{Notification: config.NotificationConfig{ShoutrrURL: url}}
+		err := sendTestNotification(cfg, "hello", false)
+		if err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Fatalf("Expected an error suggesting --force, got: %v", err)
+		}
+		if code := exitCodeFor(err); code != exitCodeConfig {
+			t.Errorf("exitCodeFor() = %d, want %d", code, exitCodeConfig)
+		}
+	})
+
+	t.Run("disabled with force", func(t *testing.T) {
+		cfg := &config.Config{Notification: config.NotificationConfig{ShoutrrURL: url, MinSeverity: "critical"}}
+		output := captureStdout(t, func() {
+			if err := sendTestNotification(cfg, "hello from the test", true); err != nil {
+				t.Errorf("sendTestNotification() error = %v", err)
+			}
+		})
+		if !strings.Contains(output, "Test notification sent") {
+			t.Errorf("Expected success message, got %q", output)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(bodies) != 1 || !strings.Contains(bodies[0], "hello from the test") {
+			t.Errorf("Expected one delivered test message, got %q", bodies)
+		}
+		if cfg.Notification.Enabled {
+			t.Error("--force must not enable notifications in the loaded config")
+		}
+	})
+
+	t.Run("delivery failure", func(t *testing.T) {
+		cfg := &config.Config{Notification: config.NotificationConfig{Enabled: true, ShoutrrURL: "generic+http://127.0.0.1:1/webhook"}}
+		err := sendTestNotification(cfg, "hello", false)
+		if err == nil || !strings.Contains(err.Error(), "notification failed") {
+			t.Errorf("Expected wrapped delivery error, got: %v", err)
+		}
+	})
+
+	t.Run("missing URL", func(t *testing.T) {
+		cfg := &config.Config{Notification: config.NotificationConfig{Enabled: true}}
+		if err := sendTestNotification(cfg, "hello", false); exitCodeFor(err) != exitCodeConfig {
+			t.Errorf("Expected config error for a missing URL, got: %v", err)
+		}
+	})
+}
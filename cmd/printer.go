@@ -0,0 +1,36 @@
+package cmd
+
+import "github.com/zorak1103/dlia/internal/ui"
+
+// printer writes scan progress to stdout. In quiet mode only warnings, errors and the
+// final summary line are printed, so cron mails stay empty on uneventful runs.
+// Verbose details stay behind scanConfig.verbose, which excludes quiet mode.
+// While a progress bar is set (scan --progress), it replaces the progress output and
+// warnings are printed above it. The zero value prints everything.
+type printer struct {
+	quiet    bool
+	progress *progressBar
+}
+
+// Printf prints progress output, suppressed in quiet mode and behind a progress bar.
+func (p printer) Printf(format string, args ...any) {
+	if !p.quiet && p.progress == nil {
+		ui.Printf(format, args...)
+	}
+}
+
+// Println prints progress output, suppressed in quiet mode and behind a progress bar.
+func (p printer) Println(args ...any) {
+	if !p.quiet && p.progress == nil {
+		ui.Println(args...)
+	}
+}
+
+// Warnf prints warnings and errors, which are shown in every mode.
+func (p printer) Warnf(format string, args ...any) {
+	if p.progress != nil {
+		p.progress.Printf(format, args...)
+		return
+	}
+	ui.Printf(format, args...)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	fn()
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	return buf.String()
+}
+
+func TestPrinter(t *testing.T) {
+	render := func(p printer) string {
+		return captureStdout(t, func() {
+			p.Printf("progress %d\n", 1)
+			p.Println("more progress")
+			p.Warnf("⚠️  warning %d\n", 2)
+		})
+	}
+
+	if got, want := render(printer{}), "progress 1\nmore progress\n⚠️  warning 2\n"; got != want {
+		t.Errorf("normal output = %q, want %q", got, want)
+	}
+	if got, want := render(printer{quiet: true}), "⚠️  warning 2\n"; got != want {
+		t.Errorf("quiet output = %q, want %q", got, want)
+	}
+}
+
+func TestDisplayScanSummary_Quiet(t *testing.T) {
+	scanCfg := newTestScanConfig()
+	scanCfg.quiet = true
+	scanCfg.out = printer{quiet: true}
+
+	got := captureStdout(t, func() {
+		displayScanSummary(scanStats{scannedContainers: 3, totalLogs: 120, quotaSkipped: 1}, scanCfg, 0)
+	})
+
+	want := "✅ Scan complete: 3 container(s) scanned, 120 log entries, 1 skipped (LLM quota)\n"
+	if got != want {
+		t.Errorf("quiet summary = %q, want %q", got, want)
+	}
+}
+
+func TestDisplayScanSummary_ChangedOnly(t *testing.T) {
+	scanCfg := newTestScanConfig()
+	scanCfg.changedOnly = true
+	stats := scanStats{scannedContainers: 1, totalLogs: 7, activeContainers: []string{"api"}, idleContainers: []string{"cron", "db"}}
+
+	got := captureStdout(t, func() {
+		displayScanSummary(stats, scanCfg, 0)
+	})
+	for _, want := range []string{"🟢 Active: 1 (api)\n", "💤 Idle: 2 (cron, db)\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+
+	scanCfg.quiet = true
+	scanCfg.out = printer{quiet: true}
+	got = captureStdout(t, func() {
+		displayScanSummary(stats, scanCfg, 0)
+	})
+	if want := "✅ Scan complete: 1 container(s) scanned, 7 log entries, 1 active, 2 idle\n"; got != want {
+		t.Errorf("quiet summary = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/ui"
+	"golang.org/x/term"
+)
+
+// progressNameWidth caps the container name shown in the progress line so the line
+// does not wrap, which would break the carriage-return redraw.
+const progressNameWidth = 40
+
+// progressBar renders scan --progress: containers done/total, the current container
+// and the elapsed time (plus an estimate of the remaining time). On a terminal it is
+// a single line redrawn with a carriage return; otherwise each container gets a plain
+// line. Writes are serialized, so it is safe for concurrent use.
+type progressBar struct {
+	mu      sync.Mutex
+	w       io.Writer
+	tty     bool
+	total   int
+	done    int
+	current string
+	start   time.Time
+	now     func() time.Time
+}
+
+// newProgressBar creates a progress bar for total containers writing to w. With tty
+// the progress is a single line redrawn in place.
+func newProgressBar(w io.Writer, total int, tty bool) *progressBar {
+	return &progressBar{w: w, tty: tty, total: total, start: time.Now(), now: time.Now}
+}
+
+// progressOnTerminal reports whether scan --progress can redraw a single line: stdout
+// is a terminal, output is not plain (--no-color) and the run does not write the report
+// index as JSON, whose consumers expect plain line output.
+func progressOnTerminal(scanCfg *scanConfig) bool {
+	return !ui.Plain() && scanCfg.output != indexOutputJSON && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Start marks the container at index (0-based) as being scanned; the containers
+// before it count as done.
+func (b *progressBar) Start(index int, name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done, b.current = index, name
+	if b.tty {
+		b.redraw()
+		return
+	}
+	_, _ = fmt.Fprintf(b.w, "⏳ %s\n", b.line())
+}
+
+// Finish counts the current container as done and ends the progress output.
+func (b *progressBar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.current != "" {
+		b.done++
+	}
+	b.current = ""
+	if b.tty {
+		b.redraw()
+		_, _ = fmt.Fprintln(b.w)
+		return
+	}
+	_, _ = fmt.Fprintf(b.w, "✅ %s\n", b.line())
+}
+
+// Printf prints a message above the progress line, e.g. a warning, and redraws the
+// progress line below it.
+func (b *progressBar) Printf(format string, args ...any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tty {
+		_, _ = io.WriteString(b.w, "\r\033[K")
+	}
+	_, _ = fmt.Fprintf(b.w, format, args...)
+	if b.tty && b.current != "" {
+		b.redraw()
+	}
+}
+
+// redraw replaces the terminal line with the current progress. Callers hold mu.
+func (b *progressBar) redraw() {
+	_, _ = fmt.Fprintf(b.w, "\r\033[K⏳ %s", b.line())
+}
+
+// line formats the progress without decoration, e.g.
+// "[12/50] 24% web-1 (elapsed 1m5s, ~3m26s left)". Callers hold mu.
+func (b *progressBar) line() string {
+	elapsed := b.now().Sub(b.start).Round(time.Second)
+	percent := 100
+	if b.total > 0 {
+		percent = b.done * 100 / b.total
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "[%d/%d] %d%%", b.done, b.total, percent)
+	if b.current != "" {
+		_, _ = fmt.Fprintf(&sb, " %s", shortenName(b.current, progressNameWidth))
+	}
+	_, _ = fmt.Fprintf(&sb, " (elapsed %s", elapsed)
+	if b.done > 0 && b.done < b.total {
+		remaining := elapsed / time.Duration(b.done) * time.Duration(b.total-b.done)
+		_, _ = fmt.Fprintf(&sb, ", ~%s left", remaining.Round(time.Second))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// shortenName cuts name to at most width runes, marking the cut with an ellipsis.
+func shortenName(name string, width int) string {
+	runes := []rune(name)
+	if len(runes) <= width {
+		return name
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/state"
+)
+
+// newTestProgressBar returns a progress bar whose clock advances by step on every read.
+func newTestProgressBar(w *bytes.Buffer, total int, tty bool, step time.Duration) *progressBar {
+	bar := newProgressBar(w, total, tty)
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	bar.start = start
+	now := start
+	bar.now = func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+	return bar
+}
+
+func TestProgressBar_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	bar := newTestProgressBar(&buf, 4, false, 10*time.Second)
+
+	bar.Start(0, "web")
+	bar.Start(1, "db")
+	bar.Printf("        ⚠️  %s\n", "log read failed")
+	bar.Finish()
+
+	want := "⏳ [0/4] 0% web (elapsed 10s)\n" +
+		"⏳ [1/4] 25% db (elapsed 20s, ~1m0s left)\n" +
+		"        ⚠️  log read failed\n" +
+		"✅ [2/4] 50% (elapsed 30s, ~30s left)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("plain progress = %q, want %q", got, want)
+	}
+}
+
+func TestProgressBar_Terminal(t *testing.T) {
+	var buf bytes.Buffer
+	bar := newTestProgressBar(&buf, 2, true, time.Second)
+
+	bar.Start(0, "web")
+	bar.Printf("warning\n")
+	bar.Start(1, strings.Repeat("x", 60))
+	bar.Finish()
+
+	got := buf.String()
+	if strings.Count(got, "\n") != 2 {
+		t.Errorf("Expected only the warning and the final line to end with a newline, got %q", got)
+	}
+	if !strings.Contains(got, "\r\033[K⏳ [0/2] 0% web (elapsed 1s)\r\033[Kwarning\n\r\033[K⏳ [0/2] 0% web") {
+		t.Errorf("Expected the warning printed on a cleared line and the progress redrawn below it, got %q", got)
+	}
+	if !strings.Contains(got, strings.Repeat("x", progressNameWidth-1)+"…") || strings.Contains(got, strings.Repeat("x", progressNameWidth)) {
+		t.Errorf("Expected long container names shortened, got %q", got)
+	}
+	if !strings.HasSuffix(got, "\r\033[K⏳ [2/2] 100% (elapsed 4s)\n") {
+		t.Errorf("Expected the final progress line to end the output, got %q", got)
+	}
+}
+
+func TestProgressBar_ConcurrentWrites(t *testing.T) {
+	var buf bytes.Buffer
+	bar := newProgressBar(&buf, 100, false)
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Go(func() {
+			bar.Start(i, "c")
+			bar.Printf("warning %d\n", i)
+		})
+	}
+	wg.Wait()
+
+	if got := strings.Count(buf.String(), "\n"); got != 200 {
+		t.Errorf("Expected 200 intact lines, got %d", got)
+	}
+}
+
+func TestPrinter_Progress(t *testing.T) {
+	var buf bytes.Buffer
+	p := printer{progress: newProgressBar(&buf, 1, false)}
+
+	stdout := captureStdout(t, func() {
+		p.Printf("progress %d\n", 1)
+		p.Println("more progress")
+		p.Warnf("⚠️  warning %d\n", 2)
+	})
+
+	if stdout != "" {
+		t.Errorf("Expected no direct output behind a progress bar, got %q", stdout)
+	}
+	if got := buf.String(); got != "⚠️  warning 2\n" {
+		t.Errorf("Expected warnings routed through the progress bar, got %q", got)
+	}
+}
+
+func TestProcessContainers_Progress(t *testing.T) {
+	containers := []docker.Container{
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abc1", Name: "web", State: "running"},
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abc2", Name: "db", State: "running"},
+	}
+	mockDocker := &MockDockerClient{
+		containers: containers,
+		logs: map[string][]docker.LogEntry{
+			containers[0].ID: {{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "Request served"}},
+		},
+	}
+	cfg := &config.Config{LLM: config.LLMConfig{Model: "test-model", MaxTokens: 4000}}
+
+	t.Run("progress", func(t *testing.T) {
+		scanCfg := newTestScanConfig()
+		scanCfg.dryRun = true
+		scanCfg.progress = true
+		st, _ := state.Load(t.TempDir() + "/state.json")
+
+		output := captureStdout(t, func() {
+			processContainers(context.Background(), mockDocker, st, containers, cfg, scanCfg, 0)
+		})
+
+		if strings.Contains(output, "Processing:") || strings.Contains(output, "DRY RUN") {
+			t.Errorf("Expected per-container output replaced by the progress line, got:\n%s", output)
+		}
+		for _, want := range []string{"⏳ [0/2] 0% web", "⏳ [1/2] 50% db", "✅ [2/2] 100%"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected %q in progress output:\n%s", want, output)
+			}
+		}
+		if scanCfg.out.progress != nil {
+			t.Error("Expected the printer restored after processing")
+		}
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		scanCfg := newTestScanConfig()
+		scanCfg.dryRun = true
+		scanCfg.progress = true
+		scanCfg.quiet = true
+		scanCfg.out = printer{quiet: true}
+		st, _ := state.Load(t.TempDir() + "/state.json")
+
+		output := captureStdout(t, func() {
+			processContainers(context.Background(), mockDocker, st, containers, cfg, scanCfg, 0)
+		})
+
+		if output != "" {
+			t.Errorf("Expected no progress line in quiet mode, got:\n%s", output)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+var promptsCmd = &cobra.Command{
+	Use:   cmdPrompts,
+	Short: "Inspect prompt templates",
+	Long: `Prompt commands for checking the prompt templates configured in the prompts
+section of config.yaml.`,
+}
+
+var promptsValidateCmd = &cobra.Command{
+	Use:   cmdValidate,
+	Short: "Check that all prompt templates load and render",
+	Long: `Validate loads every prompt (external file or built-in default) and renders it
+with placeholder data. This catches template syntax errors as well as execution
+errors such as references to undefined fields ({{.UndefinedField}}) before a scan
+runs into them. The resolved source of each prompt is shown so you can confirm
+that external files are picked up.
+
+Exits with an error if any prompt is broken.`,
+	Example: `  # Check the prompts configured in config.yaml
+  dlia prompts validate`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, cmdPrompts); err != nil {
+			return err
+		}
+
+		loader := prompts.NewPromptLoader(cfg)
+		results := loader.Validate()
+
+		broken := displayPromptValidation(cmd.OutOrStdout(), results, loader.GetAllPromptSources())
+		if broken > 0 {
+			return fmt.Errorf("%d prompt template(s) are broken", broken)
+		}
+		return nil
+	},
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(promptsCmd)
+	promptsCmd.AddCommand(promptsValidateCmd)
+}
+
+// displayPromptValidation prints one line per prompt with its resolved source and,
+// for broken prompts, the error and offending template line. Returns the number of
+// broken prompts.
+func displayPromptValidation(w io.Writer, results []prompts.ValidationResult, sources map[string]string) int {
+	_, _ = fmt.Fprintln(w, "📝 Validating prompt templates...")
+	_, _ = fmt.Fprintln(w)
+
+	broken := 0
+	for _, r := range results {
+		source, ok := sources[r.Name]
+		if !ok {
+			source = r.Source
+		}
+
+		if r.Err == nil {
+			_, _ = fmt.Fprintf(w, "✅ %s: OK (%s)\n", r.Name, source)
+			continue
+		}
+
+		broken++
+		_, _ = fmt.Fprintf(w, "❌ %s: BROKEN (%s)\n", r.Name, source)
+		if r.Line > 0 {
+			_, _ = fmt.Fprintf(w, "   line %d: %s\n", r.Line, r.LineText)
+		}
+		_, _ = fmt.Fprintf(w, "   %v\n", r.Err)
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if broken == 0 {
+		_, _ = fmt.Fprintf(w, "🎉 All %d prompt templates are valid\n", len(results))
+	} else {
+		_, _ = fmt.Fprintf(w, "⚠️  %d of %d prompt templates are broken\n", broken, len(results))
+	}
+	return broken
+}
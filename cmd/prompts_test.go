@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+func TestDisplayPromptValidation(t *testing.T) {
+	results := []prompts.ValidationResult{
+		{Name: "system_prompt", Source: "INTERNAL DEFAULT"},
+		{
+			Name:     "analysis_prompt",
+			Source:   "EXTERNAL: prompts/analysis.md",
+			Err:      errors.New(`template: analysis:3:2: executing "analysis" at <.UndefinedField>: map has no entry for key "UndefinedField"`),
+			Line:     3,
+			LineText: "{{.UndefinedField}}",
+		},
+	}
+	sources := map[string]string{
+		"system_prompt":   "INTERNAL DEFAULT",
+		"analysis_prompt": "EXTERNAL: prompts/analysis.md",
+	}
+
+	var buf bytes.Buffer
+	broken := displayPromptValidation(&buf, results, sources)
+	if broken != 1 {
+		t.Errorf("broken = %d, want 1", broken)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"✅ system_prompt: OK (INTERNAL DEFAULT)",
+		"❌ analysis_prompt: BROKEN (EXTERNAL: prompts/analysis.md)",
+		"line 3: {{.UndefinedField}}",
+		"map has no entry for key",
+		"1 of 2 prompt templates are broken",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestDisplayPromptValidation_AllValid(t *testing.T) {
+	var buf bytes.Buffer
+	broken := displayPromptValidation(&buf, []prompts.ValidationResult{{Name: "system_prompt", Source: "INTERNAL DEFAULT"}}, nil)
+	if broken != 0 {
+		t.Errorf("broken = %d, want 0", broken)
+	}
+	if !strings.Contains(buf.String(), "All 1 prompt templates are valid") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/prompts"
+	"github.com/zorak1103/dlia/internal/reporting"
+	"github.com/zorak1103/dlia/internal/ui"
+)
+
+var (
+	reanalyzeScan        string
+	reanalyzeLLMLog      bool
+	reanalyzeFilterStats bool
+)
+
+var reanalyzeCmd = &cobra.Command{
+	Use:   cmdReanalyze + " <container>",
+	Short: "Re-analyze saved raw logs with the current prompts and model",
+	Long: `Reanalyze reads the raw logs saved next to an earlier report (output.save_raw_logs)
+and runs them through the LLM pipeline again, using the current prompts, model and
+filter settings. The result is written as a new report marked as a re-analysis.
+
+Useful for iterating on prompt changes against real historical logs without
+contacting the Docker daemon or waiting for new logs. The knowledge base and the
+scan state are not changed.
+
+By default the most recent saved logs of the container are used; --scan selects
+the logs of a specific report by its timestamp.`,
+	Example: `  # Re-run the latest saved logs of my-app with the current prompts
+  dlia reanalyze my-app
+
+  # Re-analyze the logs saved with the report 2025-01-15_08-30-00.md
+  dlia reanalyze my-app --scan 2025-01-15_08-30-00`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReanalyze,
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(reanalyzeCmd)
+
+	reanalyzeCmd.Flags().StringVar(&reanalyzeScan, "scan", "", "timestamp of the report whose logs to re-analyze (YYYY-MM-DD_HH-MM-SS, default: latest)")
+	reanalyzeCmd.Flags().BoolVar(&reanalyzeLLMLog, "llmlog", false, "enable logging of all LLM requests and responses to markdown files")
+	reanalyzeCmd.Flags().BoolVar(&reanalyzeFilterStats, "filter-stats", false, "display filter statistics showing how many log lines were filtered")
+}
+
+func runReanalyze(_ *cobra.Command, args []string) error {
+	cfg = GetConfig()
+	if err := validateConfigOrExit(cfg, cmdReanalyze); err != nil {
+		return err
+	}
+
+	containerName := args[0]
+	if reanalyzeScan != "" {
+		if _, err := time.Parse("2006-01-02_15-04-05", reanalyzeScan); err != nil {
+			return fmt.Errorf("invalid --scan %q: expected a report timestamp like 2025-01-15_08-30-00", reanalyzeScan)
+		}
+	}
+
+	logsPath, err := reporting.FindRawLogs(containerName, reanalyzeScan, cfg)
+	if err != nil {
+		return err
+	}
+
+	logs, err := readSavedLogs(logsPath)
+	if err != nil {
+		return err
+	}
+
+	scanCfg := &scanConfig{
+		llmLog:      reanalyzeLLMLog,
+		filterStats: reanalyzeFilterStats,
+		verbose:     verbose,
+		noKB:        true, // Re-analyses must not add duplicate history
+	}
+
+	// Custom prompt overrides must be loaded before the pipeline is created
+	prompts.InitPrompts(cfg)
+
+	ui.Printf("🔁 Re-analyzing %s from %s\n", containerName, logsPath)
+	ui.Printf("        📝 Found %d log entries\n", len(logs))
+
+	if len(logs) == 0 {
+		ui.Printf("        ℹ️  Nothing to analyze\n")
+		return nil
+	}
+
+	displayLogsPreview(logs, scanCfg)
+
+	pipeline, err := initializeLLMPipeline(cfg, scanCfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize LLM: %w", err)
+	}
+
+	ui.Printf("        🤖 Analyzing logs with LLM...\n")
+	result, err := pipeline.AnalyzeLogs(context.Background(), containerName, logs)
+	if err != nil {
+		return fmt.Errorf("LLM re-analysis failed for %s: %w", logsPath, err)
+	}
+	result.ReanalyzedFrom = logsPath
+
+	displayAnalysisResults(result, scanCfg)
+	handleReportingAndKnowledge(containerName, result, logs, cfg, scanCfg)
+
+	ui.Printf("✅ Re-analysis complete (%d tokens, %d chunk(s))\n", result.TokensUsed, result.ChunksUsed)
+	return nil
+}
+
+// readSavedLogs parses a raw log file written by output.save_raw_logs.
+func readSavedLogs(path string) ([]docker.LogEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // Path is located in the configured reports directory
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw logs %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck // Read-only file, close error not actionable
+
+	logs, err := parseSavedLogs(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse raw logs %s: %w", path, err)
+	}
+	return logs, nil
+}
+
+// parseSavedLogs converts the "[timestamp] message" lines of chunking.FormatLogs back
+// to the "timestamp message" format of docker logs --timestamps and parses them like
+// a captured log file, so entries keep their timestamps. Bracketed prefixes that are
+// not timestamps (e.g. "[INFO]") are left untouched.
+func parseSavedLogs(r io.Reader) ([]docker.LogEntry, error) {
+	var sb strings.Builder
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if rest, ok := strings.CutPrefix(line, "["); ok {
+			if timestamp, message, found := strings.Cut(rest, "] "); found && isRFC3339(timestamp) {
+				line = timestamp + " " + message
+			}
+		}
+		sb.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading raw logs: %w", err)
+	}
+
+	return docker.ParseLogFile(strings.NewReader(sb.String()))
+}
+
+// isRFC3339 reports whether s is a timestamp as Docker writes them.
+func isRFC3339(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReanalyzeCmd_Structure(t *testing.T) {
+	t.Parallel()
+
+	if !strings.HasPrefix(reanalyzeCmd.Use, cmdReanalyze) {
+		t.Errorf("Expected command use to start with '%s', got '%s'", cmdReanalyze, reanalyzeCmd.Use)
+	}
+
+	for _, flag := range []string{"scan", "llmlog", "filter-stats"} {
+		if reanalyzeCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Expected --%s flag to be defined", flag)
+		}
+	}
+}
+
+func TestParseSavedLogs(t *testing.T) {
+	t.Parallel()
+
+	content := "[2025-01-01T10:00:00.123456789Z] starting\n" +
+		"[INFO] no timestamp\n" +
+		"\n" +
+		"[2025-01-01T10:00:01Z] ERROR failed to connect [retry 3]\n"
+
+	logs, err := parseSavedLogs(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parseSavedLogs() error = %v", err)
+	}
+	if len(logs) != 3 {
+		t.Fatalf("Expected 3 log entries, got %d", len(logs))
+	}
+	if logs[0].Timestamp != "2025-01-01T10:00:00.123456789Z" || logs[0].Message != "starting" {
+		t.Errorf("Unexpected first entry: %+v", logs[0])
+	}
+	if logs[1].Timestamp != "" || logs[1].Message != "[INFO] no timestamp" {
+		t.Errorf("Bracketed non-timestamp prefix must be kept: %+v", logs[1])
+	}
+	if logs[2].Message != "ERROR failed to connect [retry 3]" {
+		t.Errorf("Unexpected message: %q", logs[2].Message)
+	}
+}
+
+func TestReadSavedLogs_Missing(t *testing.T) {
+	t.Parallel()
+
+	_, err := readSavedLogs(filepath.Join(t.TempDir(), "missing.logs.txt"))
+	if err == nil || !strings.Contains(err.Error(), "failed to open raw logs") {
+		t.Errorf("Expected open error, got %v", err)
+	}
+}
+
+func TestReadSavedLogs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "2025-01-01_10-00-00.logs.txt")
+	if err := os.WriteFile(path, []byte("[2025-01-01T10:00:00Z] boom\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	logs, err := readSavedLogs(path)
+	if err != nil {
+		t.Fatalf("readSavedLogs() error = %v", err)
+	}
+	if len(logs) != 1 || logs[0].Message != "boom" {
+		t.Errorf("Unexpected entries: %+v", logs)
+	}
+}
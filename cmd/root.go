@@ -2,26 +2,43 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/logging"
+	"github.com/zorak1103/dlia/internal/ui"
 	"github.com/zorak1103/dlia/internal/version"
 )
 
 const (
-	cmdCleanup = "cleanup"
-	cmdConfig  = "config"
-	cmdInit    = "init"
-	cmdList    = "list"
-	cmdScan    = "scan"
-	cmdState   = "state"
+	cmdAnalyze    = "analyze"
+	cmdCleanup    = "cleanup"
+	cmdConfig     = "config"
+	cmdContainers = "containers"
+	cmdDiff       = "diff"
+	cmdInit       = "init"
+	cmdKB         = "kb"
+	cmdList       = "list"
+	cmdNotify     = "notify"
+	cmdPrompts    = "prompts"
+	cmdReanalyze  = "reanalyze"
+	cmdScan       = "scan"
+	cmdShow       = "show"
+	cmdState      = "state"
+	cmdSummary    = "summary"
+	cmdTail       = "tail"
+	cmdValidate   = "validate"
+	cmdVersion    = "version"
 )
 
 var (
 	cfgFile       string
+	profileName   string
 	verbose       bool
+	noColor       bool
 	cfg           *config.Config
 	errConfigLoad error
 )
@@ -40,13 +57,28 @@ It features:
   - Markdown-based persistent knowledge base`,
 	Version: version.GetFullVersion(),
 	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-		skipConfig := cmd.Name() == cmdInit || cmd.Name() == "help" || cmd.Name() == "version"
+		setupOutput(cmd)
+
+		skipConfig := cmd.Name() == cmdInit || cmd.Name() == "help" || cmd.Name() == cmdVersion
 		if skipConfig {
 			return nil
 		}
 
+		// An explicit --config must load exactly that file; never fall back to discovery
+		if cfgFile != "" {
+			if err := checkConfigFile(cfgFile); err != nil {
+				return configError(err)
+			}
+		}
+
 		var err error
-		cfg, err = config.Load(cfgFile)
+		cfg, err = config.LoadProfile(cfgFile, profileName)
+		if errors.Is(err, config.ErrUnknownProfile) {
+			return configError(err)
+		}
+		if err != nil && cfgFile != "" {
+			return configError(fmt.Errorf("failed to load config file %s: %w", cfgFile, err))
+		}
 		if err != nil {
 			// Store config load error for commands that need it (scan, cleanup, state).
 			// These commands will fail fast with validateConfigOrExit() in their RunE handlers.
@@ -60,23 +92,71 @@ It features:
 
 		if verbose && cfg != nil {
 			fmt.Fprintf(os.Stderr, "Loaded configuration from: %s\n", cfg.ConfigFilePath)
+			if cfg.Profile != "" {
+				_, _ = fmt.Fprintf(os.Stderr, "Using profile: %s\n", cfg.Profile)
+			}
 		}
 
-		return nil
+		return setupLogging(cfg)
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
 // nolint:gochecknoinits // Standard Cobra pattern for command registration
 func init() {
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ./config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file to load instead of searching ./config.yaml, ~/.config/dlia and /etc/dlia")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "config profile (from the profiles section) to merge over the top-level settings; defaults to $"+config.ProfileEnvVar)
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "plain ASCII output without colors and emoji (also enabled by a non-empty $"+ui.NoColorEnvVar+")")
+}
+
+// setupOutput switches the user-facing output of cmd to plain mode when requested by
+// --no-color or NO_COLOR.
+func setupOutput(cmd *cobra.Command) {
+	ui.SetPlain(noColor || os.Getenv(ui.NoColorEnvVar) != "")
+	if ui.Plain() {
+		cmd.SetOut(ui.Writer(cmd.OutOrStdout()))
+		cmd.SetErr(ui.Writer(cmd.ErrOrStderr()))
+	}
+}
+
+// setupLogging installs the operational slog logger on stderr according to the
+// logging section of cfg (defaults when cfg is nil) and --verbose.
+func setupLogging(cfg *config.Config) error {
+	var level, format string
+	if cfg != nil {
+		level, format = cfg.Logging.Level, cfg.Logging.Format
+	}
+	if err := logging.Setup(os.Stderr, level, format, verbose); err != nil {
+		return configError(fmt.Errorf("invalid logging configuration: %w", err))
+	}
+	return nil
+}
+
+// checkConfigFile verifies that path is an existing, readable regular file.
+func checkConfigFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("config file %s does not exist", path)
+		}
+		return fmt.Errorf("cannot access config file %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("config file %s is a directory", path)
+	}
+
+	f, err := os.Open(path) //nolint:gosec // Path is the user-supplied --config flag
+	if err != nil {
+		return fmt.Errorf("config file %s is not readable: %w", path, err)
+	}
+	return f.Close()
 }
 
 // GetConfig returns the loaded configuration or nil if not loaded.
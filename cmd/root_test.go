@@ -3,10 +3,15 @@ package cmd
 import (
 	"bytes"
 	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/ui"
 )
 
 const (
@@ -316,8 +321,9 @@ func TestRootCmd_PersistentPreRunE_LoadConfig(t *testing.T) {
 		Use: "scan",
 	}
 
-	// Test with non-existent config file (should not fail)
-	cfgFile = "nonexistent.yaml"
+	// Without --config, a missing config.yaml is not an error (commands check it later)
+	t.Chdir(t.TempDir())
+	cfgFile = ""
 	verbose = false
 
 	err := rootCmd.PersistentPreRunE(mockCmd, []string{})
@@ -326,6 +332,88 @@ func TestRootCmd_PersistentPreRunE_LoadConfig(t *testing.T) {
 	}
 }
 
+func TestRootCmd_PersistentPreRunE_ExplicitConfig(t *testing.T) {
+	originalCfg := cfg
+	originalCfgFile := cfgFile
+	defer func() {
+		cfg = originalCfg
+		cfgFile = originalCfgFile
+	}()
+
+	mockCmd := &cobra.Command{Use: "scan"}
+	tmpDir := t.TempDir()
+
+	// A missing --config file is a config error (exit code 2)
+	cfgFile = filepath.Join(tmpDir, "config.prod.yaml")
+	err := rootCmd.PersistentPreRunE(mockCmd, []string{})
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("Expected missing config error, got: %v", err)
+	}
+	if code := exitCodeFor(err); code != exitCodeConfig {
+		t.Errorf("exitCodeFor() = %d, want %d", code, exitCodeConfig)
+	}
+
+	// A directory is rejected as well
+	cfgFile = tmpDir
+	err = rootCmd.PersistentPreRunE(mockCmd, []string{})
+	if exitCodeFor(err) != exitCodeConfig {
+		t.Errorf("Expected config error for a directory, got: %v", err)
+	}
+
+	// An existing file is loaded and recorded as the config file path
+	configPath := filepath.Join(tmpDir, "config.prod.yaml")
+	content := "llm:\n  api_key: sk-prod\n  model: prod-model\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfgFile = configPath
+	if err := rootCmd.PersistentPreRunE(mockCmd, []string{}); err != nil {
+		t.Fatalf("Expected explicit config to load, got: %v", err)
+	}
+	if cfg.ConfigFilePath != configPath || cfg.LLM.Model != "prod-model" {
+		t.Errorf("Expected config from %s, got path %q and model %q", configPath, cfg.ConfigFilePath, cfg.LLM.Model)
+	}
+}
+
+func TestRootCmd_PersistentPreRunE_Profile(t *testing.T) {
+	originalCfg := cfg
+	originalCfgFile := cfgFile
+	originalProfile := profileName
+	defer func() {
+		cfg = originalCfg
+		cfgFile = originalCfgFile
+		profileName = originalProfile
+	}()
+
+	mockCmd := &cobra.Command{Use: "scan"}
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	content := "llm:\n  api_key: sk-test\n  model: prod-model\nprofiles:\n  ci:\n    llm:\n      model: ci-model\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfgFile = ""
+
+	profileName = "ci"
+	if err := rootCmd.PersistentPreRunE(mockCmd, []string{}); err != nil {
+		t.Fatalf("Expected profile to load, got: %v", err)
+	}
+	if cfg.Profile != "ci" || cfg.LLM.Model != "ci-model" {
+		t.Errorf("Expected ci profile applied, got profile %q and model %q", cfg.Profile, cfg.LLM.Model)
+	}
+
+	// An unknown profile is a config error (exit code 2), even without --config
+	profileName = "staging"
+	err := rootCmd.PersistentPreRunE(mockCmd, []string{})
+	if err == nil || !strings.Contains(err.Error(), "staging") {
+		t.Fatalf("Expected unknown profile error, got: %v", err)
+	}
+	if code := exitCodeFor(err); code != exitCodeConfig {
+		t.Errorf("exitCodeFor() = %d, want %d", code, exitCodeConfig)
+	}
+}
+
 func TestRootCmd_PersistentPreRunE_VerboseMode(t *testing.T) {
 	// Save original values
 	originalCfg := cfg
@@ -342,8 +430,9 @@ func TestRootCmd_PersistentPreRunE_VerboseMode(t *testing.T) {
 		Use: "scan",
 	}
 
-	// Test verbose mode with non-existent config
-	cfgFile = "nonexistent_verbose.yaml"
+	// Test verbose mode without any config file
+	t.Chdir(t.TempDir())
+	cfgFile = ""
 	verbose = true
 
 	err := rootCmd.PersistentPreRunE(mockCmd, []string{})
@@ -434,3 +523,50 @@ func TestRootCmd_SubcommandState(t *testing.T) {
 		t.Error("Expected 'state' subcommand to be registered")
 	}
 }
+
+func TestSetupLogging_InvalidFormat(t *testing.T) {
+	original := slog.Default()
+	defer slog.SetDefault(original)
+
+	err := setupLogging(&config.Config{Logging: config.LoggingConfig{Level: "info", Format: "xml"}})
+	if exitCodeFor(err) != exitCodeConfig {
+		t.Errorf("Expected configuration error for unknown log format, got %v", err)
+	}
+
+	if err := setupLogging(nil); err != nil {
+		t.Errorf("Expected defaults without config, got %v", err)
+	}
+}
+
+func TestSetupOutput_NoColor(t *testing.T) {
+	defer ui.SetPlain(false)
+
+	render := func() (string, string) {
+		var buf bytes.Buffer
+		mockCmd := &cobra.Command{Use: cmdVersion}
+		mockCmd.SetOut(&buf)
+		setupOutput(mockCmd)
+		mockCmd.Println("✅ done")
+		stdout := captureStdout(t, func() {
+			printer{}.Warnf("⚠️  Warning: %s\n", "slow")
+		})
+		return buf.String(), stdout
+	}
+
+	t.Setenv(ui.NoColorEnvVar, "")
+	if out, stdout := render(); out != "✅ done\n" || stdout != "⚠️  Warning: slow\n" {
+		t.Errorf("Expected emoji by default, got %q and %q", out, stdout)
+	}
+
+	t.Setenv(ui.NoColorEnvVar, "1")
+	if out, stdout := render(); out != "[OK] done\n" || stdout != "[WARN] Warning: slow\n" {
+		t.Errorf("Expected plain output with %s, got %q and %q", ui.NoColorEnvVar, out, stdout)
+	}
+
+	t.Setenv(ui.NoColorEnvVar, "")
+	noColor = true
+	defer func() { noColor = false }()
+	if out, _ := render(); out != "[OK] done\n" {
+		t.Errorf("Expected plain output with --no-color, got %q", out)
+	}
+}
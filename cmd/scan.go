@@ -2,7 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,12 +21,13 @@ import (
 	"github.com/zorak1103/dlia/internal/notification"
 	"github.com/zorak1103/dlia/internal/prompts"
 	"github.com/zorak1103/dlia/internal/state"
+	"github.com/zorak1103/dlia/internal/ui"
 )
 
 // coverage-exempt: requires live Docker daemon and LLM API — covered by integration tests
 
 var scanCmd = &cobra.Command{
-	Use:   cmdScan,
+	Use:   cmdScan + " [container]",
 	Short: "Perform a one-time scan of Docker container logs",
 	Long: `Scan performs a single analysis pass over Docker container logs.
 
@@ -31,7 +38,7 @@ This command:
   4. Sends notifications if configured (Phase 5)
 
 Use this for one-off scans or when integrating with external cron/schedulers.`,
-	Example: `  # Scan all containers
+	Example: `  # Scan all containers, including stopped ones
   dlia scan
 
   # Scan with dry-run (no LLM calls, no state changes)
@@ -40,11 +47,45 @@ Use this for one-off scans or when integrating with external cron/schedulers.`,
   # Scan only nginx containers
   dlia scan --filter "nginx.*"
 
+  # Scan a single container by name or ID prefix
+  dlia scan my-app
+
   # Scan last 24 hours of logs, ignoring state
   dlia scan --lookback 24h
 
+  # Scan only containers labeled dlia.scan=true (repeat the flag to require more labels)
+  dlia scan --filter-label dlia.scan=true
+
+  # Skip containers that have exited
+  dlia scan --include-stopped=false
+
+  # Give up after 30 minutes; containers finished so far keep their state
+  dlia scan --timeout 30m
+
+  # Exit with code 3 when a container has critical findings (for CI/monitoring)
+  dlia scan --fail-on-issues critical
+
+  # ...or already on warnings
+  dlia scan --fail-on-issues warning
+
+  # Cron-friendly: only warnings, errors and a one-line summary
+  dlia scan --quiet
+
+  # Show a single progress line with elapsed time instead of per-container output
+  dlia scan --progress
+
+  # See which containers logged anything since the last scan and which were idle
+  dlia scan --changed-only
+
+  # Tune prompts against the real LLM without touching the knowledge base or reports
+  dlia scan --lookback 1h --no-kb --no-reports
+
+  # Analyze only the last 500 lines per container, limited to the past 24 hours
+  dlia scan --lookback 24h --tail 500
+
   # Combine filters with lookback and verbose output
   dlia scan --filter "app-.*" --lookback 1h --verbose`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runScan,
 }
 
@@ -58,36 +99,100 @@ func init() {
 	scanCmd.Flags().String("lookback", "", "duration to look back (e.g., 1h, 24h), ignores state file")
 	scanCmd.Flags().Bool("llmlog", false, "enable logging of all LLM requests and responses to markdown files")
 	scanCmd.Flags().Bool("filter-stats", false, "display filter statistics showing how many log lines were filtered")
+	scanCmd.Flags().StringArray("filter-label", nil, "only scan containers with this label (key=value, repeatable; all must match)")
+	scanCmd.Flags().Bool("include-stopped", true, "also scan stopped/exited containers, e.g. to analyze crash logs (--include-stopped=false scans only running ones)")
+	scanCmd.Flags().Duration("timeout", 0, "abort the scan after this duration (e.g. 30m); finished containers keep their state (0 = no limit)")
+	scanCmd.Flags().String("fail-on-issues", "", "exit with code 3 when findings reach this severity: critical or warning")
+	scanCmd.Flags().String("stream", "", "only analyze this log stream: all, stdout or stderr (overrides docker.stream)")
+	scanCmd.Flags().Int("sample", 0, "scan at most N of the matching containers (0 = all)")
+	scanCmd.Flags().String("sample-mode", sampleModeRecent, "how --sample picks containers: recent (most recently active) or random")
+	scanCmd.Flags().String("order-by", orderByName, "scan order: name, or volume (most log lines in the last hour first, also preferred by --sample and max_containers_per_scan)")
+	scanCmd.Flags().Int("tail", 0, "on first scans and with --lookback, read only the last N lines per container (0 = all)")
+	scanCmd.Flags().Bool("no-kb", false, "analyze without writing the knowledge base (service entries and global summary)")
+	scanCmd.Flags().Bool("no-reports", false, "analyze without writing per-scan reports")
+	scanCmd.Flags().String("output", indexOutputMarkdown, "report index format: md writes reports/index.md, json also writes reports/index.json")
+	scanCmd.Flags().Bool("changed-only", false, "summarize which containers had new logs (active) and which stayed quiet (idle)")
+	scanCmd.Flags().BoolP("quiet", "q", false, "only print warnings, errors and a one-line summary (e.g. for cron)")
+	scanCmd.Flags().Bool("progress", false, "show a single updating progress line (done/total, current container, elapsed) instead of per-container output")
 }
 
-func runScan(cmd *cobra.Command, _ []string) error {
+func runScan(cmd *cobra.Command, args []string) error {
 	cfg = GetConfig()
 	if err := validateConfigOrExit(cfg, "scan"); err != nil {
 		return err
 	}
 
 	scanCfg := newScanConfigFromCmd(cmd)
+	if len(args) > 0 {
+		if args[0] == "" {
+			return errors.New("container argument must not be empty")
+		}
+		if scanCfg.filter != "" || len(scanCfg.labelFilters) > 0 {
+			return errors.New("a container argument cannot be combined with --filter or --filter-label")
+		}
+		scanCfg.container = args[0]
+	}
+	if scanCfg.quiet && scanCfg.verbose {
+		return errors.New("--quiet and --verbose cannot be used together")
+	}
+	if scanCfg.progress && scanCfg.verbose {
+		return errors.New("--progress and --verbose cannot be used together")
+	}
+
+	failSeverity, err := parseFailOnIssues(scanCfg.failOnIssues)
+	if err != nil {
+		return err
+	}
+	if !config.ValidStream(scanCfg.stream) {
+		return fmt.Errorf("invalid --stream %q (expected all, stdout or stderr)", scanCfg.stream)
+	}
+	if scanCfg.sample < 0 {
+		return fmt.Errorf("invalid --sample %d (must be 0 or greater)", scanCfg.sample)
+	}
+	if scanCfg.tail < 0 {
+		return fmt.Errorf("invalid --tail %d (must be 0 or greater)", scanCfg.tail)
+	}
+	if scanCfg.sampleMode != sampleModeRecent && scanCfg.sampleMode != sampleModeRandom {
+		return fmt.Errorf("invalid --sample-mode %q (expected %s or %s)", scanCfg.sampleMode, sampleModeRecent, sampleModeRandom)
+	}
+	if scanCfg.orderBy != orderByName && scanCfg.orderBy != orderByVolume {
+		return fmt.Errorf("invalid --order-by %q (expected %s or %s)", scanCfg.orderBy, orderByName, orderByVolume)
+	}
+	if scanCfg.output != indexOutputMarkdown && scanCfg.output != indexOutputJSON {
+		return fmt.Errorf("invalid --output %q (expected %s or %s)", scanCfg.output, indexOutputMarkdown, indexOutputJSON)
+	}
 
 	// Initialize custom prompt overrides from config (if user provided custom templates).
 	// This must happen before LLM pipeline creation to ensure correct prompts are loaded.
 	prompts.InitPrompts(cfg)
 
-	ctx := context.Background()
+	ctx, cancel := scanContext(scanCfg.timeout)
+	defer cancel()
 
 	lookbackDuration, err := parseLookbackDuration(scanCfg)
 	if err != nil {
 		return err
 	}
 
+	release, err := acquireScanLock(cfg, scanCfg)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	displayScanHeader(cfg, scanCfg, lookbackDuration)
+	flushPendingNotifications(cfg, scanCfg)
 
 	dockerClient, st, err := initializeDockerAndState(ctx, cfg, scanCfg, lookbackDuration)
 	if err != nil {
 		return err
 	}
 	defer dockerClient.Close() //nolint:errcheck // Close error not actionable in defer context
+	if st != nil {
+		defer st.Close() //nolint:errcheck // Close error not actionable in defer context
+	}
 
-	containers, err := getContainersToScan(ctx, dockerClient, scanCfg)
+	containers, err := getContainersToScan(ctx, dockerClient, st, cfg, scanCfg)
 	if err != nil {
 		return err
 	}
@@ -97,7 +202,7 @@ func runScan(cmd *cobra.Command, _ []string) error {
 		return nil
 	}
 
-	fmt.Printf("📦 Found %d container(s) to scan\n\n", len(containers))
+	scanCfg.out.Printf("📦 Found %d container(s) to scan\n\n", len(containers))
 
 	globalResults, scanStats := processContainers(ctx, dockerClient, st, containers, cfg, scanCfg, lookbackDuration)
 
@@ -105,16 +210,87 @@ func runScan(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	writeReportIndex(globalResults, scanStats, cfg, scanCfg)
+
 	if err := updateGlobalSummary(globalResults, cfg, scanCfg); err != nil {
-		fmt.Printf("⚠️  Failed to update global summary: %v\n", err)
+		scanCfg.out.Warnf("⚠️  Failed to update global summary: %v\n", err)
+	}
+
+	// Lookback scans ignore the state, so they neither check nor record alert cooldowns
+	alertState := st
+	if lookbackDuration > 0 {
+		alertState = nil
+	}
+	if err := handleExecutiveSummaryAndNotifications(ctx, globalResults, alertState, cfg, scanCfg); err != nil {
+		scanCfg.out.Warnf("⚠️  Failed to handle executive summary: %v\n", err)
 	}
 
-	if err := handleExecutiveSummaryAndNotifications(ctx, globalResults, cfg, scanCfg); err != nil {
-		fmt.Printf("⚠️  Failed to handle executive summary: %v\n", err)
+	if err := displayNotificationPreview(cfg, scanCfg, scanStats.scannedContainers); err != nil {
+		scanCfg.out.Warnf("⚠️  Failed to preview notification: %v\n", err)
 	}
 
 	displayScanSummary(scanStats, scanCfg, lookbackDuration)
-	return nil
+
+	if scanCfg.quotaExhausted {
+		return &exitError{
+			code: exitCodeQuotaExhausted,
+			err:  fmt.Errorf("LLM quota exhausted: %d container(s) not analyzed, re-run later to resume", scanStats.quotaSkipped),
+		}
+	}
+	if scanCfg.timedOut {
+		return fmt.Errorf("scan timed out after %s: %d container(s) not processed, re-run to resume", scanCfg.timeout, scanStats.timeoutSkipped)
+	}
+	if scanCfg.llmUnavailable {
+		return fmt.Errorf("LLM endpoint unavailable: %d container(s) not analyzed, re-run to resume", scanStats.llmDownSkipped)
+	}
+	return issuesFoundError(globalResults, failSeverity)
+}
+
+// parseFailOnIssues converts the --fail-on-issues value to the severity that fails
+// the scan. An empty value disables the check and yields SeverityHealthy.
+func parseFailOnIssues(value string) (knowledge.Severity, error) {
+	if value == "" {
+		return knowledge.SeverityHealthy, nil
+	}
+
+	severity, err := knowledge.ParseSeverity(value)
+	if err != nil || severity == knowledge.SeverityHealthy {
+		return knowledge.SeverityHealthy, fmt.Errorf("invalid --fail-on-issues value %q (expected warning or critical)", value)
+	}
+	return severity, nil
+}
+
+// issuesFoundError returns an exitCodeIssuesFound error when any analyzed container
+// reaches threshold. A SeverityHealthy threshold disables the check.
+func issuesFoundError(globalResults map[string]*chunking.AnalyzeResult, threshold knowledge.Severity) error {
+	if threshold == knowledge.SeverityHealthy {
+		return nil
+	}
+
+	var affected []string
+	for name, result := range globalResults {
+		if knowledge.ResultSeverity(result) >= threshold {
+			affected = append(affected, name)
+		}
+	}
+	if len(affected) == 0 {
+		return nil
+	}
+
+	sort.Strings(affected)
+	return &exitError{
+		code: exitCodeIssuesFound,
+		err:  fmt.Errorf("issues at or above %s severity found in %d container(s): %s", threshold, len(affected), strings.Join(affected, ", ")),
+	}
+}
+
+// scanContext returns the context bounding the whole scan. A zero timeout keeps the
+// scan unbounded; individual LLM requests are still limited by llm.request_timeout.
+func scanContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
 }
 
 func parseLookbackDuration(scanCfg *scanConfig) (time.Duration, error) {
@@ -133,159 +309,450 @@ func displayScanHeader(cfg *config.Config, scanCfg *scanConfig, lookbackDuration
 		displayVerboseHeader(cfg, scanCfg, lookbackDuration)
 	}
 
-	fmt.Println("🔍 Starting container log scan...")
+	scanCfg.out.Println("🔍 Starting container log scan...")
 
 	if scanCfg.dryRun {
-		fmt.Println("⚠️  DRY RUN MODE - No LLM calls will be made, state will not be updated")
+		scanCfg.out.Println("⚠️  DRY RUN MODE - No LLM calls will be made, state will not be updated")
 	}
-	fmt.Println()
+	if scanCfg.container != "" {
+		scanCfg.out.Printf("🎯 Scanning only %s\n", scanCfg.container)
+	}
+	if scanCfg.orderBy == orderByVolume {
+		scanCfg.out.Println("📊 Scanning containers with the most log lines in the last hour first (--order-by volume)")
+	}
+	if scanCfg.sample > 0 {
+		scanCfg.out.Printf("🎲 Sampling up to %d containers (%s)\n", scanCfg.sample, scanCfg.sampleMode)
+	}
+	if cfg.Docker.MaxContainersPerScan > 0 {
+		scanCfg.out.Printf("🧢 Scanning at most %d containers (docker.max_containers_per_scan)\n", cfg.Docker.MaxContainersPerScan)
+	}
+	scanCfg.out.Println()
 }
 
 func displayVerboseHeader(cfg *config.Config, scanCfg *scanConfig, lookbackDuration time.Duration) {
-	fmt.Println("=== DLIA Container Log Scan ===")
-	fmt.Printf("Dry Run: %v\n", scanCfg.dryRun)
+	scanCfg.out.Println("=== DLIA Container Log Scan ===")
+	scanCfg.out.Printf("Dry Run: %v\n", scanCfg.dryRun)
 	if scanCfg.filter != "" {
-		fmt.Printf("Container Filter: %s\n", scanCfg.filter)
+		scanCfg.out.Printf("Container Filter: %s\n", scanCfg.filter)
+	}
+	if scanCfg.container != "" {
+		scanCfg.out.Printf("Container: %s\n", scanCfg.container)
 	}
 	if lookbackDuration > 0 {
-		fmt.Printf("Lookback Duration: %s\n", lookbackDuration)
+		scanCfg.out.Printf("Lookback Duration: %s\n", lookbackDuration)
+	}
+	scanCfg.out.Printf("LLM Model: %s\n", cfg.LLM.Model)
+	if cfg.Source == config.SourceKubernetes {
+		scanCfg.out.Printf("Log Source: kubernetes (%s)\n", kubernetesTarget(cfg))
+	} else {
+		scanCfg.out.Printf("Docker Socket: %s\n", cfg.Docker.SocketPath)
 	}
-	fmt.Printf("LLM Model: %s\n", cfg.LLM.Model)
-	fmt.Printf("Docker Socket: %s\n", cfg.Docker.SocketPath)
-	fmt.Printf("State File: %s\n", cfg.Output.StateFile)
+	scanCfg.out.Printf("State File: %s\n", cfg.Output.StateFile)
 
 	displayPromptConfiguration()
 }
 
 func displayPromptConfiguration() {
-	fmt.Println("\n📝 Prompt Configuration:")
+	ui.Println("\n📝 Prompt Configuration:")
 	loader := prompts.GetDefaultLoader()
 	if loader == nil {
-		fmt.Println()
+		ui.Println()
 		return
 	}
 
 	sources := loader.GetAllPromptSources()
 	if len(sources) == 0 {
-		fmt.Println("   Using built-in defaults (will be loaded on first use)")
-		fmt.Println()
+		ui.Println("   Using built-in defaults (will be loaded on first use)")
+		ui.Println()
 		return
 	}
 
 	for name, source := range sources {
-		fmt.Printf("   %s: %s\n", name, source)
+		ui.Printf("   %s: %s\n", name, source)
 	}
-	fmt.Println()
+	ui.Println()
 }
 
-func initializeDockerAndState(ctx context.Context, cfg *config.Config, scanCfg *scanConfig, lookbackDuration time.Duration) (docker.Client, *state.State, error) {
-	if scanCfg.verbose {
-		fmt.Println("🐳 Connecting to Docker...")
+func initializeDockerAndState(ctx context.Context, cfg *config.Config, scanCfg *scanConfig, lookbackDuration time.Duration) (docker.Client, state.Backend, error) {
+	dockerClient, err := connectLogSource(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var st state.Backend
+	if lookbackDuration == 0 && !scanCfg.dryRun {
+		st, err = state.Open(cfg.Output.StateBackend, cfg.Output.StateFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load state: %w", err)
+		}
+		slog.Info("loaded state", "backend", cfg.Output.StateBackend, "file", cfg.Output.StateFile, "containers", st.Count())
+	} else {
+		// Lookback/dry-run mode: state tracking disabled; opened read-only so nothing is created or written
+		st, _ = state.OpenReadOnly(cfg.Output.StateBackend, cfg.Output.StateFile) //nolint:errcheck // Intentionally ignoring error in lookback/dry-run mode
+		if lookbackDuration > 0 {
+			slog.Debug("lookback mode, ignoring state file", "lookback", lookbackDuration)
+		}
 	}
-	dockerClient, err := docker.NewClient(cfg.Docker.SocketPath)
+
+	return dockerClient, st, nil
+}
+
+func getContainersToScan(ctx context.Context, dockerClient docker.Client, st state.Backend, cfg *config.Config, scanCfg *scanConfig) ([]docker.Container, error) {
+	if scanCfg.container != "" {
+		container, err := findScanContainer(ctx, dockerClient, scanCfg.container, scanCfg.includeStopped)
+		if err != nil {
+			return nil, err
+		}
+		return []docker.Container{container}, nil
+	}
+
+	labels, err := parseLabelFilters(scanCfg.labelFilters)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create Docker client: %w", err)
+		return nil, err
 	}
 
-	// Verify Docker connection
-	err = dockerClient.Ping(ctx)
+	containers, err := validateAndFilterContainers(ctx, dockerClient, docker.FilterOptions{
+		NamePattern: scanCfg.filter,
+		IncludeAll:  scanCfg.includeStopped,
+		Labels:      labels,
+	}, cfg.Docker)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to connect to Docker daemon: %w\nMake sure Docker is running and you have permission to access the socket", err)
+		return nil, err
 	}
 
-	var st *state.State
-	if lookbackDuration == 0 && !scanCfg.dryRun {
-		st, err = state.Load(cfg.Output.StateFile)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to load state: %w", err)
+	containers = skipLabeledContainers(containers, scanCfg)
+
+	var volumes map[string]int
+	if scanCfg.orderBy == orderByVolume {
+		volumes = countLogVolume(ctx, dockerClient, containers, scanCfg)
+	}
+	containers = limitContainers(containers, st, volumes, cfg.Docker.MaxContainersPerScan, scanCfg)
+	if volumes != nil {
+		return sortByLogVolume(containers, volumes), nil
+	}
+	return sortByName(containers), nil
+}
+
+// skipLabeledContainers drops containers labeled dlia.skip=true.
+func skipLabeledContainers(containers []docker.Container, scanCfg *scanConfig) []docker.Container {
+	kept := make([]docker.Container, 0, len(containers))
+	for _, c := range containers {
+		if overrides, _ := docker.ParseLabelOverrides(c.Labels); overrides.Skip {
+			scanCfg.out.Printf("⏭️  Skipping %s (label %s=true)\n", c.Name, docker.LabelSkip)
+			continue
 		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// containerOverrides reads the dlia.* labels of a container, printing ignored labels
+// in verbose mode, and returns them with the catch-up window to use for this container.
+// The dlia.lookback label replaces docker.max_catchup_window: it only bounds how far
+// back an incremental scan reads, so the container's state is still saved.
+func containerOverrides(container docker.Container, scanCfg *scanConfig, maxCatchupWindow time.Duration) (docker.LabelOverrides, time.Duration) {
+	overrides, warnings := docker.ParseLabelOverrides(container.Labels)
+	if scanCfg.verbose {
+		for _, w := range warnings {
+			scanCfg.out.Printf("        ⚠️  %s\n", w)
+		}
+	}
+
+	if overrides.Lookback > 0 {
 		if scanCfg.verbose {
-			fmt.Printf("📊 Loaded state with %d container(s)\n", st.Count())
+			scanCfg.out.Printf("        🏷️  Catch-up window %s from label %s\n", overrides.Lookback, docker.LabelLookback)
 		}
-	} else {
-		// Lookback/dry-run mode: state tracking disabled, always starts fresh
-		st, _ = state.Load(cfg.Output.StateFile) //nolint:errcheck // Intentionally ignoring error in lookback/dry-run mode
-		if scanCfg.verbose && lookbackDuration > 0 {
-			fmt.Printf("📊 Using lookback mode, ignoring state file\n")
+		maxCatchupWindow = overrides.Lookback
+	}
+	if overrides.Instructions != "" && scanCfg.verbose {
+		scanCfg.out.Printf("        🏷️  Extra instructions from label %s\n", docker.LabelInstructions)
+	}
+	return overrides, maxCatchupWindow
+}
+
+// limitContainers applies --sample and then docker.max_containers_per_scan to the
+// matched containers. Capping keeps the most recently active containers, i.e. those
+// with the newest analyzed log line in state; untracked containers come last. With
+// volumes (--order-by volume) the containers with the most log lines are kept instead.
+func limitContainers(containers []docker.Container, st state.Backend, volumes map[string]int, maxContainers int, scanCfg *scanConfig) []docker.Container {
+	matched := len(containers)
+	prioritize := func(cs []docker.Container) []docker.Container { return sortByRecentActivity(cs, st) }
+	priority := "most recently active"
+	if volumes != nil {
+		prioritize = func(cs []docker.Container) []docker.Container { return sortByLogVolume(cs, volumes) }
+		priority = "with the most log lines"
+	}
+
+	if scanCfg.sample > 0 && len(containers) > scanCfg.sample {
+		if scanCfg.sampleMode == sampleModeRandom {
+			containers = slices.Clone(containers)
+			rand.Shuffle(len(containers), func(i, j int) { containers[i], containers[j] = containers[j], containers[i] })
+		} else {
+			containers = prioritize(containers)
 		}
+		containers = containers[:scanCfg.sample]
+		scanCfg.out.Printf("🎲 Sampled %d of %d matching containers (%s)\n", len(containers), matched, scanCfg.sampleMode)
 	}
 
-	return dockerClient, st, nil
+	if maxContainers > 0 && len(containers) > maxContainers {
+		scanCfg.out.Printf("🧢 %d containers exceed docker.max_containers_per_scan (%d); scanning only the %d %s\n",
+			len(containers), maxContainers, maxContainers, priority)
+		containers = prioritize(containers)[:maxContainers]
+	}
+
+	return containers
+}
+
+// sortByRecentActivity returns a copy of containers ordered by their last scanned log
+// time, newest first. Containers unknown to st keep their relative order at the end.
+func sortByRecentActivity(containers []docker.Container, st state.Backend) []docker.Container {
+	sorted := slices.Clone(containers)
+	if st == nil {
+		return sorted
+	}
+
+	lastActive := make(map[string]time.Time, len(sorted))
+	for _, c := range sorted {
+		lastActive[c.ID], _ = st.GetLastScan(c.ID)
+	}
+	slices.SortStableFunc(sorted, func(a, b docker.Container) int {
+		return lastActive[b.ID].Compare(lastActive[a.ID])
+	})
+	return sorted
+}
+
+// volumeWindow is how far back --order-by volume counts log lines (the last hour).
+const volumeWindow = time.Hour
+
+// countLogVolume counts the log lines each container wrote within volumeWindow, keyed
+// by container ID. It only reads logs, no LLM is involved. A container whose logs
+// cannot be read counts as 0 lines; its scan reports the error.
+func countLogVolume(ctx context.Context, dockerClient docker.Client, containers []docker.Container, scanCfg *scanConfig) map[string]int {
+	volumes := make(map[string]int, len(containers))
+	for _, c := range containers {
+		logs, err := dockerClient.ReadLogsLookback(ctx, c.ID, volumeWindow)
+		if err != nil {
+			if scanCfg.verbose {
+				scanCfg.out.Printf("⚠️  Could not count log lines of %s: %v\n", c.Name, err)
+			}
+			continue
+		}
+		volumes[c.ID] = len(logs)
+	}
+	return volumes
 }
 
-func getContainersToScan(ctx context.Context, dockerClient docker.Client, scanCfg *scanConfig) ([]docker.Container, error) {
-	return validateAndFilterContainers(ctx, dockerClient, scanCfg.filter)
+// sortByLogVolume returns a copy of containers ordered by their log line count in
+// volumes, highest first. Containers with equal counts keep their relative order.
+func sortByLogVolume(containers []docker.Container, volumes map[string]int) []docker.Container {
+	sorted := slices.Clone(containers)
+	slices.SortStableFunc(sorted, func(a, b docker.Container) int {
+		return volumes[b.ID] - volumes[a.ID]
+	})
+	return sorted
+}
+
+// sortByName returns a copy of containers ordered by name.
+func sortByName(containers []docker.Container) []docker.Container {
+	sorted := slices.Clone(containers)
+	slices.SortStableFunc(sorted, func(a, b docker.Container) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return sorted
 }
 
 func displayNoContainersFound(scanCfg *scanConfig) {
-	fmt.Println("ℹ️  No containers found")
+	if scanCfg.quiet {
+		displayQuietSummary(scanStats{}, scanCfg.changedOnly)
+		return
+	}
+
+	scanCfg.out.Println("ℹ️  No containers found")
 	if scanCfg.filter != "" {
-		fmt.Printf("   (with filter: %s)\n", scanCfg.filter)
+		scanCfg.out.Printf("   (with filter: %s)\n", scanCfg.filter)
+	}
+	if len(scanCfg.labelFilters) > 0 {
+		scanCfg.out.Printf("   (with labels: %s)\n", strings.Join(scanCfg.labelFilters, ", "))
 	}
 }
 
 type scanStats struct {
 	totalLogs         int
 	scannedContainers int
+	quotaSkipped      int // Containers left unanalyzed because the LLM quota ran out
+	timeoutSkipped    int // Containers left unprocessed because the scan timeout expired
+	llmDownSkipped    int // Containers left unanalyzed because the LLM circuit breaker opened
+
+	activeContainers []string // Containers with new log lines this run, in scan order
+	idleContainers   []string // Containers without new log lines this run, in scan order
 }
 
-func processContainers(ctx context.Context, dockerClient docker.Client, st *state.State, containers []docker.Container, cfg *config.Config, scanCfg *scanConfig, lookbackDuration time.Duration) (map[string]*chunking.AnalyzeResult, scanStats) {
+func processContainers(ctx context.Context, dockerClient docker.Client, st state.Backend, containers []docker.Container, cfg *config.Config, scanCfg *scanConfig, lookbackDuration time.Duration) (map[string]*chunking.AnalyzeResult, scanStats) {
 	globalResults := make(map[string]*chunking.AnalyzeResult, len(containers))
 	stats := scanStats{}
 	// Lazy initialization: pipeline is created on first use to avoid unnecessary
 	// LLM client setup if all containers are skipped (e.g., no new logs).
 	var llmPipeline *chunking.Pipeline
+	stream := selectedStream(cfg, scanCfg)
+
+	if scanCfg.progress && !scanCfg.quiet {
+		bar := newProgressBar(ui.Writer(os.Stdout), len(containers), progressOnTerminal(scanCfg))
+		out := scanCfg.out
+		scanCfg.out = printer{progress: bar}
+		defer func() {
+			bar.Finish()
+			scanCfg.out = out
+		}()
+	}
 
 	for i, container := range containers {
-		fmt.Printf("[%d/%d] Processing: %s (ID: %s)\n", i+1, len(containers), container.Name, container.ID[:12])
+		if scanCfg.quotaExhausted {
+			// No further LLM calls; leave state untouched so the next run picks these up
+			stats.quotaSkipped += len(containers) - i
+			break
+		}
+		if scanCfg.llmUnavailable {
+			stats.llmDownSkipped += len(containers) - i
+			break
+		}
+		if ctx.Err() != nil {
+			// Deadline reached: keep state of the remaining containers so the next run resumes them
+			scanCfg.timedOut = true
+			stats.timeoutSkipped += len(containers) - i
+			break
+		}
 
-		since := determineLogStartTime(st, container.ID, scanCfg, lookbackDuration)
+		if scanCfg.out.progress != nil {
+			scanCfg.out.progress.Start(i, container.Name)
+		}
+		scanCfg.out.Printf("[%d/%d] Processing: %s (ID: %s)\n", i+1, len(containers), container.Name, shortContainerID(container.ID, cfg.Output.ShortIDLength))
+		if isStoppedContainer(container) {
+			scanCfg.out.Printf("        ⏹️  Container is not running (state: %s)\n", container.State)
+		}
 
-		logs, err := processContainerLogs(ctx, dockerClient, container.ID, since)
+		overrides, catchupWindow := containerOverrides(container, scanCfg, cfg.Docker.MaxCatchupWindow)
+
+		since := determineLogStartTime(st, container.ID, scanCfg, lookbackDuration, catchupWindow)
+		cursor := determineLogCursor(st, container.ID, since, lookbackDuration)
+		readAt := time.Now()
+
+		logs, err := processContainerLogs(ctx, dockerClient, container.ID, cursor, initialFetchTail(st, container.ID, scanCfg, lookbackDuration))
 		if err != nil {
-			fmt.Printf("        ⚠️  %v\n", err)
+			scanCfg.out.Warnf("        ⚠️  %v\n", err)
+			if ctx.Err() != nil {
+				scanCfg.timedOut = true
+				stats.timeoutSkipped += len(containers) - i
+				break
+			}
 			continue
 		}
 
 		if len(logs) == 0 {
-			fmt.Printf("        ℹ️  No new logs\n\n")
+			stats.idleContainers = append(stats.idleContainers, container.Name)
+			scanCfg.out.Printf("        ℹ️  No new logs\n\n")
+			skipCatchupGap(st, container, since, readAt, scanCfg, lookbackDuration)
 			continue
 		}
 
-		fmt.Printf("        📝 Found %d new log entries\n", len(logs))
+		scanCfg.out.Printf("        📝 Found %d new log entries\n", len(logs))
 		stats.totalLogs += len(logs)
+		stats.activeContainers = append(stats.activeContainers, container.Name)
 
 		displayLogsPreview(logs, scanCfg)
 
-		result := processLLMAnalysis(ctx, container.Name, logs, cfg, scanCfg, &llmPipeline)
+		// Lines already analyzed in recent scans are skipped, but all read lines advance the cursor
+		newLogs := filterSeenLogs(st, container.ID, logs, cfg, scanCfg, lookbackDuration)
+		if len(newLogs) == 0 {
+			scanCfg.out.Printf("        ℹ️  All log lines were already analyzed in recent scans\n\n")
+			updateContainerState(st, container, logs, scanCfg, lookbackDuration)
+			stats.scannedContainers++
+			continue
+		}
+
+		// Lines of the excluded stream still advance the cursor like seen lines
+		newLogs, streamStats := chunking.FilterStream(newLogs, stream)
+		if len(newLogs) == 0 {
+			scanCfg.out.Printf("        ℹ️  No new log lines on the %s stream\n\n", stream)
+			updateContainerState(st, container, logs, scanCfg, lookbackDuration)
+			stats.scannedContainers++
+			continue
+		}
+
+		analysisLogs := includeContainerEvents(ctx, dockerClient, container.ID, since, newLogs, cfg, scanCfg)
+		result := processLLMAnalysis(ctx, container.Name, analysisLogs, overrides.Instructions, cfg, scanCfg, &llmPipeline)
+		if scanCfg.quotaExhausted {
+			stats.quotaSkipped++
+			continue
+		}
+		if scanCfg.llmUnavailable {
+			stats.llmDownSkipped++
+			continue
+		}
+		if ctx.Err() != nil {
+			// Analysis was cut short by the deadline; do not advance past unanalyzed logs
+			scanCfg.timedOut = true
+			stats.timeoutSkipped += len(containers) - i
+			break
+		}
 		if result != nil {
-			handleReportingAndKnowledge(container.Name, result, logs, cfg, scanCfg)
+			result.FilterStats.StdoutDropped = streamStats.StdoutDropped
+			result.FilterStats.StderrDropped = streamStats.StderrDropped
+			result.ContainerState = container.State
+			result.ComposeProject = container.Labels[docker.ComposeProjectLabel]
+			handleReportingAndKnowledge(container.Name, result, newLogs, cfg, scanCfg)
 
 			globalResults[container.Name] = result
 		}
 
 		updateContainerState(st, container, logs, scanCfg, lookbackDuration)
+		if result != nil {
+			recordLogFingerprints(st, container.ID, newLogs, cfg, scanCfg, lookbackDuration)
+		}
 
 		stats.scannedContainers++
-		fmt.Println()
+		scanCfg.out.Println()
 	}
 
 	return globalResults, stats
 }
 
-func determineLogStartTime(st *state.State, containerID string, scanCfg *scanConfig, lookbackDuration time.Duration) time.Time {
+// selectedStream returns the log stream to analyze: --stream if given, else docker.stream.
+func selectedStream(cfg *config.Config, scanCfg *scanConfig) string {
+	if scanCfg.stream != "" {
+		return scanCfg.stream
+	}
+	if cfg.Docker.Stream != "" {
+		return cfg.Docker.Stream
+	}
+	return config.StreamAll
+}
+
+// isStoppedContainer reports whether Docker lists the container as anything other than running.
+// An empty state (unknown) is treated as running.
+func isStoppedContainer(container docker.Container) bool {
+	return container.State != "" && container.State != "running"
+}
+
+func determineLogStartTime(st state.Backend, containerID string, scanCfg *scanConfig, lookbackDuration, maxCatchupWindow time.Duration) time.Time {
 	if lookbackDuration > 0 {
 		since := time.Now().Add(-lookbackDuration)
 		if scanCfg.verbose {
-			fmt.Printf("        Reading logs from: %s (lookback: %s)\n", since.Format(time.RFC3339), scanCfg.lookback)
+			scanCfg.out.Printf("        Reading logs from: %s (lookback: %s)\n", since.Format(time.RFC3339), lookbackDuration)
 		}
 		return since
 	}
 
 	// Use state
 	if lastScan, exists := st.GetLastScan(containerID); exists {
+		// After downtime, read at most docker.max_catchup_window so one scan does not
+		// have to analyze everything logged while DLIA was not running
+		if earliest := time.Now().Add(-maxCatchupWindow); maxCatchupWindow > 0 && lastScan.Before(earliest) {
+			scanCfg.out.Warnf("        ⚠️  Last scan was %s ago; skipping logs older than %s (docker.max_catchup_window or label dlia.lookback)\n",
+				time.Since(lastScan).Round(time.Second), maxCatchupWindow)
+			return earliest
+		}
 		if scanCfg.verbose {
-			fmt.Printf("        Reading logs since: %s (from state)\n", lastScan.Format(time.RFC3339))
+			scanCfg.out.Printf("        Reading logs since: %s (from state)\n", lastScan.Format(time.RFC3339))
 		}
 		return lastScan
 	}
@@ -297,107 +764,220 @@ func determineLogStartTime(st *state.State, containerID string, scanCfg *scanCon
 	// After the first scan, subsequent runs process only new logs incrementally.
 	since := time.Now().Add(-1 * time.Hour)
 	if scanCfg.verbose {
-		fmt.Printf("        First scan, reading logs from: %s (last 1 hour)\n", since.Format(time.RFC3339))
+		scanCfg.out.Printf("        First scan, reading logs from: %s (last 1 hour)\n", since.Format(time.RFC3339))
 	}
 	return since
 }
 
+// initialFetchTail returns the --tail line count when this is an initial fetch
+// (lookback mode or the first scan of the container) and 0 otherwise. Incremental
+// scans always read everything after the stored cursor so no lines are skipped.
+func initialFetchTail(st state.Backend, containerID string, scanCfg *scanConfig, lookbackDuration time.Duration) int {
+	if scanCfg.tail <= 0 {
+		return 0
+	}
+	if lookbackDuration == 0 {
+		if _, exists := st.GetLastScan(containerID); exists {
+			return 0
+		}
+	}
+	if scanCfg.verbose {
+		scanCfg.out.Printf("        Reading at most the last %d lines\n", scanCfg.tail)
+	}
+	return scanCfg.tail
+}
+
+// determineLogCursor builds the read cursor for a container starting at since.
+// In incremental mode the cursor stored by the previous scan is reused when it
+// points at the same instant, so boundary lines that were already analyzed are
+// skipped instead of being read (and analyzed) a second time.
+func determineLogCursor(st state.Backend, containerID string, since time.Time, lookbackDuration time.Duration) docker.LogCursor {
+	cursor := docker.LogCursor{Timestamp: since}
+	if lookbackDuration > 0 {
+		return cursor
+	}
+
+	stored, exists := st.GetLogCursor(containerID)
+	if !exists {
+		return cursor
+	}
+
+	parsed, err := docker.ParseLogCursor(stored)
+	if err != nil || !parsed.Timestamp.Equal(since) {
+		// Unknown or legacy cursor format: fall back to timestamp-only reads
+		return cursor
+	}
+
+	return parsed
+}
+
+// skipCatchupGap advances the state of a container to readAt when the log start was
+// clamped by docker.max_catchup_window and no logs were found, so the next scan is
+// incremental again instead of warning about the same gap. Otherwise the state is
+// advanced by updateContainerState as usual.
+func skipCatchupGap(st state.Backend, container docker.Container, since, readAt time.Time, scanCfg *scanConfig, lookbackDuration time.Duration) {
+	if lookbackDuration > 0 || scanCfg.dryRun {
+		return
+	}
+	if lastScan, exists := st.GetLastScan(container.ID); exists && lastScan.Before(since) {
+		st.UpdateContainer(container.ID, container.Name, readAt, "")
+	}
+}
+
 func displayLogsPreview(logs []docker.LogEntry, scanCfg *scanConfig) {
 	if scanCfg.verbose && len(logs) > 0 {
-		fmt.Printf("        \n")
+		scanCfg.out.Printf("        \n")
 		displayCount := len(logs)
 		if displayCount > 10 {
 			displayCount = 10
 		}
 		for j := 0; j < displayCount; j++ {
 			entry := logs[j]
-			fmt.Printf("        [%s] %s\n", entry.Timestamp, entry.Message)
+			scanCfg.out.Printf("        [%s] %s\n", entry.Timestamp, entry.Message)
 		}
 		if len(logs) > 10 {
-			fmt.Printf("        ... (%d more lines)\n", len(logs)-10)
+			scanCfg.out.Printf("        ... (%d more lines)\n", len(logs)-10)
 		}
-		fmt.Printf("        \n")
+		scanCfg.out.Printf("        \n")
 	}
 }
 
 func handleReportingAndKnowledge(containerName string, result *chunking.AnalyzeResult, logs []docker.LogEntry, cfg *config.Config, scanCfg *scanConfig) {
-	_, err := generateAndSaveReport(containerName, result, logs, cfg, scanCfg)
-	if err != nil {
-		fmt.Printf("        ⚠️  Failed to save report: %v\n", err)
+	if scanCfg.dryRun {
+		return
+	}
+
+	if !scanCfg.noReports {
+		reportPath, err := generateAndSaveReport(containerName, result, logs, cfg, scanCfg)
+		if err != nil {
+			scanCfg.out.Warnf("        ⚠️  Failed to save report: %v\n", err)
+		}
+		result.ReportPath = reportPath
 	}
 
+	if scanCfg.noKB {
+		return
+	}
 	if err := knowledge.UpdateServiceKB(containerName, result, cfg); err != nil {
-		fmt.Printf("        ⚠️  Failed to update knowledge base: %v\n", err)
+		scanCfg.out.Warnf("        ⚠️  Failed to update knowledge base: %v\n", err)
 	} else if scanCfg.verbose {
-		fmt.Printf("        🧠 Knowledge base updated\n")
+		scanCfg.out.Printf("        🧠 Knowledge base updated\n")
 	}
 }
 
-func updateContainerState(st *state.State, container docker.Container, logs []docker.LogEntry, scanCfg *scanConfig, lookbackDuration time.Duration) {
+func updateContainerState(st state.Backend, container docker.Container, logs []docker.LogEntry, scanCfg *scanConfig, lookbackDuration time.Duration) {
 	if len(logs) == 0 {
 		return
 	}
 
-	latestTime, err := docker.GetLatestLogTime(logs)
+	cursor, err := docker.NewLogCursor(logs)
 	if err != nil {
 		if scanCfg.verbose {
-			fmt.Printf("        ⚠️  Could not parse latest timestamp: %v\n", err)
+			scanCfg.out.Warnf("        ⚠️  Could not parse latest timestamp: %v\n", err)
 		}
 		return
 	}
+	latestTime := cursor.Timestamp
 
 	if scanCfg.dryRun {
-		fmt.Printf("        🔸 DRY RUN: Would update state to: %s\n", latestTime.Format(time.RFC3339))
+		scanCfg.out.Printf("        🔸 DRY RUN: Would update state to: %s\n", latestTime.Format(time.RFC3339))
 	} else if lookbackDuration == 0 {
-		st.UpdateContainer(container.ID, container.Name, latestTime, "")
+		st.UpdateContainer(container.ID, container.Name, latestTime, cursor.String())
 		if scanCfg.verbose {
-			fmt.Printf("        ✅ Updated state to: %s\n", latestTime.Format(time.RFC3339))
+			scanCfg.out.Printf("        ✅ Updated state to: %s\n", latestTime.Format(time.RFC3339))
 		}
 	}
 }
 
-func saveStateIfNeeded(st *state.State, scanCfg *scanConfig, lookbackDuration time.Duration) error {
+// filterSeenLogs drops lines whose normalized fingerprint was analyzed in the previous
+// llm.dedup_across_scans scans. Disabled in lookback mode, which ignores state.
+func filterSeenLogs(st state.Backend, containerID string, logs []docker.LogEntry, cfg *config.Config, scanCfg *scanConfig, lookbackDuration time.Duration) []docker.LogEntry {
+	if cfg.LLM.DedupAcrossScans <= 0 || lookbackDuration > 0 {
+		return logs
+	}
+
+	newLogs, skipped := chunking.FilterSeen(logs, st.SeenFingerprints(containerID))
+	if skipped > 0 {
+		scanCfg.out.Printf("        🔁 Skipped %d line(s) already analyzed in the last %d scan(s)\n", skipped, cfg.LLM.DedupAcrossScans)
+	}
+
+	return newLogs
+}
+
+// recordLogFingerprints remembers the analyzed lines so later scans can skip them.
+func recordLogFingerprints(st state.Backend, containerID string, logs []docker.LogEntry, cfg *config.Config, scanCfg *scanConfig, lookbackDuration time.Duration) {
+	if cfg.LLM.DedupAcrossScans <= 0 || scanCfg.dryRun || lookbackDuration > 0 {
+		return
+	}
+
+	st.RecordFingerprints(containerID, chunking.Fingerprints(logs), cfg.LLM.DedupAcrossScans)
+}
+
+func saveStateIfNeeded(st state.Backend, scanCfg *scanConfig, lookbackDuration time.Duration) error {
 	if !scanCfg.dryRun && lookbackDuration == 0 {
 		if err := st.Save(); err != nil {
 			return fmt.Errorf("failed to save state: %w", err)
 		}
 		if scanCfg.verbose {
-			fmt.Println("💾 State saved successfully")
+			scanCfg.out.Println("💾 State saved successfully")
 		}
 	}
 	return nil
 }
 
 func updateGlobalSummary(globalResults map[string]*chunking.AnalyzeResult, cfg *config.Config, scanCfg *scanConfig) error {
-	if !scanCfg.dryRun && len(globalResults) > 0 {
+	if !scanCfg.dryRun && !scanCfg.noKB && len(globalResults) > 0 {
 		if err := knowledge.UpdateGlobalSummary(globalResults, cfg); err != nil {
 			return err
 		}
 		if scanCfg.verbose {
-			fmt.Println("🌍 Global summary updated")
+			scanCfg.out.Println("🌍 Global summary updated")
 		}
 	}
 	return nil
 }
 
-func handleExecutiveSummaryAndNotifications(ctx context.Context, globalResults map[string]*chunking.AnalyzeResult, cfg *config.Config, scanCfg *scanConfig) error {
+func handleExecutiveSummaryAndNotifications(ctx context.Context, globalResults map[string]*chunking.AnalyzeResult, st state.Backend, cfg *config.Config, scanCfg *scanConfig) error {
 	if scanCfg.dryRun || len(globalResults) == 0 {
 		return nil
 	}
 
+	if scanCfg.quotaExhausted {
+		scanCfg.out.Println("⏭️  Skipping executive summary and notification (LLM quota exhausted)")
+		return nil
+	}
+
+	if scanCfg.timedOut {
+		scanCfg.out.Println("⏭️  Skipping executive summary and notification (scan timeout reached)")
+		return nil
+	}
+
+	if scanCfg.llmUnavailable {
+		scanCfg.out.Println("⏭️  Skipping executive summary and notification (LLM endpoint unavailable)")
+		return nil
+	}
+
 	llmPipeline, err := initializeLLMPipeline(cfg, scanCfg)
 	if err != nil {
 		return fmt.Errorf("failed to initialize LLM for executive summary: %w", err)
 	}
 
 	if scanCfg.verbose {
-		fmt.Println("📊 Generating executive summary...")
+		scanCfg.out.Println("📊 Generating executive summary...")
 	}
 
 	containerAnalyses := make(map[string]string, len(globalResults))
+	statuses := make([]notification.ContainerStatus, 0, len(globalResults))
 	for name, result := range globalResults {
 		containerAnalyses[name] = result.Analysis
+		statuses = append(statuses, notification.ContainerStatus{
+			Name:       name,
+			Severity:   knowledge.ResultSeverity(result),
+			Analysis:   result.Analysis,
+			TokensUsed: result.TokensUsed,
+		})
 	}
+	notification.SortContainerStatuses(statuses)
 
 	execSummary, err := generateExecutiveSummary(ctx, llmPipeline, containerAnalyses, cfg)
 	if err != nil {
@@ -405,13 +985,16 @@ func handleExecutiveSummaryAndNotifications(ctx context.Context, globalResults m
 	}
 
 	if scanCfg.verbose {
-		fmt.Println("✅ Executive summary generated")
+		scanCfg.out.Println("✅ Executive summary generated")
 	}
 
-	return sendNotificationIfNeeded(execSummary, len(globalResults), containerAnalyses, cfg, scanCfg)
+	return sendNotificationIfNeeded(execSummary, len(globalResults), statuses, st, cfg, scanCfg)
 }
 
-func sendNotificationIfNeeded(execSummary string, resultCount int, containerAnalyses map[string]string, cfg *config.Config, scanCfg *scanConfig) error {
+// sendNotificationIfNeeded sends the run-level notification and, if configured, per-container
+// alerts. statuses holds the classified containers, most severe first. st tracks the
+// per-container alert cooldown; nil disables it.
+func sendNotificationIfNeeded(execSummary string, resultCount int, statuses []notification.ContainerStatus, st state.Backend, cfg *config.Config, scanCfg *scanConfig) error {
 	notifier, err := notification.NewNotifier(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to initialize notifier: %w", err)
@@ -421,35 +1004,164 @@ func sendNotificationIfNeeded(execSummary string, resultCount int, containerAnal
 		return nil
 	}
 
+	// A failed per-container alert must not suppress the run summary
+	var alertErr error
+	if cfg.Notification.PerContainer {
+		alertErr = sendContainerAlerts(notifier, statuses, newAlertCooldown(st, cfg.Notification.CooldownPerContainer), scanCfg)
+	}
+
+	severity := knowledge.SeverityHealthy
+	for _, status := range statuses {
+		severity = max(severity, status.Severity)
+	}
+	if !notifier.ShouldNotify(severity) {
+		if scanCfg.verbose {
+			scanCfg.out.Printf("🔕 Notification skipped (scan severity %s is below notification.min_severity %s)\n", severity, cfg.Notification.MinSeverity)
+		}
+		return alertErr
+	}
+
 	if scanCfg.verbose {
-		fmt.Println("📧 Sending notification...")
+		scanCfg.out.Println("📧 Sending notification...")
 	}
 
-	issuesFound := detectIssues(containerAnalyses)
+	if err := notifier.SendScanSummary(execSummary, resultCount, severity, statuses...); err != nil {
+		return errors.Join(alertErr, fmt.Errorf("notification failed: %w", err))
+	}
+
+	scanCfg.out.Println("✅ Notification sent successfully")
+	return alertErr
+}
 
-	if err := notifier.SendScanSummary(execSummary, resultCount, issuesFound); err != nil {
-		return fmt.Errorf("notification failed: %w", err)
+// dryRunSummaryPlaceholder stands in for the executive summary, which is not generated in dry-run mode.
+const dryRunSummaryPlaceholder = "(executive summary will be generated by the LLM)"
+
+// displayNotificationPreview prints the notification message that a real scan would send.
+// It only runs in dry-run mode and when notifications are enabled.
+func displayNotificationPreview(cfg *config.Config, scanCfg *scanConfig, containerCount int) error {
+	if !scanCfg.dryRun {
+		return nil
 	}
 
-	fmt.Println("✅ Notification sent successfully")
+	notifier, err := notification.NewNotifier(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize notifier: %w", err)
+	}
+
+	if !notifier.IsEnabled() {
+		return nil
+	}
+
+	scanCfg.out.Println("🔸 DRY RUN: Notification preview (not sent):")
+	scanCfg.out.Println("───────────────────────────────────────")
+	scanCfg.out.Println(notifier.Preview(dryRunSummaryPlaceholder, containerCount, knowledge.SeverityHealthy))
+	scanCfg.out.Println("───────────────────────────────────────")
+	scanCfg.out.Println()
+	return nil
+}
+
+// sendContainerAlerts sends a dedicated alert for every container whose analysis
+// was flagged with warnings or issues, except containers still in their alert cooldown.
+// All containers are attempted even if one fails.
+func sendContainerAlerts(notifier *notification.Notifier, statuses []notification.ContainerStatus, cooldown *alertCooldown, scanCfg *scanConfig) error {
+	sorted := slices.Clone(statuses)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var errs []error
+	for _, status := range sorted {
+		name, severity := status.Name, status.Severity
+		if severity == knowledge.SeverityHealthy || !notifier.ShouldNotify(severity) {
+			continue
+		}
+
+		if since, ok := cooldown.Active(name, severity); ok {
+			if scanCfg.verbose {
+				scanCfg.out.Printf("🔕 %s alert for %s skipped (last alert %s ago, notification.cooldown_per_container %s)\n",
+					severity, name, since.Round(time.Second), cooldown.period)
+			}
+			continue
+		}
+
+		if scanCfg.verbose {
+			scanCfg.out.Printf("📧 Sending %s alert for %s...\n", severity, name)
+		}
+
+		if err := notifier.SendContainerAlert(name, status.Analysis, severity); err != nil {
+			errs = append(errs, fmt.Errorf("alert for %s: %w", name, err))
+			continue
+		}
+		cooldown.Record(name, severity)
+	}
+
+	if err := cooldown.Save(); err != nil {
+		scanCfg.out.Warnf("⚠️  Failed to save alert cooldown: %v\n", err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("container notification failed: %w", errors.Join(errs...))
+	}
 	return nil
 }
 
 func displayScanSummary(stats scanStats, scanCfg *scanConfig, lookbackDuration time.Duration) {
-	fmt.Println("=" + "═══════════════════════════════════════")
-	fmt.Printf("✅ Scan complete!\n")
-	fmt.Printf("   Containers scanned: %d\n", stats.scannedContainers)
-	fmt.Printf("   Total log entries: %d\n", stats.totalLogs)
+	if scanCfg.quiet {
+		displayQuietSummary(stats, scanCfg.changedOnly)
+		return
+	}
+
+	scanCfg.out.Println("=" + "═══════════════════════════════════════")
+	scanCfg.out.Printf("✅ Scan complete!\n")
+	scanCfg.out.Printf("   Containers scanned: %d\n", stats.scannedContainers)
+	scanCfg.out.Printf("   Total log entries: %d\n", stats.totalLogs)
+	if stats.quotaSkipped > 0 {
+		scanCfg.out.Printf("   🛑 Skipped due to LLM quota: %d container(s) (state kept, re-run later)\n", stats.quotaSkipped)
+	}
+	if stats.timeoutSkipped > 0 {
+		scanCfg.out.Printf("   ⏱️  Not processed before --timeout %s: %d container(s) (state kept, re-run to resume)\n", scanCfg.timeout, stats.timeoutSkipped)
+	}
+	if stats.llmDownSkipped > 0 {
+		scanCfg.out.Printf("   🔌 Skipped, LLM endpoint unavailable: %d container(s) (state kept, re-run to resume)\n", stats.llmDownSkipped)
+	}
+	if scanCfg.changedOnly {
+		scanCfg.out.Printf("   🟢 Active: %d %s\n", len(stats.activeContainers), containerList(stats.activeContainers))
+		scanCfg.out.Printf("   💤 Idle: %d %s\n", len(stats.idleContainers), containerList(stats.idleContainers))
+	}
 
 	switch {
 	case scanCfg.dryRun:
-		fmt.Printf("   State: Not modified (dry-run)\n")
+		scanCfg.out.Printf("   State: Not modified (dry-run)\n")
 	case lookbackDuration > 0:
-		fmt.Printf("   State: Not modified (lookback mode)\n")
+		scanCfg.out.Printf("   State: Not modified (lookback mode)\n")
 	default:
-		fmt.Printf("   State: Updated\n")
+		scanCfg.out.Printf("   State: Updated\n")
 	}
-	fmt.Println()
+	scanCfg.out.Println()
+}
+
+// containerList formats container names for the summary, e.g. "(api, db)".
+func containerList(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// displayQuietSummary prints the scan result as a single line for --quiet.
+func displayQuietSummary(stats scanStats, changedOnly bool) {
+	line := fmt.Sprintf("✅ Scan complete: %d container(s) scanned, %d log entries", stats.scannedContainers, stats.totalLogs)
+	if stats.quotaSkipped > 0 {
+		line += fmt.Sprintf(", %d skipped (LLM quota)", stats.quotaSkipped)
+	}
+	if stats.timeoutSkipped > 0 {
+		line += fmt.Sprintf(", %d not processed (timeout)", stats.timeoutSkipped)
+	}
+	if stats.llmDownSkipped > 0 {
+		line += fmt.Sprintf(", %d skipped (LLM unavailable)", stats.llmDownSkipped)
+	}
+	if changedOnly {
+		line += fmt.Sprintf(", %d active, %d idle", len(stats.activeContainers), len(stats.idleContainers))
+	}
+	ui.Println(line)
 }
 
 func generateExecutiveSummary(ctx context.Context, _ *chunking.Pipeline, containerAnalyses map[string]string, cfg *config.Config) (string, error) {
@@ -460,7 +1172,7 @@ func generateExecutiveSummary(ctx context.Context, _ *chunking.Pipeline, contain
 		return "", fmt.Errorf("failed to load executive summary prompt: %w", err)
 	}
 
-	llmClient := llm.NewClient(cfg.LLM.BaseURL, cfg.LLM.APIKey, cfg.LLM.Model)
+	llmClient := llm.NewClientWithOptions(cfg.LLM.BaseURL, cfg.LLM.APIKey, cfg.LLM.Model, llmClientOptions(cfg))
 
 	systemPrompt, err := promptLoader.SystemPrompt("")
 	if err != nil {
@@ -475,25 +1187,3 @@ func generateExecutiveSummary(ctx context.Context, _ *chunking.Pipeline, contain
 
 	return summary, nil
 }
-
-// detectIssues performs a basic heuristic scan for common error/warning keywords
-// in the LLM analysis text. This is intentionally conservative: it may produce
-// false positives but ensures that potential issues trigger notifications.
-// Future enhancement: Consider using the LLM to classify issue severity directly.
-func detectIssues(containerAnalyses map[string]string) bool {
-	issueKeywords := []string{
-		"error", "failed", "exception", "critical", "warning",
-		"issue", "problem", "alert", "urgent", "attention",
-	}
-
-	for _, analysis := range containerAnalyses {
-		lowerAnalysis := strings.ToLower(analysis)
-		for _, keyword := range issueKeywords {
-			if strings.Contains(lowerAnalysis, keyword) {
-				return true
-			}
-		}
-	}
-
-	return false
-}
@@ -3,13 +3,22 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/zorak1103/dlia/internal/chunking"
 	"github.com/zorak1103/dlia/internal/config"
 	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/notification"
 	"github.com/zorak1103/dlia/internal/state"
 )
 
@@ -72,7 +81,229 @@ func TestProcessContainers_Success(t *testing.T) {
 	}
 }
 
+// TestProcessContainers_LookbackLabelKeepsState checks that the dlia.lookback label
+// only bounds the read window and the container's state still advances.
+func TestProcessContainers_LookbackLabelKeepsState(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	st, _ := state.Load(filepath.Join(tmpDir, "state.json"))
+
+	containers := []docker.Container{
+		{ID: "abc123def456", Name: "api", State: "running", Labels: map[string]string{docker.LabelLookback: "6h"}},
+	}
+	mockDocker := &MockDockerClient{
+		containers: containers,
+		logs: map[string][]docker.LogEntry{
+			"abc123def456": {{Timestamp: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano), Stream: "stdout", Message: "Test"}},
+		},
+	}
+	cfg := &config.Config{
+		LLM: config.LLMConfig{APIKey: "test-key", Model: "test-model", BaseURL: "http://test", MaxTokens: 4000},
+		Output: config.OutputConfig{
+			ReportsDir:       filepath.Join(tmpDir, "reports"),
+			KnowledgeBaseDir: filepath.Join(tmpDir, "kb"),
+		},
+	}
+
+	processContainers(context.Background(), mockDocker, st, containers, cfg, newTestScanConfig(), 0)
+
+	if _, exists := st.GetLastScan("abc123def456"); !exists {
+		t.Error("Expected the container state to be saved despite the dlia.lookback label")
+	}
+}
+
+// TestProcessContainers_ScrubsSecretsFromOutput feeds an API key through a whole scan,
+// with the model quoting it back, and checks that no written file contains it.
+func TestProcessContainers_ScrubsSecretsFromOutput(t *testing.T) {
+	t.Parallel()
+
+	const secret = "sk-proj4f9c2a7e1b8d3c6a0f5e9b2d7c4a1e8f"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"Warning: key %s was logged in plain text"}}],`+
+			`"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`, secret)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	st, _ := state.Load(filepath.Join(tmpDir, "state.json"))
+
+	containers := []docker.Container{{ID: "abc123def456", Name: "web", State: "running"}}
+	mockDocker := &MockDockerClient{
+		containers: containers,
+		logs: map[string][]docker.LogEntry{
+			"abc123def456": {
+				{Timestamp: "2025-01-01T10:00:00Z", Stream: "stderr", Message: "ERROR upstream rejected api_key=" + secret},
+				{Timestamp: "2025-01-01T10:00:01Z", Stream: "stderr", Message: "ERROR retrying with " + secret},
+			},
+		},
+	}
+
+	cfg := &config.Config{
+		LLM: config.LLMConfig{
+			APIKey:         "test-key",
+			Model:          "test-model",
+			BaseURL:        server.URL,
+			MaxTokens:      8000,
+			RequestTimeout: 10 * time.Second,
+		},
+		Output: config.OutputConfig{
+			ReportsDir:       filepath.Join(tmpDir, "reports"),
+			KnowledgeBaseDir: filepath.Join(tmpDir, "kb"),
+			LLMLogDir:        filepath.Join(tmpDir, "llm"),
+			LLMLogEnabled:    true,
+			SaveRawLogs:      true,
+		},
+		Privacy: config.PrivacyConfig{AnonymizeSecrets: true},
+	}
+
+	scanCfg := newTestScanConfig()
+	results, _ := processContainers(context.Background(), mockDocker, st, containers, cfg, scanCfg, 0)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 analyzed container, got %d", len(results))
+	}
+	if err := knowledge.UpdateGlobalSummary(results, cfg); err != nil {
+		t.Fatalf("UpdateGlobalSummary() error = %v", err)
+	}
+
+	var written int
+	err := filepath.WalkDir(tmpDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		written++
+		data, err := os.ReadFile(path) //nolint:gosec // Test reads files it just wrote
+		if err != nil {
+			return err
+		}
+		if strings.Contains(string(data), secret) {
+			t.Errorf("%s contains the secret", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+	// Report, raw logs, knowledge base entry, global summary and LLM log
+	if written < 5 {
+		t.Errorf("Expected at least 5 written files, got %d", written)
+	}
+}
+
 // TestProcessContainers_NoLogs tests container with no logs
+func TestProcessContainers_QuotaExhausted(t *testing.T) {
+	t.Parallel()
+
+	scanCfg := newTestScanConfig()
+	scanCfg.quotaExhausted = true
+
+	tmpDir := t.TempDir()
+	st, _ := state.Load(tmpDir + "/state.json")
+
+	containers := []docker.Container{
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abcd", Name: "container1", State: "running"},
+		{ID: "def456abc123def456abc123def456abc123def456abc123def456abc123defa", Name: "container2", State: "running"},
+	}
+	mockDocker := &MockDockerClient{containers: containers}
+
+	results, stats := processContainers(context.Background(), mockDocker, st, containers, &config.Config{}, scanCfg, 0)
+
+	if len(results) != 0 {
+		t.Errorf("Expected 0 results, got %d", len(results))
+	}
+	if stats.quotaSkipped != 2 {
+		t.Errorf("Expected 2 containers skipped due to quota, got %d", stats.quotaSkipped)
+	}
+	if stats.scannedContainers != 0 {
+		t.Errorf("Expected 0 scanned containers, got %d", stats.scannedContainers)
+	}
+	for _, c := range containers {
+		if _, exists := st.GetLastScan(c.ID); exists {
+			t.Errorf("Expected no state update for %s", c.Name)
+		}
+	}
+}
+
+func TestProcessContainers_LLMUnavailable(t *testing.T) {
+	t.Parallel()
+
+	scanCfg := newTestScanConfig()
+	scanCfg.llmUnavailable = true
+
+	tmpDir := t.TempDir()
+	st, _ := state.Load(tmpDir + "/state.json")
+
+	containers := []docker.Container{
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abcd", Name: "container1", State: "running"},
+		{ID: "def456abc123def456abc123def456abc123def456abc123def456abc123defa", Name: "container2", State: "running"},
+	}
+	mockDocker := &MockDockerClient{containers: containers}
+
+	results, stats := processContainers(context.Background(), mockDocker, st, containers, &config.Config{}, scanCfg, 0)
+
+	if len(results) != 0 || stats.scannedContainers != 0 {
+		t.Errorf("Expected nothing scanned, got %d results, %d scanned", len(results), stats.scannedContainers)
+	}
+	if stats.llmDownSkipped != 2 {
+		t.Errorf("Expected 2 containers skipped, got %d", stats.llmDownSkipped)
+	}
+	for _, c := range containers {
+		if _, exists := st.GetLastScan(c.ID); exists {
+			t.Errorf("Expected no state update for %s", c.Name)
+		}
+	}
+}
+
+func TestProcessContainers_Timeout(t *testing.T) {
+	t.Parallel()
+
+	scanCfg := newTestScanConfig()
+	scanCfg.timeout = time.Millisecond
+
+	tmpDir := t.TempDir()
+	st, _ := state.Load(tmpDir + "/state.json")
+
+	containers := []docker.Container{
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abcd", Name: "container1", State: "running"},
+		{ID: "def456abc123def456abc123def456abc123def456abc123def456abc123defa", Name: "container2", State: "running"},
+	}
+	mockDocker := &MockDockerClient{containers: containers}
+
+	ctx, cancel := scanContext(scanCfg.timeout)
+	defer cancel()
+	<-ctx.Done()
+
+	results, stats := processContainers(ctx, mockDocker, st, containers, &config.Config{}, scanCfg, 0)
+
+	if len(results) != 0 {
+		t.Errorf("Expected 0 results, got %d", len(results))
+	}
+	if !scanCfg.timedOut {
+		t.Error("Expected scan to be marked as timed out")
+	}
+	if stats.timeoutSkipped != 2 {
+		t.Errorf("Expected 2 containers skipped due to timeout, got %d", stats.timeoutSkipped)
+	}
+	for _, c := range containers {
+		if _, exists := st.GetLastScan(c.ID); exists {
+			t.Errorf("Expected no state update for %s", c.Name)
+		}
+	}
+}
+
+func TestScanContext_NoTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := scanContext(0)
+	defer cancel()
+
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		t.Error("Expected no deadline for a zero timeout")
+	}
+}
+
 func TestProcessContainers_NoLogs(t *testing.T) {
 	t.Parallel()
 
@@ -114,6 +345,42 @@ func TestProcessContainers_NoLogs(t *testing.T) {
 	}
 }
 
+// TestProcessContainers_ActiveIdle tests the active/idle breakdown of the scan stats
+func TestProcessContainers_ActiveIdle(t *testing.T) {
+	t.Parallel()
+
+	scanCfg := newTestScanConfig()
+	scanCfg.dryRun = true
+
+	st, _ := state.Load(t.TempDir() + "/state.json")
+
+	containers := []docker.Container{
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abc6", Name: "quiet", State: "running"},
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abc7", Name: "busy", State: "running"},
+		{ID: "abc123def456abc123def456abc123def456abc123def456abc123def456abc8", Name: "cron", State: "running"},
+	}
+
+	mockDocker := &MockDockerClient{
+		containers: containers,
+		logs: map[string][]docker.LogEntry{
+			"abc123def456abc123def456abc123def456abc123def456abc123def456abc7": {
+				{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "Request served"},
+			},
+		},
+	}
+
+	cfg := &config.Config{LLM: config.LLMConfig{Model: "test-model", MaxTokens: 4000}}
+
+	_, stats := processContainers(context.Background(), mockDocker, st, containers, cfg, scanCfg, 0)
+
+	if got := strings.Join(stats.activeContainers, ","); got != "busy" {
+		t.Errorf("activeContainers = %q, want busy", got)
+	}
+	if got := strings.Join(stats.idleContainers, ","); got != "quiet,cron" {
+		t.Errorf("idleContainers = %q, want quiet,cron (scan order)", got)
+	}
+}
+
 // TestProcessContainers_LogReadError tests error reading logs
 func TestProcessContainers_LogReadError(t *testing.T) {
 	t.Parallel()
@@ -176,7 +443,7 @@ func TestProcessLLMAnalysis_InitError(t *testing.T) {
 		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "Test"},
 	}
 
-	result := processLLMAnalysis(ctx, "test", logs, cfg, scanCfg, &pipeline)
+	result := processLLMAnalysis(ctx, "test", logs, "", cfg, scanCfg, &pipeline)
 
 	if result != nil {
 		t.Error("Expected nil result when LLM init fails")
@@ -251,6 +518,75 @@ func TestHandleReportingAndKnowledge_VerboseMode(t *testing.T) {
 	handleReportingAndKnowledge("test-container", result, logs, cfg, scanCfg)
 }
 
+// TestHandleReportingAndKnowledge_OutputSwitches verifies that dry-run, --no-kb and
+// --no-reports suppress the corresponding files.
+func TestHandleReportingAndKnowledge_OutputSwitches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		configure   func(*scanConfig)
+		wantReports bool
+		wantKB      bool
+	}{
+		{name: "default writes both", configure: func(*scanConfig) {}, wantReports: true, wantKB: true},
+		{name: "dry run writes nothing", configure: func(s *scanConfig) { s.dryRun = true }},
+		{name: "no-kb keeps reports", configure: func(s *scanConfig) { s.noKB = true }, wantReports: true},
+		{name: "no-reports keeps knowledge base", configure: func(s *scanConfig) { s.noReports = true }, wantKB: true},
+		{name: "both disabled", configure: func(s *scanConfig) { s.noKB, s.noReports = true, true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scanCfg := newTestScanConfig()
+			tt.configure(scanCfg)
+
+			tmpDir := t.TempDir()
+			cfg := &config.Config{
+				Output: config.OutputConfig{
+					ReportsDir:             tmpDir + "/reports",
+					KnowledgeBaseDir:       tmpDir + "/kb",
+					KnowledgeRetentionDays: 30,
+				},
+			}
+			results := map[string]*chunking.AnalyzeResult{"test-container": {Analysis: "Test analysis"}}
+
+			handleReportingAndKnowledge("test-container", results["test-container"], nil, cfg, scanCfg)
+			if err := updateGlobalSummary(results, cfg, scanCfg); err != nil {
+				t.Fatalf("updateGlobalSummary() error = %v", err)
+			}
+
+			if got := countFiles(t, cfg.Output.ReportsDir) > 0; got != tt.wantReports {
+				t.Errorf("reports written = %v, want %v", got, tt.wantReports)
+			}
+			if got := countFiles(t, cfg.Output.KnowledgeBaseDir) > 0; got != tt.wantKB {
+				t.Errorf("knowledge base written = %v, want %v", got, tt.wantKB)
+			}
+		})
+	}
+}
+
+// countFiles returns the number of regular files below dir (0 if dir does not exist).
+func countFiles(t *testing.T, dir string) int {
+	t.Helper()
+	count := 0
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			count++
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("failed to walk %s: %v", dir, err)
+	}
+	return count
+}
+
 // TestUpdateGlobalSummary_DryRun tests dry run mode
 func TestUpdateGlobalSummary_DryRun(t *testing.T) {
 	t.Parallel()
@@ -331,7 +667,7 @@ func TestHandleExecutiveSummaryAndNotifications_DryRun(t *testing.T) {
 	}
 	cfg := &config.Config{}
 
-	err := handleExecutiveSummaryAndNotifications(ctx, results, cfg, scanCfg)
+	err := handleExecutiveSummaryAndNotifications(ctx, results, nil, cfg, scanCfg)
 
 	if err != nil {
 		t.Errorf("Expected no error in dry run, got: %v", err)
@@ -349,7 +685,7 @@ func TestHandleExecutiveSummaryAndNotifications_NoResults(t *testing.T) {
 	results := map[string]*chunking.AnalyzeResult{}
 	cfg := &config.Config{}
 
-	err := handleExecutiveSummaryAndNotifications(ctx, results, cfg, scanCfg)
+	err := handleExecutiveSummaryAndNotifications(ctx, results, nil, cfg, scanCfg)
 
 	if err != nil {
 		t.Errorf("Expected no error with empty results, got: %v", err)
@@ -374,7 +710,7 @@ func TestHandleExecutiveSummaryAndNotifications_LLMInitError(t *testing.T) {
 		},
 	}
 
-	err := handleExecutiveSummaryAndNotifications(ctx, results, cfg, scanCfg)
+	err := handleExecutiveSummaryAndNotifications(ctx, results, nil, cfg, scanCfg)
 
 	if err == nil {
 		t.Error("Expected error when LLM init fails")
@@ -397,7 +733,7 @@ func TestSendNotificationIfNeeded_Disabled(t *testing.T) {
 		"container1": "Test",
 	}
 
-	err := sendNotificationIfNeeded("summary", 1, containerAnalyses, cfg, scanCfg)
+	err := sendNotificationIfNeeded("summary", 1, notification.ContainerStatuses(containerAnalyses), nil, cfg, scanCfg)
 
 	if err != nil {
 		t.Errorf("Expected no error when notifications disabled, got: %v", err)
@@ -421,13 +757,79 @@ func TestSendNotificationIfNeeded_InvalidConfig(t *testing.T) {
 		"container1": "Test",
 	}
 
-	err := sendNotificationIfNeeded("summary", 1, containerAnalyses, cfg, scanCfg)
+	err := sendNotificationIfNeeded("summary", 1, notification.ContainerStatuses(containerAnalyses), nil, cfg, scanCfg)
 
 	if err == nil {
 		t.Error("Expected error with invalid notification config")
 	}
 }
 
+// TestDisplayNotificationPreview tests the dry-run notification preview
+func TestDisplayNotificationPreview(t *testing.T) {
+	t.Parallel()
+
+	enabled := &config.Config{
+		Notification: config.NotificationConfig{
+			Enabled:    true,
+			ShoutrrURL: "invalid://url",
+		},
+	}
+
+	scanCfg := newTestScanConfig()
+	if err := displayNotificationPreview(enabled, scanCfg, 2); err != nil {
+		t.Errorf("Expected no preview outside dry-run, got: %v", err)
+	}
+
+	scanCfg.dryRun = true
+	if err := displayNotificationPreview(enabled, scanCfg, 2); err != nil {
+		t.Errorf("Expected preview without sending, got: %v", err)
+	}
+
+	invalid := &config.Config{
+		Notification: config.NotificationConfig{Enabled: true},
+	}
+	if err := displayNotificationPreview(invalid, scanCfg, 2); err == nil {
+		t.Error("Expected error for enabled notifications without URL")
+	}
+}
+
+// TestSendContainerAlerts tests per-container alerts only target flagged containers
+func TestSendContainerAlerts(t *testing.T) {
+	t.Parallel()
+
+	scanCfg := newTestScanConfig()
+
+	notifier, err := notification.NewNotifier(&config.Config{
+		Notification: config.NotificationConfig{
+			Enabled:    true,
+			ShoutrrURL: "invalid://url",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+
+	// Healthy containers must not trigger an alert (the invalid URL would fail)
+	healthy := map[string]string{"web": "All good", "db": "Running smoothly"}
+	if err := sendContainerAlerts(notifier, notification.ContainerStatuses(healthy), nil, scanCfg); err != nil {
+		t.Errorf("Expected no alerts for healthy containers, got: %v", err)
+	}
+
+	flagged := map[string]string{"web": "All good", "db": "Critical: disk full", "cache": "Warning: evictions"}
+	err = sendContainerAlerts(notifier, notification.ContainerStatuses(flagged), nil, scanCfg)
+	if err == nil {
+		t.Fatal("Expected error when sending alerts to invalid URL")
+	}
+	for _, name := range []string{"db", "cache"} {
+		if !strings.Contains(err.Error(), "alert for "+name) {
+			t.Errorf("Expected error to mention %s, got: %v", name, err)
+		}
+	}
+	if strings.Contains(err.Error(), "alert for web") {
+		t.Errorf("Healthy container should not be alerted, got: %v", err)
+	}
+}
+
 // TestGenerateExecutiveSummary_Error tests LLM error
 func TestGenerateExecutiveSummary_Error(t *testing.T) {
 	ctx := context.Background()
@@ -453,6 +855,31 @@ func TestGenerateExecutiveSummary_Error(t *testing.T) {
 	}
 }
 
+func TestHandleExecutiveSummaryAndNotifications_QuotaExhausted(t *testing.T) {
+	t.Parallel()
+
+	scanCfg := newTestScanConfig()
+	scanCfg.quotaExhausted = true
+
+	// An empty config would fail LLM initialization; quota exhaustion must skip before that
+	results := map[string]*chunking.AnalyzeResult{"c1": {Analysis: "ok"}}
+	if err := handleExecutiveSummaryAndNotifications(context.Background(), results, nil, &config.Config{}, scanCfg); err != nil {
+		t.Errorf("Expected no error when quota is exhausted, got: %v", err)
+	}
+}
+
+func TestHandleExecutiveSummaryAndNotifications_LLMUnavailable(t *testing.T) {
+	t.Parallel()
+
+	scanCfg := newTestScanConfig()
+	scanCfg.llmUnavailable = true
+
+	results := map[string]*chunking.AnalyzeResult{"c1": {Analysis: "ok"}}
+	if err := handleExecutiveSummaryAndNotifications(context.Background(), results, nil, &config.Config{}, scanCfg); err != nil {
+		t.Errorf("Expected no error when the LLM is unavailable, got: %v", err)
+	}
+}
+
 // TestSaveStateIfNeeded_Success tests successful state save
 func TestSaveStateIfNeeded_Success(t *testing.T) {
 	t.Parallel()
@@ -610,7 +1037,7 @@ func TestHandleExecutiveSummaryAndNotifications_VerboseMode(t *testing.T) {
 		},
 	}
 
-	err := handleExecutiveSummaryAndNotifications(ctx, results, cfg, scanCfg)
+	err := handleExecutiveSummaryAndNotifications(ctx, results, nil, cfg, scanCfg)
 
 	if err == nil {
 		t.Error("Expected error when LLM init fails")
@@ -634,9 +1061,40 @@ func TestSendNotificationIfNeeded_VerboseMode(t *testing.T) {
 		"container1": "Test",
 	}
 
-	err := sendNotificationIfNeeded("summary", 1, containerAnalyses, cfg, scanCfg)
+	err := sendNotificationIfNeeded("summary", 1, notification.ContainerStatuses(containerAnalyses), nil, cfg, scanCfg)
 
 	if err != nil {
 		t.Errorf("Expected no error when notifications disabled, got: %v", err)
 	}
 }
+
+// TestSendNotificationIfNeeded_AlertFailureStillSendsSummary tests that a failed
+// per-container alert does not suppress the run summary
+func TestSendNotificationIfNeeded_AlertFailureStillSendsSummary(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Notification: config.NotificationConfig{
+			Enabled:      true,
+			PerContainer: true,
+			ShoutrrURL:   "generic+" + server.URL + "/webhook",
+		},
+	}
+	containerAnalyses := map[string]string{"web": "Critical: database down"}
+
+	err := sendNotificationIfNeeded("summary", 1, notification.ContainerStatuses(containerAnalyses), nil, cfg, newTestScanConfig())
+	if err == nil || !strings.Contains(err.Error(), "alert for web") {
+		t.Errorf("Expected the failed alert to be reported, got: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected the alert and the summary to be sent, got %d requests", got)
+	}
+}
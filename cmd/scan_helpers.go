@@ -2,25 +2,31 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/zorak1103/dlia/internal/chunking"
 	"github.com/zorak1103/dlia/internal/config"
 	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/knowledge"
 	"github.com/zorak1103/dlia/internal/llm"
 	"github.com/zorak1103/dlia/internal/llmlogger"
 	"github.com/zorak1103/dlia/internal/prompts"
 	"github.com/zorak1103/dlia/internal/reporting"
+	"github.com/zorak1103/dlia/internal/state"
 )
 
-func validateAndFilterContainers(ctx context.Context, dockerClient docker.Client, namePattern string) ([]docker.Container, error) {
-	filterOpts := docker.FilterOptions{
-		NamePattern: namePattern,
-		IncludeAll:  true,
-	}
-
+// validateAndFilterContainers lists containers matching the name pattern and labels in
+// filterOpts. Only running containers are returned unless filterOpts.IncludeAll is set,
+// in which case exited containers are included so their final logs can still be analyzed.
+// The docker.include_containers and docker.exclude_containers lists of dockerCfg are
+// applied on top; exclude wins over include.
+func validateAndFilterContainers(ctx context.Context, dockerClient docker.Client, filterOpts docker.FilterOptions, dockerCfg config.DockerConfig) ([]docker.Container, error) {
 	containers, err := dockerClient.ListContainers(ctx, filterOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
@@ -30,41 +36,177 @@ func validateAndFilterContainers(ctx context.Context, dockerClient docker.Client
 		return containers, nil
 	}
 
-	return containers, nil
+	return filterContainerNameLists(containers, dockerCfg.IncludeContainers, dockerCfg.ExcludeContainers)
+}
+
+// findScanContainer returns the container of "dlia scan <container>": the one named
+// nameOrID or, without such a name, the only one whose ID starts with nameOrID.
+// Stopped containers are only considered with includeStopped. The config's container
+// name lists are not applied, so any container can be scanned on demand.
+func findScanContainer(ctx context.Context, dockerClient docker.Client, nameOrID string, includeStopped bool) (docker.Container, error) {
+	containers, err := dockerClient.ListContainers(ctx, docker.FilterOptions{IncludeAll: includeStopped})
+	if err != nil {
+		return docker.Container{}, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var matches []docker.Container
+	for _, c := range containers {
+		if c.Name == nameOrID {
+			return c, nil
+		}
+		if strings.HasPrefix(c.ID, nameOrID) {
+			matches = append(matches, c)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if !includeStopped {
+			return docker.Container{}, fmt.Errorf("no running container named %q or with an ID starting with it (stopped containers are skipped with --include-stopped=false)", nameOrID)
+		}
+		return docker.Container{}, fmt.Errorf("no container named %q or with an ID starting with it", nameOrID)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, c := range matches {
+			names[i] = c.Name
+		}
+		sort.Strings(names)
+		return docker.Container{}, fmt.Errorf("ID prefix %q is ambiguous: matches %d containers (%s)", nameOrID, len(matches), strings.Join(names, ", "))
+	}
+}
+
+// filterContainerNameLists keeps containers whose name matches any include pattern
+// (all when include is empty) and no exclude pattern.
+func filterContainerNameLists(containers []docker.Container, include, exclude []string) ([]docker.Container, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return containers, nil
+	}
+
+	includeRes, err := compilePatterns(include, "docker.include_containers")
+	if err != nil {
+		return nil, err
+	}
+	excludeRes, err := compilePatterns(exclude, "docker.exclude_containers")
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]docker.Container, 0, len(containers))
+	for _, c := range containers {
+		if matchesAnyPattern(excludeRes, c.Name) {
+			continue
+		}
+		if len(includeRes) > 0 && !matchesAnyPattern(includeRes, c.Name) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, nil
+}
+
+func compilePatterns(patterns []string, key string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp in %s[%d]: %s: %w", key, i, pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchesAnyPattern(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLabelFilters converts repeated --filter-label key=value flags into a label map.
+func parseLabelFilters(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(filters))
+	for _, f := range filters {
+		key, value, ok := strings.Cut(f, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --filter-label %q (expected key=value)", f)
+		}
+		labels[key] = value
+	}
+
+	return labels, nil
 }
 
-func processContainerLogs(ctx context.Context, dockerClient docker.Client, containerID string, since time.Time) ([]docker.LogEntry, error) {
-	logs, err := dockerClient.ReadLogsSince(ctx, containerID, since)
+// processContainerLogs reads the logs of a container from cursor on. A tail greater
+// than zero reads only the last tail lines instead and drops those older than the
+// cursor, so the tail is taken within the lookback window.
+func processContainerLogs(ctx context.Context, dockerClient docker.Client, containerID string, cursor docker.LogCursor, tail int) ([]docker.LogEntry, error) {
+	var logs []docker.LogEntry
+	var err error
+	if tail > 0 {
+		logs, err = dockerClient.ReadLogsTail(ctx, containerID, tail)
+		if err == nil {
+			logs = docker.EntriesSince(logs, cursor.Timestamp)
+		}
+	} else {
+		logs, err = dockerClient.ReadLogsAfter(ctx, containerID, cursor)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read logs for container %s: %w", containerID[:12], err)
+		return nil, fmt.Errorf("failed to read logs for container %s: %w", shortContainerID(containerID, 0), err)
 	}
 
 	return logs, nil
 }
 
-func processLLMAnalysis(ctx context.Context, containerName string, logs []docker.LogEntry, cfg *config.Config, scanCfg *scanConfig, pipelineRef **chunking.Pipeline) *chunking.AnalyzeResult {
+func processLLMAnalysis(ctx context.Context, containerName string, logs []docker.LogEntry, instructions string, cfg *config.Config, scanCfg *scanConfig, pipelineRef **chunking.Pipeline) *chunking.AnalyzeResult {
 	if scanCfg.dryRun {
-		fmt.Printf("        🔸 DRY RUN: Skipping LLM analysis\n")
+		scanCfg.out.Printf("        🔸 DRY RUN: Skipping LLM analysis\n")
 		return nil
 	}
 
-	fmt.Printf("        🤖 Analyzing logs with LLM...\n")
+	scanCfg.out.Printf("        🤖 Analyzing logs with LLM...\n")
 
 	if *pipelineRef == nil {
 		pipeline, err := initializeLLMPipeline(cfg, scanCfg)
 		if err != nil {
-			fmt.Printf("        ⚠️  Failed to initialize LLM: %v\n", err)
-			fmt.Printf("        ⚠️  Switching to dry-run mode (logs will be read but not analyzed)\n\n")
+			scanCfg.out.Warnf("        ⚠️  Failed to initialize LLM: %v\n", err)
+			scanCfg.out.Warnf("        ⚠️  Switching to dry-run mode (logs will be read but not analyzed)\n\n")
 			scanCfg.dryRun = true
 			return nil
 		}
 		*pipelineRef = pipeline
 	}
 
-	result, err := (*pipelineRef).AnalyzeLogs(ctx, containerName, logs)
+	result, err := (*pipelineRef).AnalyzeLogsWithHistory(ctx, containerName, logs, instructions, previousAnalysis(containerName, cfg, scanCfg))
 	if err != nil {
-		fmt.Printf("        ⚠️  LLM analysis failed: %v\n", err)
-		fmt.Printf("        ⚠️  Logs were read but not analyzed\n\n")
+		if ctx.Err() != nil {
+			scanCfg.out.Warnf("        ⏱️  Scan timeout reached: %v\n", err)
+			scanCfg.out.Warnf("        ⏱️  Stopping; state is kept so a re-run resumes here\n\n")
+			return nil
+		}
+		if llm.IsQuotaError(err) {
+			scanCfg.out.Warnf("        🛑 LLM quota exhausted: %v\n", err)
+			scanCfg.out.Warnf("        🛑 Stopping LLM analysis; state is kept so a re-run resumes here\n\n")
+			scanCfg.quotaExhausted = true
+			return nil
+		}
+		if errors.Is(err, llm.ErrCircuitOpen) {
+			scanCfg.out.Warnf("        🔌 LLM endpoint unavailable: %v\n", err)
+			scanCfg.out.Warnf("        🔌 Skipping LLM analysis for the remaining containers; state is kept so a re-run resumes here\n\n")
+			scanCfg.llmUnavailable = true
+			return nil
+		}
+		scanCfg.out.Warnf("        ⚠️  LLM analysis failed: %v\n", err)
+		scanCfg.out.Warnf("        ⚠️  Logs were read but not analyzed\n\n")
 		return nil
 	}
 
@@ -72,9 +214,35 @@ func processLLMAnalysis(ctx context.Context, containerName string, logs []docker
 	return result
 }
 
+// previousAnalysis returns the analysis of the container's latest knowledge base
+// entry when llm.include_previous_analysis is enabled. It is empty on the first scan,
+// and when the knowledge base cannot be read the analysis runs without history.
+func previousAnalysis(containerName string, cfg *config.Config, scanCfg *scanConfig) string {
+	if !cfg.LLM.IncludePreviousAnalysis {
+		return ""
+	}
+
+	entries, err := knowledge.ContainerEntries(cfg.Output.KnowledgeBaseDir, containerName)
+	if err != nil {
+		scanCfg.out.Warnf("        ⚠️  Failed to read previous analysis: %v\n", err)
+		return ""
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return entries[len(entries)-1].Analysis()
+}
+
 func displayAnalysisResults(result *chunking.AnalyzeResult, scanCfg *scanConfig) {
+	switch result.SkipReason {
+	case chunking.SkipReasonCleanLogs:
+		scanCfg.out.Printf("        ⏩ Logs look clean, LLM skipped (llm.skip_clean_logs)\n")
+	case chunking.SkipReasonBelowThreshold:
+		scanCfg.out.Printf("        ⏩ Only %d log line(s) after filtering, LLM skipped (llm.min_log_lines)\n", result.ProcessedCount)
+	}
+
 	if scanCfg.verbose && result.Deduplicated {
-		fmt.Printf("        📊 Deduplication: %d → %d entries\n", result.OriginalCount, result.ProcessedCount)
+		scanCfg.out.Printf("        📊 Deduplication: %d → %d entries\n", result.OriginalCount, result.ProcessedCount)
 	}
 
 	if scanCfg.filterStats && result.FilterStats.LinesTotal > 0 {
@@ -82,55 +250,104 @@ func displayAnalysisResults(result *chunking.AnalyzeResult, scanCfg *scanConfig)
 		if result.FilterStats.LinesTotal > 0 {
 			percentage = float64(result.FilterStats.LinesFiltered) / float64(result.FilterStats.LinesTotal) * 100
 		}
-		fmt.Printf("        🔍 Regexp Filter: Filtered %d/%d log lines (%.1f%%)\n",
+		scanCfg.out.Printf("        🔍 Regexp Filter: Filtered %d/%d log lines (%.1f%%)\n",
 			result.FilterStats.LinesFiltered,
 			result.FilterStats.LinesTotal,
 			percentage)
+		printPatternHits(scanCfg, result.FilterStats)
 	}
 
-	fmt.Printf("        \n")
-	fmt.Printf("        ┌─ Analysis Results ─────────────────────\n")
+	if scanCfg.filterStats && result.FilterStats.PhysicalLines > result.FilterStats.LinesTotal {
+		scanCfg.out.Printf("        🧵 Multi-line: grouped %d log lines into %d entries\n",
+			result.FilterStats.PhysicalLines,
+			result.FilterStats.LinesTotal)
+	}
+
+	if scanCfg.filterStats && result.FilterStats.SanitizedLines > 0 {
+		scanCfg.out.Printf("        🧹 Invalid UTF-8: sanitized %d log lines (docker.invalid_utf8)\n", result.FilterStats.SanitizedLines)
+	}
+
+	if scanCfg.filterStats && result.FilterStats.StdoutDropped+result.FilterStats.StderrDropped > 0 {
+		scanCfg.out.Printf("        🔀 Stream Filter: dropped %d stdout and %d stderr log lines\n",
+			result.FilterStats.StdoutDropped,
+			result.FilterStats.StderrDropped)
+	}
+
+	if scanCfg.filterStats && result.TruncatedLines > 0 {
+		scanCfg.out.Printf("        ✂️  Truncated: dropped %d oldest log lines (llm.max_log_lines / llm.max_log_bytes)\n", result.TruncatedLines)
+	}
+
+	if result.CacheHit {
+		scanCfg.out.Printf("        💾 Cache hit: reused the analysis of identical logs (0 tokens)\n")
+	}
+
+	if scanCfg.filterStats && result.SkipReason != "" {
+		scanCfg.out.Printf("        ⏩ LLM skipped: %s\n", result.SkipReason)
+	}
+
+	if scanCfg.filterStats && result.Redactions.Total() > 0 {
+		scanCfg.out.Printf("        🔒 Redacted: %d IPs, %d emails, %d secrets, %d card numbers\n",
+			result.Redactions.IPs,
+			result.Redactions.Emails,
+			result.Redactions.Secrets,
+			result.Redactions.CardNumbers)
+	}
+
+	scanCfg.out.Printf("        \n")
+	scanCfg.out.Printf("        ┌─ Analysis Results ─────────────────────\n")
 
 	lines := strings.Split(result.Analysis, "\n")
 	for _, line := range lines {
 		if line != "" {
-			fmt.Printf("        │ %s\n", line)
+			scanCfg.out.Printf("        │ %s\n", line)
 		}
 	}
 
-	fmt.Printf("        └────────────────────────────────────────\n")
+	scanCfg.out.Printf("        └────────────────────────────────────────\n")
 
 	if scanCfg.verbose {
-		fmt.Printf("        📊 Tokens used: %d", result.TokensUsed)
+		scanCfg.out.Printf("        📊 Tokens used: %d", result.TokensUsed)
 		if result.ChunksUsed > 1 {
-			fmt.Printf(" (chunked analysis)")
+			scanCfg.out.Printf(" (chunked analysis)")
 		}
-		fmt.Printf("\n")
+		scanCfg.out.Printf("\n")
 	}
 
-	fmt.Printf("        \n")
+	scanCfg.out.Printf("        \n")
+}
+
+// printPatternHits lists the lines each regexp filter pattern removed, so dead
+// patterns (0 lines) and overly broad ones stand out when tuning filters.
+func printPatternHits(scanCfg *scanConfig, stats chunking.FilterStats) {
+	for i, hit := range stats.PatternHits {
+		if hit.Lines == 0 {
+			scanCfg.out.Printf("           #%d %q: no matches\n", i+1, hit.Pattern)
+			continue
+		}
+		scanCfg.out.Printf("           #%d %q: %d lines (%.1f%%)\n", i+1, hit.Pattern, hit.Lines,
+			float64(hit.Lines)/float64(stats.LinesTotal)*100)
+	}
 }
 
 func initializeLLMPipeline(cfg *config.Config, scanCfg *scanConfig) (*chunking.Pipeline, error) {
-	if cfg.LLM.APIKey == "" {
+	if len(cfg.LLM.Keys()) == 0 && cfg.LLM.Provider != config.ProviderOllama {
 		return nil, fmt.Errorf("LLM API key not configured (set DLIA_LLM_API_KEY in .env)")
 	}
 
-	llmClient := llm.NewClient(cfg.LLM.BaseURL, cfg.LLM.APIKey, cfg.LLM.Model)
+	llmClient := llm.NewClientWithOptions(cfg.LLM.BaseURL, cfg.LLM.APIKey, cfg.LLM.Model, llmClientOptions(cfg))
 
 	llmLogEnabled := scanCfg.llmLog || cfg.Output.LLMLogEnabled
 	if llmLogEnabled {
 		logger := llmlogger.NewLogger(cfg.Output.LLMLogDir, true)
+		logger.SetPrivacy(cfg.Privacy)
 		llmClient.SetLogger(logger)
-		if scanCfg.verbose {
-			fmt.Printf("📝 LLM logging enabled: %s\n", cfg.Output.LLMLogDir)
-		}
+		slog.Debug("LLM interaction logging enabled", "dir", cfg.Output.LLMLogDir)
 	}
 
 	// Create PromptLoader for dependency injection
 	promptLoader := prompts.NewPromptLoader(cfg)
 
-	pipeline, err := chunking.NewPipelineWithConfig(cfg.LLM.Model, cfg.LLM.MaxTokens, llmClient, promptLoader, cfg.Output.IgnoreDir, cfg)
+	pipeline, err := chunking.NewPipelineWithConfig(cfg.LLM.Model, contextWindow(cfg, scanCfg), llmClient, promptLoader, cfg.Output.IgnoreDir, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pipeline: %w", err)
 	}
@@ -138,17 +355,191 @@ func initializeLLMPipeline(cfg *config.Config, scanCfg *scanConfig) (*chunking.P
 	return pipeline, nil
 }
 
+// contextWindow returns llm.max_tokens, or the model's context window from the
+// built-in table when it is 0. Unknown models get llm.DefaultContextWindow.
+func contextWindow(cfg *config.Config, scanCfg *scanConfig) int {
+	if cfg.LLM.MaxTokens > 0 {
+		if scanCfg.verbose {
+			scanCfg.out.Printf("📏 Context window: %d tokens (llm.max_tokens)\n", cfg.LLM.MaxTokens)
+		}
+		return cfg.LLM.MaxTokens
+	}
+
+	window, known := llm.ContextWindow(cfg.LLM.Model)
+	if !known {
+		scanCfg.out.Warnf("⚠️  Unknown context window for model %s, using %d tokens (set llm.max_tokens to override)\n", cfg.LLM.Model, window)
+	} else if scanCfg.verbose {
+		scanCfg.out.Printf("📏 Context window: %d tokens (detected for model %s)\n", window, cfg.LLM.Model)
+	}
+	return window
+}
+
+// llmClientOptions maps the LLM configuration to client options.
+func llmClientOptions(cfg *config.Config) llm.ClientOptions {
+	analysisTemperature, summaryTemperature := cfg.LLM.Temperature, cfg.LLM.SummaryTemperature
+	opts := llm.ClientOptions{
+		RequestTimeout:        cfg.LLM.RequestTimeout,
+		AnalysisMaxTokens:     cfg.LLM.ResponseReserveTokens,
+		ChunkSummaryMaxTokens: cfg.LLM.ChunkSummaryMaxTokens,
+		AnalysisTemperature:   &analysisTemperature,
+		SummaryTemperature:    &summaryTemperature,
+		TopP:                  cfg.LLM.TopP,
+		FrequencyPenalty:      cfg.LLM.FrequencyPenalty,
+		RateLimiter:           llm.SharedRateLimiter(cfg.LLM.RequestsPerMinute),
+		CircuitBreaker:        llm.NewCircuitBreaker(cfg.LLM.CircuitBreakerThreshold, cfg.LLM.CircuitBreakerCooldown),
+		KeyRing:               llm.SharedKeyRing(cfg.LLM.Keys()),
+	}
+	if cfg.LLM.Provider == config.ProviderAzure {
+		opts.Azure = &llm.AzureOptions{
+			Deployment: cfg.LLM.Azure.Deployment,
+			APIVersion: cfg.LLM.Azure.APIVersion,
+		}
+	}
+	opts.Ollama = cfg.LLM.Provider == config.ProviderOllama
+	return opts
+}
+
 func generateAndSaveReport(containerName string, result *chunking.AnalyzeResult, logs []docker.LogEntry, cfg *config.Config, scanCfg *scanConfig) (string, error) {
-	reportContent := reporting.GenerateScanReport(containerName, result, logs)
+	reportContent, err := reporting.GenerateCappedReport(containerName, result, logs, cfg.Output.ReportFormat, cfg.DisplayLocation(), cfg.Output.MaxReportBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate report for %s: %w", containerName, err)
+	}
 
-	reportPath, err := reporting.SaveReport(containerName, reportContent, cfg)
+	reportPath, err := reporting.SaveReport(containerName, result.ComposeProject, reportContent, cfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to save report for %s: %w", containerName, err)
 	}
 
 	if scanCfg.verbose {
-		fmt.Printf("        📄 Report saved: %s\n", reportPath)
+		scanCfg.out.Printf("        📄 Report saved: %s\n", reportPath)
+	}
+
+	if cfg.Output.SaveRawLogs && result.LogsText != "" {
+		logsPath, err := reporting.SaveRawLogs(reportPath, result.LogsText)
+		if err != nil {
+			return reportPath, fmt.Errorf("failed to save raw logs for %s: %w", containerName, err)
+		}
+		if scanCfg.verbose {
+			scanCfg.out.Printf("        📄 Raw logs saved: %s\n", logsPath)
+		}
 	}
 
 	return reportPath, nil
 }
+
+// includeContainerEvents folds the Docker events since the start of the scan window
+// into the logs sent to the LLM when docker.include_events is enabled. Events only
+// add context, so a failed events query is reported and the logs are analyzed alone.
+func includeContainerEvents(ctx context.Context, dockerClient docker.Client, containerID string, since time.Time, logs []docker.LogEntry, cfg *config.Config, scanCfg *scanConfig) []docker.LogEntry {
+	if !cfg.Docker.IncludeEvents {
+		return logs
+	}
+
+	events, err := dockerClient.ReadEventsSince(ctx, containerID, since)
+	if err != nil {
+		scanCfg.out.Warnf("        ⚠️  Could not read Docker events: %v\n", err)
+		return logs
+	}
+	if len(events) == 0 {
+		return logs
+	}
+
+	scanCfg.out.Printf("        🐳 Including %d Docker event(s): %s\n", len(events), summarizeEvents(events))
+	return docker.MergeEvents(logs, events)
+}
+
+// summarizeEvents counts events per action in order of first occurrence ("die x3, oom x1").
+func summarizeEvents(events []docker.Event) string {
+	counts := make(map[string]int, len(events))
+	var actions []string
+	for _, e := range events {
+		if counts[e.Action] == 0 {
+			actions = append(actions, e.Action)
+		}
+		counts[e.Action]++
+	}
+
+	parts := make([]string, 0, len(actions))
+	for _, action := range actions {
+		parts = append(parts, fmt.Sprintf("%s x%d", action, counts[action]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// acquireScanLock takes the cross-process scan lock beside the state file so that
+// overlapping scans (e.g. slow cron runs) cannot corrupt the state and knowledge base.
+// Dry runs change nothing and run without the lock. The returned release func is
+// meant to be deferred; the OS also drops the lock if the process dies.
+func acquireScanLock(cfg *config.Config, scanCfg *scanConfig) (func(), error) {
+	if scanCfg.dryRun {
+		return func() {}, nil
+	}
+
+	lock, err := state.AcquireLock(cfg.Output.StateFile)
+	if err != nil {
+		if errors.Is(err, state.ErrLocked) {
+			return nil, &exitError{code: exitCodeScanInProgress, err: err}
+		}
+		return nil, fmt.Errorf("failed to acquire scan lock: %w", err)
+	}
+
+	return func() {
+		if err := lock.Release(); err != nil {
+			scanCfg.out.Warnf("⚠️  Failed to release scan lock: %v\n", err)
+		}
+	}, nil
+}
+
+// writeReportIndex writes reports/index.md (and index.json with --output json) listing
+// the reports of this run, most severe first and then by name. With --changed-only the
+// active/idle breakdown of stats is included. Nothing is written when no report was
+// saved, e.g. in dry runs or with --no-reports.
+func writeReportIndex(globalResults map[string]*chunking.AnalyzeResult, stats scanStats, cfg *config.Config, scanCfg *scanConfig) {
+	type indexed struct {
+		entry    reporting.IndexEntry
+		severity knowledge.Severity
+	}
+
+	var items []indexed
+	for name, result := range globalResults {
+		if result.ReportPath == "" {
+			continue
+		}
+		severity := knowledge.ResultSeverity(result)
+		items = append(items, indexed{
+			entry: reporting.IndexEntry{
+				Container:  name,
+				Status:     severity.String(),
+				TokensUsed: result.TokensUsed,
+				Report:     result.ReportPath,
+			},
+			severity: severity,
+		})
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].severity != items[j].severity {
+			return items[i].severity > items[j].severity
+		}
+		return items[i].entry.Container < items[j].entry.Container
+	})
+	entries := make([]reporting.IndexEntry, len(items))
+	for i, item := range items {
+		entries[i] = item.entry
+	}
+
+	var activity *reporting.IndexActivity
+	if scanCfg.changedOnly {
+		activity = &reporting.IndexActivity{Active: stats.activeContainers, Idle: stats.idleContainers}
+	}
+
+	paths, err := reporting.WriteIndex(entries, activity, scanCfg.output == indexOutputJSON, cfg)
+	if err != nil {
+		scanCfg.out.Warnf("⚠️  Failed to write report index: %v\n", err)
+		return
+	}
+	scanCfg.out.Printf("🗂️  Report index: %s\n", strings.Join(paths, ", "))
+}
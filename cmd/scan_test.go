@@ -20,13 +20,16 @@ type MockDockerClient struct {
 	pingErr    error
 	listErr    error
 	logsErr    error
+	events     map[string][]docker.Event
+	listOpts   docker.FilterOptions // Options passed to the last ListContainers call
 }
 
 func (m *MockDockerClient) Ping(_ context.Context) error {
 	return m.pingErr
 }
 
-func (m *MockDockerClient) ListContainers(_ context.Context, _ docker.FilterOptions) ([]docker.Container, error) {
+func (m *MockDockerClient) ListContainers(_ context.Context, opts docker.FilterOptions) ([]docker.Container, error) {
+	m.listOpts = opts
 	if m.listErr != nil {
 		return nil, m.listErr
 	}
@@ -75,6 +78,16 @@ func (m *MockDockerClient) ReadLogs(_ context.Context, containerID string, _ doc
 	return []docker.LogEntry{}, nil
 }
 
+func (m *MockDockerClient) ReadLogsAfter(_ context.Context, containerID string, cursor docker.LogCursor) ([]docker.LogEntry, error) {
+	if m.logsErr != nil {
+		return nil, m.logsErr
+	}
+	if logs, exists := m.logs[containerID]; exists {
+		return cursor.Filter(logs), nil
+	}
+	return []docker.LogEntry{}, nil
+}
+
 func (m *MockDockerClient) ReadLogsLookback(_ context.Context, containerID string, _ time.Duration) ([]docker.LogEntry, error) {
 	if m.logsErr != nil {
 		return nil, m.logsErr
@@ -85,6 +98,39 @@ func (m *MockDockerClient) ReadLogsLookback(_ context.Context, containerID strin
 	return []docker.LogEntry{}, nil
 }
 
+func (m *MockDockerClient) ReadLogsTail(_ context.Context, containerID string, n int) ([]docker.LogEntry, error) {
+	if m.logsErr != nil {
+		return nil, m.logsErr
+	}
+	logs := m.logs[containerID]
+	if n < len(logs) {
+		return logs[len(logs)-n:], nil
+	}
+	return logs, nil
+}
+
+// FollowLogs streams the container's configured logs, then closes the channel.
+func (m *MockDockerClient) FollowLogs(_ context.Context, containerID string) (<-chan docker.LogEntry, error) {
+	if m.logsErr != nil {
+		return nil, m.logsErr
+	}
+	logs := m.logs[containerID]
+	entries := make(chan docker.LogEntry, len(logs))
+	for _, entry := range logs {
+		entries <- entry
+	}
+	close(entries)
+	return entries, nil
+}
+
+// ReadEventsSince returns the container's configured events.
+func (m *MockDockerClient) ReadEventsSince(_ context.Context, containerID string, _ time.Time) ([]docker.Event, error) {
+	if m.logsErr != nil {
+		return nil, m.logsErr
+	}
+	return m.events[containerID], nil
+}
+
 // MockLLMClient for testing
 type MockLLMClient struct {
 	analyzeResponse string
@@ -1,7 +1,29 @@
 // coverage-exempt: thin Cobra flag adapter; newScanConfigFromCmd is tested indirectly via scan integration tests
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Container selection modes for --sample-mode.
+const (
+	sampleModeRecent = "recent"
+	sampleModeRandom = "random"
+)
+
+// Scan orders for --order-by.
+const (
+	orderByName   = "name"
+	orderByVolume = "volume"
+)
+
+// Report index formats for --output.
+const (
+	indexOutputMarkdown = "md"
+	indexOutputJSON     = "json"
+)
 
 // scanConfig holds all scan-specific configuration flags.
 // This structure replaces the package-level global variables
@@ -15,6 +37,15 @@ type scanConfig struct {
 	// Only containers matching this pattern will be scanned.
 	filter string
 
+	// container is the "dlia scan <container>" argument: scan only the container with
+	// this exact name or ID prefix, bypassing filter, labelFilters and the config's
+	// container name lists.
+	container string
+
+	// labelFilters holds raw --filter-label key=value flags. Containers must carry
+	// every listed label (AND); combinable with filter.
+	labelFilters []string
+
 	// lookback specifies a duration to look back for logs (e.g., "1h", "24h").
 	// When set, the state file is ignored and logs are read from the specified duration ago.
 	lookback string
@@ -27,6 +58,72 @@ type scanConfig struct {
 	// were filtered by regexp patterns during log processing.
 	filterStats bool
 
+	// includeStopped also scans containers that are not running (exited, dead, ...)
+	// so the final logs of a crashed container are not lost. On by default.
+	includeStopped bool
+
+	// timeout bounds the whole scan (0 = unbounded). When it expires, containers not yet
+	// processed are skipped and the state of finished containers is still saved.
+	timeout time.Duration
+
+	// failOnIssues is the --fail-on-issues severity ("warning" or "critical"). When
+	// set, a scan whose findings reach it exits with exitCodeIssuesFound.
+	failOnIssues string
+
+	// stream is the --stream selection (all, stdout or stderr); empty falls back
+	// to docker.stream.
+	stream string
+
+	// sample limits the scan to this many of the matching containers (0 = all),
+	// picked according to sampleMode.
+	sample int
+
+	// sampleMode is sampleModeRecent or sampleModeRandom.
+	sampleMode string
+
+	// orderBy is the scan order, orderByName or orderByVolume (most log lines in the
+	// recent volume window first).
+	orderBy string
+
+	// tail limits initial fetches (first scan of a container or lookback mode) to
+	// the last N lines within the time window (0 = no limit).
+	tail int
+
+	// noKB skips writing the knowledge base (per-container entries and the global
+	// summary) while still calling the LLM; noReports skips the per-scan reports.
+	noKB      bool
+	noReports bool
+
+	// output is the --output format of the per-run report index: indexOutputMarkdown
+	// or indexOutputJSON (index.md plus index.json).
+	output string
+
+	// changedOnly adds the active/idle breakdown (containers with and without new
+	// log lines this run) to the scan summary and the report index.
+	changedOnly bool
+
+	// timedOut is set once the scan deadline has passed.
+	timedOut bool
+
+	// quotaExhausted is set once the LLM reports an exhausted quota or rate limit.
+	// Remaining containers are skipped without touching their state so a re-run resumes them.
+	quotaExhausted bool
+
+	// llmUnavailable is set once the LLM circuit breaker opens. Like quotaExhausted,
+	// remaining containers are skipped without touching their state.
+	llmUnavailable bool
+
+	// quiet suppresses progress output: only warnings, errors and the final one-line
+	// summary are printed. Mutually exclusive with verbose.
+	quiet bool
+
+	// progress replaces the per-container output with a progress line (containers
+	// done/total, current container, elapsed time). Ignored in quiet mode.
+	progress bool
+
+	// out prints progress output according to quiet.
+	out printer
+
 	// verbose enables detailed output during scan operations.
 	// Inherited from root command but included here for explicit dependency tracking.
 	verbose bool
@@ -42,14 +139,45 @@ func newScanConfigFromCmd(cmd *cobra.Command) *scanConfig {
 	lookback, _ := cmd.Flags().GetString("lookback")
 	llmLog, _ := cmd.Flags().GetBool("llmlog")
 	filterStats, _ := cmd.Flags().GetBool("filter-stats")
+	includeStopped, _ := cmd.Flags().GetBool("include-stopped")
+	labelFilters, _ := cmd.Flags().GetStringArray("filter-label")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	failOnIssues, _ := cmd.Flags().GetString("fail-on-issues")
+	stream, _ := cmd.Flags().GetString("stream")
+	sample, _ := cmd.Flags().GetInt("sample")
+	sampleMode, _ := cmd.Flags().GetString("sample-mode")
+	orderBy, _ := cmd.Flags().GetString("order-by")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	tail, _ := cmd.Flags().GetInt("tail")
+	noKB, _ := cmd.Flags().GetBool("no-kb")
+	noReports, _ := cmd.Flags().GetBool("no-reports")
+	output, _ := cmd.Flags().GetString("output")
+	changedOnly, _ := cmd.Flags().GetBool("changed-only")
+	progress, _ := cmd.Flags().GetBool("progress")
 
 	return &scanConfig{
-		dryRun:      dryRun,
-		filter:      filter,
-		lookback:    lookback,
-		llmLog:      llmLog,
-		filterStats: filterStats,
-		verbose:     verbose, // Still using global from root command
+		dryRun:         dryRun,
+		filter:         filter,
+		labelFilters:   labelFilters,
+		lookback:       lookback,
+		llmLog:         llmLog,
+		filterStats:    filterStats,
+		includeStopped: includeStopped,
+		timeout:        timeout,
+		failOnIssues:   failOnIssues,
+		stream:         stream,
+		sample:         sample,
+		sampleMode:     sampleMode,
+		orderBy:        orderBy,
+		tail:           tail,
+		noKB:           noKB,
+		noReports:      noReports,
+		output:         output,
+		changedOnly:    changedOnly,
+		quiet:          quiet,
+		progress:       progress,
+		out:            printer{quiet: quiet},
+		verbose:        verbose, // Still using global from root command
 	}
 }
 
@@ -57,11 +185,15 @@ func newScanConfigFromCmd(cmd *cobra.Command) *scanConfig {
 // This helps tests avoid depending on Cobra commands or global variables.
 func newTestScanConfig() *scanConfig {
 	return &scanConfig{
-		dryRun:      false,
-		filter:      "",
-		lookback:    "",
-		llmLog:      false,
-		filterStats: false,
-		verbose:     false,
+		dryRun:         false,
+		filter:         "",
+		lookback:       "",
+		llmLog:         false,
+		filterStats:    false,
+		includeStopped: true,
+		sampleMode:     sampleModeRecent,
+		orderBy:        orderByName,
+		output:         indexOutputMarkdown,
+		verbose:        false,
 	}
 }
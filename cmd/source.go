@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
+)
+
+// newLogClient creates the client of the configured log source: the Docker daemon,
+// or the Kubernetes API with source: kubernetes.
+func newLogClient(cfg *config.Config) (docker.Client, error) {
+	opts := docker.ClientOptions{
+		TimestampFormat:  cfg.Docker.TimestampFormat,
+		TimestampPattern: cfg.Docker.TimestampPattern,
+		TimestampZone:    cfg.DisplayLocation(),
+	}
+
+	if cfg.Source == config.SourceKubernetes {
+		client, err := docker.NewKubernetesClient(docker.KubernetesOptions{
+			ClientOptions: opts,
+			APIServer:     cfg.Kubernetes.APIServer,
+			TokenFile:     cfg.Kubernetes.TokenFile,
+			CAFile:        cfg.Kubernetes.CAFile,
+			Namespace:     cfg.Kubernetes.Namespace,
+			LabelSelector: cfg.Kubernetes.LabelSelector,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+		}
+		return client, nil
+	}
+
+	client, err := docker.NewClientWithOptions(cfg.Docker.SocketPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	return client, nil
+}
+
+// connectLogSource creates the log source client and verifies that it is reachable.
+func connectLogSource(ctx context.Context, cfg *config.Config) (docker.Client, error) {
+	slog.Debug("connecting to log source", "source", cfg.Source, "socket", cfg.Docker.SocketPath, "api_server", cfg.Kubernetes.APIServer)
+	client, err := newLogClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		_ = client.Close() // Close error not actionable: the connection is unusable anyway
+		if cfg.Source == config.SourceKubernetes {
+			return nil, fmt.Errorf("failed to connect to the Kubernetes API: %w", err)
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, dockerPermissionError(cfg.Docker.SocketPath, err)
+		}
+		return nil, fmt.Errorf("failed to connect to Docker daemon: %w\nMake sure Docker is running and you have permission to access the socket", err)
+	}
+	slog.Debug("connected to log source", "source", cfg.Source)
+	return client, nil
+}
+
+// dockerPermissionError explains how to fix a denied Docker socket, the most common
+// first-run failure. It is a configuration error (exit code 2): the setup needs
+// fixing, retrying does not help.
+func dockerPermissionError(socketPath string, err error) error {
+	return configError(fmt.Errorf("permission denied on the Docker socket %s: %w\n"+
+		"To fix this, do one of the following:\n"+
+		"  - add your user to the docker group: sudo usermod -aG docker $USER (then log out and back in)\n"+
+		"  - run dlia as a user that can access the socket, e.g. with sudo\n"+
+		"  - set docker.socket_path in config.yaml to a socket you can access, e.g. unix://$XDG_RUNTIME_DIR/docker.sock for rootless Docker",
+		socketPath, err))
+}
+
+// kubernetesTarget describes the configured API server and pod selection for display.
+func kubernetesTarget(cfg *config.Config) string {
+	target := cfg.Kubernetes.APIServer
+	if target == "" {
+		target = "in-cluster"
+	}
+	namespace := cfg.Kubernetes.Namespace
+	if namespace == "" {
+		namespace = "all namespaces"
+	}
+	target += ", " + namespace
+	if cfg.Kubernetes.LabelSelector != "" {
+		target += ", " + cfg.Kubernetes.LabelSelector
+	}
+	return target
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+func TestConnectLogSource_Kubernetes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			_, _ = w.Write([]byte(`{"gitVersion":"v1.31.0"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	testCfg := &config.Config{Source: config.SourceKubernetes, Kubernetes: config.KubernetesConfig{APIServer: server.URL}}
+	client, err := connectLogSource(context.Background(), testCfg)
+	if err != nil {
+		t.Fatalf("connectLogSource() error = %v", err)
+	}
+	defer client.Close() //nolint:errcheck // test cleanup
+
+	server.Close()
+	if _, err := connectLogSource(context.Background(), testCfg); err == nil || !strings.Contains(err.Error(), "failed to connect to the Kubernetes API") {
+		t.Errorf("Expected a Kubernetes connection error, got %v", err)
+	}
+}
+
+func TestDockerPermissionError(t *testing.T) {
+	// The error chain of a Docker client denied access to its socket
+	dialErr := &net.OpError{Op: "dial", Net: "unix", Err: os.NewSyscallError("connect", syscall.EACCES)}
+	pingErr := fmt.Errorf("failed to ping Docker daemon at unix:///var/run/docker.sock: %w",
+		&url.Error{Op: "Get", URL: "http://%2Fvar%2Frun%2Fdocker.sock/_ping", Err: dialErr})
+	if !errors.Is(pingErr, fs.ErrPermission) {
+		t.Fatal("Expected EACCES to match fs.ErrPermission")
+	}
+
+	err := dockerPermissionError("unix:///var/run/docker.sock", pingErr)
+	if code := exitCodeFor(err); code != exitCodeConfig {
+		t.Errorf("exitCodeFor() = %d, want %d", code, exitCodeConfig)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Error("Expected the cause to stay wrapped")
+	}
+	for _, want := range []string{"permission denied on the Docker socket unix:///var/run/docker.sock", "usermod -aG docker", "docker.socket_path"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in error:\n%v", want, err)
+		}
+	}
+}
+
+func TestKubernetesTarget(t *testing.T) {
+	testCfg := &config.Config{}
+	if got := kubernetesTarget(testCfg); got != "in-cluster, all namespaces" {
+		t.Errorf("kubernetesTarget() = %q", got)
+	}
+	testCfg.Kubernetes = config.KubernetesConfig{APIServer: "http://127.0.0.1:8001", Namespace: "shop", LabelSelector: "app=web"}
+	if got := kubernetesTarget(testCfg); got != "http://127.0.0.1:8001, shop, app=web" {
+		t.Errorf("kubernetesTarget() = %q", got)
+	}
+}
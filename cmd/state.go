@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -35,10 +38,11 @@ and the timestamp of the last processed log entry for each.`,
 		}
 
 		// Load state
-		st, err := state.Load(cfg.Output.StateFile)
+		st, err := state.Open(cfg.Output.StateBackend, cfg.Output.StateFile)
 		if err != nil {
 			return fmt.Errorf("failed to load state: %w", err)
 		}
+		defer st.Close() //nolint:errcheck // Close error not actionable in defer context
 
 		containers := st.GetAllContainers()
 
@@ -58,12 +62,9 @@ and the timestamp of the last processed log entry for each.`,
 		_, _ = fmt.Fprintln(w, "------------\t----\t---------\t------")
 
 		for id, ctr := range containers {
-			shortID := id
-			if len(shortID) > 12 {
-				shortID = shortID[:12]
-			}
+			shortID := shortContainerID(id, cfg.Output.ShortIDLength)
 
-			lastScan := ctr.LastScan.Format("2006-01-02 15:04:05")
+			lastScan := ctr.LastScan.In(cfg.DisplayLocation()).Format("2006-01-02 15:04:05")
 			if ctr.LastScan.IsZero() {
 				lastScan = "Never"
 			}
@@ -80,14 +81,141 @@ and the timestamp of the last processed log entry for each.`,
 		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Total: %d container(s)\n", len(containers))
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "State file: %s\n", cfg.Output.StateFile)
-		if !st.LastUpdated.IsZero() {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last updated: %s\n", st.LastUpdated.Format(time.RFC3339))
+		if updatedAt := st.UpdatedAt(); !updatedAt.IsZero() {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last updated: %s\n", updatedAt.In(cfg.DisplayLocation()).Format(time.RFC3339))
+		}
+
+		return nil
+	},
+}
+
+var stateShowJSON bool
+
+var stateShowCmd = &cobra.Command{
+	Use:   cmdShow + " <container-name-or-id>",
+	Short: "Show the stored scan state of one container",
+	Long: `Display the state stored for a single container: its ID, name, last scan
+time, log cursor and the line fingerprints kept for cross-scan deduplication.
+
+The container is matched by exact name, exact ID or ID prefix. A container that
+is not in the state file is reported, not treated as an error.`,
+	Example: `  # Show state by name
+  dlia state show nginx
+
+  # Show state by ID prefix as JSON
+  dlia state show 3f2b8c1e --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg = GetConfig()
+		if err := validateConfigOrExit(cfg, "state"); err != nil {
+			return err
+		}
+
+		st, err := state.Open(cfg.Output.StateBackend, cfg.Output.StateFile)
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+		defer st.Close() //nolint:errcheck // Close error not actionable in defer context
+
+		id, ctr, err := findStateContainer(st.GetAllContainers(), args[0])
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if ctr == nil {
+			if stateShowJSON {
+				// Keep stdout valid JSON for scripts; explain on stderr
+				_, _ = fmt.Fprintln(out, "null")
+				out = cmd.ErrOrStderr()
+			}
+			_, _ = fmt.Fprintf(out, "ℹ️  Container %q is not tracked in the state file\n", args[0])
+			_, _ = fmt.Fprintf(out, "   State file: %s\n", cfg.Output.StateFile)
+			return nil
 		}
 
+		if stateShowJSON {
+			return writeStateContainerJSON(out, id, ctr)
+		}
+		displayStateContainer(out, id, ctr, cfg.DisplayLocation())
 		return nil
 	},
 }
 
+// findStateContainer looks up a tracked container by exact name, exact ID or unique
+// ID prefix, in that order. When a recreated container left several IDs under one
+// name, the most recently scanned one is returned. Returns a nil container if none
+// matches.
+func findStateContainer(containers map[string]*state.Container, nameOrID string) (string, *state.Container, error) {
+	var nameID string
+	for id, ctr := range containers {
+		if ctr.Name == nameOrID && (nameID == "" || ctr.LastScan.After(containers[nameID].LastScan)) {
+			nameID = id
+		}
+	}
+	if nameID != "" {
+		return nameID, containers[nameID], nil
+	}
+
+	if ctr, ok := containers[nameOrID]; ok {
+		return nameOrID, ctr, nil
+	}
+
+	var matches []string
+	for id := range containers {
+		if strings.HasPrefix(id, nameOrID) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil, nil
+	case 1:
+		return matches[0], containers[matches[0]], nil
+	default:
+		return "", nil, fmt.Errorf("ID prefix %q is ambiguous: matches %d containers", nameOrID, len(matches))
+	}
+}
+
+// displayStateContainer prints the state of one container in human-readable form,
+// with the last scan time shown in loc.
+func displayStateContainer(w io.Writer, id string, ctr *state.Container, loc *time.Location) {
+	lastScan := ctr.LastScan.In(loc).Format(time.RFC3339)
+	if ctr.LastScan.IsZero() {
+		lastScan = "Never"
+	}
+	cursor := ctr.LogCursor
+	if cursor == "" {
+		cursor = "-"
+	}
+	fingerprints := 0
+	for _, scan := range ctr.Fingerprints {
+		fingerprints += len(scan)
+	}
+
+	_, _ = fmt.Fprintf(w, "📊 State for %s\n\n", ctr.Name)
+	_, _ = fmt.Fprintf(w, "   ID:           %s\n", id)
+	_, _ = fmt.Fprintf(w, "   Name:         %s\n", ctr.Name)
+	_, _ = fmt.Fprintf(w, "   Last Scan:    %s\n", lastScan)
+	_, _ = fmt.Fprintf(w, "   Log Cursor:   %s\n", cursor)
+	_, _ = fmt.Fprintf(w, "   Fingerprints: %d line(s) from %d scan(s)\n", fingerprints, len(ctr.Fingerprints))
+}
+
+// writeStateContainerJSON writes the container's state fields together with its ID.
+func writeStateContainerJSON(w io.Writer, id string, ctr *state.Container) error {
+	out := struct {
+		ID string `json:"id"`
+		*state.Container
+	}{ID: id, Container: ctr}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("failed to encode state as JSON: %w", err)
+	}
+	return nil
+}
+
 var stateResetCmd = &cobra.Command{
 	Use:   "reset [container-filter]",
 	Short: "Reset log scan state (optionally for specific containers)",
@@ -134,10 +262,11 @@ which may result in duplicate analysis and higher LLM costs.`,
 		}
 
 		// Load state
-		st, err := state.Load(cfg.Output.StateFile)
+		st, err := state.Open(cfg.Output.StateBackend, cfg.Output.StateFile)
 		if err != nil {
 			return fmt.Errorf("failed to load state: %w", err)
 		}
+		defer st.Close() //nolint:errcheck // Close error not actionable in defer context
 
 		if filter == "" {
 			// Reset all
@@ -173,8 +302,11 @@ which may result in duplicate analysis and higher LLM costs.`,
 func init() {
 	rootCmd.AddCommand(stateCmd)
 	stateCmd.AddCommand(stateListCmd)
+	stateCmd.AddCommand(stateShowCmd)
 	stateCmd.AddCommand(stateResetCmd)
 
+	stateShowCmd.Flags().BoolVar(&stateShowJSON, "json", false, "print the state as JSON")
+
 	// Reset-specific flags
 	stateResetCmd.Flags().BoolVar(&force, "force", false, "confirm state reset")
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -505,7 +506,7 @@ func TestStateCmd_Subcommands(t *testing.T) {
 	// Verify subcommands are registered
 	subcommands := stateCmd.Commands()
 
-	expectedSubcommands := []string{"list", "reset"}
+	expectedSubcommands := []string{"list", "show", "reset"}
 	foundSubcommands := make(map[string]bool)
 
 	for _, subcmd := range subcommands {
@@ -655,3 +656,140 @@ func TestStateResetCmd_MaximumOneArg(t *testing.T) {
 		t.Error("Expected error with 2 args")
 	}
 }
+
+func TestFindStateContainer(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	containers := map[string]*state.Container{
+		"abc123def456": {Name: "web", LastScan: now.Add(-time.Hour)},
+		"abc999000111": {Name: "web", LastScan: now},
+		"fed456abc123": {Name: "db", LastScan: now},
+	}
+
+	tests := []struct {
+		query   string
+		wantID  string
+		wantErr bool
+	}{
+		{"web", "abc999000111", false}, // Most recently scanned of two IDs
+		{"db", "fed456abc123", false},
+		{"fed456abc123", "fed456abc123", false},
+		{"fed4", "fed456abc123", false},
+		{"abc", "", true}, // Ambiguous prefix
+		{"cache", "", false},
+	}
+
+	for _, tt := range tests {
+		id, ctr, err := findStateContainer(containers, tt.query)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("findStateContainer(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if id != tt.wantID {
+			t.Errorf("findStateContainer(%q) id = %q, want %q", tt.query, id, tt.wantID)
+		}
+		if (ctr != nil) != (tt.wantID != "") {
+			t.Errorf("findStateContainer(%q) container = %v", tt.query, ctr)
+		}
+	}
+}
+
+func TestStateShowCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateFile := filepath.Join(tmpDir, "state.json")
+	configFile := filepath.Join(tmpDir, "config.yaml")
+
+	if err := os.WriteFile(configFile, []byte("llm:\n  api_key: test\n"), 0600); err != nil {
+		t.Fatalf("Failed to create config file: %v", err)
+	}
+
+	viper.Reset()
+	viper.SetConfigFile(configFile)
+	viper.Set("llm.api_key", "test-key")
+	viper.Set("llm.model", "test-model")
+	viper.Set("llm.base_url", "http://test")
+	viper.Set("docker.socket_path", "unix:///test")
+	viper.Set("output.reports_dir", tmpDir)
+	viper.Set("output.knowledge_base_dir", tmpDir)
+	viper.Set("output.state_file", stateFile)
+
+	testCfg, err := config.LoadFromViper()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	originalCfg := cfg
+	cfg = testCfg
+	defer func() {
+		cfg = originalCfg
+		stateShowJSON = false
+	}()
+
+	lastScan := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	st, err := state.Load(stateFile)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	st.UpdateContainer("abc123def456", "web", lastScan, "2025-01-02T03:04:05.123Z")
+	if err := st.Save(); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	var buf bytes.Buffer
+	stateShowCmd.SetOut(&buf)
+	stateShowCmd.SetErr(&buf)
+
+	if err := stateShowCmd.RunE(stateShowCmd, []string{"abc123"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, want := range []string{"abc123def456", "web", "2025-01-02T03:04:05Z", "2025-01-02T03:04:05.123Z"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in output, got: %s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	stateShowJSON = true
+	if err := stateShowCmd.RunE(stateShowCmd, []string{"web"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var got struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		LastScan  time.Time `json:"last_scan"`
+		LogCursor string    `json:"log_cursor"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", buf.String(), err)
+	}
+	if got.ID != "abc123def456" || got.Name != "web" || !got.LastScan.Equal(lastScan) || got.LogCursor != "2025-01-02T03:04:05.123Z" {
+		t.Errorf("Unexpected JSON state: %+v", got)
+	}
+
+	buf.Reset()
+	stateShowJSON = false
+	if err := stateShowCmd.RunE(stateShowCmd, []string{"missing"}); err != nil {
+		t.Fatalf("Untracked container must not be an error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "not tracked") {
+		t.Errorf("Expected not tracked message, got: %s", buf.String())
+	}
+}
+
+func TestDisplayStateContainer_DisplayTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	ctr := &state.Container{Name: "web", LastScan: time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)}
+
+	var buf bytes.Buffer
+	displayStateContainer(&buf, "abc", ctr, loc)
+	if !strings.Contains(buf.String(), "2025-01-02T10:00:00-05:00") {
+		t.Errorf("Expected last scan in New York time, got: %s", buf.String())
+	}
+	if ctr.LastScan.Location() != time.UTC {
+		t.Error("Stored time must stay in UTC")
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/fsutil"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+// executiveSummaryFile is the file in the knowledge base directory that dlia summary writes.
+const executiveSummaryFile = "executive_summary.md"
+
+var (
+	summarySince  string
+	summaryNoSave bool
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   cmdSummary,
+	Short: "Generate the executive summary from the knowledge base",
+	Long: `Summary regenerates the cross-container executive summary without scanning.
+
+It reads the newest scan entry of every container from the knowledge base,
+sends them to the LLM with the executive summary prompt, prints the digest and
+saves it to knowledge_base/executive_summary.md. Only one LLM call is made,
+so refreshing the summary is cheap compared to a full scan.`,
+	Example: `  # Summarize the latest state of all containers
+  dlia summary
+
+  # Ignore containers that have not been scanned in the last 24 hours
+  dlia summary --since 24h
+
+  # Print only, do not write executive_summary.md
+  dlia summary --no-save`,
+	Args: cobra.NoArgs,
+	RunE: runSummary,
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+
+	summaryCmd.Flags().StringVar(&summarySince, "since", "", "only use containers scanned within this duration (e.g., 24h, 168h)")
+	summaryCmd.Flags().BoolVar(&summaryNoSave, "no-save", false, "print the summary without saving it to the knowledge base")
+}
+
+func runSummary(cmd *cobra.Command, _ []string) error {
+	cfg = GetConfig()
+	if err := validateConfigOrExit(cfg, cmdSummary); err != nil {
+		return err
+	}
+	if len(cfg.LLM.Keys()) == 0 && cfg.LLM.Provider != config.ProviderOllama {
+		return fmt.Errorf("LLM API key not configured (set DLIA_LLM_API_KEY in .env)")
+	}
+
+	var since time.Time
+	if summarySince != "" {
+		duration, err := time.ParseDuration(summarySince)
+		if err != nil {
+			return fmt.Errorf("invalid since duration '%s': %w (use format like: 1h, 24h, 30m)", summarySince, err)
+		}
+		since = time.Now().Add(-duration)
+	}
+
+	containerAnalyses, err := knowledge.LatestAnalyses(cfg.Output.KnowledgeBaseDir, since)
+	if err != nil {
+		return fmt.Errorf("failed to read knowledge base: %w", err)
+	}
+
+	w := cmd.OutOrStdout()
+	if len(containerAnalyses) == 0 {
+		_, _ = fmt.Fprintln(w, "ℹ️  No knowledge base entries found; run 'dlia scan' first")
+		return nil
+	}
+
+	prompts.InitPrompts(cfg)
+
+	_, _ = fmt.Fprintf(w, "📊 Generating executive summary for %d container(s)...\n\n", len(containerAnalyses))
+	summary, err := generateExecutiveSummary(context.Background(), nil, containerAnalyses, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to generate executive summary: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(w, summary)
+
+	if summaryNoSave {
+		return nil
+	}
+
+	path, err := saveExecutiveSummary(cfg, summary, containerAnalyses, time.Now())
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "\n💾 Saved to %s\n", path)
+	return nil
+}
+
+// saveExecutiveSummary writes the digest and the list of summarized containers to
+// executiveSummaryFile in the knowledge base directory and returns its path.
+func saveExecutiveSummary(cfg *config.Config, summary string, containerAnalyses map[string]string, now time.Time) (string, error) {
+	if err := os.MkdirAll(cfg.Output.KnowledgeBaseDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create KB directory: %w", err)
+	}
+
+	path := filepath.Join(cfg.Output.KnowledgeBaseDir, executiveSummaryFile)
+	content := formatExecutiveSummary(summary, containerAnalyses, now)
+	if err := fsutil.WriteFileAtomic(path, []byte(content), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write executive summary: %w", err)
+	}
+	return path, nil
+}
+
+// formatExecutiveSummary renders the executive summary file: the digest followed by
+// the summarized containers and their severity.
+func formatExecutiveSummary(summary string, containerAnalyses map[string]string, now time.Time) string {
+	names := make([]string, 0, len(containerAnalyses))
+	for name := range containerAnalyses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("# Executive Summary\n\n")
+	_, _ = fmt.Fprintf(&sb, "_Generated: %s from the latest knowledge base entries of %d container(s)_\n\n", now.Format("2006-01-02 15:04:05"), len(names))
+	sb.WriteString(summary)
+	sb.WriteString("\n\n## Containers\n\n")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&sb, "- %s: %s\n", name, knowledge.ClassifySeverity(containerAnalyses[name]))
+	}
+	return sb.String()
+}
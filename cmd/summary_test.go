@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+func TestFormatExecutiveSummary(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	got := formatExecutiveSummary("All systems nominal.", map[string]string{
+		"web": "All good",
+		"db":  "Critical: disk full",
+	}, now)
+
+	for _, want := range []string{
+		"# Executive Summary",
+		"_Generated: 2025-01-02 03:04:05 from the latest knowledge base entries of 2 container(s)_",
+		"All systems nominal.",
+		"- db: critical\n- web: healthy\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatExecutiveSummary() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestSaveExecutiveSummary(t *testing.T) {
+	t.Parallel()
+
+	kbDir := filepath.Join(t.TempDir(), "kb")
+	testCfg := &config.Config{Output: config.OutputConfig{KnowledgeBaseDir: kbDir}}
+
+	path, err := saveExecutiveSummary(testCfg, "Digest", map[string]string{"web": "All good"}, time.Now())
+	if err != nil {
+		t.Fatalf("saveExecutiveSummary() error = %v", err)
+	}
+	if path != filepath.Join(kbDir, executiveSummaryFile) {
+		t.Errorf("path = %s, want %s", path, filepath.Join(kbDir, executiveSummaryFile))
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // Test code reading file created by the test
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Digest") {
+		t.Errorf("saved summary missing digest: %s", data)
+	}
+}
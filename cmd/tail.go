@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/chunking"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/ui"
+)
+
+var (
+	tailBatchLines int
+	tailInterval   time.Duration
+	tailLLMLog     bool
+)
+
+var tailCmd = &cobra.Command{
+	Use:   cmdTail + " <container>",
+	Short: "Follow a container's logs and analyze them continuously",
+	Long: `Tail follows the logs of a single running container in real time and runs a
+rolling LLM analysis over the new lines whenever --batch-lines lines have been
+buffered or --interval has passed, whichever comes first.
+
+Tail only prints the analyses; it does not update the state file, reports or the
+knowledge base. Stop it with Ctrl+C.`,
+	Example: `  # Follow a container and analyze every 100 lines or 30 seconds
+  dlia tail my-app
+
+  # Smaller, more frequent batches
+  dlia tail my-app --batch-lines 20 --interval 10s`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTail,
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(tailCmd)
+
+	tailCmd.Flags().IntVar(&tailBatchLines, "batch-lines", 100, "analyze once this many new lines are buffered")
+	tailCmd.Flags().DurationVar(&tailInterval, "interval", 30*time.Second, "analyze buffered lines at least this often")
+	tailCmd.Flags().BoolVar(&tailLLMLog, "llmlog", false, "enable logging of all LLM requests and responses to markdown files")
+}
+
+func runTail(_ *cobra.Command, args []string) error {
+	cfg = GetConfig()
+	if err := validateConfigOrExit(cfg, cmdTail); err != nil {
+		return err
+	}
+
+	if tailBatchLines < 1 {
+		return fmt.Errorf("--batch-lines must be at least 1, got %d", tailBatchLines)
+	}
+	if tailInterval <= 0 {
+		return fmt.Errorf("--interval must be a positive duration, got %s", tailInterval)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dockerClient, err := connectLogSource(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close() //nolint:errcheck // Close error not actionable in defer context
+
+	container, err := findScanContainer(ctx, dockerClient, args[0], false)
+	if err != nil {
+		return err
+	}
+
+	scanCfg := &scanConfig{llmLog: tailLLMLog, verbose: verbose}
+	pipeline, err := initializeLLMPipeline(cfg, scanCfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize LLM: %w", err)
+	}
+
+	entries, err := dockerClient.FollowLogs(ctx, container.ID)
+	if err != nil {
+		return err
+	}
+
+	ui.Printf("👀 Following %s (ID: %s); analyzing every %d lines or %s. Press Ctrl+C to stop.\n\n",
+		container.Name, shortContainerID(container.ID, cfg.Output.ShortIDLength), tailBatchLines, tailInterval)
+
+	analyzed := bufferAndAnalyze(ctx, entries, tailBatchLines, tailInterval, func(batch []docker.LogEntry) {
+		analyzeTailBatch(ctx, pipeline, container.Name, batch, scanCfg)
+	})
+
+	ui.Printf("\n✅ Stopped following %s (%d batch(es) analyzed)\n", container.Name, analyzed)
+	return nil
+}
+
+// bufferAndAnalyze collects streamed entries and hands them to analyze whenever
+// batchLines entries are buffered or interval passes with entries pending. Entries
+// still buffered when the stream ends are analyzed; those pending when ctx is
+// cancelled are dropped. Returns the number of batches analyzed.
+func bufferAndAnalyze(ctx context.Context, entries <-chan docker.LogEntry, batchLines int, interval time.Duration, analyze func([]docker.LogEntry)) int {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		buffer  []docker.LogEntry
+		batches int
+	)
+	flush := func() {
+		if len(buffer) == 0 {
+			return
+		}
+		analyze(buffer)
+		batches++
+		buffer = nil
+		ticker.Reset(interval)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return batches
+		case entry, ok := <-entries:
+			if !ok {
+				flush()
+				return batches
+			}
+			buffer = append(buffer, entry)
+			if len(buffer) >= batchLines {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// analyzeTailBatch runs one rolling analysis and prints the result.
+func analyzeTailBatch(ctx context.Context, pipeline *chunking.Pipeline, containerName string, batch []docker.LogEntry, scanCfg *scanConfig) {
+	ui.Printf("[%s] 🤖 Analyzing %d new log line(s)...\n", time.Now().Format("15:04:05"), len(batch))
+
+	result, err := pipeline.AnalyzeLogs(ctx, containerName, batch)
+	if err != nil {
+		if ctx.Err() == nil {
+			ui.Printf("        ⚠️  LLM analysis failed: %v\n\n", err)
+		}
+		return
+	}
+
+	displayAnalysisResults(result, scanCfg)
+}
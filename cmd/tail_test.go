@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/docker"
+)
+
+func TestBufferAndAnalyze_BatchesByLines(t *testing.T) {
+	t.Parallel()
+
+	entries := make(chan docker.LogEntry, 5)
+	for i := 0; i < 5; i++ {
+		entries <- docker.LogEntry{Message: "line"}
+	}
+	close(entries)
+
+	var sizes []int
+	batches := bufferAndAnalyze(context.Background(), entries, 2, time.Hour, func(batch []docker.LogEntry) {
+		sizes = append(sizes, len(batch))
+	})
+
+	if batches != 3 {
+		t.Errorf("Expected 3 batches, got %d", batches)
+	}
+	want := []int{2, 2, 1}
+	for i, size := range want {
+		if i >= len(sizes) || sizes[i] != size {
+			t.Fatalf("Expected batch sizes %v, got %v", want, sizes)
+		}
+	}
+}
+
+func TestBufferAndAnalyze_FlushesOnInterval(t *testing.T) {
+	t.Parallel()
+
+	entries := make(chan docker.LogEntry)
+	analyzed := make(chan int, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan int)
+	go func() {
+		done <- bufferAndAnalyze(ctx, entries, 100, 20*time.Millisecond, func(batch []docker.LogEntry) {
+			analyzed <- len(batch)
+		})
+	}()
+
+	entries <- docker.LogEntry{Message: "only line"}
+
+	select {
+	case size := <-analyzed:
+		if size != 1 {
+			t.Errorf("Expected a batch of 1, got %d", size)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the buffered line to be analyzed after the interval")
+	}
+
+	cancel()
+	if batches := <-done; batches != 1 {
+		t.Errorf("Expected 1 batch, got %d", batches)
+	}
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zorak1103/dlia/internal/version"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   cmdVersion,
+	Short: "Print version and build information",
+	Long: `Print the DLIA version, git commit and build date, together with the Go version
+and OS/architecture of the binary. Please include this output in bug reports.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), version.Get().String())
+	},
+}
+
+// nolint:gochecknoinits // Standard Cobra pattern for command registration
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	// --version prints the same details as dlia version
+	rootCmd.SetVersionTemplate(version.Get().String())
+}
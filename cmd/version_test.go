@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestVersionCommand(t *testing.T) {
+	var out bytes.Buffer
+	versionCmd.SetOut(&out)
+	defer versionCmd.SetOut(nil)
+
+	versionCmd.Run(versionCmd, nil)
+
+	got := out.String()
+	for _, want := range []string{"dlia version ", "Commit:", "Built:", runtime.Version(), runtime.GOOS + "/" + runtime.GOARCH} {
+		if !strings.Contains(got, want) {
+			t.Errorf("version output = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestVersionFlag_UsesBuildDetails(t *testing.T) {
+	if !strings.Contains(rootCmd.VersionTemplate(), "Go version: "+runtime.Version()) {
+		t.Errorf("--version template = %q, want full build details", rootCmd.VersionTemplate())
+	}
+}
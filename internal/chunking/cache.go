@@ -0,0 +1,116 @@
+package chunking
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/fsutil"
+	"github.com/zorak1103/dlia/internal/llm"
+)
+
+// ResultCache stores analyses on disk keyed by a hash of everything sent to the
+// LLM, so re-analyzing identical input (e.g. a repeated --lookback window) costs no
+// tokens. Entries older than the TTL are ignored on lookup and removed by the
+// first write of each cache, so entries nobody looks up again do not pile up.
+type ResultCache struct {
+	dir       string
+	ttl       time.Duration
+	now       func() time.Time
+	pruneOnce sync.Once
+}
+
+// CachedAnalysis is an analysis stored by ResultCache.
+type CachedAnalysis struct {
+	CreatedAt  time.Time               `json:"created_at"`
+	Analysis   string                  `json:"analysis"`
+	Structured *llm.StructuredAnalysis `json:"structured,omitempty"`
+	ChunksUsed int                     `json:"chunks_used"`
+}
+
+// NewResultCache creates a cache storing its entries in dir (created on first write)
+// that are valid for ttl.
+func NewResultCache(dir string, ttl time.Duration) *ResultCache {
+	return &ResultCache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// CacheKey hashes the given parts into a cache key. Parts are length-prefixed, so
+// moving text from one part to the next changes the key.
+func CacheKey(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(h, "%d:%s", len(part), part) // hash.Hash writes never fail
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the file of the entry with the given key.
+func (c *ResultCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Get returns the cached analysis for key. It reports false for a missing,
+// unreadable or expired entry.
+func (c *ResultCache) Get(key string) (*CachedAnalysis, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CachedAnalysis
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if c.now().Sub(entry.CreatedAt) > c.ttl {
+		_ = os.Remove(c.path(key)) // Expired; a failed removal is retried on the next lookup
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put stores the analysis of result under key. The first Put of a cache also
+// removes all expired entries.
+func (c *ResultCache) Put(key string, result *AnalyzeResult) error {
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory %s: %w", c.dir, err)
+	}
+	c.pruneOnce.Do(c.prune)
+
+	data, err := json.Marshal(CachedAnalysis{
+		CreatedAt:  c.now(),
+		Analysis:   result.Analysis,
+		Structured: result.Structured,
+		ChunksUsed: result.ChunksUsed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cached analysis: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(c.path(key), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cached analysis: %w", err)
+	}
+	return nil
+}
+
+// prune removes expired and unreadable entries. Failures are ignored; the next
+// scan retries them.
+func (c *ResultCache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		key := strings.TrimSuffix(e.Name(), ".json")
+		if _, ok := c.Get(key); !ok {
+			_ = os.Remove(c.path(key))
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package chunking
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/llm"
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+func TestCacheKey(t *testing.T) {
+	assert.Equal(t, CacheKey("gpt-4", "system", "logs"), CacheKey("gpt-4", "system", "logs"))
+	assert.NotEqual(t, CacheKey("gpt-4", "system", "logs"), CacheKey("gpt-4o", "system", "logs"))
+	assert.NotEqual(t, CacheKey("ab", "c"), CacheKey("a", "bc"), "parts must not run into each other")
+}
+
+func TestResultCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	now := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	cache := NewResultCache(dir, time.Hour)
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.Get("missing")
+	assert.False(t, ok)
+
+	structured := &llm.StructuredAnalysis{Severity: "warning", Summary: "slow queries"}
+	require.NoError(t, cache.Put("key", &AnalyzeResult{Analysis: "analysis", Structured: structured, ChunksUsed: 3, TokensUsed: 900}))
+
+	now = now.Add(30 * time.Minute)
+	cached, ok := cache.Get("key")
+	require.True(t, ok)
+	assert.Equal(t, "analysis", cached.Analysis)
+	assert.Equal(t, structured, cached.Structured)
+	assert.Equal(t, 3, cached.ChunksUsed)
+
+	now = now.Add(time.Hour)
+	_, ok = cache.Get("key")
+	assert.False(t, ok, "entries older than the TTL expire")
+	_, err := os.Stat(filepath.Join(dir, "key.json"))
+	assert.True(t, os.IsNotExist(err), "an expired entry is removed")
+}
+
+func TestResultCache_PrunesExpiredEntriesOnPut(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	old := NewResultCache(dir, time.Hour)
+	old.now = func() time.Time { return now }
+	require.NoError(t, old.Put("stale", &AnalyzeResult{Analysis: "stale"}))
+	require.NoError(t, old.Put("recent", &AnalyzeResult{Analysis: "recent"}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600))
+
+	// A later scan writes a new entry without ever looking up the stale one
+	now = now.Add(2 * time.Hour)
+	cache := NewResultCache(dir, time.Hour)
+	cache.now = func() time.Time { return now }
+	require.NoError(t, cache.Put("recent", &AnalyzeResult{Analysis: "recent"}))
+	require.NoError(t, cache.Put("new", &AnalyzeResult{Analysis: "new"}))
+
+	_, err := os.Stat(filepath.Join(dir, "stale.json"))
+	assert.True(t, os.IsNotExist(err), "an expired entry is pruned")
+	_, err = os.Stat(filepath.Join(dir, "broken.json"))
+	assert.True(t, os.IsNotExist(err), "an unreadable entry is pruned")
+	_, ok := cache.Get("recent")
+	assert.True(t, ok)
+	_, ok = cache.Get("new")
+	assert.True(t, ok)
+}
+
+func TestPipeline_ResultCache(t *testing.T) {
+	client := NewMockLLMClient()
+	client.analyzeResponse = "first analysis"
+	pipeline := &Pipeline{
+		client:       client,
+		maxTokens:    8000,
+		tokenizer:    NewMockTokenizer(0.1),
+		promptLoader: prompts.NewPromptLoader(&config.Config{}),
+		ignoreDir:    t.TempDir(),
+		model:        "test-model",
+		cache:        NewResultCache(t.TempDir(), time.Hour),
+	}
+	logs := []docker.LogEntry{{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "ERROR disk full"}}
+
+	first, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.False(t, first.CacheHit)
+	assert.Equal(t, 150, first.TokensUsed)
+
+	client.analyzeResponse = "second analysis"
+	cached, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.True(t, cached.CacheHit)
+	assert.Equal(t, "first analysis", cached.Analysis)
+	assert.Zero(t, cached.TokensUsed)
+
+	changed := []docker.LogEntry{{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "ERROR disk almost full"}}
+	fresh, err := pipeline.AnalyzeLogs(context.Background(), "web", changed)
+	require.NoError(t, err)
+	assert.False(t, fresh.CacheHit, "different logs must not hit the cache")
+	assert.Equal(t, "second analysis", fresh.Analysis)
+
+	pipeline.model = "other-model"
+	otherModel, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.False(t, otherModel.CacheHit, "a different model must not hit the cache")
+
+	pipeline.model = "test-model"
+	pipeline.config = &config.Config{}
+	pipeline.config.LLM.TopP = 0.9
+	otherSampling, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.False(t, otherSampling.CacheHit, "different sampling settings must not hit the cache")
+
+	pipeline.config.LLM.FrequencyPenalty = 0.5
+	otherPenalty, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.False(t, otherPenalty.CacheHit, "a different frequency_penalty must not hit the cache")
+
+	pipeline.maxTokens = 16000
+	otherWindow, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.False(t, otherWindow.CacheHit, "a different context window must not hit the cache")
+
+	promptDir := t.TempDir()
+	synthesisPath := filepath.Join(promptDir, "synthesis.md")
+	require.NoError(t, os.WriteFile(synthesisPath, []byte("Combine {{.Summaries}} for {{.ContainerName}}"), 0o600))
+	promptCfg := &config.Config{}
+	promptCfg.LLM = pipeline.config.LLM
+	promptCfg.Prompts.SynthesisPrompt = synthesisPath
+	pipeline.promptLoader = prompts.NewPromptLoader(promptCfg)
+	otherSynthesis, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.False(t, otherSynthesis.CacheHit, "a different synthesis prompt must not hit the cache")
+}
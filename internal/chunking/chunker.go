@@ -14,6 +14,7 @@ type Chunk struct {
 	TokenCount int
 	Index      int
 	Total      int
+	Overlap    int // Leading entries repeated from the end of the previous chunk
 }
 
 // ChunkLogs splits logs into token-sized chunks suitable for token-limited LLM contexts.
@@ -36,45 +37,60 @@ type Chunk struct {
 //
 // Returns an empty slice if logs is empty. Each chunk includes Index (0-based) and Total for progress tracking.
 func ChunkLogs(logs []docker.LogEntry, maxTokensPerChunk int, tokenizer TokenizerInterface) []Chunk {
+	return ChunkLogsWithOverlap(logs, maxTokensPerChunk, 0, tokenizer)
+}
+
+// ChunkLogsWithOverlap works like ChunkLogs, but starts every chunk after the first
+// with up to overlapLines entries from the end of the previous chunk, so context that
+// spans a chunk boundary is seen together. The repeated entries count against
+// maxTokensPerChunk and are limited to half of it; fewer are repeated when they would
+// not fit, so every chunk still contains at least one new entry. Chunk.Overlap holds
+// the number of repeated entries.
+func ChunkLogsWithOverlap(logs []docker.LogEntry, maxTokensPerChunk, overlapLines int, tokenizer TokenizerInterface) []Chunk {
 	if len(logs) == 0 {
 		return []Chunk{}
 	}
 
-	var chunks []Chunk
-	currentChunk := []docker.LogEntry{}
-	currentTokens := 0
-
-	for _, log := range logs {
-		// Format log entry
-		logText := fmt.Sprintf("[%s] %s\n", log.Timestamp, log.Message)
-		logTokens := tokenizer.CountTokens(logText)
+	logTokens := make([]int, len(logs))
+	for i, log := range logs {
+		logTokens[i] = tokenizer.CountTokens(fmt.Sprintf("[%s] %s\n", log.Timestamp, log.Message))
+	}
 
-		// Check if adding this log would exceed limit
-		if currentTokens+logTokens > maxTokensPerChunk && len(currentChunk) > 0 {
-			// Save current chunk
-			chunks = append(chunks, Chunk{
-				Logs:       currentChunk,
-				TokenCount: currentTokens,
-			})
+	var chunks []Chunk
+	start, overlap, currentTokens := 0, 0, 0
 
-			// Start new chunk
-			currentChunk = []docker.LogEntry{log}
-			currentTokens = logTokens
-		} else {
-			// Add to current chunk
-			currentChunk = append(currentChunk, log)
-			currentTokens += logTokens
+	for i := range logs {
+		// Close the current chunk if this log would exceed the limit, unless the chunk
+		// holds nothing but repeated entries yet
+		if currentTokens+logTokens[i] <= maxTokensPerChunk || i-start <= overlap {
+			currentTokens += logTokens[i]
+			continue
 		}
-	}
 
-	// Add final chunk
-	if len(currentChunk) > 0 {
 		chunks = append(chunks, Chunk{
-			Logs:       currentChunk,
+			Logs:       logs[start:i:i],
 			TokenCount: currentTokens,
+			Overlap:    overlap,
 		})
+
+		// Start the next chunk with the tail of this one, as far as it fits
+		overlap = min(overlapLines, i-start)
+		overlapTokens := sumTokens(logTokens[i-overlap : i])
+		for overlap > 0 && (overlapTokens > maxTokensPerChunk/2 || overlapTokens+logTokens[i] > maxTokensPerChunk) {
+			overlapTokens -= logTokens[i-overlap]
+			overlap--
+		}
+		start = i - overlap
+		currentTokens = overlapTokens + logTokens[i]
 	}
 
+	// Add final chunk
+	chunks = append(chunks, Chunk{
+		Logs:       logs[start:len(logs):len(logs)],
+		TokenCount: currentTokens,
+		Overlap:    overlap,
+	})
+
 	// Set index and total for each chunk
 	total := len(chunks)
 	for i := range chunks {
@@ -85,6 +101,15 @@ func ChunkLogs(logs []docker.LogEntry, maxTokensPerChunk int, tokenizer Tokenize
 	return chunks
 }
 
+// sumTokens returns the sum of the token counts.
+func sumTokens(tokens []int) int {
+	total := 0
+	for _, n := range tokens {
+		total += n
+	}
+	return total
+}
+
 // FormatChunk converts a chunk to a formatted string with timestamps.
 // Each log entry is formatted as "[timestamp] message\n". Entries without
 // timestamps are formatted as "message\n".
@@ -1,6 +1,8 @@
 package chunking
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -184,6 +186,63 @@ func TestChunkLogs(t *testing.T) {
 	}
 }
 
+func TestChunkLogsWithOverlap(t *testing.T) {
+	// Each entry formats to "[t] mNN\n", 8 tokens at one token per character
+	logs := make([]docker.LogEntry, 10)
+	for i := range logs {
+		logs[i] = docker.LogEntry{Timestamp: "t", Message: fmt.Sprintf("m%02d", i)}
+	}
+	tokenizer := NewMockTokenizer(1)
+
+	tests := []struct {
+		name         string
+		overlapLines int
+		wantFirst    []string // Message of the first entry of each chunk
+		wantOverlap  []int
+	}{
+		{name: "no overlap", overlapLines: 0, wantFirst: []string{"m00", "m05"}, wantOverlap: []int{0, 0}},
+		{name: "two lines", overlapLines: 2, wantFirst: []string{"m00", "m03", "m06"}, wantOverlap: []int{0, 2, 2}},
+		{name: "limited to half the chunk", overlapLines: 10, wantFirst: []string{"m00", "m03", "m06"}, wantOverlap: []int{0, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := ChunkLogsWithOverlap(logs, 40, tt.overlapLines, tokenizer)
+			require.Len(t, chunks, len(tt.wantFirst))
+
+			seen := make(map[string]bool)
+			for i, chunk := range chunks {
+				assert.Equal(t, tt.wantFirst[i], chunk.Logs[0].Message)
+				assert.Equal(t, tt.wantOverlap[i], chunk.Overlap)
+				assert.LessOrEqual(t, chunk.TokenCount, 40, "overlap must fit in the chunk")
+				assert.Equal(t, 8*len(chunk.Logs), chunk.TokenCount)
+				assert.Equal(t, len(chunks), chunk.Total)
+				for _, log := range chunk.Logs {
+					seen[log.Message] = true
+				}
+			}
+			assert.Len(t, seen, len(logs), "every entry must be in a chunk")
+			assert.Equal(t, "m09", chunks[len(chunks)-1].Logs[len(chunks[len(chunks)-1].Logs)-1].Message)
+		})
+	}
+}
+
+func TestChunkLogsWithOverlap_OversizedEntry(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Timestamp: "t", Message: "m00"},
+		{Timestamp: "t", Message: strings.Repeat("x", 100)},
+		{Timestamp: "t", Message: "m02"},
+	}
+
+	chunks := ChunkLogsWithOverlap(logs, 40, 5, NewMockTokenizer(1))
+
+	// The oversized entry leaves no room for overlap but still gets its own chunk
+	require.Len(t, chunks, 3)
+	assert.Equal(t, 0, chunks[1].Overlap)
+	assert.Len(t, chunks[1].Logs, 1)
+	assert.Equal(t, "m02", chunks[2].Logs[0].Message)
+}
+
 func TestFormatChunk(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -0,0 +1,47 @@
+package chunking
+
+import (
+	"strings"
+
+	"github.com/zorak1103/dlia/internal/docker"
+)
+
+// CleanLogsAnalysis is the analysis recorded when llm.skip_clean_logs skips the LLM.
+const CleanLogsAnalysis = "No issues detected (heuristic, LLM skipped)"
+
+// BelowThresholdAnalysis is the analysis recorded when llm.min_log_lines skips the LLM.
+const BelowThresholdAnalysis = "Too few new log lines to analyze (below llm.min_log_lines, LLM skipped)"
+
+// Values of AnalyzeResult.SkipReason
+const (
+	SkipReasonCleanLogs      = "clean_logs"
+	SkipReasonBelowThreshold = "below_min_log_lines"
+)
+
+// looksClean reports whether logs are short enough and free of all keywords
+// (lowercase, matched as substrings of the lowercased message).
+func looksClean(logs []docker.LogEntry, maxLines int, keywords []string) bool {
+	if len(logs) > maxLines {
+		return false
+	}
+	for _, entry := range logs {
+		message := strings.ToLower(entry.Message)
+		for _, keyword := range keywords {
+			if strings.Contains(message, keyword) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lowerKeywords lowercases keywords and drops empty entries, which would match every line.
+func lowerKeywords(keywords []string) []string {
+	lowered := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			lowered = append(lowered, keyword)
+		}
+	}
+	return lowered
+}
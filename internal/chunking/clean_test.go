@@ -0,0 +1,87 @@
+package chunking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+func TestLooksClean(t *testing.T) {
+	keywords := lowerKeywords([]string{"ERROR", " warn ", ""})
+	logs := []docker.LogEntry{
+		{Message: "GET /health 200"},
+		{Message: "cache refreshed"},
+	}
+
+	assert.True(t, looksClean(logs, 2, keywords))
+	assert.False(t, looksClean(logs, 1, keywords), "more lines than allowed")
+	assert.False(t, looksClean(append(logs, docker.LogEntry{Message: "Connection Error: reset"}), 5, keywords))
+	assert.False(t, looksClean(append(logs, docker.LogEntry{Message: "WARNING: disk 91% full"}), 5, keywords))
+	assert.Equal(t, []string{"error", "warn"}, keywords, "empty keywords must be dropped")
+}
+
+func TestPipeline_AnalyzeLogs_SkipCleanLogs(t *testing.T) {
+	client := NewMockLLMClient()
+	pipeline := &Pipeline{
+		client:            client,
+		maxTokens:         8000,
+		tokenizer:         NewMockTokenizer(0.1),
+		promptLoader:      prompts.NewPromptLoader(&config.Config{}),
+		skipCleanMaxLines: 10,
+		cleanKeywords:     lowerKeywords(config.DefaultSkipCleanKeywords),
+	}
+
+	clean := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "GET /health 200"},
+		{Timestamp: "2023-01-01T10:00:01Z", Stream: "stdout", Message: "GET /api/items 200"},
+	}
+	result, err := pipeline.AnalyzeLogs(context.Background(), "web", clean)
+	require.NoError(t, err)
+	assert.True(t, result.LLMSkipped)
+	assert.Equal(t, CleanLogsAnalysis, result.Analysis)
+	assert.Equal(t, SkipReasonCleanLogs, result.SkipReason)
+	assert.Zero(t, result.TokensUsed)
+	assert.Empty(t, client.lastUserPrompt, "LLM must not be called")
+
+	noisy := append(clean, docker.LogEntry{Timestamp: "2023-01-01T10:00:02Z", Stream: "stderr", Message: "panic: nil map"})
+	result, err = pipeline.AnalyzeLogs(context.Background(), "web", noisy)
+	require.NoError(t, err)
+	assert.False(t, result.LLMSkipped)
+	assert.Equal(t, "Mock analysis response", result.Analysis)
+}
+
+func TestPipeline_AnalyzeLogs_MinLogLines(t *testing.T) {
+	client := NewMockLLMClient()
+	pipeline := &Pipeline{
+		client:       client,
+		maxTokens:    8000,
+		tokenizer:    NewMockTokenizer(0.1),
+		promptLoader: prompts.NewPromptLoader(&config.Config{}),
+		minLogLines:  3,
+	}
+
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stderr", Message: "panic: nil map"},
+		{Timestamp: "2023-01-01T10:00:01Z", Stream: "stderr", Message: "goroutine 1 [running]"},
+	}
+	result, err := pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.True(t, result.LLMSkipped)
+	assert.Equal(t, SkipReasonBelowThreshold, result.SkipReason)
+	assert.Equal(t, BelowThresholdAnalysis, result.Analysis)
+	assert.Equal(t, 2, result.ProcessedCount)
+	assert.Zero(t, result.TokensUsed)
+	assert.Empty(t, client.lastUserPrompt, "LLM must not be called")
+
+	logs = append(logs, docker.LogEntry{Timestamp: "2023-01-01T10:00:02Z", Stream: "stderr", Message: "main.main()"})
+	result, err = pipeline.AnalyzeLogs(context.Background(), "web", logs)
+	require.NoError(t, err)
+	assert.False(t, result.LLMSkipped)
+	assert.Empty(t, result.SkipReason)
+	assert.Equal(t, "Mock analysis response", result.Analysis)
+}
@@ -0,0 +1,90 @@
+package chunking
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zorak1103/dlia/internal/docker"
+)
+
+// criticalLineTiers ranks log lines for llm.synthesis_critical_lines, most severe tier
+// first. A line belongs to the first tier with a keyword it contains (lowercase,
+// matched as substrings of the lowercased message); lines without any keyword are
+// never selected.
+var criticalLineTiers = [][]string{
+	{"panic", "fatal", "critical", "out of memory", "oom", "killed", "segfault"},
+	{"error", "exception", "traceback", "fail"},
+	{"warn", "timeout", "timed out", "refused", "denied"},
+}
+
+// criticalTier returns the criticalLineTiers index of message, or -1 without a keyword.
+func criticalTier(message string) int {
+	message = strings.ToLower(message)
+	for tier, keywords := range criticalLineTiers {
+		for _, keyword := range keywords {
+			if strings.Contains(message, keyword) {
+				return tier
+			}
+		}
+	}
+	return -1
+}
+
+// criticalLines returns up to n of the most severe entries of logs: the lines of the
+// most severe tier first and, within a tier, the earliest. The result keeps log order.
+func criticalLines(logs []docker.LogEntry, n int) []docker.LogEntry {
+	type ranked struct {
+		index, tier int
+	}
+
+	var candidates []ranked
+	for i, entry := range logs {
+		if tier := criticalTier(entry.Message); tier >= 0 {
+			candidates = append(candidates, ranked{index: i, tier: tier})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b ranked) int { return a.tier - b.tier })
+	candidates = candidates[:min(n, len(candidates))]
+	slices.SortFunc(candidates, func(a, b ranked) int { return a.index - b.index })
+
+	selected := make([]docker.LogEntry, len(candidates))
+	for i, c := range candidates {
+		selected[i] = logs[c.index]
+	}
+	return selected
+}
+
+// synthesisCriticalLines formats the most severe raw lines of every chunk for the
+// synthesis prompt, at most perChunk per chunk and only as many as fit in budget
+// tokens. Lines repeated from the previous chunk (overlap) are not selected again.
+// Returns "" when no line qualifies or fits.
+func synthesisCriticalLines(chunks []Chunk, perChunk, budget int, tokenizer TokenizerInterface) string {
+	var sb strings.Builder
+	for i, chunk := range chunks {
+		selected := criticalLines(chunk.Logs[min(chunk.Overlap, len(chunk.Logs)):], perChunk)
+		for len(selected) > 0 {
+			section := fmt.Sprintf("\n--- Chunk %d Critical Lines ---\n%s", i+1, FormatLogs(selected))
+			if tokens := tokenizer.CountTokens(section); tokens <= budget {
+				budget -= tokens
+				sb.WriteString(section)
+				break
+			}
+			// Drop the least severe line that is still selected until the section fits
+			drop := leastSevere(selected)
+			selected = slices.Delete(selected, drop, drop+1)
+		}
+	}
+	return sb.String()
+}
+
+// leastSevere returns the index of the latest entry of the least severe tier in logs.
+func leastSevere(logs []docker.LogEntry) int {
+	worst, worstTier := 0, -1
+	for i, entry := range logs {
+		if tier := criticalTier(entry.Message); tier >= worstTier {
+			worst, worstTier = i, tier
+		}
+	}
+	return worst
+}
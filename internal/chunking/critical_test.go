@@ -0,0 +1,81 @@
+package chunking
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+func TestCriticalLines(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Message: "GET /health 200"},
+		{Message: "WARN slow query"},
+		{Message: "ERROR connection reset"},
+		{Message: "cache refreshed"},
+		{Message: "FATAL: out of disk"},
+		{Message: "ERROR retry failed"},
+	}
+
+	got := criticalLines(logs, 2)
+	require.Len(t, got, 2)
+	assert.Equal(t, "ERROR connection reset", got[0].Message, "earliest error wins, log order kept")
+	assert.Equal(t, "FATAL: out of disk", got[1].Message)
+
+	assert.Len(t, criticalLines(logs, 10), 4, "lines without a keyword are never selected")
+	assert.Empty(t, criticalLines(logs[:1], 3))
+}
+
+func TestSynthesisCriticalLines(t *testing.T) {
+	tokenizer := NewMockTokenizer(1.0)
+	chunks := []Chunk{
+		{Logs: []docker.LogEntry{{Message: "panic: nil map"}, {Message: "warn: retrying"}}},
+		{Logs: []docker.LogEntry{{Message: "warn: retrying"}, {Message: "all good"}}, Overlap: 1},
+		{Logs: []docker.LogEntry{{Message: "error: timeout"}}},
+	}
+
+	got := synthesisCriticalLines(chunks, 2, 10000, tokenizer)
+	assert.Contains(t, got, "--- Chunk 1 Critical Lines ---\npanic: nil map\nwarn: retrying\n")
+	assert.NotContains(t, got, "Chunk 2", "overlap lines are not selected again")
+	assert.Contains(t, got, "--- Chunk 3 Critical Lines ---\nerror: timeout\n")
+
+	section := "\n--- Chunk 1 Critical Lines ---\npanic: nil map\n"
+	got = synthesisCriticalLines(chunks[:1], 2, tokenizer.CountTokens(section), tokenizer)
+	assert.Equal(t, section, got, "the least severe line is dropped to fit the budget")
+
+	assert.Empty(t, synthesisCriticalLines(chunks, 2, 5, tokenizer))
+}
+
+func TestPipeline_AnalyzeWithChunking_CriticalLines(t *testing.T) {
+	logs := make([]docker.LogEntry, 0, 40)
+	for i := range 40 {
+		logs = append(logs, docker.LogEntry{Timestamp: "2023-01-01T10:00:00Z", Message: fmt.Sprintf("request %d served", i)})
+	}
+	logs[17].Message = "FATAL: database connection pool exhausted"
+
+	for _, perChunk := range []int{0, 2} {
+		client := NewMockLLMClient()
+		pipeline := &Pipeline{
+			client:                 client,
+			maxTokens:              2000,
+			tokenizer:              NewMockTokenizer(1.0),
+			promptLoader:           prompts.NewPromptLoader(&config.Config{}),
+			synthesisCriticalLines: perChunk,
+		}
+
+		_, _, _, chunksUsed, err := pipeline.analyzeWithChunking(context.Background(), "web", logs, "system prompt", 1600)
+		require.NoError(t, err)
+		require.Greater(t, chunksUsed, 1)
+
+		if perChunk == 0 {
+			assert.NotContains(t, client.lastUserPrompt, "FATAL", "raw lines are off by default")
+		} else {
+			assert.Contains(t, client.lastUserPrompt, "FATAL: database connection pool exhausted")
+		}
+	}
+}
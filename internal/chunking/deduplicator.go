@@ -3,6 +3,7 @@ package chunking
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/zorak1103/dlia/internal/docker"
 )
@@ -11,26 +12,53 @@ import (
 const DeduplicateThreshold = 3
 
 // Deduplicate reduces repeated consecutive log lines into [REPEAT x...] markers.
+// It compares messages exactly and collapses runs of DeduplicateThreshold or more;
+// see DeduplicateWith.
+func Deduplicate(logs []docker.LogEntry) []docker.LogEntry {
+	return DeduplicateWith(logs, false, DeduplicateThreshold, false)
+}
+
+// DeduplicateWith reduces runs of repeated consecutive log lines into a single
+// "[REPEAT xN] <message>" entry carrying the first line of the run, so one real
+// example is preserved. With normalized set, lines are compared after
+// NormalizeMessage, so lines differing only in IDs, numbers or timestamps form one
+// run; such runs are marked "[REPEAT xN similar]". Runs shorter than minRepeats are
+// kept as-is; minRepeats below 2 selects DeduplicateThreshold. With timestamps set,
+// the marker also records when the run started and ended, e.g.
+// "[REPEAT x42 10:00:01–10:03:17]", so collapsed bursts keep their place in an
+// incident timeline.
 //
 // Algorithm:
-// Uses a single-pass scan tracking the start of each sequence of identical messages.
+// Uses a single-pass scan tracking the start of each sequence of equal messages.
 // When a different message is encountered (or end of input), the accumulated sequence
-// is "flushed" - either as a single deduplicated entry (if count >= threshold) or
-// as individual entries (if below threshold).
+// is "flushed" - either as a single deduplicated entry (if count >= minRepeats) or
+// as individual entries (if below the threshold).
 //
 // Threshold Rationale:
-// The threshold of 3 (DeduplicateThreshold) balances deduplication benefit vs. information loss.
+// The default of 3 (DeduplicateThreshold) balances deduplication benefit vs. information loss.
 // - Duplicates < 3: Kept as-is (losing 2 lines saves minimal space, may hide patterns)
 // - Duplicates >= 3: Collapsed to [REPEAT xN] marker (significant space/token savings)
 //
 // Complexity:
 //   - Time:  O(n) where n is the number of log entries. Each entry is visited at most twice.
-//   - Space: O(n) in the worst case (no duplicates), O(1) additional working memory.
-func Deduplicate(logs []docker.LogEntry) []docker.LogEntry {
+//   - Space: O(n) in the worst case (no duplicates), plus O(n) normalized keys in normalized mode.
+func DeduplicateWith(logs []docker.LogEntry, normalized bool, minRepeats int, timestamps bool) []docker.LogEntry {
 	n := len(logs)
 	if n == 0 {
 		return logs
 	}
+	if minRepeats < 2 {
+		minRepeats = DeduplicateThreshold
+	}
+
+	key := func(i int) string { return logs[i].Message }
+	if normalized {
+		keys := make([]string, n)
+		for i := range logs {
+			keys[i] = NormalizeMessage(logs[i].Message)
+		}
+		key = func(i int) string { return keys[i] }
+	}
 
 	var result []docker.LogEntry
 	seqStart := 0
@@ -38,11 +66,23 @@ func Deduplicate(logs []docker.LogEntry) []docker.LogEntry {
 	flushSequence := func(endIdx int) {
 		seqLen := endIdx - seqStart
 		firstEntry := logs[seqStart]
-		if seqLen >= DeduplicateThreshold {
+		if seqLen >= minRepeats {
+			marker := fmt.Sprintf("[REPEAT x%d", seqLen)
+			for j := seqStart + 1; j < endIdx; j++ {
+				if logs[j].Message != firstEntry.Message {
+					marker += " similar"
+					break
+				}
+			}
+			if timestamps {
+				if span := repeatSpan(logs[seqStart:endIdx]); span != "" {
+					marker += " " + span
+				}
+			}
 			result = append(result, docker.LogEntry{
 				Timestamp: firstEntry.Timestamp,
 				Stream:    firstEntry.Stream,
-				Message:   fmt.Sprintf("[REPEAT x%d] %s", seqLen, firstEntry.Message),
+				Message:   marker + "] " + firstEntry.Message,
 			})
 		} else {
 			for j := seqStart; j < endIdx; j++ {
@@ -52,7 +92,7 @@ func Deduplicate(logs []docker.LogEntry) []docker.LogEntry {
 	}
 
 	for i := 1; i < n; i++ {
-		if logs[i].Message != logs[seqStart].Message {
+		if key(i) != key(seqStart) {
 			flushSequence(i)
 			seqStart = i
 		}
@@ -63,6 +103,39 @@ func Deduplicate(logs []docker.LogEntry) []docker.LogEntry {
 	return result
 }
 
+// repeatSpan returns the time range of a collapsed run as "15:04:05–15:04:09", with
+// dates when the run crosses midnight and a single time when it fits in one second.
+// Entries without a parsable timestamp are ignored; none at all yields "".
+func repeatSpan(run []docker.LogEntry) string {
+	var first, last time.Time
+	for _, entry := range run {
+		t, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if last.IsZero() || t.After(last) {
+			last = t
+		}
+	}
+	if first.IsZero() {
+		return ""
+	}
+
+	last = last.In(first.Location())
+	layout := "15:04:05"
+	if first.Format(time.DateOnly) != last.Format(time.DateOnly) {
+		layout = time.DateTime
+	}
+	from, to := first.Format(layout), last.Format(layout)
+	if from == to {
+		return from
+	}
+	return from + "–" + to
+}
+
 // FormatLogs converts log entries to a timestamp-prefixed string format
 // suitable for LLM analysis. Format: "[timestamp] message\n" per entry.
 // Entries without timestamps are formatted as "message\n".
@@ -254,3 +254,130 @@ func TestDeduplicateThreshold(t *testing.T) {
 	// Verify the constant value hasn't changed
 	assert.Equal(t, 3, DeduplicateThreshold, "DeduplicateThreshold constant should be 3")
 }
+
+func TestDeduplicateWith_Normalized(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Timestamp: "2024-01-01T00:00:00Z", Stream: "stderr", Message: "request 3f2b8c1e-9a4d-4e5f-8b6a-1c2d3e4f5a6b failed after 120ms"},
+		{Timestamp: "2024-01-01T00:00:01Z", Stream: "stderr", Message: "request 7a1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e failed after 98ms"},
+		{Timestamp: "2024-01-01T00:00:02Z", Stream: "stderr", Message: "request 0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e failed after 311ms"},
+		{Timestamp: "2024-01-01T00:00:03Z", Stream: "stdout", Message: "shutting down"},
+	}
+
+	exact := DeduplicateWith(logs, false, 3, false)
+	assert.Len(t, exact, 4, "exact mode must not collapse lines that differ in IDs")
+
+	result := DeduplicateWith(logs, true, 3, false)
+	require.Len(t, result, 2)
+	assert.Equal(t, "[REPEAT x3 similar] "+logs[0].Message, result[0].Message, "first real line is kept as the example")
+	assert.Equal(t, logs[0].Timestamp, result[0].Timestamp)
+	assert.Equal(t, "stderr", result[0].Stream)
+	assert.Equal(t, "shutting down", result[1].Message)
+}
+
+func TestDeduplicateWith_NormalizedIdenticalRun(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Message: "ping"}, {Message: "ping"}, {Message: "ping"},
+	}
+
+	result := DeduplicateWith(logs, true, 3, false)
+	require.Len(t, result, 1)
+	assert.Equal(t, "[REPEAT x3] ping", result[0].Message, "identical runs keep the plain marker")
+}
+
+func TestDeduplicateWith_MinRepeats(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Message: "a"}, {Message: "a"}, {Message: "a"}, {Message: "a"},
+		{Message: "b"}, {Message: "b"},
+	}
+
+	tests := []struct {
+		minRepeats int
+		want       []string
+	}{
+		{2, []string{"[REPEAT x4] a", "[REPEAT x2] b"}},
+		{5, []string{"a", "a", "a", "a", "b", "b"}},
+		{0, []string{"[REPEAT x4] a", "b", "b"}}, // Below 2 selects DeduplicateThreshold
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, e := range DeduplicateWith(logs, false, tt.minRepeats, false) {
+			got = append(got, e.Message)
+		}
+		assert.Equal(t, tt.want, got, "minRepeats=%d", tt.minRepeats)
+	}
+}
+
+func TestDeduplicateWith_Timestamps(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalized bool
+		logs       []docker.LogEntry
+		want       []string
+	}{
+		{
+			name: "run annotated with first and last time",
+			logs: []docker.LogEntry{
+				{Timestamp: "2024-01-01T10:00:01Z", Message: "timeout"},
+				{Timestamp: "2024-01-01T10:01:30Z", Message: "timeout"},
+				{Timestamp: "2024-01-01T10:03:17.5Z", Message: "timeout"},
+				{Timestamp: "2024-01-01T10:03:18Z", Message: "recovered"},
+			},
+			want: []string{"[REPEAT x3 10:00:01–10:03:17] timeout", "recovered"},
+		},
+		{
+			name:       "similar run",
+			normalized: true,
+			logs: []docker.LogEntry{
+				{Timestamp: "2024-01-01T10:00:01Z", Message: "request 1 failed"},
+				{Timestamp: "2024-01-01T10:00:02Z", Message: "request 2 failed"},
+				{Timestamp: "2024-01-01T10:00:05Z", Message: "request 3 failed"},
+			},
+			want: []string{"[REPEAT x3 similar 10:00:01–10:00:05] request 1 failed"},
+		},
+		{
+			name: "run across midnight shows dates",
+			logs: []docker.LogEntry{
+				{Timestamp: "2024-01-01T23:59:59Z", Message: "retry"},
+				{Timestamp: "2024-01-02T00:00:00Z", Message: "retry"},
+				{Timestamp: "2024-01-02T00:00:01Z", Message: "retry"},
+			},
+			want: []string{"[REPEAT x3 2024-01-01 23:59:59–2024-01-02 00:00:01] retry"},
+		},
+		{
+			name: "burst within one second",
+			logs: []docker.LogEntry{
+				{Timestamp: "2024-01-01T10:00:01.1Z", Message: "retry"},
+				{Timestamp: "2024-01-01T10:00:01.2Z", Message: "retry"},
+				{Timestamp: "2024-01-01T10:00:01.3Z", Message: "retry"},
+			},
+			want: []string{"[REPEAT x3 10:00:01] retry"},
+		},
+		{
+			name: "no timestamps keeps the plain marker",
+			logs: []docker.LogEntry{{Message: "ping"}, {Message: "ping"}, {Message: "ping"}},
+			want: []string{"[REPEAT x3] ping"},
+		},
+		{
+			name: "single occurrences are not annotated",
+			logs: []docker.LogEntry{
+				{Timestamp: "2024-01-01T10:00:01Z", Message: "start"},
+				{Timestamp: "2024-01-01T10:00:02Z", Message: "ready"},
+			},
+			want: []string{"start", "ready"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DeduplicateWith(tt.logs, tt.normalized, 3, true)
+
+			var got []string
+			for _, e := range result {
+				got = append(got, e.Message)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.logs[0].Timestamp, result[0].Timestamp, "the collapsed entry keeps the first timestamp")
+		})
+	}
+}
@@ -3,6 +3,9 @@ package chunking
 import (
 	"fmt"
 	"regexp"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/docker"
 )
 
 // RegexpFilter provides regexp-based filtering of log lines before LLM processing.
@@ -46,20 +49,14 @@ func (rf *RegexpFilter) Filter(logs []string) ([]string, FilterStats) {
 	}
 
 	filtered := make([]string, 0, len(logs))
+	stats.PatternHits = rf.newPatternHits()
 
 	for _, log := range logs {
-		matched := false
-		// Check if log matches any pattern
-		for _, pattern := range rf.patterns {
-			if pattern.MatchString(log) {
-				matched = true
-				stats.LinesFiltered++
-				break // No need to check other patterns
-			}
-		}
-
 		// Keep the log if it didn't match any pattern
-		if !matched {
+		if i := rf.Match(log); i >= 0 {
+			stats.LinesFiltered++
+			stats.PatternHits[i].Lines++
+		} else {
 			filtered = append(filtered, log)
 		}
 	}
@@ -71,12 +68,36 @@ func (rf *RegexpFilter) Filter(logs []string) ([]string, FilterStats) {
 // MatchesAny checks if the given text matches any of the configured patterns.
 // Returns true if a match is found, false otherwise. Returns false if no patterns are configured.
 func (rf *RegexpFilter) MatchesAny(text string) bool {
-	for _, pattern := range rf.patterns {
+	return rf.Match(text) >= 0
+}
+
+// Match returns the index of the first pattern matching text, or -1 if none does.
+// A line is attributed to this pattern only, even if later patterns match as well.
+func (rf *RegexpFilter) Match(text string) int {
+	for i, pattern := range rf.patterns {
 		if pattern.MatchString(text) {
-			return true
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// newPatternHits returns a zero hit count for every pattern, in configuration order.
+func (rf *RegexpFilter) newPatternHits() []PatternHit {
+	if len(rf.patterns) == 0 {
+		return nil
+	}
+	hits := make([]PatternHit, len(rf.patterns))
+	for i, pattern := range rf.patterns {
+		hits[i].Pattern = pattern.String()
+	}
+	return hits
+}
+
+// PatternHit counts the lines a single filter pattern removed.
+type PatternHit struct {
+	Pattern string // The pattern as configured
+	Lines   int    // Lines this pattern was the first to match
 }
 
 // FilterStats tracks statistics about the filtering operation.
@@ -84,4 +105,38 @@ type FilterStats struct {
 	LinesTotal    int // Total number of input lines
 	LinesFiltered int // Number of lines matched and filtered out
 	LinesKept     int // Number of lines kept (Total - Filtered)
+	StdoutDropped int // stdout lines excluded by docker.stream / --stream
+	StderrDropped int // stderr lines excluded by docker.stream / --stream
+	// PhysicalLines is the input line count before docker.multiline_pattern merged
+	// continuation lines; LinesTotal then counts logical entries (0 = grouping disabled)
+	PhysicalLines int
+	// SanitizedLines counts lines with invalid UTF-8 rewritten per docker.invalid_utf8
+	SanitizedLines int
+	// PatternHits holds the lines filtered by each regexp pattern, in configuration
+	// order; patterns that matched nothing are included with 0 lines
+	PatternHits []PatternHit
+}
+
+// FilterStream keeps only the entries of the selected stream ("stdout" or "stderr").
+// Any other selection, including "all" and "", keeps every entry. The returned
+// stats only carry StdoutDropped and StderrDropped.
+func FilterStream(logs []docker.LogEntry, stream string) ([]docker.LogEntry, FilterStats) {
+	var stats FilterStats
+	if stream != config.StreamStdout && stream != config.StreamStderr {
+		return logs, stats
+	}
+
+	kept := make([]docker.LogEntry, 0, len(logs))
+	for _, entry := range logs {
+		switch {
+		case entry.Stream == stream:
+			kept = append(kept, entry)
+		case entry.Stream == config.StreamStderr:
+			stats.StderrDropped++
+		default:
+			stats.StdoutDropped++
+		}
+	}
+
+	return kept, stats
 }
@@ -2,6 +2,8 @@ package chunking
 
 import (
 	"testing"
+
+	"github.com/zorak1103/dlia/internal/docker"
 )
 
 func TestNewRegexpFilter_ValidPatterns(t *testing.T) {
@@ -474,6 +476,40 @@ func TestRegexpFilter_Filter_StatsConsistency(t *testing.T) {
 	}
 }
 
+func TestRegexpFilter_Filter_PatternHits(t *testing.T) {
+	filter, err := NewRegexpFilter([]string{"^TRACE:", "health", "^DEBUG:"})
+	if err != nil {
+		t.Fatalf("NewRegexpFilter() failed: %v", err)
+	}
+
+	logs := []string{
+		"DEBUG: health check ok",
+		"INFO: GET /health 200",
+		"DEBUG: cache miss",
+		"ERROR: connection refused",
+	}
+
+	_, stats := filter.Filter(logs)
+
+	// The first line matches both "health" and "^DEBUG:"; it counts for "health" only
+	want := []PatternHit{
+		{Pattern: "^TRACE:", Lines: 0},
+		{Pattern: "health", Lines: 2},
+		{Pattern: "^DEBUG:", Lines: 1},
+	}
+	if len(stats.PatternHits) != len(want) {
+		t.Fatalf("Filter() stats.PatternHits = %+v, want %+v", stats.PatternHits, want)
+	}
+	for i := range want {
+		if stats.PatternHits[i] != want[i] {
+			t.Errorf("Filter() stats.PatternHits[%d] = %+v, want %+v", i, stats.PatternHits[i], want[i])
+		}
+	}
+	if stats.LinesFiltered != 3 {
+		t.Errorf("Filter() stats.LinesFiltered = %d, want 3", stats.LinesFiltered)
+	}
+}
+
 func TestRegexpFilter_MatchesAny(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -533,3 +569,43 @@ func TestRegexpFilter_MatchesAny(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterStream(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Stream: "stdout", Message: "GET /health 200"},
+		{Stream: "stderr", Message: "panic: nil map"},
+		{Stream: "stdout", Message: "GET /api 200"},
+		{Stream: "stderr", Message: "goroutine 1 [running]"},
+		{Stream: "stderr", Message: "exit status 2"},
+	}
+
+	tests := []struct {
+		stream     string
+		wantKept   int
+		wantStdout int
+		wantStderr int
+	}{
+		{"", 5, 0, 0},
+		{"all", 5, 0, 0},
+		{"stdout", 2, 0, 3},
+		{"stderr", 3, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stream, func(t *testing.T) {
+			kept, stats := FilterStream(logs, tt.stream)
+			if len(kept) != tt.wantKept {
+				t.Errorf("kept %d entries, want %d", len(kept), tt.wantKept)
+			}
+			if stats.StdoutDropped != tt.wantStdout || stats.StderrDropped != tt.wantStderr {
+				t.Errorf("dropped stdout=%d stderr=%d, want stdout=%d stderr=%d",
+					stats.StdoutDropped, stats.StderrDropped, tt.wantStdout, tt.wantStderr)
+			}
+			for _, entry := range kept {
+				if tt.wantStdout+tt.wantStderr > 0 && entry.Stream != tt.stream {
+					t.Errorf("kept entry from stream %q", entry.Stream)
+				}
+			}
+		})
+	}
+}
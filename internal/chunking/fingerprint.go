@@ -0,0 +1,73 @@
+package chunking
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/zorak1103/dlia/internal/docker"
+)
+
+// Normalization patterns, applied in order. More specific patterns run first so
+// e.g. a UUID is replaced as a whole rather than as several hex/number fragments.
+var normalizePatterns = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`), "<ts>"},
+	{regexp.MustCompile(`\d{2}:\d{2}:\d{2}(?:[.,]\d+)?`), "<time>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`(?i)\b(?:0x)?[0-9a-f]*\d[0-9a-f]*\b`), "<hex>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+}
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+// NormalizeMessage replaces volatile tokens (timestamps, UUIDs, IPs, hex IDs and
+// numbers) with placeholders so recurrences of the same event compare equal.
+func NormalizeMessage(message string) string {
+	normalized := message
+	for _, p := range normalizePatterns {
+		normalized = p.re.ReplaceAllString(normalized, p.placeholder)
+	}
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(normalized, " "))
+}
+
+// Fingerprint returns a short stable hash of the normalized message.
+func Fingerprint(message string) string {
+	sum := sha256.Sum256([]byte(NormalizeMessage(message)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// FilterSeen drops entries whose fingerprint is in seen and returns the remaining
+// entries together with the number of dropped lines.
+func FilterSeen(logs []docker.LogEntry, seen map[string]bool) ([]docker.LogEntry, int) {
+	if len(seen) == 0 {
+		return logs, 0
+	}
+
+	result := make([]docker.LogEntry, 0, len(logs))
+	for _, entry := range logs {
+		if !seen[Fingerprint(entry.Message)] {
+			result = append(result, entry)
+		}
+	}
+
+	return result, len(logs) - len(result)
+}
+
+// Fingerprints returns the unique fingerprints of logs in first-seen order.
+func Fingerprints(logs []docker.LogEntry) []string {
+	seen := make(map[string]bool, len(logs))
+	result := make([]string, 0, len(logs))
+	for _, entry := range logs {
+		fp := Fingerprint(entry.Message)
+		if !seen[fp] {
+			seen[fp] = true
+			result = append(result, fp)
+		}
+	}
+	return result
+}
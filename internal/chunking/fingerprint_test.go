@@ -0,0 +1,91 @@
+package chunking
+
+import (
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/docker"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{
+			name:  "different request IDs",
+			a:     "request 3f2b8c1e-9a4d-4e2f-8b1a-0c9d8e7f6a5b failed",
+			b:     "request 7d1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a failed",
+			equal: true,
+		},
+		{
+			name:  "different timestamps and durations",
+			a:     "2025-01-01T10:00:00.123Z timeout after 3012ms",
+			b:     "2025-01-02T11:30:12.999Z timeout after 5000ms",
+			equal: true,
+		},
+		{
+			name:  "different client IPs and hex IDs",
+			a:     "connection from 10.0.0.1:5432 reset (conn 0x7ffde4a3)",
+			b:     "connection from 192.168.1.20:6000 reset (conn 0x1a2b3c4d)",
+			equal: true,
+		},
+		{
+			name:  "different events",
+			a:     "connection refused",
+			b:     "connection reset",
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			na, nb := NormalizeMessage(tt.a), NormalizeMessage(tt.b)
+			if (na == nb) != tt.equal {
+				t.Errorf("NormalizeMessage equality = %v, want %v (%q vs %q)", na == nb, tt.equal, na, nb)
+			}
+			if (Fingerprint(tt.a) == Fingerprint(tt.b)) != tt.equal {
+				t.Errorf("Fingerprint equality should match normalized equality")
+			}
+		})
+	}
+}
+
+func TestFilterSeen(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Message: "user 42 logged in"},
+		{Message: "disk usage at 91%"},
+		{Message: "user 7 logged in"},
+	}
+
+	seen := map[string]bool{Fingerprint("user 1 logged in"): true}
+	result, skipped := FilterSeen(logs, seen)
+
+	if skipped != 2 {
+		t.Errorf("Expected 2 skipped lines, got %d", skipped)
+	}
+	if len(result) != 1 || result[0].Message != "disk usage at 91%" {
+		t.Errorf("Unexpected remaining lines: %+v", result)
+	}
+
+	result, skipped = FilterSeen(logs, nil)
+	if skipped != 0 || len(result) != len(logs) {
+		t.Errorf("Empty seen set should keep all lines, got %d kept, %d skipped", len(result), skipped)
+	}
+}
+
+func TestFingerprints_Unique(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Message: "job 1 done"},
+		{Message: "job 2 done"},
+		{Message: "job failed"},
+	}
+
+	fps := Fingerprints(logs)
+	if len(fps) != 2 {
+		t.Errorf("Expected 2 unique fingerprints, got %d", len(fps))
+	}
+	if fps[0] != Fingerprint("job 1 done") {
+		t.Error("Fingerprints should preserve first-seen order")
+	}
+}
@@ -0,0 +1,56 @@
+package chunking
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+// containerInstruction is a compiled container_instructions entry.
+type containerInstruction struct {
+	pattern      *regexp.Regexp
+	instructions string
+}
+
+// compileContainerInstructions compiles the configured patterns, keeping their order.
+func compileContainerInstructions(entries []config.ContainerInstruction) ([]containerInstruction, error) {
+	compiled := make([]containerInstruction, 0, len(entries))
+	for i, entry := range entries {
+		re, err := regexp.Compile(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile container_instructions[%d] pattern %q: %w", i, entry.Pattern, err)
+		}
+		compiled = append(compiled, containerInstruction{pattern: re, instructions: strings.TrimSpace(entry.Instructions)})
+	}
+	return compiled, nil
+}
+
+// matchContainerInstructions returns the instructions of the first entry whose
+// pattern matches containerName, or "" if none does.
+func matchContainerInstructions(entries []containerInstruction, containerName string) string {
+	for _, entry := range entries {
+		if entry.pattern.MatchString(containerName) {
+			return entry.instructions
+		}
+	}
+	return ""
+}
+
+// userInstructions combines the container_instructions match, the dlia.instructions
+// label value and the container's ignore file from ignoreDir. All are passed to the
+// system prompt; the config entry comes first so the more specific label and ignore
+// file are read last.
+func (p *Pipeline) userInstructions(containerName, labelInstructions string) string {
+	configured := matchContainerInstructions(p.containerInstructions, containerName)
+	ignoreInstructions, _ := config.GetIgnoreInstructions(containerName, p.ignoreDir) //nolint:errcheck // Error returns empty string, which is valid
+
+	var parts []string
+	for _, part := range []string{configured, strings.TrimSpace(labelInstructions), ignoreInstructions} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,95 @@
+package chunking
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+func TestMatchContainerInstructions_FirstMatchWins(t *testing.T) {
+	entries, err := compileContainerInstructions([]config.ContainerInstruction{
+		{Pattern: "^postgres-primary$", Instructions: "Primary only"},
+		{Pattern: "^postgres", Instructions: "  Focus on connection pool exhaustion\n"},
+		{Pattern: "nginx", Instructions: "Ignore routine access logs"},
+	})
+	if err != nil {
+		t.Fatalf("compileContainerInstructions() error = %v", err)
+	}
+
+	tests := []struct {
+		container string
+		want      string
+	}{
+		{"postgres-primary", "Primary only"},
+		{"postgres-replica", "Focus on connection pool exhaustion"},
+		{"edge-nginx-1", "Ignore routine access logs"},
+		{"redis", ""},
+	}
+
+	for _, tt := range tests {
+		if got := matchContainerInstructions(entries, tt.container); got != tt.want {
+			t.Errorf("matchContainerInstructions(%q) = %q, want %q", tt.container, got, tt.want)
+		}
+	}
+}
+
+func TestCompileContainerInstructions_InvalidPattern(t *testing.T) {
+	_, err := compileContainerInstructions([]config.ContainerInstruction{{Pattern: "[unclosed", Instructions: "x"}})
+	if err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestPipelineUserInstructions(t *testing.T) {
+	ignoreDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(ignoreDir, "db.md"), []byte("Ignore the 3 AM backup errors"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := compileContainerInstructions([]config.ContainerInstruction{
+		{Pattern: "^db$", Instructions: "Focus on connection pool exhaustion"},
+		{Pattern: "^web$", Instructions: "Ignore routine access logs"},
+	})
+	if err != nil {
+		t.Fatalf("compileContainerInstructions() error = %v", err)
+	}
+	p := &Pipeline{ignoreDir: ignoreDir, containerInstructions: entries}
+
+	tests := []struct {
+		container string
+		want      string
+	}{
+		{"db", "Focus on connection pool exhaustion\n\nIgnore the 3 AM backup errors"},
+		{"web", "Ignore routine access logs"},
+		{"cache", ""},
+	}
+
+	for _, tt := range tests {
+		if got := p.userInstructions(tt.container, ""); got != tt.want {
+			t.Errorf("userInstructions(%q) = %q, want %q", tt.container, got, tt.want)
+		}
+	}
+}
+
+func TestPipelineUserInstructions_Label(t *testing.T) {
+	ignoreDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(ignoreDir, "db.md"), []byte("Ignore the 3 AM backup errors"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := compileContainerInstructions([]config.ContainerInstruction{{Pattern: "^db$", Instructions: "Focus on connection pool exhaustion"}})
+	if err != nil {
+		t.Fatalf("compileContainerInstructions() error = %v", err)
+	}
+	p := &Pipeline{ignoreDir: ignoreDir, containerInstructions: entries}
+
+	want := "Focus on connection pool exhaustion\n\nfocus on auth\n\nIgnore the 3 AM backup errors"
+	if got := p.userInstructions("db", " focus on auth "); got != want {
+		t.Errorf("userInstructions() = %q, want %q", got, want)
+	}
+	if got := p.userInstructions("web", "focus on auth"); got != "focus on auth" {
+		t.Errorf("userInstructions() = %q, want label instructions only", got)
+	}
+}
@@ -4,19 +4,29 @@ package chunking
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/zorak1103/dlia/internal/config"
 	"github.com/zorak1103/dlia/internal/docker"
 	"github.com/zorak1103/dlia/internal/llm"
+	"github.com/zorak1103/dlia/internal/privacy"
 	"github.com/zorak1103/dlia/internal/prompts"
 )
 
 const (
-	// ResponseReserveTokens ensures the model has adequate space for complete responses
+	// ResponseReserveTokens is the default for llm.response_reserve_tokens.
+	// It ensures the model has adequate space for complete responses
 	// while processing log analysis requests. Insufficient reserve may cause truncated outputs.
 	ResponseReserveTokens = 4000
 
-	// SystemPromptReserveTokens accounts for the system prompt overhead in token calculations.
+	// SystemPromptReserveTokens is the default for llm.system_prompt_reserve_tokens, the minimum
+	// budget reserved for the system prompt overhead in token calculations.
 	// This estimate is based on typical prompt templates and may need adjustment for custom prompts.
 	SystemPromptReserveTokens = 500
 
@@ -26,15 +36,46 @@ const (
 	ChunkSizeDivisor = 2
 )
 
+// systemPromptEntry is a rendered system prompt with its estimated token count.
+type systemPromptEntry struct {
+	prompt string
+	tokens int
+}
+
 // Pipeline orchestrates the log processing pipeline
 type Pipeline struct {
 	tokenizer                  TokenizerInterface
 	client                     llm.ClientInterface
 	maxTokens                  int
+	responseReserveTokens      int // 0 = ResponseReserveTokens
+	systemPromptReserveTokens  int // Minimum system prompt budget; 0 = none
 	ignoreDir                  string
 	config                     *config.Config
 	compiledRegexpsByContainer map[string]*RegexpFilter
+	defaultRegexpFilter        *RegexpFilter          // regexp_filters_file patterns for containers without an entry; nil = none
+	containerInstructions      []containerInstruction // First match wins
 	promptLoader               *prompts.PromptLoader
+	maxLogLines                int // 0 = unlimited
+	maxLogBytes                int // 0 = unlimited
+	dedupNormalized            bool
+	dedupMinRepeats            int  // 0 = DeduplicateThreshold
+	dedupTimestamps            bool // Annotate [REPEAT xN] markers with the first and last timestamp
+	skipCleanMaxLines          int  // > 0 enables the llm.skip_clean_logs heuristic
+	minLogLines                int  // Fewer lines skip the LLM; 0 = always analyze
+	cleanKeywords              []string
+	chunkConcurrency           int            // Parallel chunk summaries; 0 or 1 = sequential
+	chunkOverlapLines          int            // Entries repeated at the start of the next chunk; 0 = none
+	synthesisCriticalLines     int            // Raw lines per chunk added to the synthesis prompt; 0 = none
+	multiline                  *regexp.Regexp // Continuation lines merged into the previous entry; nil = disabled
+	invalidUTF8                string         // docker.invalid_utf8 mode; "" = replace
+	systemPromptsMu            sync.Mutex
+	systemPrompts              map[string]systemPromptEntry // Rendered system prompts keyed by user instructions
+	structuredOutput           bool
+	structuredTools            bool // Request structured output via the tools API instead of JSON mode
+	keepLogsText               bool // Set AnalyzeResult.LogsText (output.save_raw_logs)
+	privacy                    config.PrivacyConfig
+	model                      string
+	cache                      *ResultCache // llm.cache_enabled; nil = every analysis calls the LLM
 }
 
 // NewPipeline creates a new processing pipeline with default configuration.
@@ -47,37 +88,126 @@ func NewPipeline(model string, maxTokens int, client llm.ClientInterface, prompt
 // NewPipelineWithConfig creates a new processing pipeline with custom ignore directory.
 // Use this when you need to specify a non-default location for container-specific ignore patterns.
 func NewPipelineWithConfig(model string, maxTokens int, client llm.ClientInterface, promptLoader *prompts.PromptLoader, ignoreDir string, cfg *config.Config) (*Pipeline, error) {
-	tokenizer, err := NewTokenizer(model)
+	tokenizer, err := newTokenizer(model)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create tokenizer for model %s: %w", model, err)
+		// Approximate counts are better than no analysis at all
+		slog.Warn("tokenizer unavailable, estimating token counts from text length", "model", model, "error", err)
+		tokenizer = NewEstimatingTokenizer()
 	}
 
 	if ignoreDir == "" {
 		ignoreDir = config.DefaultIgnoreDir
 	}
 
+	// 0 selects the model's context window from the built-in table
+	if maxTokens <= 0 {
+		maxTokens, _ = llm.ContextWindow(model)
+	}
+
 	// Pre-allocate map capacity: typical deployments use 3-5 filter patterns
 	regexpFilters := make(map[string]*RegexpFilter, 5)
+	var maxLogLines, maxLogBytes int
+	var structuredOutput, structuredTools, dedupNormalized, dedupTimestamps, keepLogsText bool
+	var dedupMinRepeats, skipCleanMaxLines, minLogLines, chunkConcurrency, chunkOverlapLines, synthesisCriticalLines int
+	var cleanKeywords []string
+	var privacyCfg config.PrivacyConfig
+	var instructions []containerInstruction
+	var multiline *regexp.Regexp
+	var defaultFilter *RegexpFilter
+	var invalidUTF8 string
+	var cache *ResultCache
+	responseReserve, systemPromptReserve := ResponseReserveTokens, SystemPromptReserveTokens
 	if cfg != nil {
+		privacyCfg = cfg.Privacy
+		if cfg.LLM.ResponseReserveTokens > 0 {
+			responseReserve = cfg.LLM.ResponseReserveTokens
+		}
+		if cfg.LLM.SystemPromptReserveTokens > 0 {
+			systemPromptReserve = cfg.LLM.SystemPromptReserveTokens
+		}
+		maxLogLines = cfg.LLM.MaxLogLines
+		maxLogBytes = cfg.LLM.MaxLogBytes
+		structuredOutput = cfg.LLM.StructuredOutput
+		structuredTools = cfg.LLM.StructuredMethod == config.StructuredMethodTools
+		dedupNormalized = cfg.LLM.DedupMode == config.DedupModeNormalized
+		dedupMinRepeats = cfg.LLM.DedupMinRepeats
+		dedupTimestamps = cfg.LLM.DedupTimestamps
+		chunkConcurrency = cfg.LLM.ChunkConcurrency
+		chunkOverlapLines = cfg.LLM.ChunkOverlapLines
+		synthesisCriticalLines = cfg.LLM.SynthesisCriticalLines
+		minLogLines = cfg.LLM.MinLogLines
+		keepLogsText = cfg.Output.SaveRawLogs
+		invalidUTF8 = cfg.Docker.InvalidUTF8
+		if cfg.LLM.CacheEnabled {
+			cache = NewResultCache(cfg.LLM.CacheDir, cfg.LLM.CacheTTL)
+		}
+		if cfg.LLM.SkipCleanLogs {
+			skipCleanMaxLines = cfg.LLM.SkipCleanMaxLines
+			cleanKeywords = lowerKeywords(cfg.LLM.SkipCleanKeywords)
+		}
+		filePatterns, err := cfg.RegexpFiltersFilePatterns()
+		if err != nil {
+			return nil, err
+		}
 		for containerName, filterCfg := range cfg.RegexpFilters {
 			if filterCfg.Enabled && len(filterCfg.Patterns) > 0 {
-				filter, err := NewRegexpFilter(filterCfg.Patterns)
+				patterns := append(slices.Clone(filterCfg.Patterns), filePatterns...)
+				filter, err := NewRegexpFilter(patterns)
 				if err != nil {
 					return nil, fmt.Errorf("failed to create regexp filter for container %s: %w", containerName, err)
 				}
 				regexpFilters[containerName] = filter
 			}
 		}
+		if len(filePatterns) > 0 {
+			defaultFilter, err = NewRegexpFilter(filePatterns)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create regexp filter from regexp_filters_file: %w", err)
+			}
+		}
+		instructions, err = compileContainerInstructions(cfg.ContainerInstructions)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Docker.MultilinePattern != "" {
+			multiline, err = regexp.Compile(cfg.Docker.MultilinePattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid docker.multiline_pattern: %w", err)
+			}
+		}
 	}
 
 	return &Pipeline{
 		tokenizer:                  tokenizer,
 		client:                     client,
 		maxTokens:                  maxTokens,
+		responseReserveTokens:      responseReserve,
+		systemPromptReserveTokens:  systemPromptReserve,
 		ignoreDir:                  ignoreDir,
 		config:                     cfg,
 		compiledRegexpsByContainer: regexpFilters,
+		defaultRegexpFilter:        defaultFilter,
+		containerInstructions:      instructions,
 		promptLoader:               promptLoader,
+		maxLogLines:                maxLogLines,
+		maxLogBytes:                maxLogBytes,
+		dedupNormalized:            dedupNormalized,
+		dedupMinRepeats:            dedupMinRepeats,
+		dedupTimestamps:            dedupTimestamps,
+		skipCleanMaxLines:          skipCleanMaxLines,
+		minLogLines:                minLogLines,
+		cleanKeywords:              cleanKeywords,
+		chunkConcurrency:           chunkConcurrency,
+		chunkOverlapLines:          chunkOverlapLines,
+		synthesisCriticalLines:     synthesisCriticalLines,
+		multiline:                  multiline,
+		invalidUTF8:                invalidUTF8,
+		structuredOutput:           structuredOutput,
+		structuredTools:            structuredTools,
+		keepLogsText:               keepLogsText,
+		privacy:                    privacyCfg,
+		model:                      model,
+		cache:                      cache,
 	}, nil
 }
 
@@ -90,12 +220,92 @@ type AnalyzeResult struct {
 	OriginalCount  int
 	ProcessedCount int
 	FilterStats    FilterStats
+	TruncatedLines int // Oldest lines dropped to honor llm.max_log_lines / llm.max_log_bytes
+	// Structured holds the parsed JSON analysis when llm.structured_output is enabled
+	// and the model returned valid JSON; nil otherwise (Analysis then holds prose).
+	Structured *llm.StructuredAnalysis
+	// ContainerState is the Docker state at scan time (e.g. "exited"); set by the caller,
+	// empty when unknown. Reports flag containers that are no longer running.
+	ContainerState string
+	// ComposeProject is the docker compose project of the container; set by the caller,
+	// empty for standalone containers. Used to group the global summary.
+	ComposeProject string
+	// Redactions counts values anonymized according to the privacy settings
+	Redactions privacy.Stats
+	// LogsText is the formatted, scrubbed log text sent to the LLM; only set when
+	// output.save_raw_logs is enabled.
+	LogsText string
+	// LLMSkipped is set when llm.skip_clean_logs judged the logs clean or fewer than
+	// llm.min_log_lines lines remained; no tokens were used and SkipReason says why.
+	LLMSkipped bool
+	// SkipReason is SkipReasonCleanLogs or SkipReasonBelowThreshold when LLMSkipped
+	SkipReason string
+	// ReanalyzedFrom is the saved raw log file the analysis was re-run on by
+	// dlia reanalyze; reports mark such analyses as re-analyses.
+	ReanalyzedFrom string
+	// ReportPath is the report file written for this analysis; set by the caller,
+	// empty when no report was saved. Used for the per-run report index.
+	ReportPath string
+	// CacheHit is set when llm.cache_enabled returned the stored analysis of identical
+	// input instead of calling the LLM; TokensUsed is then 0.
+	CacheHit bool
+}
+
+// PreviousAnalysisMaxBytes caps the previous analysis included in the analysis
+// prompt by AnalyzeLogsWithHistory.
+const PreviousAnalysisMaxBytes = 4000
+
+// TruncatedMarker ends an analysis cut by TruncateAnalysis.
+const TruncatedMarker = "\n\n[truncated]"
+
+// TruncateAnalysis shortens analysis to at most maxBytes bytes, including the
+// TruncatedMarker that flags the cut (a limit below the marker's length yields just
+// the marker). The cut falls on a rune boundary. An analysis within the limit and a
+// maxBytes of 0 or less (unlimited) return analysis unchanged.
+func TruncateAnalysis(analysis string, maxBytes int) string {
+	if maxBytes <= 0 || len(analysis) <= maxBytes {
+		return analysis
+	}
+
+	cut := max(maxBytes-len(TruncatedMarker), 0)
+	for cut > 0 && !utf8.RuneStart(analysis[cut]) {
+		cut--
+	}
+	return strings.TrimRightFunc(analysis[:cut], unicode.IsSpace) + TruncatedMarker
+}
+
+// systemPrompt renders the system prompt for the given user instructions and
+// estimates its tokens. Most containers of a scan share the same instructions, so
+// both are cached per instructions string for the lifetime of the pipeline.
+func (p *Pipeline) systemPrompt(instructions string) (string, int, error) {
+	p.systemPromptsMu.Lock()
+	defer p.systemPromptsMu.Unlock()
+
+	if entry, ok := p.systemPrompts[instructions]; ok {
+		return entry.prompt, entry.tokens, nil
+	}
+
+	prompt, err := p.promptLoader.SystemPrompt(instructions)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to load system prompt: %w", err)
+	}
+	entry := systemPromptEntry{prompt: prompt, tokens: p.tokenizer.EstimateSystemPromptTokens(prompt)}
+
+	if p.systemPrompts == nil {
+		p.systemPrompts = make(map[string]systemPromptEntry)
+	}
+	p.systemPrompts[instructions] = entry
+	return entry.prompt, entry.tokens, nil
 }
 
-// applyRegexpFilter applies container-specific regexp filtering to logs.
+// applyRegexpFilter applies container-specific regexp filtering to logs, falling back
+// to the regexp_filters_file patterns for containers without their own filters.
 // Returns filtered logs and filter statistics. Logs that match any pattern are excluded.
 func (p *Pipeline) applyRegexpFilter(containerName string, logs []docker.LogEntry) ([]docker.LogEntry, FilterStats) {
 	filter, exists := p.compiledRegexpsByContainer[containerName]
+	if !exists && p.defaultRegexpFilter != nil {
+		filter, exists = p.defaultRegexpFilter, true
+	}
 	if !exists {
 		return logs, FilterStats{
 			LinesTotal:    len(logs),
@@ -105,13 +315,15 @@ func (p *Pipeline) applyRegexpFilter(containerName string, logs []docker.LogEntr
 	}
 
 	stats := FilterStats{
-		LinesTotal: len(logs),
+		LinesTotal:  len(logs),
+		PatternHits: filter.newPatternHits(),
 	}
 
 	filteredLogs := make([]docker.LogEntry, 0, len(logs))
 	for _, entry := range logs {
-		if filter.MatchesAny(entry.Message) {
+		if i := filter.Match(entry.Message); i >= 0 {
 			stats.LinesFiltered++
+			stats.PatternHits[i].Lines++
 		} else {
 			filteredLogs = append(filteredLogs, entry)
 		}
@@ -121,10 +333,127 @@ func (p *Pipeline) applyRegexpFilter(containerName string, logs []docker.LogEntr
 	return filteredLogs, stats
 }
 
+// truncatedLineMarker ends a log line cut to fit llm.max_log_bytes.
+const truncatedLineMarker = " [truncated]"
+
+// truncateLogs enforces the configured line and byte caps, keeping the most recent
+// entries. Byte size is measured on the formatted lines sent to the LLM. When even
+// the most recent line exceeds the byte cap, it is kept cut to the cap instead of
+// dropping every line. Returns the kept logs and the number of dropped lines.
+func (p *Pipeline) truncateLogs(logs []docker.LogEntry) ([]docker.LogEntry, int) {
+	start := 0
+	if p.maxLogLines > 0 && len(logs) > p.maxLogLines {
+		start = len(logs) - p.maxLogLines
+	}
+
+	if p.maxLogBytes > 0 {
+		size := 0
+		for i := len(logs) - 1; i >= start; i-- {
+			size += formattedLogSize(logs[i])
+			if size > p.maxLogBytes {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	if start == len(logs) && start > 0 {
+		last := logs[start-1]
+		last.Message = cutLogMessage(last.Message, p.maxLogBytes-(formattedLogSize(last)-len(last.Message)))
+		return []docker.LogEntry{last}, start - 1
+	}
+	return logs[start:], start
+}
+
+// cutLogMessage shortens message to at most maxBytes bytes including the
+// truncatedLineMarker (just the marker when maxBytes is below its length). The cut
+// falls on a rune boundary.
+func cutLogMessage(message string, maxBytes int) string {
+	if len(message) <= maxBytes {
+		return message
+	}
+
+	cut := max(maxBytes-len(truncatedLineMarker), 0)
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + truncatedLineMarker
+}
+
+// responseReserve returns the tokens reserved for the model response.
+func (p *Pipeline) responseReserve() int {
+	if p.responseReserveTokens > 0 {
+		return p.responseReserveTokens
+	}
+	return ResponseReserveTokens
+}
+
+// scrubAnalysis anonymizes the analysis text and the structured findings of result
+// according to the privacy settings.
+func (p *Pipeline) scrubAnalysis(result *AnalyzeResult) {
+	result.Analysis = privacy.Scrub(result.Analysis, p.privacy)
+	if result.Structured == nil {
+		return
+	}
+
+	result.Structured.Summary = privacy.Scrub(result.Structured.Summary, p.privacy)
+	for i, e := range result.Structured.Errors {
+		result.Structured.Errors[i] = privacy.Scrub(e, p.privacy)
+	}
+	for i, r := range result.Structured.Recommendations {
+		result.Structured.Recommendations[i] = privacy.Scrub(r, p.privacy)
+	}
+}
+
+// scrubLogs anonymizes log messages according to the privacy settings.
+// The input slice is left untouched because callers reuse it for state and reports.
+func (p *Pipeline) scrubLogs(logs []docker.LogEntry) ([]docker.LogEntry, privacy.Stats) {
+	var total privacy.Stats
+	scrubbed := make([]docker.LogEntry, len(logs))
+	for i, entry := range logs {
+		message, stats := privacy.ScrubWithStats(entry.Message, p.privacy)
+		entry.Message = message
+		scrubbed[i] = entry
+		total.Add(stats)
+	}
+	return scrubbed, total
+}
+
+// sanitizeLogs applies docker.invalid_utf8 and returns the number of changed lines.
+func (p *Pipeline) sanitizeLogs(logs []docker.LogEntry) ([]docker.LogEntry, int) {
+	if p.invalidUTF8 == config.InvalidUTF8Keep {
+		return logs, 0
+	}
+	return docker.SanitizeUTF8(logs, p.invalidUTF8 == config.InvalidUTF8Escape)
+}
+
+// formattedLogSize returns the byte length of an entry as rendered by FormatLogs.
+func formattedLogSize(entry docker.LogEntry) int {
+	if entry.Timestamp != "" {
+		return len(entry.Timestamp) + len(entry.Message) + 4 // "[" + "] " + "\n"
+	}
+	return len(entry.Message) + 1
+}
+
 // AnalyzeLogs processes container logs through the complete pipeline: deduplication,
 // optional regexp filtering, and LLM-based analysis. Automatically handles chunking
 // and recursive summarization when logs exceed the model's context window.
 func (p *Pipeline) AnalyzeLogs(ctx context.Context, containerName string, logs []docker.LogEntry) (*AnalyzeResult, error) {
+	return p.AnalyzeLogsWithInstructions(ctx, containerName, logs, "")
+}
+
+// AnalyzeLogsWithInstructions is AnalyzeLogs with extra system-prompt instructions
+// for this container, e.g. from its dlia.instructions label. They are combined with
+// the container_instructions match and the ignore file.
+func (p *Pipeline) AnalyzeLogsWithInstructions(ctx context.Context, containerName string, logs []docker.LogEntry, instructions string) (*AnalyzeResult, error) {
+	return p.AnalyzeLogsWithHistory(ctx, containerName, logs, instructions, "")
+}
+
+// AnalyzeLogsWithHistory is AnalyzeLogsWithInstructions with the container's previous
+// analysis (llm.include_previous_analysis) passed to the analysis prompt for trend
+// detection. It is cut to PreviousAnalysisMaxBytes to bound its token cost; chunked
+// analyses end with the synthesis prompt and do not see it. Empty = no history.
+func (p *Pipeline) AnalyzeLogsWithHistory(ctx context.Context, containerName string, logs []docker.LogEntry, instructions, previousAnalysis string) (*AnalyzeResult, error) {
 	if len(logs) == 0 {
 		return &AnalyzeResult{
 			Analysis: "No logs to analyze",
@@ -135,8 +464,14 @@ func (p *Pipeline) AnalyzeLogs(ctx context.Context, containerName string, logs [
 		OriginalCount: len(logs),
 	}
 
+	// Step 0: Make binary output safe for prompts and reports, then merge continuation
+	// lines so later steps work on logical entries
+	logs, sanitizedLines := p.sanitizeLogs(logs)
+	physicalLines := len(logs)
+	logs = docker.GroupMultiline(logs, p.multiline)
+
 	// Step 1: Deduplicate
-	dedupLogs := Deduplicate(logs)
+	dedupLogs := DeduplicateWith(logs, p.dedupNormalized, p.dedupMinRepeats, p.dedupTimestamps)
 	if len(dedupLogs) < len(logs) {
 		result.Deduplicated = true
 		result.ProcessedCount = len(dedupLogs)
@@ -147,19 +482,46 @@ func (p *Pipeline) AnalyzeLogs(ctx context.Context, containerName string, logs [
 	// Step 1.5: Apply regexp filtering if configured for this container
 	processedLogs, filterStats := p.applyRegexpFilter(containerName, dedupLogs)
 	result.FilterStats = filterStats
+	result.FilterStats.SanitizedLines = sanitizedLines
+	if p.multiline != nil {
+		result.FilterStats.PhysicalLines = physicalLines
+	}
+	result.ProcessedCount = len(processedLogs)
+
+	// Step 1.75: Hard-cap input size, keeping the most recent lines
+	processedLogs, result.TruncatedLines = p.truncateLogs(processedLogs)
 	result.ProcessedCount = len(processedLogs)
 
+	// Step 1.8: Skip the LLM for too few lines, and for short logs without any issue keyword
+	if len(processedLogs) < p.minLogLines {
+		result.Analysis = BelowThresholdAnalysis
+		result.LLMSkipped = true
+		result.SkipReason = SkipReasonBelowThreshold
+		return result, nil
+	}
+	if p.skipCleanMaxLines > 0 && looksClean(processedLogs, p.skipCleanMaxLines, p.cleanKeywords) {
+		result.Analysis = CleanLogsAnalysis
+		result.LLMSkipped = true
+		result.SkipReason = SkipReasonCleanLogs
+		return result, nil
+	}
+
+	// Step 1.9: Anonymize sensitive values before anything is sent to the LLM
+	processedLogs, result.Redactions = p.scrubLogs(processedLogs)
+
 	// Step 2: Format logs
 	logsText := FormatLogs(processedLogs)
+	if p.keepLogsText {
+		result.LogsText = logsText
+	}
 
-	// Step 3: Load container-specific ignore patterns (error returns empty string, which is valid)
-	ignoreInstructions, _ := config.GetIgnoreInstructions(containerName, p.ignoreDir) //nolint:errcheck // Error returns empty string, which is valid
-
-	systemPrompt, err := p.promptLoader.SystemPrompt(ignoreInstructions)
+	// Step 3: Load container-specific instructions (container_instructions, label and ignore file)
+	systemPrompt, systemPromptTokens, err := p.systemPrompt(p.userInstructions(containerName, instructions))
 	if err != nil {
-		return nil, fmt.Errorf("failed to load system prompt: %w", err)
+		return nil, err
 	}
-	userPromptBase, err := p.promptLoader.AnalysisPrompt(containerName, "", len(processedLogs))
+	previousAnalysis = TruncateAnalysis(previousAnalysis, PreviousAnalysisMaxBytes)
+	userPromptBase, err := p.promptLoader.AnalysisPromptWithHistory(containerName, "", len(processedLogs), previousAnalysis)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load analysis prompt: %w", err)
 	}
@@ -167,83 +529,233 @@ func (p *Pipeline) AnalyzeLogs(ctx context.Context, containerName string, logs [
 	// Calculate token budget: system prompt + base user prompt + actual log content.
 	// Available tokens for logs = model limit - response reserve - system overhead.
 	// This ensures the model can generate a complete response without truncation.
-	systemTokens := p.tokenizer.EstimateSystemPromptTokens(systemPrompt)
+	// The system overhead is at least the configured system prompt reserve.
+	systemTokens := max(systemPromptTokens, p.systemPromptReserveTokens)
 	baseUserTokens := p.tokenizer.CountTokens(userPromptBase)
 	logsTokens := p.tokenizer.CountTokens(logsText)
+	responseReserve := p.responseReserve()
 
 	totalTokens := systemTokens + baseUserTokens + logsTokens
-	availableTokens := p.maxTokens - ResponseReserveTokens - systemTokens
+	availableTokens := p.maxTokens - responseReserve - systemTokens
+
+	// Step 3.5: Reuse the stored analysis of identical input (llm.cache_enabled)
+	var cacheKey string
+	if p.cache != nil {
+		cacheKey = p.cacheKey(containerName, systemPrompt, userPromptBase, logsText)
+		if cached, ok := p.cache.Get(cacheKey); ok {
+			result.Analysis = cached.Analysis
+			result.Structured = cached.Structured
+			result.ChunksUsed = cached.ChunksUsed
+			result.CacheHit = true
+			p.scrubAnalysis(result)
+			return result, nil
+		}
+	}
 
 	// Step 4: Choose analysis strategy based on token budget
-	if totalTokens+ResponseReserveTokens <= p.maxTokens {
-		analysis, usage, err := p.analyzeDirectly(ctx, containerName, processedLogs, systemPrompt, logsText)
+	if totalTokens+responseReserve <= p.maxTokens {
+		analysis, structured, usage, err := p.analyzeDirectly(ctx, containerName, processedLogs, systemPrompt, logsText, previousAnalysis)
 		if err != nil {
 			return nil, err
 		}
 		result.Analysis = analysis
+		result.Structured = structured
 		result.TokensUsed = usage.TotalTokens
 		result.ChunksUsed = 1
 	} else {
-		analysis, tokensUsed, chunksUsed, err := p.analyzeWithChunking(ctx, containerName, processedLogs, systemPrompt, availableTokens)
+		analysis, structured, tokensUsed, chunksUsed, err := p.analyzeWithChunking(ctx, containerName, processedLogs, systemPrompt, availableTokens)
 		if err != nil {
 			return nil, err
 		}
 		result.Analysis = analysis
+		result.Structured = structured
 		result.TokensUsed = tokensUsed
 		result.ChunksUsed = chunksUsed
 	}
 
+	// Step 5: The model may quote log lines back; scrub its output like the logs so
+	// reports, the knowledge base, notifications and the cache hold no redacted values
+	p.scrubAnalysis(result)
+
+	if p.cache != nil {
+		if err := p.cache.Put(cacheKey, result); err != nil {
+			slog.Warn("failed to cache analysis", "container", containerName, "error", err)
+		}
+	}
+
 	return result, nil
 }
 
-func (p *Pipeline) analyzeDirectly(ctx context.Context, containerName string, logs []docker.LogEntry, systemPrompt, logsText string) (string, *llm.TokenUsage, error) {
-	userPrompt, err := p.promptLoader.AnalysisPrompt(containerName, logsText, len(logs))
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to load analysis prompt: %w", err)
+// cacheKey returns the ResultCache key of an analysis. Besides the prompts sent
+// directly it covers the chunk summary and synthesis prompts, the sampling settings
+// and the token limits, which decide between a direct and a chunked analysis and
+// how long responses may be.
+func (p *Pipeline) cacheKey(containerName, systemPrompt, userPromptBase, logsText string) string {
+	options := fmt.Sprintf("structured=%t tools=%t critical_lines=%d max_tokens=%d response_reserve=%d overlap_lines=%d",
+		p.structuredOutput, p.structuredTools, p.synthesisCriticalLines, p.maxTokens, p.responseReserve(), p.chunkOverlapLines)
+	if p.config != nil {
+		options += fmt.Sprintf(" temperature=%g summary_temperature=%g top_p=%g frequency_penalty=%g chunk_summary_max_tokens=%d",
+			p.config.LLM.Temperature, p.config.LLM.SummaryTemperature, p.config.LLM.TopP,
+			p.config.LLM.FrequencyPenalty, p.config.LLM.ChunkSummaryMaxTokens)
 	}
-	return p.client.Analyze(ctx, containerName, systemPrompt, userPrompt)
+	// A template that fails to render fails the chunked analysis, which is then not cached
+	chunkPrompt, _ := p.promptLoader.ChunkSummaryPrompt(containerName, 0, 0, "")
+	synthesisPrompt, _ := p.promptLoader.SynthesisPrompt(containerName, nil)
+	return CacheKey(p.model, options, systemPrompt, userPromptBase, chunkPrompt, synthesisPrompt, logsText)
 }
 
-func (p *Pipeline) analyzeWithChunking(ctx context.Context, containerName string, logs []docker.LogEntry, systemPrompt string, availableTokens int) (analysis string, totalTokens, chunksUsed int, err error) {
-	chunks := ChunkLogs(logs, availableTokens/ChunkSizeDivisor, p.tokenizer)
+// analyze runs the final analysis call. In structured mode it requests JSON output
+// (or a report_analysis function call with llm.structured_method "tools") when the
+// client supports it and renders the parsed result as Markdown; if the model returns
+// prose instead, the prose response is used unchanged and structured is nil.
+func (p *Pipeline) analyze(ctx context.Context, containerName, systemPrompt, userPrompt string) (string, *llm.StructuredAnalysis, *llm.TokenUsage, error) {
+	if p.structuredOutput {
+		var analyzeStructured func(context.Context, string, string, string) (string, *llm.StructuredAnalysis, *llm.TokenUsage, error)
+		if analyzer, ok := p.client.(llm.ToolAnalyzer); ok && p.structuredTools {
+			analyzeStructured = analyzer.AnalyzeWithTool
+		} else if analyzer, ok := p.client.(llm.StructuredAnalyzer); ok && !p.structuredTools {
+			analyzeStructured = analyzer.AnalyzeStructured
+		}
+		if analyzeStructured != nil {
+			content, structured, usage, err := analyzeStructured(ctx, containerName, systemPrompt, userPrompt)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			if structured == nil {
+				return content, nil, usage, nil
+			}
+			return structured.Markdown(), structured, usage, nil
+		}
+	}
+
+	analysis, usage, err := p.client.Analyze(ctx, containerName, systemPrompt, userPrompt)
+	return analysis, nil, usage, err
+}
 
-	if len(chunks) == 0 {
-		return "No logs could be processed within token limits", 0, 0, nil
+func (p *Pipeline) analyzeDirectly(ctx context.Context, containerName string, logs []docker.LogEntry, systemPrompt, logsText, previousAnalysis string) (string, *llm.StructuredAnalysis, *llm.TokenUsage, error) {
+	userPrompt, err := p.promptLoader.AnalysisPromptWithHistory(containerName, logsText, len(logs), previousAnalysis)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to load analysis prompt: %w", err)
 	}
+	return p.analyze(ctx, containerName, systemPrompt, userPrompt)
+}
 
-	summaries := make([]string, len(chunks))
-	chunksUsed = len(chunks)
-	totalTokens = 0
+// synthesisPrompt renders the prompt combining the chunk summaries. With
+// llm.synthesis_critical_lines it also carries the most severe raw lines of each chunk,
+// as many as fit in availableTokens next to the summaries.
+func (p *Pipeline) synthesisPrompt(containerName string, summaries []string, chunks []Chunk, availableTokens int) (string, error) {
+	prompt, err := p.promptLoader.SynthesisPrompt(containerName, summaries)
+	if err != nil || p.synthesisCriticalLines <= 0 {
+		return prompt, err
+	}
 
-	for i, chunk := range chunks {
-		chunkText := FormatChunk(chunk)
-		chunkPrompt, promptErr := p.promptLoader.ChunkSummaryPrompt(containerName, i+1, len(chunks), chunkText)
-		if promptErr != nil {
-			return "", totalTokens, chunksUsed, fmt.Errorf("failed to load chunk summary prompt: %w", promptErr)
-		}
+	lines := synthesisCriticalLines(chunks, p.synthesisCriticalLines, availableTokens-p.tokenizer.CountTokens(prompt), p.tokenizer)
+	if lines == "" {
+		return prompt, nil
+	}
+	return p.promptLoader.SynthesisPromptWithLines(containerName, summaries, lines)
+}
 
-		summary, summarizeErr := p.client.SummarizeChunk(ctx, containerName, systemPrompt, chunkPrompt)
-		if summarizeErr != nil {
-			return "", totalTokens, chunksUsed, fmt.Errorf("failed to summarize chunk %d/%d (length: %d logs, %d tokens) for container %s: %w",
-				i+1, len(chunks), len(chunk.Logs), chunk.TokenCount, containerName, summarizeErr)
-		}
+func (p *Pipeline) analyzeWithChunking(ctx context.Context, containerName string, logs []docker.LogEntry, systemPrompt string, availableTokens int) (analysis string, structured *llm.StructuredAnalysis, totalTokens, chunksUsed int, err error) {
+	chunks := ChunkLogsWithOverlap(logs, availableTokens/ChunkSizeDivisor, p.chunkOverlapLines, p.tokenizer)
 
-		summaries[i] = summary
-		// Estimate token usage since SummarizeChunk doesn't return usage metrics
-		totalTokens += p.tokenizer.CountTokens(chunkText) + p.tokenizer.CountTokens(summary)
+	if len(chunks) == 0 {
+		return "No logs could be processed within token limits", nil, 0, 0, nil
 	}
 
-	synthesisPrompt, synthesisErr := p.promptLoader.SynthesisPrompt(containerName, summaries)
+	chunksUsed = len(chunks)
+	summaries, totalTokens, err := p.summarizeChunks(ctx, containerName, systemPrompt, chunks)
+	if err != nil {
+		return "", nil, totalTokens, chunksUsed, err
+	}
+
+	synthesisPrompt, synthesisErr := p.synthesisPrompt(containerName, summaries, chunks, availableTokens)
 	if synthesisErr != nil {
-		return "", totalTokens, chunksUsed, fmt.Errorf("failed to load synthesis prompt: %w", synthesisErr)
+		return "", nil, totalTokens, chunksUsed, fmt.Errorf("failed to load synthesis prompt: %w", synthesisErr)
 	}
-	finalAnalysis, usage, analyzeErr := p.client.Analyze(ctx, containerName, systemPrompt, synthesisPrompt)
+	finalAnalysis, structured, usage, analyzeErr := p.analyze(ctx, containerName, systemPrompt, synthesisPrompt)
 	if analyzeErr != nil {
-		return "", totalTokens, chunksUsed, fmt.Errorf("failed to synthesize %d chunk summaries for container %s: %w",
+		return "", nil, totalTokens, chunksUsed, fmt.Errorf("failed to synthesize %d chunk summaries for container %s: %w",
 			len(summaries), containerName, analyzeErr)
 	}
 
 	totalTokens += usage.TotalTokens
 
-	return finalAnalysis, totalTokens, chunksUsed, nil
+	return finalAnalysis, structured, totalTokens, chunksUsed, nil
+}
+
+// summarizeChunks summarizes the chunks with up to llm.chunk_concurrency parallel LLM
+// calls. Summaries keep chunk order for the synthesis step. The first failing chunk
+// cancels the calls still in flight and its error is returned together with the
+// tokens of the chunks summarized so far.
+func (p *Pipeline) summarizeChunks(ctx context.Context, containerName, systemPrompt string, chunks []Chunk) ([]string, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	summaries := make([]string, len(chunks))
+	tokens := make([]int, len(chunks))
+	var (
+		errOnce  sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	indexes := make(chan int)
+	workers := min(max(p.chunkConcurrency, 1), len(chunks))
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				summary, used, err := p.summarizeChunk(ctx, containerName, systemPrompt, chunks, i)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					cancel()
+					continue
+				}
+				summaries[i] = summary
+				tokens[i] = used
+			}
+		}()
+	}
+
+feed:
+	for i := range chunks {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	totalTokens := 0
+	for _, used := range tokens {
+		totalTokens += used
+	}
+	if firstErr == nil && ctx.Err() != nil {
+		// The caller's context ended before every chunk was handed out
+		firstErr = fmt.Errorf("chunk summarization for container %s interrupted: %w", containerName, ctx.Err())
+	}
+	return summaries, totalTokens, firstErr
+}
+
+// summarizeChunk summarizes chunk i and returns the summary with its estimated token usage.
+func (p *Pipeline) summarizeChunk(ctx context.Context, containerName, systemPrompt string, chunks []Chunk, i int) (string, int, error) {
+	chunk := chunks[i]
+	chunkText := FormatChunk(chunk)
+	chunkPrompt, err := p.promptLoader.ChunkSummaryPrompt(containerName, i+1, len(chunks), chunkText)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to load chunk summary prompt: %w", err)
+	}
+
+	summary, err := p.client.SummarizeChunk(ctx, containerName, systemPrompt, chunkPrompt)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to summarize chunk %d/%d (length: %d logs, %d tokens) for container %s: %w",
+			i+1, len(chunks), len(chunk.Logs), chunk.TokenCount, containerName, err)
+	}
+
+	// Estimate token usage since SummarizeChunk doesn't return usage metrics
+	return summary, p.tokenizer.CountTokens(chunkText) + p.tokenizer.CountTokens(summary), nil
 }
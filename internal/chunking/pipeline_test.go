@@ -2,7 +2,16 @@ package chunking
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,7 +27,8 @@ const (
 
 // MockTokenizer implements a mock tokenizer for testing
 type MockTokenizer struct {
-	tokensPerChar float64
+	tokensPerChar   float64
+	systemEstimates int // Calls to EstimateSystemPromptTokens
 }
 
 func NewMockTokenizer(tokensPerChar float64) *MockTokenizer {
@@ -30,6 +40,7 @@ func (m *MockTokenizer) CountTokens(text string) int {
 }
 
 func (m *MockTokenizer) EstimateSystemPromptTokens(systemPrompt string) int {
+	m.systemEstimates++
 	return m.CountTokens(systemPrompt) + 4
 }
 
@@ -48,6 +59,7 @@ type MockLLMClient struct {
 	analyzeError      error
 	summarizeResponse string
 	summarizeError    error
+	lastUserPrompt    string
 }
 
 func NewMockLLMClient() *MockLLMClient {
@@ -62,7 +74,8 @@ func NewMockLLMClient() *MockLLMClient {
 	}
 }
 
-func (m *MockLLMClient) Analyze(_ context.Context, _, _, _ string) (string, *llm.TokenUsage, error) {
+func (m *MockLLMClient) Analyze(_ context.Context, _, _, userPrompt string) (string, *llm.TokenUsage, error) {
+	m.lastUserPrompt = userPrompt
 	return m.analyzeResponse, m.analyzeUsage, m.analyzeError
 }
 
@@ -84,6 +97,46 @@ func (m *MockLLMClient) ChatCompletion(_ context.Context, _ []llm.ChatMessage, _
 	}, m.analyzeError
 }
 
+func TestNewPipeline_TokenizerFallback(t *testing.T) {
+	orig := newTokenizer
+	newTokenizer = func(string) (TokenizerInterface, error) {
+		return nil, errors.New("encoding download failed")
+	}
+	t.Cleanup(func() { newTokenizer = orig })
+
+	pipeline, err := NewPipeline("gpt-4", 8000, NewMockLLMClient(), prompts.NewPromptLoader(&config.Config{}), nil)
+	require.NoError(t, err, "tokenizer failures must not abort the scan")
+	assert.IsType(t, &EstimatingTokenizer{}, pipeline.tokenizer)
+}
+
+func TestNewPipeline_RegexpFiltersFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".dliaignore")
+	require.NoError(t, os.WriteFile(path, []byte("# probes\nGET /health\n"), 0o600))
+
+	cfg := &config.Config{
+		RegexpFiltersFile: path,
+		RegexpFilters: map[string]config.RegexpFilter{
+			"api": {Enabled: true, Patterns: []string{"^DEBUG"}},
+		},
+	}
+	pipeline, err := NewPipeline("gpt-4", 8000, NewMockLLMClient(), prompts.NewPromptLoader(cfg), cfg)
+	require.NoError(t, err)
+
+	logs := []docker.LogEntry{
+		{Message: "GET /health 200"},
+		{Message: "DEBUG cache warm"},
+		{Message: "POST /orders 500"},
+	}
+
+	kept, stats := pipeline.applyRegexpFilter("api", logs)
+	assert.Equal(t, 2, stats.LinesFiltered, "file patterns are merged with the container's filters")
+	assert.Equal(t, "POST /orders 500", kept[0].Message)
+
+	kept, stats = pipeline.applyRegexpFilter("web", logs)
+	assert.Equal(t, 1, stats.LinesFiltered, "file patterns apply to containers without filters")
+	assert.Len(t, kept, 2)
+}
+
 func TestNewPipeline(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -154,6 +207,7 @@ func TestPipeline_AnalyzeLogs(t *testing.T) {
 		tokensPerChar       float64
 		setupMock           func(*MockLLMClient)
 		regexpFilters       map[string]*RegexpFilter
+		dedupNormalized     bool
 		containerID         string
 		wantErr             bool
 		checkAnalysis       string
@@ -212,6 +266,22 @@ func TestPipeline_AnalyzeLogs(t *testing.T) {
 			checkProcessedCount: 2, // [REPEAT x4] and unique message
 			checkDeduplicated:   true,
 		},
+		{
+			name: "with normalized deduplication",
+			logs: []docker.LogEntry{
+				{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "request 1001 failed"},
+				{Timestamp: "2023-01-01T10:00:01Z", Stream: "stdout", Message: "request 1002 failed"},
+				{Timestamp: "2023-01-01T10:00:02Z", Stream: "stdout", Message: "request 1003 failed"},
+				{Timestamp: "2023-01-01T10:00:03Z", Stream: "stdout", Message: "Unique message"},
+			},
+			dedupNormalized:     true,
+			maxTokens:           8000,
+			tokensPerChar:       0.1,
+			containerID:         "test-container",
+			checkOriginalCount:  4,
+			checkProcessedCount: 2, // [REPEAT x3 similar] and unique message
+			checkDeduplicated:   true,
+		},
 		{
 			name: "LLM error",
 			logs: []docker.LogEntry{
@@ -323,6 +393,7 @@ func TestPipeline_AnalyzeLogs(t *testing.T) {
 				tokenizer:                  tokenizer,
 				compiledRegexpsByContainer: tt.regexpFilters,
 				promptLoader:               promptLoader,
+				dedupNormalized:            tt.dedupNormalized,
 			}
 
 			ctx := context.Background()
@@ -378,6 +449,303 @@ func TestPipeline_AnalyzeLogs(t *testing.T) {
 	}
 }
 
+func TestPipeline_TruncateLogs(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "first"},
+		{Timestamp: "2023-01-01T10:00:01Z", Stream: "stdout", Message: "second"},
+		{Timestamp: "2023-01-01T10:00:02Z", Stream: "stdout", Message: "third"},
+		{Timestamp: "2023-01-01T10:00:03Z", Stream: "stdout", Message: "fourth"},
+	}
+	lineSize := len(FormatLogs(logs[3:]))
+
+	tests := []struct {
+		name        string
+		maxLogLines int
+		maxLogBytes int
+		wantFirst   string
+		wantKept    int
+	}{
+		{name: "unlimited", wantFirst: "first", wantKept: 4},
+		{name: "line cap keeps most recent", maxLogLines: 2, wantFirst: "third", wantKept: 2},
+		{name: "line cap above input", maxLogLines: 10, wantFirst: "first", wantKept: 4},
+		{name: "byte cap keeps most recent", maxLogBytes: lineSize * 2, wantFirst: "third", wantKept: 2},
+		{name: "tighter cap wins", maxLogLines: 3, maxLogBytes: lineSize, wantFirst: "fourth", wantKept: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := &Pipeline{maxLogLines: tt.maxLogLines, maxLogBytes: tt.maxLogBytes}
+
+			kept, dropped := pipeline.truncateLogs(logs)
+
+			assert.Len(t, kept, tt.wantKept)
+			assert.Equal(t, len(logs)-tt.wantKept, dropped)
+			if tt.wantKept > 0 {
+				assert.Equal(t, tt.wantFirst, kept[0].Message)
+				assert.Equal(t, "fourth", kept[len(kept)-1].Message)
+			}
+		})
+	}
+}
+
+func TestPipeline_TruncateLogs_OversizedLine(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Message: "earlier"},
+		{Timestamp: "2023-01-01T10:00:01Z", Message: "panic: " + strings.Repeat("ä", 100)},
+	}
+	pipeline := &Pipeline{maxLogBytes: 60}
+
+	kept, dropped := pipeline.truncateLogs(logs)
+
+	require.Len(t, kept, 1, "the newest line must be kept cut rather than dropped")
+	assert.Equal(t, 1, dropped)
+	assert.True(t, strings.HasPrefix(kept[0].Message, "panic: ä"))
+	assert.True(t, strings.HasSuffix(kept[0].Message, truncatedLineMarker))
+	assert.LessOrEqual(t, formattedLogSize(kept[0]), 60)
+	assert.True(t, utf8.ValidString(kept[0].Message))
+	assert.Contains(t, logs[1].Message, strings.Repeat("ä", 100), "input logs must not be modified")
+}
+
+func TestTruncateAnalysis(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis string
+		maxBytes int
+		want     string
+	}{
+		{"unlimited", "all good", 0, "all good"},
+		{"within limit", "all good", 8, "all good"},
+		{"cut", "first line\nsecond line is longer", 24, "first line" + TruncatedMarker},
+		{"rune boundary", "ééééééééé", 16, "é" + TruncatedMarker},
+		{"below marker length", "all good, nothing to report", 5, TruncatedMarker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateAnalysis(tt.analysis, tt.maxBytes); got != tt.want {
+				t.Errorf("TruncateAnalysis(%q, %d) = %q, want %q", tt.analysis, tt.maxBytes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_AnalyzeLogs_Truncation(t *testing.T) {
+	pipeline := &Pipeline{
+		client:       NewMockLLMClient(),
+		maxTokens:    8000,
+		tokenizer:    NewMockTokenizer(0.1),
+		promptLoader: prompts.NewPromptLoader(&config.Config{}),
+		maxLogLines:  2,
+	}
+
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "Log message 1"},
+		{Timestamp: "2023-01-01T10:00:01Z", Stream: "stdout", Message: "Log message 2"},
+		{Timestamp: "2023-01-01T10:00:02Z", Stream: "stdout", Message: "Log message 3"},
+	}
+
+	result, err := pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.OriginalCount)
+	assert.Equal(t, 2, result.ProcessedCount)
+	assert.Equal(t, 1, result.TruncatedLines)
+}
+
+func TestPipeline_AnalyzeLogs_ReserveTokens(t *testing.T) {
+	logs := make([]docker.LogEntry, 5)
+	for i := range logs {
+		logs[i] = docker.LogEntry{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: fmt.Sprintf("line %d %s", i, strings.Repeat("x", 1000))}
+	}
+	newPipeline := func(responseReserve, systemReserve int) *Pipeline {
+		return &Pipeline{
+			client:                    NewMockLLMClient(),
+			maxTokens:                 2000,
+			tokenizer:                 NewMockTokenizer(0.1),
+			promptLoader:              prompts.NewPromptLoader(&config.Config{}),
+			responseReserveTokens:     responseReserve,
+			systemPromptReserveTokens: systemReserve,
+		}
+	}
+
+	// Default 4000 response reserve exceeds the 2000 token model: logs must be chunked
+	result, err := newPipeline(0, 0).AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+	assert.Greater(t, result.ChunksUsed, 1)
+
+	// A small reserve leaves room to analyze the ~500 log tokens directly
+	result, err = newPipeline(500, 100).AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ChunksUsed)
+
+	// A large system prompt reserve pushes the same logs back into chunking
+	result, err = newPipeline(500, 1400).AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+	assert.NotEqual(t, 1, result.ChunksUsed)
+}
+
+func TestPipeline_AnalyzeLogs_Redaction(t *testing.T) {
+	client := NewMockLLMClient()
+	pipeline := &Pipeline{
+		client:       client,
+		maxTokens:    8000,
+		tokenizer:    NewMockTokenizer(0.1),
+		promptLoader: prompts.NewPromptLoader(&config.Config{}),
+		privacy:      config.PrivacyConfig{AnonymizeIPs: true, AnonymizeEmails: true},
+	}
+
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "login from 192.168.1.20 by alice@example.com"},
+		{Timestamp: "2023-01-01T10:00:01Z", Stream: "stdout", Message: "request done"},
+	}
+
+	result, err := pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Redactions.IPs)
+	assert.Equal(t, 1, result.Redactions.Emails)
+	assert.NotContains(t, client.lastUserPrompt, "192.168.1.20")
+	assert.NotContains(t, client.lastUserPrompt, "alice@example.com")
+	assert.Contains(t, logs[0].Message, "192.168.1.20", "input logs must not be modified")
+	assert.Empty(t, result.LogsText, "log text is only kept with output.save_raw_logs")
+
+	pipeline.keepLogsText = true
+	result, err = pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+	assert.Contains(t, result.LogsText, "request done")
+	assert.NotContains(t, result.LogsText, "192.168.1.20", "saved log text must be scrubbed")
+
+	client.analyzeResponse = "Repeated logins from 192.168.1.20"
+	result, err = pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+	assert.NotContains(t, result.Analysis, "192.168.1.20", "values quoted back by the model must be scrubbed")
+	assert.Contains(t, result.Analysis, "[IP]")
+}
+
+func TestPipeline_AnalyzeLogs_Multiline(t *testing.T) {
+	client := NewMockLLMClient()
+	pipeline := &Pipeline{
+		client:       client,
+		maxTokens:    8000,
+		tokenizer:    NewMockTokenizer(0.1),
+		promptLoader: prompts.NewPromptLoader(&config.Config{}),
+		multiline:    regexp.MustCompile(`^(\s|at )`),
+	}
+
+	// Three identical stack traces: grouped, they are consecutive repeats and deduplicated
+	var logs []docker.LogEntry
+	for range 3 {
+		logs = append(logs,
+			docker.LogEntry{Timestamp: "2023-01-01T10:00:00Z", Stream: "stderr", Message: "panic: nil pointer"},
+			docker.LogEntry{Timestamp: "2023-01-01T10:00:00Z", Stream: "stderr", Message: "\tat main.go:10"},
+			docker.LogEntry{Timestamp: "2023-01-01T10:00:00Z", Stream: "stderr", Message: "\tat main.go:20"},
+		)
+	}
+
+	result, err := pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+
+	assert.Equal(t, 9, result.OriginalCount)
+	assert.Equal(t, 9, result.FilterStats.PhysicalLines)
+	assert.True(t, result.Deduplicated)
+	assert.Equal(t, 1, result.ProcessedCount)
+	assert.Contains(t, client.lastUserPrompt, "panic: nil pointer\n\tat main.go:10\n\tat main.go:20")
+}
+
+func TestPipeline_AnalyzeLogs_InvalidUTF8(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "upload \x89PNG\x00\xff done"},
+		{Timestamp: "2023-01-01T10:00:01Z", Stream: "stdout", Message: "request ok"},
+	}
+
+	tests := []struct {
+		mode          string
+		wantPrompt    string
+		wantSanitized int
+	}{
+		{mode: "", wantPrompt: "upload �PNG\x00� done", wantSanitized: 1},
+		{mode: config.InvalidUTF8Escape, wantPrompt: `upload \x89PNG` + "\x00" + `\xff done`, wantSanitized: 1},
+		{mode: config.InvalidUTF8Keep, wantPrompt: "upload \x89PNG\x00\xff done", wantSanitized: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode "+tt.mode, func(t *testing.T) {
+			client := NewMockLLMClient()
+			pipeline := &Pipeline{
+				client:       client,
+				maxTokens:    8000,
+				tokenizer:    NewMockTokenizer(0.1),
+				promptLoader: prompts.NewPromptLoader(&config.Config{}),
+				invalidUTF8:  tt.mode,
+			}
+
+			result, err := pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantSanitized, result.FilterStats.SanitizedLines)
+			assert.Contains(t, client.lastUserPrompt, tt.wantPrompt)
+		})
+	}
+
+	assert.Equal(t, "upload \x89PNG\x00\xff done", logs[0].Message, "input logs must not be modified")
+}
+
+func TestPipeline_SystemPromptCache(t *testing.T) {
+	tokenizer := NewMockTokenizer(0.1)
+	pipeline := &Pipeline{
+		client:       NewMockLLMClient(),
+		maxTokens:    8000,
+		tokenizer:    tokenizer,
+		promptLoader: prompts.NewPromptLoader(&config.Config{}),
+		ignoreDir:    t.TempDir(),
+	}
+	logs := []docker.LogEntry{{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "hello"}}
+
+	for _, name := range []string{"web", "api", "db"} {
+		_, err := pipeline.AnalyzeLogs(context.Background(), name, logs)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, tokenizer.systemEstimates, "containers without instructions share one system prompt")
+
+	_, err := pipeline.AnalyzeLogsWithInstructions(context.Background(), "web", logs, "Ignore health checks")
+	require.NoError(t, err)
+	assert.Equal(t, 2, tokenizer.systemEstimates, "different instructions need their own system prompt")
+
+	plain, _, err := pipeline.systemPrompt("")
+	require.NoError(t, err)
+	withInstructions, _, err := pipeline.systemPrompt("Ignore health checks")
+	require.NoError(t, err)
+	assert.NotContains(t, plain, "Ignore health checks")
+	assert.Contains(t, withInstructions, "Ignore health checks")
+	assert.Equal(t, 2, tokenizer.systemEstimates)
+}
+
+func TestPipeline_AnalyzeLogsWithHistory(t *testing.T) {
+	client := NewMockLLMClient()
+	pipeline := &Pipeline{
+		client:       client,
+		maxTokens:    8000,
+		tokenizer:    NewMockTokenizer(0.1),
+		promptLoader: prompts.NewPromptLoader(&config.Config{}),
+		ignoreDir:    t.TempDir(),
+	}
+	logs := []docker.LogEntry{{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "hello"}}
+
+	_, err := pipeline.AnalyzeLogsWithHistory(context.Background(), "web", logs, "", "Errors: disk full")
+	require.NoError(t, err)
+	assert.Contains(t, client.lastUserPrompt, "Errors: disk full")
+
+	long := strings.Repeat("x", PreviousAnalysisMaxBytes*2)
+	_, err = pipeline.AnalyzeLogsWithHistory(context.Background(), "web", logs, "", long)
+	require.NoError(t, err)
+	assert.Contains(t, client.lastUserPrompt, TruncatedMarker, "a long previous analysis is cut")
+	assert.NotContains(t, client.lastUserPrompt, long)
+
+	_, err = pipeline.AnalyzeLogsWithHistory(context.Background(), "web", logs, "", "")
+	require.NoError(t, err)
+	assert.NotContains(t, client.lastUserPrompt, "Previous analysis")
+}
+
 func TestMockTokenizer(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -479,13 +847,135 @@ func TestPipeline_AnalyzeDirectly(t *testing.T) {
 
 	logsText := FormatLogs(logs)
 	ctx := context.Background()
-	analysis, usage, err := pipeline.analyzeDirectly(ctx, "test-container", logs, "system prompt", logsText)
+	analysis, structured, usage, err := pipeline.analyzeDirectly(ctx, "test-container", logs, "system prompt", logsText, "")
 
 	require.NoError(t, err)
 	assert.Equal(t, testMockAnalysisResponse, analysis)
+	assert.Nil(t, structured, "structured output is disabled")
 	assert.Equal(t, 150, usage.TotalTokens)
 }
 
+// MockStructuredLLMClient adds JSON-mode support to MockLLMClient
+type MockStructuredLLMClient struct {
+	*MockLLMClient
+	structuredContent string
+	structuredCalls   int
+}
+
+func (m *MockStructuredLLMClient) AnalyzeStructured(_ context.Context, _, _, _ string) (string, *llm.StructuredAnalysis, *llm.TokenUsage, error) {
+	m.structuredCalls++
+	parsed, err := llm.ParseStructuredAnalysis(m.structuredContent)
+	if err != nil {
+		return m.structuredContent, nil, m.analyzeUsage, nil
+	}
+	return m.structuredContent, parsed, m.analyzeUsage, nil
+}
+
+// MockToolLLMClient adds tools-API support to MockStructuredLLMClient
+type MockToolLLMClient struct {
+	*MockStructuredLLMClient
+	toolCalls int
+}
+
+func (m *MockToolLLMClient) AnalyzeWithTool(_ context.Context, _, _, _ string) (string, *llm.StructuredAnalysis, *llm.TokenUsage, error) {
+	m.toolCalls++
+	parsed, err := llm.ParseStructuredAnalysis(m.structuredContent)
+	if err != nil {
+		return m.structuredContent, nil, m.analyzeUsage, nil
+	}
+	return m.structuredContent, parsed, m.analyzeUsage, nil
+}
+
+func TestPipeline_AnalyzeLogs_StructuredTools(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "ERROR database unreachable"},
+	}
+	client := &MockToolLLMClient{MockStructuredLLMClient: &MockStructuredLLMClient{
+		MockLLMClient:     NewMockLLMClient(),
+		structuredContent: `{"severity":"warning","summary":"DB slow","errors":["slow query"]}`,
+	}}
+	pipeline := &Pipeline{
+		client:           client,
+		maxTokens:        8000,
+		tokenizer:        NewMockTokenizer(0.1),
+		promptLoader:     prompts.NewPromptLoader(&config.Config{}),
+		structuredOutput: true,
+		structuredTools:  true,
+	}
+
+	result, err := pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, client.toolCalls)
+	assert.Equal(t, 0, client.structuredCalls, "JSON mode must not be used in tools mode")
+	require.NotNil(t, result.Structured)
+	assert.Equal(t, "warning", result.Structured.Severity)
+	assert.Contains(t, result.Analysis, "**Summary**: DB slow")
+}
+
+func TestPipeline_AnalyzeLogs_StructuredOutput(t *testing.T) {
+	logs := []docker.LogEntry{
+		{Timestamp: "2023-01-01T10:00:00Z", Stream: "stdout", Message: "ERROR database unreachable"},
+	}
+
+	tests := []struct {
+		name             string
+		structuredOutput bool
+		content          string
+		wantStructured   bool
+		wantCalls        int
+		wantAnalysis     string
+	}{
+		{
+			name:             "valid JSON is parsed",
+			structuredOutput: true,
+			content:          `{"severity":"critical","summary":"DB down","errors":["database unreachable"],"recommendations":["check DB"]}`,
+			wantStructured:   true,
+			wantCalls:        1,
+		},
+		{
+			name:             "prose falls back to raw content",
+			structuredOutput: true,
+			content:          "The database is unreachable.",
+			wantCalls:        1,
+			wantAnalysis:     "The database is unreachable.",
+		},
+		{
+			name:         "disabled uses plain analysis",
+			content:      `{"severity":"critical","summary":"DB down"}`,
+			wantCalls:    0,
+			wantAnalysis: testMockAnalysisResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockStructuredLLMClient{MockLLMClient: NewMockLLMClient(), structuredContent: tt.content}
+			pipeline := &Pipeline{
+				client:           client,
+				maxTokens:        8000,
+				tokenizer:        NewMockTokenizer(0.1),
+				promptLoader:     prompts.NewPromptLoader(&config.Config{}),
+				structuredOutput: tt.structuredOutput,
+			}
+
+			result, err := pipeline.AnalyzeLogs(context.Background(), "test-container", logs)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantCalls, client.structuredCalls)
+			if tt.wantStructured {
+				require.NotNil(t, result.Structured)
+				assert.Equal(t, "critical", result.Structured.Severity)
+				assert.Contains(t, result.Analysis, "**Summary**: DB down")
+				assert.Contains(t, result.Analysis, "- database unreachable")
+			} else {
+				assert.Nil(t, result.Structured)
+				assert.Equal(t, tt.wantAnalysis, result.Analysis)
+			}
+		})
+	}
+}
+
 func TestPipeline_AnalyzeWithChunking(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -552,7 +1042,7 @@ func TestPipeline_AnalyzeWithChunking(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			analysis, tokens, chunksUsed, err := pipeline.analyzeWithChunking(ctx, "test-container", tt.logs, "system prompt", tt.availableTokens)
+			analysis, _, tokens, chunksUsed, err := pipeline.analyzeWithChunking(ctx, "test-container", tt.logs, "system prompt", tt.availableTokens)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -575,6 +1065,93 @@ func TestPipeline_AnalyzeWithChunking(t *testing.T) {
 	}
 }
 
+// orderedSummaryClient returns the chunk number as summary after a delay that is
+// longer for earlier chunks, so parallel calls finish out of order.
+type orderedSummaryClient struct {
+	MockLLMClient
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	failOn   string
+}
+
+func (c *orderedSummaryClient) SummarizeChunk(ctx context.Context, _, _, chunkPrompt string) (string, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.peak = max(c.peak, c.inFlight)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	marker := chunkMarker.FindString(chunkPrompt)
+	if c.failOn != "" && marker == c.failOn {
+		return "", errors.New("summarize failed")
+	}
+	var n int
+	_, _ = fmt.Sscanf(marker, "chunk-%d", &n)
+	select {
+	case <-time.After(time.Duration(10-n) * time.Millisecond):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	return marker, nil
+}
+
+var chunkMarker = regexp.MustCompile(`chunk-\d+`)
+
+func TestPipeline_SummarizeChunks_Concurrent(t *testing.T) {
+	chunks := make([]Chunk, 6)
+	for i := range chunks {
+		chunks[i] = Chunk{Logs: []docker.LogEntry{{Timestamp: "2023-01-01T10:00:00Z", Message: fmt.Sprintf("chunk-%d", i)}}}
+	}
+
+	client := &orderedSummaryClient{MockLLMClient: *NewMockLLMClient()}
+	pipeline := &Pipeline{
+		client:           client,
+		tokenizer:        NewMockTokenizer(1.0),
+		promptLoader:     prompts.NewPromptLoader(&config.Config{}),
+		chunkConcurrency: 3,
+	}
+
+	summaries, tokens, err := pipeline.summarizeChunks(context.Background(), "test-container", "system prompt", chunks)
+	require.NoError(t, err)
+
+	for i, summary := range summaries {
+		assert.Equal(t, fmt.Sprintf("chunk-%d", i), summary, "summaries must keep chunk order")
+	}
+	assert.LessOrEqual(t, client.peak, 3)
+	assert.Greater(t, client.peak, 1, "chunks should be summarized in parallel")
+
+	want := 0
+	for i, chunk := range chunks {
+		want += len(FormatChunk(chunk)) + len(summaries[i])
+	}
+	assert.Equal(t, want, tokens)
+}
+
+func TestPipeline_SummarizeChunks_ErrorCancelsRest(t *testing.T) {
+	chunks := make([]Chunk, 8)
+	for i := range chunks {
+		chunks[i] = Chunk{Logs: []docker.LogEntry{{Timestamp: "2023-01-01T10:00:00Z", Message: fmt.Sprintf("chunk-%d", i)}}}
+	}
+
+	client := &orderedSummaryClient{MockLLMClient: *NewMockLLMClient(), failOn: "chunk-1"}
+	pipeline := &Pipeline{
+		client:           client,
+		tokenizer:        NewMockTokenizer(1.0),
+		promptLoader:     prompts.NewPromptLoader(&config.Config{}),
+		chunkConcurrency: 2,
+	}
+
+	_, _, err := pipeline.summarizeChunks(context.Background(), "test-container", "system prompt", chunks)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to summarize chunk 2/8")
+	assert.Contains(t, err.Error(), "summarize failed")
+}
+
 func TestMockLLMClient_ChatCompletion(t *testing.T) {
 	client := NewMockLLMClient()
 	ctx := context.Background()
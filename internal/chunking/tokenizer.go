@@ -19,6 +19,11 @@ type Tokenizer struct {
 	encoding *tiktoken.Tiktoken
 }
 
+// newTokenizer is replaced in tests to simulate an unavailable encoding
+var newTokenizer = func(model string) (TokenizerInterface, error) {
+	return NewTokenizer(model)
+}
+
 // NewTokenizer creates a new tokenizer for the specified model
 func NewTokenizer(model string) (*Tokenizer, error) {
 	// Get encoding for model
@@ -56,3 +61,37 @@ func (t *Tokenizer) EstimateUserPromptTokens(userPrompt string) int {
 func (t *Tokenizer) WillFitInContext(content string, maxTokens int) bool {
 	return t.CountTokens(content) <= maxTokens
 }
+
+// estimatedBytesPerToken is deliberately lower than the ~4 bytes per token of typical
+// English text, so estimates err on the high side and chunks stay within the context.
+const estimatedBytesPerToken = 3
+
+// EstimatingTokenizer approximates token counts from the text length. It is used when
+// no tiktoken encoding can be loaded (e.g. offline without a cached BPE file) and
+// overestimates rather than underestimates, so WillFitInContext stays safe.
+type EstimatingTokenizer struct{}
+
+// NewEstimatingTokenizer creates a tokenizer that estimates token counts.
+func NewEstimatingTokenizer() *EstimatingTokenizer {
+	return &EstimatingTokenizer{}
+}
+
+// CountTokens estimates the number of tokens in a text, rounding up
+func (t *EstimatingTokenizer) CountTokens(text string) int {
+	return (len(text) + estimatedBytesPerToken - 1) / estimatedBytesPerToken
+}
+
+// EstimateSystemPromptTokens estimates tokens for system prompt
+func (t *EstimatingTokenizer) EstimateSystemPromptTokens(systemPrompt string) int {
+	return t.CountTokens(systemPrompt) + 4
+}
+
+// EstimateUserPromptTokens estimates tokens for user prompt
+func (t *EstimatingTokenizer) EstimateUserPromptTokens(userPrompt string) int {
+	return t.CountTokens(userPrompt) + 4
+}
+
+// WillFitInContext checks if content fits within token budget
+func (t *EstimatingTokenizer) WillFitInContext(content string, maxTokens int) bool {
+	return t.CountTokens(content) <= maxTokens
+}
@@ -1,6 +1,7 @@
 package chunking
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -316,3 +317,33 @@ func TestTokenizerConsistency(t *testing.T) {
 		}
 	}
 }
+
+func TestEstimatingTokenizer(t *testing.T) {
+	tokenizer := NewEstimatingTokenizer()
+
+	if got := tokenizer.CountTokens(""); got != 0 {
+		t.Errorf("CountTokens(\"\") = %d, want 0", got)
+	}
+	if got := tokenizer.CountTokens("abcd"); got != 2 {
+		t.Errorf("CountTokens(\"abcd\") = %d, want 2 (rounded up)", got)
+	}
+	if got := tokenizer.EstimateSystemPromptTokens("abc"); got != 5 {
+		t.Errorf("EstimateSystemPromptTokens() = %d, want 5", got)
+	}
+	if got := tokenizer.EstimateUserPromptTokens("abc"); got != 5 {
+		t.Errorf("EstimateUserPromptTokens() = %d, want 5", got)
+	}
+	if !tokenizer.WillFitInContext("abcdef", 2) || tokenizer.WillFitInContext("abcdefg", 2) {
+		t.Error("WillFitInContext() should compare the estimate against the budget")
+	}
+}
+
+func TestEstimatingTokenizer_Overestimates(t *testing.T) {
+	// Typical log text averages about 4 bytes per cl100k token
+	line := "2025-01-01T10:00:00Z INFO request completed path=/api/items status=200 duration=12ms\n"
+	text := strings.Repeat(line, 50)
+
+	if got, floor := NewEstimatingTokenizer().CountTokens(text), len(text)/4; got < floor {
+		t.Errorf("CountTokens() = %d, should not underestimate below %d", got, floor)
+	}
+}
@@ -2,11 +2,15 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -20,7 +24,14 @@ var (
 // RegexpFilter represents regexp-based filtering configuration for a container
 type RegexpFilter struct {
 	Enabled  bool     `mapstructure:"enabled"`
-	Patterns []string `mapstructure:"patterns"`
+	Patterns []string `mapstructure:"patterns" expand:"-"`
+}
+
+// ContainerInstruction adds analysis instructions for containers whose name matches
+// Pattern (Go regexp, unanchored like scan --filter).
+type ContainerInstruction struct {
+	Pattern      string `mapstructure:"pattern" expand:"-"`
+	Instructions string `mapstructure:"instructions"`
 }
 
 // Config represents the application configuration
@@ -31,10 +42,22 @@ type Config struct {
 	Output        OutputConfig            `mapstructure:"output"`
 	Privacy       PrivacyConfig           `mapstructure:"privacy"`
 	Prompts       PromptsConfig           `mapstructure:"prompts"`
+	Logging       LoggingConfig           `mapstructure:"logging"`
 	RegexpFilters map[string]RegexpFilter `mapstructure:"regexp_filters"`
+	// RegexpFiltersFile is a .dliaignore-style file of exclude patterns applied to all
+	// containers; empty reads .dliaignore from the working directory if present
+	RegexpFiltersFile string `mapstructure:"regexp_filters_file"`
+	// ContainerInstructions is an ordered list; the first matching pattern wins
+	ContainerInstructions []ContainerInstruction `mapstructure:"container_instructions"`
+	// Source selects where container logs are read from: "docker" (default) or "kubernetes"
+	Source     string           `mapstructure:"source"`
+	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
 
 	// ConfigFilePath stores the path to the loaded config file (not marshaled from YAML)
 	ConfigFilePath string `mapstructure:"-"`
+	// Profile is the name of the profiles entry merged over the top-level settings;
+	// empty when no profile is active (not marshaled from YAML)
+	Profile string `mapstructure:"-"`
 }
 
 // PromptsConfig contains paths to custom prompt templates
@@ -44,6 +67,7 @@ type PromptsConfig struct {
 	ChunkSummaryPrompt     string `mapstructure:"chunk_summary_prompt"`
 	SynthesisPrompt        string `mapstructure:"synthesis_prompt"`
 	ExecutiveSummaryPrompt string `mapstructure:"executive_summary_prompt"`
+	NotificationPrompt     string `mapstructure:"notification_prompt"` // Template for the body of plain text scan notifications
 }
 
 // LLMConfig contains settings for the LLM API
@@ -51,18 +75,201 @@ type LLMConfig struct {
 	BaseURL   string `mapstructure:"base_url"`
 	APIKey    string `mapstructure:"api_key"`
 	Model     string `mapstructure:"model"`
-	MaxTokens int    `mapstructure:"max_tokens"`
+	MaxTokens int    `mapstructure:"max_tokens"` // Context window; 0 detects it from the model name
+	// APIKeys are further keys to fail over to when a key is rejected as unauthorized
+	// or out of quota; api_key, if set, is tried first
+	APIKeys []string `mapstructure:"api_keys"`
+	// MaxLogLines and MaxLogBytes cap the log input sent per container (0 = unlimited)
+	MaxLogLines int `mapstructure:"max_log_lines"`
+	MaxLogBytes int `mapstructure:"max_log_bytes"`
+	// StructuredOutput requests JSON analysis (response_format json_object) instead of prose
+	StructuredOutput bool `mapstructure:"structured_output"`
+	// StructuredMethod selects how structured output is requested: "json" (response_format,
+	// default) or "tools" (a forced function call, for gateways without JSON mode)
+	StructuredMethod string `mapstructure:"structured_method"`
+	// RequestTimeout is the HTTP timeout for a single LLM API request
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	// CircuitBreakerThreshold is the number of consecutive failed LLM requests (network
+	// errors or 5xx after retries) after which remaining calls fail fast (0 = disabled)
+	CircuitBreakerThreshold int `mapstructure:"circuit_breaker_threshold"`
+	// CircuitBreakerCooldown is how long calls fail fast before a trial request is sent
+	CircuitBreakerCooldown time.Duration `mapstructure:"circuit_breaker_cooldown"`
+	// ResponseReserveTokens is the context budget kept free for the analysis response
+	// and the max_tokens requested for analysis calls
+	ResponseReserveTokens int `mapstructure:"response_reserve_tokens"`
+	// SystemPromptReserveTokens is the minimum context budget reserved for the system prompt
+	SystemPromptReserveTokens int `mapstructure:"system_prompt_reserve_tokens"`
+	// ChunkSummaryMaxTokens is the max_tokens requested for each chunk summary
+	ChunkSummaryMaxTokens int `mapstructure:"chunk_summary_max_tokens"`
+	// Temperature is the sampling temperature of analysis requests (0-2; 0 = deterministic)
+	Temperature float64 `mapstructure:"temperature"`
+	// SummaryTemperature is the sampling temperature of chunk summaries, configured
+	// separately so summaries can stay conservative while analyses are tuned
+	SummaryTemperature float64 `mapstructure:"summary_temperature"`
+	// TopP is the nucleus sampling cutoff sent with every request (0-1; 0 = provider default)
+	TopP float64 `mapstructure:"top_p"`
+	// FrequencyPenalty penalizes repeated tokens in every response (-2 to 2; 0 = none)
+	FrequencyPenalty float64 `mapstructure:"frequency_penalty"`
+	// RequestsPerMinute caps outbound LLM requests across the whole process (0 = unlimited)
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	// ChunkConcurrency is the number of chunk summaries requested in parallel when a
+	// container's logs are chunked (0 or 1 = sequential)
+	ChunkConcurrency int `mapstructure:"chunk_concurrency"`
+	// ChunkOverlapLines repeats the last N lines of each chunk at the start of the next,
+	// so causes and effects split across a chunk boundary stay together (0 = disabled)
+	ChunkOverlapLines int `mapstructure:"chunk_overlap_lines"`
+	// SynthesisCriticalLines adds up to N of the most severe raw lines of each chunk
+	// (by error keywords) to the synthesis prompt, within its token budget (0 = disabled)
+	SynthesisCriticalLines int `mapstructure:"synthesis_critical_lines"`
+	// Provider selects the API conventions: "openai" (default, any OpenAI-compatible API),
+	// "azure" or "ollama" (Ollama's native /api/chat)
+	Provider string      `mapstructure:"provider"`
+	Azure    AzureConfig `mapstructure:"azure"`
+	// DedupAcrossScans skips lines whose normalized form was analyzed in the previous N scans (0 = disabled)
+	DedupAcrossScans int `mapstructure:"dedup_across_scans"`
+	// DedupMode selects how consecutive repeated lines are compared: "exact" (default)
+	// or "normalized" (timestamps, UUIDs, IPs, hex IDs and numbers are ignored)
+	DedupMode string `mapstructure:"dedup_mode"`
+	// DedupMinRepeats is the minimum run length collapsed into one [REPEAT xN] line
+	DedupMinRepeats int `mapstructure:"dedup_min_repeats"`
+	// DedupTimestamps adds the first and last timestamp of a collapsed run to its
+	// [REPEAT xN] marker, e.g. "[REPEAT x42 10:00:01–10:03:17]"
+	DedupTimestamps bool `mapstructure:"dedup_timestamps"`
+	// SkipCleanLogs skips the LLM call when at most SkipCleanMaxLines lines remain after
+	// deduplication and filtering and none contains a SkipCleanKeywords entry
+	SkipCleanLogs     bool     `mapstructure:"skip_clean_logs"`
+	SkipCleanMaxLines int      `mapstructure:"skip_clean_max_lines"`
+	SkipCleanKeywords []string `mapstructure:"skip_clean_keywords"` // Case-insensitive substrings
+	// MinLogLines skips the LLM call when fewer lines remain after deduplication and
+	// filtering; the scan still advances the state (0 = always analyze)
+	MinLogLines int `mapstructure:"min_log_lines"`
+	// IncludePreviousAnalysis adds the container's latest knowledge base entry to the
+	// analysis prompt so the model can point out new, recurring and resolved issues
+	IncludePreviousAnalysis bool `mapstructure:"include_previous_analysis"`
+	// CacheEnabled stores each analysis on disk keyed by a hash of the model, prompts
+	// and log content, and reuses it for identical input instead of calling the LLM
+	CacheEnabled bool `mapstructure:"cache_enabled"`
+	// CacheTTL is how long a cached analysis is reused
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// CacheDir holds the cached analyses
+	CacheDir string `mapstructure:"cache_dir"`
+}
+
+// DefaultSkipCleanKeywords is the default for llm.skip_clean_keywords: any of these
+// in a log line means the logs are not clean and go to the LLM.
+var DefaultSkipCleanKeywords = []string{
+	"error", "exception", "fatal", "panic", "critical", "fail", "warn",
+	"timeout", "timed out", "refused", "denied", "traceback", "killed", "oom",
 }
 
+// Supported llm.provider values
+const (
+	ProviderOpenAI = "openai"
+	ProviderAzure  = "azure"
+	ProviderOllama = "ollama"
+)
+
+// AzureConfig contains Azure OpenAI settings, used when llm.provider is "azure".
+// base_url is the resource endpoint, e.g. https://my-resource.openai.azure.com
+type AzureConfig struct {
+	Deployment string `mapstructure:"deployment"`
+	APIVersion string `mapstructure:"api_version"`
+}
+
+// Supported llm.dedup_mode values
+const (
+	DedupModeExact      = "exact"
+	DedupModeNormalized = "normalized"
+)
+
+// Supported llm.structured_method values
+const (
+	StructuredMethodJSON  = "json"
+	StructuredMethodTools = "tools"
+)
+
+// MaxDedupAcrossScans bounds llm.dedup_across_scans to keep per-container state small.
+const MaxDedupAcrossScans = 50
+
 // DockerConfig contains Docker-specific settings
 type DockerConfig struct {
-	SocketPath string `mapstructure:"socket_path"`
+	SocketPath       string `mapstructure:"socket_path"`
+	TimestampFormat  string `mapstructure:"timestamp_format"`             // Go time layout of a custom log timestamp
+	TimestampPattern string `mapstructure:"timestamp_pattern" expand:"-"` // Regex locating the timestamp in each line
+	Stream           string `mapstructure:"stream"`                       // Log stream to analyze: all, stdout or stderr
+	// MultilinePattern is a regex matching continuation lines (e.g. stack trace frames)
+	// that are merged into the preceding log entry before analysis (empty = disabled)
+	MultilinePattern string `mapstructure:"multiline_pattern" expand:"-"`
+	// MaxContainersPerScan caps how many containers one scan analyzes (0 = unlimited);
+	// the most recently active containers are kept.
+	MaxContainersPerScan int `mapstructure:"max_containers_per_scan"`
+	// MaxCatchupWindow limits how far back an incremental scan reads after downtime
+	// (0 = unlimited); older logs are skipped with a warning.
+	MaxCatchupWindow time.Duration `mapstructure:"max_catchup_window"`
+	// IncludeContainers and ExcludeContainers are container name regexps applied to every
+	// scan on top of --filter; a container must match an include pattern (if any are set)
+	// and no exclude pattern. Exclude wins over include.
+	IncludeContainers []string `mapstructure:"include_containers" expand:"-"`
+	ExcludeContainers []string `mapstructure:"exclude_containers" expand:"-"`
+	// IncludeEvents folds the container's Docker events of the scan window (deaths,
+	// OOM kills, restarts, failed health checks) into the logs sent to the LLM
+	IncludeEvents bool `mapstructure:"include_events"`
+	// InvalidUTF8 selects how log lines with invalid UTF-8 (binary output) are
+	// sanitized before analysis: replace, escape or keep
+	InvalidUTF8 string `mapstructure:"invalid_utf8"`
+}
+
+// Supported source values
+const (
+	SourceDocker     = "docker"
+	SourceKubernetes = "kubernetes"
+)
+
+// KubernetesConfig contains the settings of the Kubernetes log source (source: kubernetes).
+// With an empty APIServer the in-cluster service account configuration is used.
+type KubernetesConfig struct {
+	APIServer     string `mapstructure:"api_server"`     // API server URL, e.g. http://127.0.0.1:8001 for kubectl proxy
+	TokenFile     string `mapstructure:"token_file"`     // File holding a bearer token
+	CAFile        string `mapstructure:"ca_file"`        // CA certificate of the API server
+	Namespace     string `mapstructure:"namespace"`      // Only scan pods of this namespace (empty = all)
+	LabelSelector string `mapstructure:"label_selector"` // Kubernetes label selector for the pods to scan
+}
+
+// Log stream selections for docker.stream.
+const (
+	StreamAll    = "all"
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// Invalid UTF-8 handling modes for docker.invalid_utf8.
+const (
+	InvalidUTF8Replace = "replace" // Replace invalid byte sequences with U+FFFD
+	InvalidUTF8Escape  = "escape"  // Write invalid bytes as \xNN escapes
+	InvalidUTF8Keep    = "keep"    // Pass lines through unchanged
+)
+
+// ValidStream reports whether stream is a valid docker.stream / --stream value.
+// An empty value is accepted and means StreamAll.
+func ValidStream(stream string) bool {
+	switch stream {
+	case "", StreamAll, StreamStdout, StreamStderr:
+		return true
+	}
+	return false
 }
 
 // NotificationConfig contains notification settings
 type NotificationConfig struct {
-	ShoutrrURL string `mapstructure:"shoutrrr_url"` // Shoutrrr URL format
-	Enabled    bool   `mapstructure:"enabled"`
+	ShoutrrURL   string `mapstructure:"shoutrrr_url"` // Shoutrrr URL format
+	Enabled      bool   `mapstructure:"enabled"`
+	MinSeverity  string `mapstructure:"min_severity"`  // healthy, warning or critical
+	PerContainer bool   `mapstructure:"per_container"` // Send a separate alert for each flagged container
+	PendingDir   string `mapstructure:"pending_dir"`   // Queue for failed notifications (empty = disabled)
+	MaxPending   int    `mapstructure:"max_pending"`   // Oldest queued notifications are dropped beyond this
+	// CooldownPerContainer suppresses repeated per-container alerts within this period
+	// unless the container's severity increased (0 = alert on every scan)
+	CooldownPerContainer time.Duration `mapstructure:"cooldown_per_container"`
 }
 
 // OutputConfig contains output path settings
@@ -70,18 +277,67 @@ type OutputConfig struct {
 	ReportsDir             string `mapstructure:"reports_dir"`
 	KnowledgeBaseDir       string `mapstructure:"knowledge_base_dir"`
 	StateFile              string `mapstructure:"state_file"`
+	StateBackend           string `mapstructure:"state_backend"` // json or sqlite
 	IgnoreDir              string `mapstructure:"ignore_dir"`
 	LLMLogDir              string `mapstructure:"llm_log_dir"`
 	LLMLogEnabled          bool   `mapstructure:"llm_log_enabled"`
 	KnowledgeRetentionDays int    `mapstructure:"knowledge_retention_days"`
+	KnowledgeMaxEntries    int    `mapstructure:"knowledge_max_entries"` // 0 = unlimited
+	ReportFormat           string `mapstructure:"report_format"`         // md or html
+	KnowledgeFormat        string `mapstructure:"knowledge_format"`      // md or json
+	// KnowledgeSkipUnchanged updates the "last seen" time of the newest KB entry instead of
+	// appending a new one when status and analysis are unchanged
+	KnowledgeSkipUnchanged bool `mapstructure:"knowledge_skip_unchanged"`
+	// GroupByComposeProject groups the global summary status table by docker compose project
+	GroupByComposeProject bool `mapstructure:"group_by_compose_project"`
+	// SaveRawLogs writes the (scrubbed) log text sent to the LLM next to each report
+	SaveRawLogs bool `mapstructure:"save_raw_logs"`
+	// MaxReportBytes caps the size of a report file by truncating its analysis section;
+	// the same cap applies to the analysis of a knowledge base entry (0 = unlimited)
+	MaxReportBytes int `mapstructure:"max_report_bytes"`
+	// ShortIDLength is the number of container ID characters shown in command output
+	// (0 = DefaultShortIDLength; 64 shows full Docker IDs)
+	ShortIDLength int `mapstructure:"short_id_length"`
+	// ReportPathTemplate is a Go template for the report directory below reports_dir,
+	// with {{.Container}}, {{.Project}} and {{.Date}} (default: "{{.Container}}")
+	ReportPathTemplate string `mapstructure:"report_path_template"`
+	// DisplayTimezone is the IANA zone (e.g. "Europe/Berlin") used to display timestamps
+	// in reports, the knowledge base and state listings; empty = local time
+	DisplayTimezone string `mapstructure:"display_timezone"`
 }
 
+// DefaultShortIDLength is the container ID length shown by default, as in docker ps.
+const DefaultShortIDLength = 12
+
 // PrivacyConfig contains privacy/anonymization settings
 type PrivacyConfig struct {
-	AnonymizeIPs     bool `mapstructure:"anonymize_ips"`
-	AnonymizeSecrets bool `mapstructure:"anonymize_secrets"`
+	AnonymizeIPs         bool `mapstructure:"anonymize_ips"`
+	AnonymizeSecrets     bool `mapstructure:"anonymize_secrets"`
+	AnonymizeEmails      bool `mapstructure:"anonymize_emails"`
+	AnonymizeCardNumbers bool `mapstructure:"anonymize_card_numbers"` // Luhn-checked 13-16 digit numbers
 }
 
+// LoggingConfig controls the operational log written to stderr (connection, state
+// and retry diagnostics), which is separate from the scan progress on stdout.
+type LoggingConfig struct {
+	// Level is the minimum level logged: "debug", "info", "warn" (default) or "error".
+	// --verbose lowers it to debug.
+	Level string `mapstructure:"level"`
+	// Format is "text" (default, key=value) or "json" for log aggregators
+	Format string `mapstructure:"format"`
+}
+
+// Supported logging.level and logging.format values
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 // autoDetectDockerSocket determines the Docker socket path based on environment and platform.
 func autoDetectDockerSocket() string {
 	if os.Getenv("DOCKER_HOST") != "" {
@@ -95,8 +351,23 @@ func autoDetectDockerSocket() string {
 	return "npipe:////./pipe/docker_engine"
 }
 
-// Load reads configuration from file and environment variables
+// ProfileEnvVar selects the config profile when no profile is passed explicitly.
+const ProfileEnvVar = "DLIA_PROFILE"
+
+// ErrUnknownProfile is returned when the selected profile is not defined under
+// profiles in the config file.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// Load reads configuration from file and environment variables, applying the
+// profile named by DLIA_PROFILE if set.
 func Load(configPath string) (*Config, error) {
+	return LoadProfile(configPath, "")
+}
+
+// LoadProfile is Load with the settings of profiles.<profile> merged over the
+// top-level settings of the config file. An empty profile falls back to DLIA_PROFILE;
+// environment variables still override the merged values.
+func LoadProfile(configPath, profile string) (*Config, error) {
 	// Try to load .env file (ignore error if not exists)
 	_ = godotenv.Load() // nolint:errcheck // .env file is optional
 
@@ -129,6 +400,11 @@ func Load(configPath string) (*Config, error) {
 		// Config file not found; using defaults and env vars
 	}
 
+	profile, err := applyProfile(v, profile)
+	if err != nil {
+		return nil, err
+	}
+
 	// Environment variable support
 	v.SetEnvPrefix("DLIA")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -146,6 +422,11 @@ func Load(configPath string) (*Config, error) {
 
 	// Store the config file path in the struct (DI approach, no global state)
 	cfg.ConfigFilePath = v.ConfigFileUsed()
+	cfg.Profile = profile
+
+	if err := expandEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("error expanding environment variables in config: %w", err)
+	}
 
 	// Auto-detect Docker socket if not specified
 	if cfg.Docker.SocketPath == "" {
@@ -169,6 +450,11 @@ func LoadFromViper() (*Config, error) {
 	// Set defaults first
 	setDefaults(viper.GetViper())
 
+	profile, err := applyProfile(viper.GetViper(), "")
+	if err != nil {
+		return nil, err
+	}
+
 	// Environment variable support
 	viper.SetEnvPrefix("DLIA")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -182,6 +468,11 @@ func LoadFromViper() (*Config, error) {
 
 	// Store the config file path (DI approach, even for testing)
 	cfg.ConfigFilePath = viper.ConfigFileUsed()
+	cfg.Profile = profile
+
+	if err := expandEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("error expanding environment variables in config: %w", err)
+	}
 
 	// Auto-detect Docker socket if not specified
 	if cfg.Docker.SocketPath == "" {
@@ -196,12 +487,96 @@ func LoadFromViper() (*Config, error) {
 	return &cfg, nil
 }
 
+// applyProfile merges the profiles.<profile> section of the config into its top-level
+// settings and returns the applied profile name; an empty profile falls back to
+// DLIA_PROFILE, and "" is returned when neither names a profile.
+func applyProfile(v *viper.Viper, profile string) (string, error) {
+	if profile == "" {
+		profile = os.Getenv(ProfileEnvVar)
+	}
+	if profile == "" {
+		return "", nil
+	}
+
+	section, ok := v.Get("profiles." + profile).(map[string]any)
+	if !ok {
+		defined := slices.Sorted(maps.Keys(v.GetStringMap("profiles")))
+		if len(defined) == 0 {
+			return "", fmt.Errorf("%w %q: the config file defines no profiles", ErrUnknownProfile, profile)
+		}
+		return "", fmt.Errorf("%w %q (defined profiles: %s)", ErrUnknownProfile, profile, strings.Join(defined, ", "))
+	}
+	if err := v.MergeConfigMap(section); err != nil {
+		return "", fmt.Errorf("failed to apply profile %q: %w", profile, err)
+	}
+
+	return profile, nil
+}
+
+// Parse reads configuration from YAML content with defaults applied, without
+// consulting .env or environment variables and without validating. Used by init to
+// check a generated config.yaml before writing it.
+func Parse(content []byte) (*Config, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	setDefaults(v)
+
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("error parsing config: %w", err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+
+	if cfg.Docker.SocketPath == "" {
+		cfg.Docker.SocketPath = autoDetectDockerSocket()
+	}
+
+	return &cfg, nil
+}
+
 func setDefaults(v *viper.Viper) {
 	// LLM defaults
 	v.SetDefault("llm.base_url", "https://api.openai.com/v1")
 	v.SetDefault("llm.model", "gpt-4o-mini")
-	v.SetDefault("llm.max_tokens", 128000)
+	v.SetDefault("llm.max_tokens", 0)
 	v.SetDefault("llm.api_key", "") // Required for AutomaticEnv to work
+	v.SetDefault("llm.api_keys", []string{})
+	v.SetDefault("llm.max_log_lines", 0)
+	v.SetDefault("llm.max_log_bytes", 0)
+	v.SetDefault("llm.structured_output", false)
+	v.SetDefault("llm.structured_method", StructuredMethodJSON)
+	v.SetDefault("llm.request_timeout", "120s")
+	v.SetDefault("llm.circuit_breaker_threshold", 3)
+	v.SetDefault("llm.circuit_breaker_cooldown", "5m")
+	v.SetDefault("llm.dedup_across_scans", 0)
+	v.SetDefault("llm.dedup_mode", DedupModeExact)
+	v.SetDefault("llm.dedup_timestamps", true)
+	v.SetDefault("llm.dedup_min_repeats", 3)
+	v.SetDefault("llm.skip_clean_logs", false)
+	v.SetDefault("llm.skip_clean_max_lines", 200)
+	v.SetDefault("llm.skip_clean_keywords", DefaultSkipCleanKeywords)
+	v.SetDefault("llm.min_log_lines", 0)
+	v.SetDefault("llm.include_previous_analysis", false)
+	v.SetDefault("llm.cache_enabled", false)
+	v.SetDefault("llm.cache_ttl", "24h")
+	v.SetDefault("llm.cache_dir", "./cache")
+	v.SetDefault("llm.provider", ProviderOpenAI)
+	v.SetDefault("llm.azure.deployment", "")
+	v.SetDefault("llm.azure.api_version", "")
+	v.SetDefault("llm.response_reserve_tokens", 4000)
+	v.SetDefault("llm.system_prompt_reserve_tokens", 500)
+	v.SetDefault("llm.chunk_summary_max_tokens", 2000)
+	v.SetDefault("llm.temperature", 0.3)
+	v.SetDefault("llm.summary_temperature", 0.3)
+	v.SetDefault("llm.top_p", 0.0)
+	v.SetDefault("llm.frequency_penalty", 0.0)
+	v.SetDefault("llm.requests_per_minute", 0)
+	v.SetDefault("llm.chunk_concurrency", 1)
+	v.SetDefault("llm.chunk_overlap_lines", 0)
+	v.SetDefault("llm.synthesis_critical_lines", 0)
 
 	// Docker defaults
 	if os.Getenv("DOCKER_HOST") != "" {
@@ -214,25 +589,61 @@ func setDefaults(v *viper.Viper) {
 			v.SetDefault("docker.socket_path", "npipe:////./pipe/docker_engine")
 		}
 	}
+	v.SetDefault("docker.timestamp_format", "")
+	v.SetDefault("docker.timestamp_pattern", "")
+	v.SetDefault("docker.stream", StreamAll)
+	v.SetDefault("docker.max_containers_per_scan", 0)
+	v.SetDefault("docker.max_catchup_window", "0s")
+	v.SetDefault("docker.include_containers", []string{})
+	v.SetDefault("docker.exclude_containers", []string{})
+	v.SetDefault("docker.multiline_pattern", "")
+	v.SetDefault("docker.include_events", false)
+	v.SetDefault("docker.invalid_utf8", InvalidUTF8Replace)
+
+	// Log source defaults
+	v.SetDefault("source", SourceDocker)
+	v.SetDefault("kubernetes.api_server", "")
+	v.SetDefault("kubernetes.token_file", "")
+	v.SetDefault("kubernetes.ca_file", "")
+	v.SetDefault("kubernetes.namespace", "")
+	v.SetDefault("kubernetes.label_selector", "")
 
 	// Scheduler defaults
 
 	// Notification defaults
 	v.SetDefault("notification.shoutrrr_url", "") // Required for AutomaticEnv to work
 	v.SetDefault("notification.enabled", false)
+	v.SetDefault("notification.min_severity", "healthy")
+	v.SetDefault("notification.per_container", false)
+	v.SetDefault("notification.pending_dir", "")
+	v.SetDefault("notification.max_pending", 100)
+	v.SetDefault("notification.cooldown_per_container", "0s")
 
 	// Output defaults
 	v.SetDefault("output.reports_dir", "./reports")
 	v.SetDefault("output.knowledge_base_dir", "./knowledge_base")
 	v.SetDefault("output.state_file", "./state.json")
+	v.SetDefault("output.state_backend", "json")
 	v.SetDefault("output.ignore_dir", "./config/ignore")
 	v.SetDefault("output.llm_log_dir", "./logs/llm")
 	v.SetDefault("output.llm_log_enabled", false)
 	v.SetDefault("output.knowledge_retention_days", 30)
+	v.SetDefault("output.knowledge_max_entries", 0)
+	v.SetDefault("output.report_format", "md")
+	v.SetDefault("output.knowledge_format", "md")
+	v.SetDefault("output.knowledge_skip_unchanged", false)
+	v.SetDefault("output.max_report_bytes", 0)
+	v.SetDefault("output.short_id_length", DefaultShortIDLength)
+	v.SetDefault("output.save_raw_logs", false)
+	v.SetDefault("output.report_path_template", DefaultReportPathTemplate)
+	v.SetDefault("output.display_timezone", "")
+	v.SetDefault("output.group_by_compose_project", false)
 
 	// Privacy defaults
 	v.SetDefault("privacy.anonymize_ips", true)
 	v.SetDefault("privacy.anonymize_secrets", true)
+	v.SetDefault("privacy.anonymize_emails", true)
+	v.SetDefault("privacy.anonymize_card_numbers", true)
 
 	// Prompts defaults (empty = use embedded defaults)
 	v.SetDefault("prompts.system_prompt", "")
@@ -240,9 +651,15 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("prompts.chunk_summary_prompt", "")
 	v.SetDefault("prompts.synthesis_prompt", "")
 	v.SetDefault("prompts.executive_summary_prompt", "")
+	v.SetDefault("prompts.notification_prompt", "")
+
+	// Logging defaults
+	v.SetDefault("logging.level", LogLevelWarn)
+	v.SetDefault("logging.format", LogFormatText)
 
 	// Regexp filters defaults (empty map = no filters)
 	v.SetDefault("regexp_filters", map[string]RegexpFilter{})
+	v.SetDefault("regexp_filters_file", "")
 }
 
 // Validate ensures all required fields are set and values are within valid ranges.
@@ -260,25 +677,53 @@ func (c *Config) Validate() error {
 		return err
 	}
 
-	return c.validateRegexpFilters()
+	if err := c.validateNotification(configSource); err != nil {
+		return err
+	}
+
+	if err := c.validateLogging(configSource); err != nil {
+		return err
+	}
+
+	if err := c.validateReportPathTemplate(configSource); err != nil {
+		return err
+	}
+
+	if err := c.validateRegexpFilters(); err != nil {
+		return err
+	}
+
+	return c.validateContainerInstructions()
 }
 
 func (c *Config) validateRequiredFields(configSource string) error {
+	for i, key := range c.LLM.APIKeys {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("llm.api_keys[%d] is empty in config %s", i, configSource)
+		}
+	}
+	var apiKey string
+	if keys := c.LLM.Keys(); len(keys) > 0 {
+		apiKey = keys[0]
+	}
+
 	requiredFields := []struct {
-		value   string
-		message string
+		value    string
+		message  string
+		optional bool
 	}{
-		{c.LLM.BaseURL, "llm.base_url is required in config %s"},
-		{c.LLM.APIKey, "llm.api_key is required in config %s (set DLIA_LLM_API_KEY environment variable)"},
-		{c.LLM.Model, "llm.model is required in config %s"},
-		{c.Docker.SocketPath, "docker.socket_path is required in config %s"},
-		{c.Output.ReportsDir, "output.reports_dir is required in config %s"},
-		{c.Output.KnowledgeBaseDir, "output.knowledge_base_dir is required in config %s"},
-		{c.Output.StateFile, "output.state_file is required in config %s"},
+		{c.LLM.BaseURL, "llm.base_url is required in config %s", false},
+		// Local Ollama servers do not need an API key
+		{apiKey, "llm.api_key is required in config %s (set DLIA_LLM_API_KEY environment variable)", c.LLM.Provider == ProviderOllama},
+		{c.LLM.Model, "llm.model is required in config %s", false},
+		{c.Docker.SocketPath, "docker.socket_path is required in config %s", false},
+		{c.Output.ReportsDir, "output.reports_dir is required in config %s", false},
+		{c.Output.KnowledgeBaseDir, "output.knowledge_base_dir is required in config %s", false},
+		{c.Output.StateFile, "output.state_file is required in config %s", false},
 	}
 
 	for _, field := range requiredFields {
-		if field.value == "" {
+		if field.value == "" && !field.optional {
 			return fmt.Errorf(field.message, configSource)
 		}
 	}
@@ -286,10 +731,300 @@ func (c *Config) validateRequiredFields(configSource string) error {
 }
 
 func (c *Config) validateRanges(configSource string) error {
+	if c.LLM.RequestTimeout <= 0 {
+		return fmt.Errorf("llm.request_timeout must be a positive duration (e.g. 120s, 5m), got %s in config %s",
+			c.LLM.RequestTimeout, configSource)
+	}
+	if c.LLM.CircuitBreakerThreshold < 0 {
+		return fmt.Errorf("llm.circuit_breaker_threshold must be 0 (disabled) or greater, got %d in config %s",
+			c.LLM.CircuitBreakerThreshold, configSource)
+	}
+	if c.LLM.CircuitBreakerThreshold > 0 && c.LLM.CircuitBreakerCooldown <= 0 {
+		return fmt.Errorf("llm.circuit_breaker_cooldown must be a positive duration when llm.circuit_breaker_threshold is set, got %s in config %s",
+			c.LLM.CircuitBreakerCooldown, configSource)
+	}
+	if c.LLM.Temperature < 0 || c.LLM.Temperature > 2 {
+		return fmt.Errorf("llm.temperature must be between 0 and 2, got %g in config %s",
+			c.LLM.Temperature, configSource)
+	}
+	if c.LLM.SummaryTemperature < 0 || c.LLM.SummaryTemperature > 2 {
+		return fmt.Errorf("llm.summary_temperature must be between 0 and 2, got %g in config %s",
+			c.LLM.SummaryTemperature, configSource)
+	}
+	if c.LLM.TopP < 0 || c.LLM.TopP > 1 {
+		return fmt.Errorf("llm.top_p must be between 0 (provider default) and 1, got %g in config %s",
+			c.LLM.TopP, configSource)
+	}
+	if c.LLM.FrequencyPenalty < -2 || c.LLM.FrequencyPenalty > 2 {
+		return fmt.Errorf("llm.frequency_penalty must be between -2 and 2, got %g in config %s",
+			c.LLM.FrequencyPenalty, configSource)
+	}
+	if c.LLM.CacheEnabled && c.LLM.CacheTTL <= 0 {
+		return fmt.Errorf("llm.cache_ttl must be a positive duration when llm.cache_enabled is set, got %s in config %s",
+			c.LLM.CacheTTL, configSource)
+	}
+	if c.LLM.CacheEnabled && c.LLM.CacheDir == "" {
+		return fmt.Errorf("llm.cache_dir must be set when llm.cache_enabled is set in config %s", configSource)
+	}
+	if c.LLM.DedupAcrossScans < 0 || c.LLM.DedupAcrossScans > MaxDedupAcrossScans {
+		return fmt.Errorf("llm.dedup_across_scans must be between 0 (disabled) and %d, got %d in config %s",
+			MaxDedupAcrossScans, c.LLM.DedupAcrossScans, configSource)
+	}
+	if c.LLM.StructuredMethod != "" && c.LLM.StructuredMethod != StructuredMethodJSON && c.LLM.StructuredMethod != StructuredMethodTools {
+		return fmt.Errorf("llm.structured_method must be \"json\" or \"tools\", got %q in config %s",
+			c.LLM.StructuredMethod, configSource)
+	}
+	if c.LLM.DedupMode != "" && c.LLM.DedupMode != DedupModeExact && c.LLM.DedupMode != DedupModeNormalized {
+		return fmt.Errorf("llm.dedup_mode must be \"exact\" or \"normalized\", got %q in config %s",
+			c.LLM.DedupMode, configSource)
+	}
+	if c.LLM.DedupMinRepeats < 0 || c.LLM.DedupMinRepeats == 1 {
+		return fmt.Errorf("llm.dedup_min_repeats must be at least 2, got %d in config %s",
+			c.LLM.DedupMinRepeats, configSource)
+	}
+	if c.LLM.SkipCleanLogs && c.LLM.SkipCleanMaxLines < 1 {
+		return fmt.Errorf("llm.skip_clean_max_lines must be at least 1 when llm.skip_clean_logs is enabled, got %d in config %s",
+			c.LLM.SkipCleanMaxLines, configSource)
+	}
+	if c.LLM.MinLogLines < 0 {
+		return fmt.Errorf("llm.min_log_lines must be 0 (disabled) or greater, got %d in config %s",
+			c.LLM.MinLogLines, configSource)
+	}
+	if c.LLM.MaxLogLines < 0 {
+		return fmt.Errorf("llm.max_log_lines must be 0 (unlimited) or greater, got %d in config %s",
+			c.LLM.MaxLogLines, configSource)
+	}
+	if c.LLM.MaxLogBytes < 0 {
+		return fmt.Errorf("llm.max_log_bytes must be 0 (unlimited) or greater, got %d in config %s",
+			c.LLM.MaxLogBytes, configSource)
+	}
+	if c.LLM.RequestsPerMinute < 0 {
+		return fmt.Errorf("llm.requests_per_minute must be 0 (unlimited) or greater, got %d in config %s",
+			c.LLM.RequestsPerMinute, configSource)
+	}
+	if c.LLM.ChunkConcurrency < 0 {
+		return fmt.Errorf("llm.chunk_concurrency must be 0 (default, sequential) or greater, got %d in config %s",
+			c.LLM.ChunkConcurrency, configSource)
+	}
+	if c.LLM.ChunkOverlapLines < 0 {
+		return fmt.Errorf("llm.chunk_overlap_lines must be 0 (disabled) or greater, got %d in config %s",
+			c.LLM.ChunkOverlapLines, configSource)
+	}
+	if c.LLM.SynthesisCriticalLines < 0 {
+		return fmt.Errorf("llm.synthesis_critical_lines must be 0 (disabled) or greater, got %d in config %s",
+			c.LLM.SynthesisCriticalLines, configSource)
+	}
+	if err := c.validateProvider(configSource); err != nil {
+		return err
+	}
+	switch c.Source {
+	case "", SourceDocker, SourceKubernetes:
+	default:
+		return fmt.Errorf("source must be %q or %q, got %q in config %s", SourceDocker, SourceKubernetes, c.Source, configSource)
+	}
+	if err := c.validateTokenBudget(configSource); err != nil {
+		return err
+	}
+	if c.Docker.TimestampPattern != "" {
+		if _, err := regexp.Compile(c.Docker.TimestampPattern); err != nil {
+			return fmt.Errorf("docker.timestamp_pattern is not a valid regexp in config %s: %w", configSource, err)
+		}
+	}
+	if c.Docker.MultilinePattern != "" {
+		if _, err := regexp.Compile(c.Docker.MultilinePattern); err != nil {
+			return fmt.Errorf("docker.multiline_pattern is not a valid regexp in config %s: %w", configSource, err)
+		}
+	}
+	if !ValidStream(c.Docker.Stream) {
+		return fmt.Errorf("docker.stream must be %q, %q or %q, got %q in config %s",
+			StreamAll, StreamStdout, StreamStderr, c.Docker.Stream, configSource)
+	}
+	switch c.Docker.InvalidUTF8 {
+	case "", InvalidUTF8Replace, InvalidUTF8Escape, InvalidUTF8Keep:
+	default:
+		return fmt.Errorf("docker.invalid_utf8 must be %q, %q or %q, got %q in config %s",
+			InvalidUTF8Replace, InvalidUTF8Escape, InvalidUTF8Keep, c.Docker.InvalidUTF8, configSource)
+	}
+	if c.Docker.MaxContainersPerScan < 0 {
+		return fmt.Errorf("docker.max_containers_per_scan must be 0 (unlimited) or greater, got %d in config %s",
+			c.Docker.MaxContainersPerScan, configSource)
+	}
+	if err := validateNamePatterns("docker.include_containers", c.Docker.IncludeContainers, configSource); err != nil {
+		return err
+	}
+	if err := validateNamePatterns("docker.exclude_containers", c.Docker.ExcludeContainers, configSource); err != nil {
+		return err
+	}
+	if c.Docker.MaxCatchupWindow < 0 {
+		return fmt.Errorf("docker.max_catchup_window must be 0 (unlimited) or a positive duration, got %s in config %s",
+			c.Docker.MaxCatchupWindow, configSource)
+	}
 	if c.Output.KnowledgeRetentionDays < 1 || c.Output.KnowledgeRetentionDays > 365 {
 		return fmt.Errorf("output.knowledge_retention_days must be between 1 and 365, got %d in config %s",
 			c.Output.KnowledgeRetentionDays, configSource)
 	}
+	if c.Output.KnowledgeMaxEntries < 0 {
+		return fmt.Errorf("output.knowledge_max_entries must be 0 (unlimited) or greater, got %d in config %s",
+			c.Output.KnowledgeMaxEntries, configSource)
+	}
+	if c.Output.ShortIDLength < 0 {
+		return fmt.Errorf("output.short_id_length must be 0 (default %d) or greater, got %d in config %s",
+			DefaultShortIDLength, c.Output.ShortIDLength, configSource)
+	}
+	if c.Output.MaxReportBytes < 0 {
+		return fmt.Errorf("output.max_report_bytes must be 0 (unlimited) or greater, got %d in config %s",
+			c.Output.MaxReportBytes, configSource)
+	}
+	switch c.Output.StateBackend {
+	case "", "json", "sqlite":
+	default:
+		return fmt.Errorf("output.state_backend must be \"json\" or \"sqlite\", got %q in config %s",
+			c.Output.StateBackend, configSource)
+	}
+	switch c.Output.ReportFormat {
+	case "", "md", "html":
+	default:
+		return fmt.Errorf("output.report_format must be \"md\" or \"html\", got %q in config %s",
+			c.Output.ReportFormat, configSource)
+	}
+	switch c.Output.KnowledgeFormat {
+	case "", "md", "json":
+	default:
+		return fmt.Errorf("output.knowledge_format must be \"md\" or \"json\", got %q in config %s",
+			c.Output.KnowledgeFormat, configSource)
+	}
+	if c.Output.DisplayTimezone != "" {
+		if _, err := time.LoadLocation(c.Output.DisplayTimezone); err != nil {
+			return fmt.Errorf("output.display_timezone %q is not a valid IANA time zone in config %s: %w",
+				c.Output.DisplayTimezone, configSource, err)
+		}
+	}
+	return nil
+}
+
+// Keys returns the API keys in failover order: api_key followed by api_keys, without
+// empty entries and duplicates.
+func (l LLMConfig) Keys() []string {
+	keys := make([]string, 0, len(l.APIKeys)+1)
+	for _, key := range append([]string{l.APIKey}, l.APIKeys...) {
+		if key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// DisplayLocation returns the time zone for displayed timestamps: output.display_timezone,
+// or local time when it is empty or invalid. Stored timestamps and comparisons stay in UTC.
+func (c *Config) DisplayLocation() *time.Location {
+	if c == nil || c.Output.DisplayTimezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(c.Output.DisplayTimezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// validateProvider checks llm.provider and the settings the provider requires.
+func (c *Config) validateProvider(configSource string) error {
+	switch c.LLM.Provider {
+	case "", ProviderOpenAI, ProviderOllama:
+		return nil
+	case ProviderAzure:
+		if c.LLM.Azure.Deployment == "" {
+			return fmt.Errorf("llm.azure.deployment is required when llm.provider is \"azure\" in config %s", configSource)
+		}
+		if c.LLM.Azure.APIVersion == "" {
+			return fmt.Errorf("llm.azure.api_version is required when llm.provider is \"azure\" in config %s", configSource)
+		}
+		return nil
+	default:
+		return fmt.Errorf("llm.provider must be \"openai\", \"azure\" or \"ollama\", got %q in config %s", c.LLM.Provider, configSource)
+	}
+}
+
+// validateTokenBudget checks the token reserves. Zero selects the built-in default.
+func (c *Config) validateTokenBudget(configSource string) error {
+	reserves := []struct {
+		key   string
+		value int
+	}{
+		{"llm.max_tokens", c.LLM.MaxTokens},
+		{"llm.response_reserve_tokens", c.LLM.ResponseReserveTokens},
+		{"llm.system_prompt_reserve_tokens", c.LLM.SystemPromptReserveTokens},
+		{"llm.chunk_summary_max_tokens", c.LLM.ChunkSummaryMaxTokens},
+	}
+	for _, r := range reserves {
+		if r.value < 0 {
+			return fmt.Errorf("%s must be 0 (default) or greater, got %d in config %s", r.key, r.value, configSource)
+		}
+	}
+
+	reserved := c.LLM.ResponseReserveTokens + c.LLM.SystemPromptReserveTokens
+	if c.LLM.MaxTokens > 0 && reserved >= c.LLM.MaxTokens {
+		return fmt.Errorf("llm.response_reserve_tokens + llm.system_prompt_reserve_tokens (%d) must be less than llm.max_tokens (%d) in config %s",
+			reserved, c.LLM.MaxTokens, configSource)
+	}
+	return nil
+}
+
+func (c *Config) validateNotification(configSource string) error {
+	if c.Notification.PendingDir != "" && c.Notification.MaxPending < 1 {
+		return fmt.Errorf("notification.max_pending must be at least 1 when notification.pending_dir is set, got %d in config %s",
+			c.Notification.MaxPending, configSource)
+	}
+
+	if c.Notification.CooldownPerContainer < 0 {
+		return fmt.Errorf("notification.cooldown_per_container must be 0 (disabled) or a positive duration, got %s in config %s",
+			c.Notification.CooldownPerContainer, configSource)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(c.Notification.MinSeverity)) {
+	case "", "healthy", "warning", "critical":
+		return nil
+	default:
+		return fmt.Errorf("notification.min_severity must be one of healthy, warning, critical, got %q in config %s",
+			c.Notification.MinSeverity, configSource)
+	}
+}
+
+func (c *Config) validateLogging(configSource string) error {
+	switch c.Logging.Level {
+	case "", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		return fmt.Errorf("logging.level must be one of %s, %s, %s, %s, got %q in config %s",
+			LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, c.Logging.Level, configSource)
+	}
+	switch c.Logging.Format {
+	case "", LogFormatText, LogFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("logging.format must be %q or %q, got %q in config %s",
+			LogFormatText, LogFormatJSON, c.Logging.Format, configSource)
+	}
+}
+
+// validateNamePatterns checks that every container name pattern under key compiles.
+func validateNamePatterns(key string, patterns []string, configSource string) error {
+	for i, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid regexp in %s[%d] in config %s: %s: %w", key, i, configSource, pattern, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateContainerInstructions() error {
+	for i, ci := range c.ContainerInstructions {
+		if ci.Pattern == "" {
+			return fmt.Errorf("container_instructions[%d].pattern must not be empty", i)
+		}
+		if _, err := regexp.Compile(ci.Pattern); err != nil {
+			return fmt.Errorf("invalid regexp pattern in container_instructions[%d].pattern: %s: %w", i, ci.Pattern, err)
+		}
+	}
 	return nil
 }
 
@@ -305,5 +1040,8 @@ func (c *Config) validateRegexpFilters() error {
 			}
 		}
 	}
+	if _, err := c.RegexpFiltersFilePatterns(); err != nil {
+		return fmt.Errorf("invalid regexp_filters_file: %w", err)
+	}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -23,6 +24,7 @@ func TestLoad_EnvVars(t *testing.T) {
 
 	// Verify values from env vars
 	assert.Equal(t, "test-api-key", cfg.LLM.APIKey)
+	assert.Empty(t, cfg.LLM.APIKeys)
 	assert.Equal(t, "test-model", cfg.LLM.Model)
 }
 
@@ -38,14 +40,57 @@ func TestLoad_Defaults(t *testing.T) {
 	// Check defaults
 	assert.Equal(t, "https://api.openai.com/v1", cfg.LLM.BaseURL)
 	assert.Equal(t, "gpt-4o-mini", cfg.LLM.Model)
-	assert.Equal(t, 128000, cfg.LLM.MaxTokens)
+	assert.Equal(t, 0, cfg.LLM.MaxTokens)
+	assert.Equal(t, 0, cfg.LLM.MaxLogLines)
+	assert.Equal(t, 0, cfg.LLM.MaxLogBytes)
+	assert.False(t, cfg.LLM.StructuredOutput)
+	assert.Equal(t, 120*time.Second, cfg.LLM.RequestTimeout)
+	assert.Equal(t, 0, cfg.LLM.DedupAcrossScans)
+	assert.Equal(t, 1, cfg.LLM.ChunkConcurrency)
+	assert.Equal(t, 0, cfg.LLM.ChunkOverlapLines)
+	assert.Equal(t, 0, cfg.LLM.MinLogLines)
+	assert.Equal(t, 3, cfg.LLM.CircuitBreakerThreshold)
+	assert.Equal(t, LogLevelWarn, cfg.Logging.Level)
+	assert.Equal(t, LogFormatText, cfg.Logging.Format)
+	assert.Equal(t, 5*time.Minute, cfg.LLM.CircuitBreakerCooldown)
+	assert.Equal(t, 4000, cfg.LLM.ResponseReserveTokens)
+	assert.Equal(t, ProviderOpenAI, cfg.LLM.Provider)
+	assert.Equal(t, 500, cfg.LLM.SystemPromptReserveTokens)
+	assert.Equal(t, 2000, cfg.LLM.ChunkSummaryMaxTokens)
+	assert.InDelta(t, 0.3, cfg.LLM.Temperature, 1e-9)
+	assert.InDelta(t, 0.3, cfg.LLM.SummaryTemperature, 1e-9)
+	assert.Zero(t, cfg.LLM.TopP)
+	assert.Zero(t, cfg.LLM.FrequencyPenalty)
+	assert.False(t, cfg.LLM.IncludePreviousAnalysis)
+	assert.Equal(t, DefaultShortIDLength, cfg.Output.ShortIDLength)
+	assert.False(t, cfg.LLM.CacheEnabled)
+	assert.Equal(t, 24*time.Hour, cfg.LLM.CacheTTL)
+	assert.Equal(t, "./cache", cfg.LLM.CacheDir)
+	assert.Equal(t, time.Duration(0), cfg.Notification.CooldownPerContainer)
+	assert.Equal(t, 0, cfg.LLM.SynthesisCriticalLines)
 	assert.Equal(t, "./reports", cfg.Output.ReportsDir)
 	assert.Equal(t, "./knowledge_base", cfg.Output.KnowledgeBaseDir)
 	assert.Equal(t, "./state.json", cfg.Output.StateFile)
 	assert.Equal(t, 30, cfg.Output.KnowledgeRetentionDays)
 	assert.True(t, cfg.Privacy.AnonymizeIPs)
 	assert.True(t, cfg.Privacy.AnonymizeSecrets)
+	assert.True(t, cfg.Privacy.AnonymizeEmails)
+	assert.True(t, cfg.Privacy.AnonymizeCardNumbers)
 	assert.False(t, cfg.Notification.Enabled)
+	assert.Equal(t, "healthy", cfg.Notification.MinSeverity)
+	assert.Equal(t, "md", cfg.Output.ReportFormat)
+	assert.Equal(t, 0, cfg.Output.KnowledgeMaxEntries)
+	assert.Equal(t, 0, cfg.Output.MaxReportBytes)
+	assert.Equal(t, "json", cfg.Output.StateBackend)
+	assert.Equal(t, "md", cfg.Output.KnowledgeFormat)
+	assert.False(t, cfg.Output.KnowledgeSkipUnchanged)
+	assert.Equal(t, SourceDocker, cfg.Source)
+	assert.Empty(t, cfg.Kubernetes.APIServer)
+	assert.Equal(t, DefaultReportPathTemplate, cfg.Output.ReportPathTemplate)
+	assert.False(t, cfg.Docker.IncludeEvents)
+	assert.True(t, cfg.LLM.DedupTimestamps)
+	assert.Empty(t, cfg.RegexpFiltersFile)
+	assert.Equal(t, InvalidUTF8Replace, cfg.Docker.InvalidUTF8)
 }
 
 func TestLoad_ConfigFile(t *testing.T) {
@@ -70,6 +115,7 @@ output:
 privacy:
   anonymize_ips: false
   anonymize_secrets: false
+  anonymize_emails: false
 `
 	err := os.WriteFile(configPath, []byte(configContent), 0600)
 	assert.NoError(t, err)
@@ -91,6 +137,87 @@ privacy:
 	assert.Equal(t, "/test/state.json", cfg.Output.StateFile)
 	assert.False(t, cfg.Privacy.AnonymizeIPs)
 	assert.False(t, cfg.Privacy.AnonymizeSecrets)
+	assert.False(t, cfg.Privacy.AnonymizeEmails)
+	assert.True(t, cfg.Privacy.AnonymizeCardNumbers)
+}
+
+func TestLoadProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `llm:
+  api_key: base-key
+  model: base-model
+notification:
+  enabled: true
+  shoutrrr_url: generic://prod
+profiles:
+  ci:
+    llm:
+      model: ci-model
+    notification:
+      shoutrrr_url: generic://ci
+  prod:
+    notification:
+      min_severity: critical
+`
+	assert.NoError(t, os.WriteFile(configPath, []byte(configContent), 0600))
+
+	base, err := LoadProfile(configPath, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", base.Profile)
+	assert.Equal(t, "base-model", base.LLM.Model)
+
+	ci, err := LoadProfile(configPath, "ci")
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", ci.Profile)
+	assert.Equal(t, "ci-model", ci.LLM.Model)
+	assert.Equal(t, "base-key", ci.LLM.APIKey, "settings the profile does not set keep their top-level value")
+	assert.Equal(t, "generic://ci", ci.Notification.ShoutrrURL)
+	assert.True(t, ci.Notification.Enabled)
+
+	t.Setenv(ProfileEnvVar, "prod")
+	prod, err := Load(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", prod.Profile)
+	assert.Equal(t, "critical", prod.Notification.MinSeverity)
+	assert.Equal(t, "base-model", prod.LLM.Model)
+
+	explicit, err := LoadProfile(configPath, "ci")
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", explicit.Profile, "an explicit profile takes precedence over DLIA_PROFILE")
+
+	t.Setenv("DLIA_LLM_MODEL", "env-model")
+	withEnv, err := LoadProfile(configPath, "ci")
+	assert.NoError(t, err)
+	assert.Equal(t, "env-model", withEnv.LLM.Model, "environment variables override profile values")
+}
+
+func TestLoadProfile_Unknown(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `llm:
+  api_key: base-key
+  model: base-model
+profiles:
+  ci:
+    llm:
+      model: ci-model
+  prod: {}
+`
+	assert.NoError(t, os.WriteFile(configPath, []byte(configContent), 0600))
+
+	_, err := LoadProfile(configPath, "staging")
+	assert.ErrorIs(t, err, ErrUnknownProfile)
+	assert.Contains(t, err.Error(), `"staging"`)
+	assert.Contains(t, err.Error(), "ci, prod")
+
+	noProfiles := filepath.Join(tmpDir, "plain.yaml")
+	assert.NoError(t, os.WriteFile(noProfiles, []byte("llm:\n  api_key: k\n  model: m\n"), 0600))
+	_, err = LoadProfile(noProfiles, "ci")
+	assert.ErrorIs(t, err, ErrUnknownProfile)
+	assert.Contains(t, err.Error(), "defines no profiles")
 }
 
 func TestLoad_InvalidConfigFile(t *testing.T) {
@@ -111,202 +238,1071 @@ func TestLoad_MalformedConfigFile(t *testing.T) {
 	err := os.WriteFile(configPath, []byte(configContent), 0600)
 	assert.NoError(t, err)
 
-	_, err = Load(configPath)
+	_, err = Load(configPath)
+	assert.Error(t, err)
+}
+
+func TestValidate_MissingBaseURL(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:       "test",
+			KnowledgeBaseDir: "test",
+			StateFile:        "test",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.base_url")
+}
+
+func TestValidate_MissingAPIKey(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:       "test",
+			KnowledgeBaseDir: "test",
+			StateFile:        "test",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.api_key")
+}
+
+func TestValidate_MissingModel(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:       "test",
+			KnowledgeBaseDir: "test",
+			StateFile:        "test",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.model")
+}
+
+func TestValidate_MissingDockerSocket(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: ""},
+		Output: OutputConfig{
+			ReportsDir:       "test",
+			KnowledgeBaseDir: "test",
+			StateFile:        "test",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "docker.socket_path")
+}
+
+func TestValidate_MissingReportsDir(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:       "",
+			KnowledgeBaseDir: "test",
+			StateFile:        "test",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.reports_dir")
+}
+
+func TestValidate_MissingKnowledgeBaseDir(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:       "test",
+			KnowledgeBaseDir: "",
+			StateFile:        "test",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.knowledge_base_dir")
+}
+
+func TestValidate_MissingStateFile(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:       "test",
+			KnowledgeBaseDir: "test",
+			StateFile:        "",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.state_file")
+}
+
+func TestValidate_InvalidRetentionDaysTooLow(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 0,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.knowledge_retention_days")
+	assert.Contains(t, err.Error(), "between 1 and 365")
+}
+
+func TestValidate_InvalidRetentionDaysTooHigh(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 366,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.knowledge_retention_days")
+	assert.Contains(t, err.Error(), "between 1 and 365")
+}
+
+func TestValidate_ValidConfig(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.NoError(t, err)
+}
+
+func TestValidate_NegativeKnowledgeMaxEntries(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+			KnowledgeMaxEntries:    -1,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.knowledge_max_entries")
+}
+
+func TestValidate_NegativeMaxReportBytes(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+			MaxReportBytes:         -1,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.max_report_bytes")
+}
+
+func TestValidate_SamplingParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*LLMConfig)
+		wantErr string
+	}{
+		{name: "deterministic", modify: func(l *LLMConfig) { l.Temperature, l.SummaryTemperature = 0, 0 }},
+		{name: "tuned", modify: func(l *LLMConfig) { l.Temperature, l.TopP, l.FrequencyPenalty = 1.2, 0.9, -0.5 }},
+		{name: "temperature too high", modify: func(l *LLMConfig) { l.Temperature = 2.5 }, wantErr: "llm.temperature"},
+		{name: "negative summary temperature", modify: func(l *LLMConfig) { l.SummaryTemperature = -0.1 }, wantErr: "llm.summary_temperature"},
+		{name: "top_p above 1", modify: func(l *LLMConfig) { l.TopP = 1.5 }, wantErr: "llm.top_p"},
+		{name: "frequency_penalty below -2", modify: func(l *LLMConfig) { l.FrequencyPenalty = -3 }, wantErr: "llm.frequency_penalty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				LLM: LLMConfig{
+					BaseURL:            "https://test.com",
+					APIKey:             "test",
+					Model:              "test",
+					RequestTimeout:     120 * time.Second,
+					Temperature:        0.3,
+					SummaryTemperature: 0.3,
+				},
+				Docker: DockerConfig{SocketPath: "test"},
+				Output: OutputConfig{
+					ReportsDir:             "test",
+					KnowledgeBaseDir:       "test",
+					StateFile:              "test",
+					KnowledgeRetentionDays: 30,
+				},
+			}
+			tt.modify(&cfg.LLM)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_NegativeShortIDLength(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+			ShortIDLength:          -1,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.short_id_length")
+}
+
+func TestValidate_CacheTTL(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+			CacheEnabled:   true,
+			CacheDir:       "./cache",
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.cache_ttl")
+
+	cfg.LLM.CacheTTL = time.Hour
+	assert.NoError(t, cfg.Validate())
+
+	cfg.LLM.CacheDir = ""
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.cache_dir")
+}
+
+func TestValidate_KnowledgeFormat(t *testing.T) {
+	for _, format := range []string{"", "md", "json", "yaml"} {
+		cfg := &Config{
+			LLM: LLMConfig{
+				BaseURL:        "https://test.com",
+				APIKey:         "test",
+				Model:          "test",
+				RequestTimeout: 120 * time.Second,
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+				KnowledgeFormat:        format,
+			},
+		}
+
+		err := cfg.Validate()
+		if format == "yaml" {
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "output.knowledge_format")
+		} else {
+			assert.NoError(t, err, "format %q", format)
+		}
+	}
+}
+
+func TestValidate_NonPositiveRequestTimeout(t *testing.T) {
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		cfg := &Config{
+			LLM: LLMConfig{
+				BaseURL:        "https://test.com",
+				APIKey:         "test",
+				Model:          "test",
+				RequestTimeout: timeout,
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+			},
+		}
+
+		err := cfg.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "llm.request_timeout")
+	}
+}
+
+func TestValidate_DedupAcrossScansRange(t *testing.T) {
+	for _, scans := range []int{-1, MaxDedupAcrossScans + 1} {
+		cfg := &Config{
+			LLM: LLMConfig{
+				BaseURL:          "https://test.com",
+				APIKey:           "test",
+				Model:            "test",
+				RequestTimeout:   120 * time.Second,
+				DedupAcrossScans: scans,
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+			},
+		}
+
+		err := cfg.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "llm.dedup_across_scans")
+	}
+}
+
+func TestValidate_NegativeChunkConcurrency(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:          "https://test.com",
+			APIKey:           "test",
+			Model:            "test",
+			RequestTimeout:   120 * time.Second,
+			ChunkConcurrency: -1,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.chunk_concurrency")
+}
+
+func TestValidate_Logging(t *testing.T) {
+	newCfg := func() *Config {
+		return &Config{
+			LLM: LLMConfig{
+				BaseURL:        "https://test.com",
+				APIKey:         "test",
+				Model:          "test",
+				RequestTimeout: 120 * time.Second,
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+			},
+			Logging: LoggingConfig{Level: LogLevelInfo, Format: LogFormatJSON},
+		}
+	}
+
+	assert.NoError(t, newCfg().Validate())
+
+	cfg := newCfg()
+	cfg.Logging.Level = "trace"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "logging.level")
+
+	cfg = newCfg()
+	cfg.Logging.Format = "logfmt"
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "logging.format")
+}
+
+func TestValidate_CircuitBreaker(t *testing.T) {
+	newCfg := func() *Config {
+		return &Config{
+			LLM: LLMConfig{
+				BaseURL:                 "https://test.com",
+				APIKey:                  "test",
+				Model:                   "test",
+				RequestTimeout:          120 * time.Second,
+				CircuitBreakerThreshold: 3,
+				CircuitBreakerCooldown:  5 * time.Minute,
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+			},
+		}
+	}
+
+	assert.NoError(t, newCfg().Validate())
+
+	cfg := newCfg()
+	cfg.LLM.CircuitBreakerThreshold = -1
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.circuit_breaker_threshold")
+
+	cfg = newCfg()
+	cfg.LLM.CircuitBreakerCooldown = 0
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.circuit_breaker_cooldown")
+
+	// The cooldown is irrelevant while the breaker is disabled
+	cfg.LLM.CircuitBreakerThreshold = 0
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_NegativeChunkOverlapLines(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:           "https://test.com",
+			APIKey:            "test",
+			Model:             "test",
+			RequestTimeout:    120 * time.Second,
+			ChunkOverlapLines: -1,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.chunk_overlap_lines")
+}
+
+func TestValidate_NegativeSynthesisCriticalLines(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:                "https://test.com",
+			APIKey:                 "test",
+			Model:                  "test",
+			RequestTimeout:         120 * time.Second,
+			SynthesisCriticalLines: -1,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.synthesis_critical_lines")
+}
+
+func TestValidate_NegativeMinLogLines(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+			MinLogLines:    -1,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.min_log_lines")
+
+	cfg.LLM.MinLogLines = 5
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_NegativeLogCaps(t *testing.T) {
+	newCfg := func() *Config {
+		return &Config{
+			LLM: LLMConfig{
+				BaseURL:        "https://test.com",
+				APIKey:         "test",
+				Model:          "test",
+				RequestTimeout: 120 * time.Second,
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+			},
+		}
+	}
+
+	cfg := newCfg()
+	cfg.LLM.MaxLogLines = -1
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.max_log_lines")
+
+	cfg = newCfg()
+	cfg.LLM.MaxLogBytes = -1
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.max_log_bytes")
+}
+
+func TestValidate_TokenBudget(t *testing.T) {
+	newCfg := func() *Config {
+		return &Config{
+			LLM: LLMConfig{
+				BaseURL:                   "https://test.com",
+				APIKey:                    "test",
+				Model:                     "test",
+				MaxTokens:                 8000,
+				RequestTimeout:            120 * time.Second,
+				ResponseReserveTokens:     4000,
+				SystemPromptReserveTokens: 500,
+				ChunkSummaryMaxTokens:     2000,
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+			},
+		}
+	}
+
+	assert.NoError(t, newCfg().Validate())
+
+	cfg := newCfg()
+	cfg.LLM.ResponseReserveTokens = -1
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.response_reserve_tokens")
+
+	cfg = newCfg()
+	cfg.LLM.ChunkSummaryMaxTokens = -1
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.chunk_summary_max_tokens")
+
+	cfg = newCfg()
+	cfg.LLM.MaxTokens = 4500
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must be less than llm.max_tokens")
+}
+
+func TestValidate_Provider(t *testing.T) {
+	newCfg := func() *Config {
+		return &Config{
+			LLM: LLMConfig{
+				BaseURL:        "https://my-resource.openai.azure.com",
+				APIKey:         "test",
+				Model:          "test",
+				RequestTimeout: 120 * time.Second,
+				Provider:       ProviderAzure,
+				Azure:          AzureConfig{Deployment: "gpt4o", APIVersion: "2024-06-01"},
+			},
+			Docker: DockerConfig{SocketPath: "test"},
+			Output: OutputConfig{
+				ReportsDir:             "test",
+				KnowledgeBaseDir:       "test",
+				StateFile:              "test",
+				KnowledgeRetentionDays: 30,
+			},
+		}
+	}
+
+	assert.NoError(t, newCfg().Validate())
+
+	cfg := newCfg()
+	cfg.LLM.Azure.Deployment = ""
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.azure.deployment")
+
+	cfg = newCfg()
+	cfg.LLM.Azure.APIVersion = ""
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.azure.api_version")
+
+	cfg = newCfg()
+	cfg.LLM.Provider = "anthropic"
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.provider")
+
+	cfg = newCfg()
+	cfg.LLM.Provider = ProviderOpenAI
+	cfg.LLM.Azure = AzureConfig{}
+	assert.NoError(t, cfg.Validate())
+
+	// Ollama needs no API key; other providers still do
+	cfg = newCfg()
+	cfg.LLM.Provider = ProviderOllama
+	cfg.LLM.APIKey = ""
+	assert.NoError(t, cfg.Validate())
+
+	cfg.LLM.Provider = ProviderOpenAI
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.api_key")
+}
+
+func TestValidate_InvalidTimestampPattern(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test", TimestampPattern: "^\\[("},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
 	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "docker.timestamp_pattern")
+
+	cfg.Docker.TimestampPattern = "^\\[([^\\]]+)\\]"
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_MissingBaseURL(t *testing.T) {
+func TestValidate_InvalidMultilinePattern(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
-		Docker: DockerConfig{SocketPath: "test"},
+		Docker: DockerConfig{SocketPath: "test", MultilinePattern: "^(\\s|at "},
 		Output: OutputConfig{
-			ReportsDir:       "test",
-			KnowledgeBaseDir: "test",
-			StateFile:        "test",
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "llm.base_url")
+	assert.Contains(t, err.Error(), "docker.multiline_pattern")
+
+	cfg.Docker.MultilinePattern = "^(\\s|at )"
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_MissingAPIKey(t *testing.T) {
+func TestValidate_DockerStream(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
-		Docker: DockerConfig{SocketPath: "test"},
+		Docker: DockerConfig{SocketPath: "test", Stream: "both"},
 		Output: OutputConfig{
-			ReportsDir:       "test",
-			KnowledgeBaseDir: "test",
-			StateFile:        "test",
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "llm.api_key")
+	assert.Contains(t, err.Error(), "docker.stream")
+
+	for _, stream := range []string{"", StreamAll, StreamStdout, StreamStderr} {
+		cfg.Docker.Stream = stream
+		assert.NoError(t, cfg.Validate(), "stream %q", stream)
+	}
 }
 
-func TestValidate_MissingModel(t *testing.T) {
+func TestValidate_RegexpFiltersFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".dliaignore")
+	assert.NoError(t, os.WriteFile(path, []byte("healthcheck\n(unclosed\n"), 0o600))
+
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
-			ReportsDir:       "test",
-			KnowledgeBaseDir: "test",
-			StateFile:        "test",
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
 		},
+		RegexpFiltersFile: path,
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "llm.model")
+	assert.Contains(t, err.Error(), "regexp_filters_file")
+	assert.Contains(t, err.Error(), "line 2")
+
+	assert.NoError(t, os.WriteFile(path, []byte("healthcheck\n"), 0o600))
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_MissingDockerSocket(t *testing.T) {
+func TestValidate_DockerInvalidUTF8(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
-		Docker: DockerConfig{SocketPath: ""},
+		Docker: DockerConfig{SocketPath: "test", InvalidUTF8: "drop"},
 		Output: OutputConfig{
-			ReportsDir:       "test",
-			KnowledgeBaseDir: "test",
-			StateFile:        "test",
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "docker.socket_path")
+	assert.Contains(t, err.Error(), "docker.invalid_utf8")
+
+	for _, mode := range []string{"", InvalidUTF8Replace, InvalidUTF8Escape, InvalidUTF8Keep} {
+		cfg.Docker.InvalidUTF8 = mode
+		assert.NoError(t, cfg.Validate(), "mode %q", mode)
+	}
 }
 
-func TestValidate_MissingReportsDir(t *testing.T) {
+func TestValidate_SkipCleanMaxLines(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+			SkipCleanLogs:  true,
 		},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
-			ReportsDir:       "",
-			KnowledgeBaseDir: "test",
-			StateFile:        "test",
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "output.reports_dir")
+	assert.Contains(t, err.Error(), "llm.skip_clean_max_lines")
+
+	cfg.LLM.SkipCleanMaxLines = 200
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_MissingKnowledgeBaseDir(t *testing.T) {
+func TestValidate_ContainerInstructions(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
-			ReportsDir:       "test",
-			KnowledgeBaseDir: "",
-			StateFile:        "test",
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+		ContainerInstructions: []ContainerInstruction{
+			{Pattern: "^postgres", Instructions: "Focus on connection pool exhaustion"},
+			{Pattern: "nginx(", Instructions: "Ignore routine access logs"},
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "output.knowledge_base_dir")
+	assert.Contains(t, err.Error(), "container_instructions[1]")
+
+	cfg.ContainerInstructions[1].Pattern = ""
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be empty")
+
+	cfg.ContainerInstructions[1].Pattern = "nginx"
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_MissingStateFile(t *testing.T) {
+func TestValidate_DedupSettings(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+			DedupMode:      "fuzzy",
 		},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
-			ReportsDir:       "test",
-			KnowledgeBaseDir: "test",
-			StateFile:        "",
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "output.state_file")
+	assert.Contains(t, err.Error(), "llm.dedup_mode")
+
+	cfg.LLM.DedupMode = DedupModeNormalized
+	cfg.LLM.DedupMinRepeats = 1
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.dedup_min_repeats")
+
+	cfg.LLM.DedupMinRepeats = 5
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_InvalidRetentionDaysTooLow(t *testing.T) {
+func TestValidate_InvalidStateBackend(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
 			ReportsDir:             "test",
 			KnowledgeBaseDir:       "test",
 			StateFile:              "test",
-			KnowledgeRetentionDays: 0,
+			KnowledgeRetentionDays: 30,
+			StateBackend:           "redis",
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "output.knowledge_retention_days")
-	assert.Contains(t, err.Error(), "between 1 and 365")
+	assert.Contains(t, err.Error(), "output.state_backend")
+
+	cfg.Output.StateBackend = "sqlite"
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_InvalidRetentionDaysTooHigh(t *testing.T) {
+func TestValidate_InvalidReportFormat(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
 			ReportsDir:             "test",
 			KnowledgeBaseDir:       "test",
 			StateFile:              "test",
-			KnowledgeRetentionDays: 366,
+			KnowledgeRetentionDays: 30,
+			ReportFormat:           "pdf",
 		},
 	}
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "output.knowledge_retention_days")
-	assert.Contains(t, err.Error(), "between 1 and 365")
+	assert.Contains(t, err.Error(), "output.report_format")
+
+	cfg.Output.ReportFormat = "html"
+	assert.NoError(t, cfg.Validate())
 }
 
-func TestValidate_ValidConfig(t *testing.T) {
+func TestValidate_ReportPathTemplate(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	for _, invalid := range []string{"{{.Container", "{{.Host}}", "../{{.Container}}", "{{.Container}}/../.."} {
+		cfg.Output.ReportPathTemplate = invalid
+		err := cfg.Validate()
+		assert.Error(t, err, "template %q", invalid)
+		assert.Contains(t, err.Error(), "output.report_path_template")
+	}
+
+	cfg.Output.ReportPathTemplate = "{{.Project}}/{{.Date}}/{{.Container}}"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_InvalidNotificationMinSeverity(t *testing.T) {
 	cfg := &Config{
 		LLM: LLMConfig{
-			BaseURL: "https://test.com",
-			APIKey:  "test",
-			Model:   "test",
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
 		},
 		Docker: DockerConfig{SocketPath: "test"},
+		Notification: NotificationConfig{
+			MinSeverity: "urgent",
+		},
 		Output: OutputConfig{
 			ReportsDir:             "test",
 			KnowledgeBaseDir:       "test",
@@ -316,7 +1312,11 @@ func TestValidate_ValidConfig(t *testing.T) {
 	}
 
 	err := cfg.Validate()
-	assert.NoError(t, err)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "notification.min_severity")
+
+	cfg.Notification.MinSeverity = "critical"
+	assert.NoError(t, cfg.Validate())
 }
 
 func TestLoad_DockerHostEnvVar(t *testing.T) {
@@ -368,6 +1368,7 @@ prompts:
   chunk_summary_prompt: /custom/chunk.md
   synthesis_prompt: /custom/synthesis.md
   executive_summary_prompt: /custom/executive.md
+  notification_prompt: /custom/notification.md
 docker:
   socket_path: unix:///var/run/docker.sock
 output:
@@ -388,6 +1389,7 @@ output:
 	assert.Equal(t, "/custom/chunk.md", cfg.Prompts.ChunkSummaryPrompt)
 	assert.Equal(t, "/custom/synthesis.md", cfg.Prompts.SynthesisPrompt)
 	assert.Equal(t, "/custom/executive.md", cfg.Prompts.ExecutiveSummaryPrompt)
+	assert.Equal(t, "/custom/notification.md", cfg.Prompts.NotificationPrompt)
 }
 
 func TestLoad_EmptyPromptsUseDefaults(t *testing.T) {
@@ -404,6 +1406,7 @@ func TestLoad_EmptyPromptsUseDefaults(t *testing.T) {
 	assert.Equal(t, "", cfg.Prompts.ChunkSummaryPrompt)
 	assert.Equal(t, "", cfg.Prompts.SynthesisPrompt)
 	assert.Equal(t, "", cfg.Prompts.ExecutiveSummaryPrompt)
+	assert.Equal(t, "", cfg.Prompts.NotificationPrompt)
 }
 
 func TestLoad_NotificationConfig(t *testing.T) {
@@ -442,7 +1445,7 @@ func TestErr_ErrorVariable(t *testing.T) {
 
 func TestValidate_InvalidRegexpPattern(t *testing.T) {
 	cfg := &Config{
-		LLM:    LLMConfig{BaseURL: "https://test.com", APIKey: "test", Model: "test"},
+		LLM:    LLMConfig{BaseURL: "https://test.com", APIKey: "test", Model: "test", RequestTimeout: 120 * time.Second},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
 			ReportsDir:             "test",
@@ -465,7 +1468,7 @@ func TestValidate_InvalidRegexpPattern(t *testing.T) {
 
 func TestValidate_DisabledRegexpFilter_NotValidated(t *testing.T) {
 	cfg := &Config{
-		LLM:    LLMConfig{BaseURL: "https://test.com", APIKey: "test", Model: "test"},
+		LLM:    LLMConfig{BaseURL: "https://test.com", APIKey: "test", Model: "test", RequestTimeout: 120 * time.Second},
 		Docker: DockerConfig{SocketPath: "test"},
 		Output: OutputConfig{
 			ReportsDir:             "test",
@@ -485,3 +1488,240 @@ func TestValidate_DisabledRegexpFilter_NotValidated(t *testing.T) {
 	err := cfg.Validate()
 	assert.NoError(t, err)
 }
+
+func TestParse_ContainerInstructions(t *testing.T) {
+	content := []byte(`
+container_instructions:
+  - pattern: "^postgres"
+    instructions: "Focus on connection pool exhaustion"
+  - pattern: "nginx"
+    instructions: "Ignore routine access logs"
+`)
+
+	cfg, err := Parse(content)
+	assert.NoError(t, err)
+	assert.Equal(t, []ContainerInstruction{
+		{Pattern: "^postgres", Instructions: "Focus on connection pool exhaustion"},
+		{Pattern: "nginx", Instructions: "Ignore routine access logs"},
+	}, cfg.ContainerInstructions)
+}
+
+func TestValidate_NotificationMaxPending(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker:       DockerConfig{SocketPath: "test"},
+		Notification: NotificationConfig{PendingDir: "./notifications/pending"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "notification.max_pending")
+
+	cfg.Notification.MaxPending = 100
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_NegativeNotificationCooldown(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker:       DockerConfig{SocketPath: "test"},
+		Notification: NotificationConfig{CooldownPerContainer: -time.Hour},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "notification.cooldown_per_container")
+
+	cfg.Notification.CooldownPerContainer = time.Hour
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_NegativeMaxCatchupWindow(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test", MaxCatchupWindow: -time.Hour},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "docker.max_catchup_window")
+
+	cfg.Docker.MaxCatchupWindow = 6 * time.Hour
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_StructuredMethod(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:          "https://test.com",
+			APIKey:           "test",
+			Model:            "test",
+			RequestTimeout:   120 * time.Second,
+			StructuredMethod: "xml",
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.structured_method")
+
+	cfg.LLM.StructuredMethod = StructuredMethodTools
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_DisplayTimezone(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+			DisplayTimezone:        "Mars/Olympus_Mons",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output.display_timezone")
+	assert.Equal(t, time.Local, cfg.DisplayLocation())
+
+	cfg.Output.DisplayTimezone = "UTC"
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, time.UTC, cfg.DisplayLocation())
+}
+
+func TestValidate_ContainerNameLists(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test", IncludeContainers: []string{"^app-"}, ExcludeContainers: []string{"^ok$", "[sidecar"}},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "docker.exclude_containers[1]")
+
+	cfg.Docker.ExcludeContainers = []string{"-sidecar$"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLLMConfig_Keys(t *testing.T) {
+	assert.Empty(t, LLMConfig{}.Keys())
+	assert.Equal(t, []string{"k1"}, LLMConfig{APIKey: "k1"}.Keys())
+	assert.Equal(t, []string{"k2", "k3"}, LLMConfig{APIKeys: []string{"k2", "k3"}}.Keys())
+	assert.Equal(t, []string{"k1", "k2", "k3"}, LLMConfig{APIKey: "k1", APIKeys: []string{"k2", "k1", "k3"}}.Keys(),
+		"api_key comes first and duplicates are dropped")
+}
+
+func TestValidate_APIKeys(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKeys:        []string{"k1", "k2"},
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	// api_keys alone satisfies the required API key
+	assert.NoError(t, cfg.Validate())
+
+	cfg.LLM.APIKeys = []string{"k1", " "}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.api_keys[1] is empty")
+
+	cfg.LLM.APIKeys = nil
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "llm.api_key is required")
+}
+
+func TestValidate_Source(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			BaseURL:        "https://test.com",
+			APIKey:         "test",
+			Model:          "test",
+			RequestTimeout: 120 * time.Second,
+		},
+		Docker: DockerConfig{SocketPath: "test"},
+		Output: OutputConfig{
+			ReportsDir:             "test",
+			KnowledgeBaseDir:       "test",
+			StateFile:              "test",
+			KnowledgeRetentionDays: 30,
+		},
+		Source: SourceKubernetes,
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Source = "podman"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `source must be "docker" or "kubernetes"`)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// envReference matches $$, ${VAR}, ${VAR:-default} and $VAR. "$$" is a literal "$"
+// (e.g. in passwords); any other "$" not followed by a variable name is kept as is.
+var envReference = regexp.MustCompile(`\$(\$|\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// noExpandTag marks fields holding regexps, whose "$" anchors must not be expanded:
+// `expand:"-"`.
+const noExpandTag = "expand"
+
+// expandEnv replaces environment variable references in every string value of cfg,
+// so secrets like api_key: ${OPENAI_API_KEY} stay out of config files. A reference
+// to an unset variable is an error unless it has a ${VAR:-default} fallback. Fields
+// tagged `expand:"-"` (regexp patterns) are left unchanged.
+func expandEnv(cfg *Config) error {
+	return expandValue(reflect.ValueOf(cfg).Elem(), "")
+}
+
+func expandValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		expanded, err := expandString(v.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetString(expanded)
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			tag := t.Field(i).Tag.Get("mapstructure")
+			if tag == "-" || !t.Field(i).IsExported() || t.Field(i).Tag.Get(noExpandTag) == "-" {
+				continue
+			}
+			if err := expandValue(v.Field(i), joinKey(path, tag)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			if err := expandValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map values are not addressable: expand a copy and store it back
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := expandValue(elem, joinKey(path, fmt.Sprint(iter.Key()))); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
+
+// expandString expands the environment variable references in s.
+func expandString(s string) (string, error) {
+	var missing []string
+	expanded := envReference.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$$" {
+			return "$"
+		}
+		m := envReference.FindStringSubmatch(ref)
+		name, hasDefault, fallback := m[2], m[3] != "", m[4]
+		if name == "" {
+			name = m[5]
+		}
+		if value, ok := os.LookupEnv(name); ok && (value != "" || !hasDefault) {
+			return value
+		}
+		if hasDefault {
+			return fallback
+		}
+		missing = append(missing, name)
+		return ref
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set (use ${%s:-default} for a fallback or $$ for a literal $)", strings.Join(missing, ", "), missing[0])
+	}
+	return expanded, nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
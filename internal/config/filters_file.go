@@ -0,0 +1,61 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultRegexpFiltersFile is read from the working directory when
+// regexp_filters_file is not set. A missing default file is not an error.
+const DefaultRegexpFiltersFile = ".dliaignore"
+
+// RegexpFiltersFilePatterns returns the exclude patterns of regexp_filters_file, or of
+// .dliaignore in the working directory if none is configured. The patterns apply to
+// every container on top of its regexp_filters entry.
+func (c *Config) RegexpFiltersFilePatterns() ([]string, error) {
+	if c.RegexpFiltersFile != "" {
+		return LoadRegexpFiltersFile(c.RegexpFiltersFile)
+	}
+
+	patterns, err := LoadRegexpFiltersFile(DefaultRegexpFiltersFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return patterns, err
+}
+
+// LoadRegexpFiltersFile reads a .gitignore-style filters file: one Go regexp per line,
+// surrounding whitespace trimmed, blank lines and lines starting with # ignored.
+// Invalid patterns are reported with their line number.
+func LoadRegexpFiltersFile(path string) ([]string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path comes from the application config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read regexp filters file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // Read-only file, close error not actionable
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := regexp.Compile(line); err != nil {
+			return nil, fmt.Errorf("invalid regexp pattern in %s line %d: %s: %w", path, lineNum, line, err)
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read regexp filters file %s: %w", path, err)
+	}
+
+	return patterns, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRegexpFiltersFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".dliaignore")
+	content := "# Health checks\nGET /health\n\n   \n  ^DEBUG:  \n# (?i)verbose\n\\[TRACE\\]\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	patterns, err := LoadRegexpFiltersFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /health", "^DEBUG:", `\[TRACE\]`}, patterns)
+}
+
+func TestLoadRegexpFiltersFile_InvalidPattern(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".dliaignore")
+	require.NoError(t, os.WriteFile(path, []byte("# noise\nhealthcheck\n[unclosed\n"), 0o600))
+
+	_, err := LoadRegexpFiltersFile(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+	assert.Contains(t, err.Error(), "[unclosed")
+}
+
+func TestRegexpFiltersFilePatterns(t *testing.T) {
+	t.Run("missing default file is not an error", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+
+		patterns, err := (&Config{}).RegexpFiltersFilePatterns()
+		require.NoError(t, err)
+		assert.Empty(t, patterns)
+	})
+
+	t.Run("default file in working directory", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Chdir(dir)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, DefaultRegexpFiltersFile), []byte("healthcheck\n"), 0o600))
+
+		patterns, err := (&Config{}).RegexpFiltersFilePatterns()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"healthcheck"}, patterns)
+	})
+
+	t.Run("configured file must exist", func(t *testing.T) {
+		cfg := &Config{RegexpFiltersFile: filepath.Join(t.TempDir(), "missing")}
+
+		_, err := cfg.RegexpFiltersFilePatterns()
+		assert.Error(t, err)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/zorak1103/dlia/internal/ui"
 )
 
 // DefaultIgnoreDir is the default directory for ignore instruction files
@@ -35,7 +37,7 @@ func GetIgnoreInstructions(containerName, ignoreDir string) (string, error) {
 	}
 
 	// Display that ignore instructions were found and will be included
-	fmt.Printf("📋 Ignore instructions found for container '%s' (from %s) - will be included in analysis request\n", containerName, path)
+	ui.Printf("📋 Ignore instructions found for container '%s' (from %s) - will be included in analysis request\n", containerName, path)
 
 	return string(content), nil
 }
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultReportPathTemplate keeps one flat report directory per container.
+const DefaultReportPathTemplate = "{{.Container}}"
+
+// ReportPathData holds the variables of output.report_path_template.
+type ReportPathData struct {
+	Container string // Sanitized container name
+	Project   string // Docker compose project; empty for standalone containers
+	Date      string // Scan date, YYYY-MM-DD
+}
+
+// FlatReportLayout reports whether reports are stored in one directory per container
+// directly below reports_dir, the layout cleanup relies on to find obsolete containers.
+func (o OutputConfig) FlatReportLayout() bool {
+	return o.ReportPathTemplate == "" || o.ReportPathTemplate == DefaultReportPathTemplate
+}
+
+// ReportSubdir renders output.report_path_template into the report directory below
+// reports_dir. Empty path segments (e.g. an unset project) are dropped; a path that
+// is empty or would leave reports_dir is rejected.
+func (o OutputConfig) ReportSubdir(data ReportPathData) (string, error) {
+	text := o.ReportPathTemplate
+	if text == "" {
+		text = DefaultReportPathTemplate
+	}
+
+	tmpl, err := template.New("report_path").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid output.report_path_template: %w", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("invalid output.report_path_template: %w", err)
+	}
+
+	rendered := strings.ReplaceAll(sb.String(), `\`, "/")
+	var segments []string
+	for _, segment := range strings.Split(rendered, "/") {
+		segment = strings.TrimSpace(segment)
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			return "", fmt.Errorf("output.report_path_template rendered %q, which leaves the reports directory", rendered)
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 0 {
+		return "", errors.New("output.report_path_template rendered an empty path")
+	}
+
+	return filepath.FromSlash(path.Join(segments...)), nil
+}
+
+// validateReportPathTemplate renders the template with sample values so syntax errors
+// and unknown variables are reported at config load rather than during a scan.
+func (c *Config) validateReportPathTemplate(configSource string) error {
+	_, err := c.Output.ReportSubdir(ReportPathData{Container: "web", Project: "shop", Date: "2025-01-01"})
+	if err != nil {
+		return fmt.Errorf("%w in config %s", err, configSource)
+	}
+	return nil
+}
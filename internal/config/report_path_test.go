@@ -0,0 +1,54 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportSubdir(t *testing.T) {
+	data := ReportPathData{Container: "web", Project: "shop", Date: "2025-01-15"}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "", want: "web"},
+		{template: DefaultReportPathTemplate, want: "web"},
+		{template: "{{.Project}}/{{.Container}}", want: filepath.Join("shop", "web")},
+		{template: "{{.Date}}/{{.Container}}", want: filepath.Join("2025-01-15", "web")},
+		{template: "/{{.Container}}/./", want: "web"},
+	}
+
+	for _, tt := range tests {
+		got, err := OutputConfig{ReportPathTemplate: tt.template}.ReportSubdir(data)
+		require.NoError(t, err, "template %q", tt.template)
+		assert.Equal(t, tt.want, got, "template %q", tt.template)
+	}
+}
+
+func TestReportSubdir_EmptyProject(t *testing.T) {
+	got, err := OutputConfig{ReportPathTemplate: "{{.Project}}/{{.Container}}"}.ReportSubdir(ReportPathData{Container: "web"})
+	require.NoError(t, err)
+	assert.Equal(t, "web", got, "empty segments must be dropped")
+}
+
+func TestReportSubdir_RejectsTraversal(t *testing.T) {
+	out := OutputConfig{ReportPathTemplate: "{{.Project}}/{{.Container}}"}
+
+	for _, project := range []string{"..", `..\..`, " .. "} {
+		_, err := out.ReportSubdir(ReportPathData{Container: "web", Project: project})
+		assert.Error(t, err, "project %q", project)
+	}
+
+	_, err := OutputConfig{ReportPathTemplate: "{{.Project}}"}.ReportSubdir(ReportPathData{Container: "web"})
+	assert.ErrorContains(t, err, "empty path")
+}
+
+func TestFlatReportLayout(t *testing.T) {
+	assert.True(t, OutputConfig{}.FlatReportLayout())
+	assert.True(t, OutputConfig{ReportPathTemplate: DefaultReportPathTemplate}.FlatReportLayout())
+	assert.False(t, OutputConfig{ReportPathTemplate: "{{.Project}}/{{.Container}}"}.FlatReportLayout())
+}
@@ -6,10 +6,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 )
 
@@ -34,6 +38,7 @@ type Client interface {
 	//   opts := FilterOptions{
 	//       IncludeAll:  true,              // Include stopped containers
 	//       NamePattern: "^app-.*-prod$",   // Match production app containers
+	//       Labels:      map[string]string{"dlia.scan": "true"}, // Require all labels
 	//   }
 	//   containers, err := client.ListContainers(ctx, opts)
 	//   if err != nil {
@@ -57,32 +62,90 @@ type Client interface {
 	//   for _, entry := range logs {
 	//       fmt.Printf("[%s] %s: %s\n", entry.Timestamp.Format("15:04:05"), entry.Stream, entry.Message)
 	//   }
+	//
+	// The since bound is inclusive: entries stamped exactly at since are returned again.
+	// Use ReadLogsAfter to resume from a previous scan without re-reading boundary lines.
 	ReadLogsSince(ctx context.Context, containerID string, since time.Time) ([]LogEntry, error)
+	// ReadLogsAfter reads container logs starting at the cursor position, dropping
+	// entries the cursor marks as already processed. A cursor without boundary hashes
+	// behaves exactly like ReadLogsSince(cursor.Timestamp).
+	ReadLogsAfter(ctx context.Context, containerID string, cursor LogCursor) ([]LogEntry, error)
 	// ReadLogsLookback reads logs from a container looking back a specific duration.
 	ReadLogsLookback(ctx context.Context, containerID string, lookback time.Duration) ([]LogEntry, error)
+	// ReadLogsTail reads only the last n log lines of a container using Docker's
+	// tail option, bounding memory for very chatty containers.
+	//
+	// Example reading the last 500 lines of the past hour:
+	//   logs, err := client.ReadLogsTail(ctx, "container-id-abc123", 500)
+	//   if err != nil {
+	//       return fmt.Errorf("failed to read logs: %w", err)
+	//   }
+	//   logs = EntriesSince(logs, time.Now().Add(-time.Hour))
+	ReadLogsTail(ctx context.Context, containerID string, n int) ([]LogEntry, error)
+	// FollowLogs streams log lines written from now on until ctx is cancelled or the
+	// container stops. The channel is closed when streaming ends.
+	//
+	// Example usage:
+	//   entries, err := client.FollowLogs(ctx, "container-id-abc123")
+	//   if err != nil {
+	//       return fmt.Errorf("failed to follow logs: %w", err)
+	//   }
+	//   for entry := range entries {
+	//       fmt.Printf("[%s] %s\n", entry.Timestamp, entry.Message)
+	//   }
+	FollowLogs(ctx context.Context, containerID string) (<-chan LogEntry, error)
+	// ReadEventsSince queries the Docker events API for the crash signals of a
+	// container from since until now: deaths (with exit code), OOM kills, restarts
+	// and failed health checks. Events are returned oldest first.
+	//
+	// Example folding the events into the logs of the same window:
+	//   events, err := client.ReadEventsSince(ctx, "container-id-abc123", since)
+	//   if err != nil {
+	//       return fmt.Errorf("failed to read events: %w", err)
+	//   }
+	//   logs = MergeEvents(logs, events)
+	ReadEventsSince(ctx context.Context, containerID string, since time.Time) ([]Event, error)
 }
 
 // dockerClientWrapper wraps the Docker client to implement our interface
 type dockerClientWrapper struct {
 	cli        *client.Client
 	socketPath string
+	timestamps *TimestampParser
 }
 
 // Compile-time verification that dockerClientWrapper implements Client
 var _ Client = (*dockerClientWrapper)(nil)
 
+// ClientOptions configures optional client behavior.
+type ClientOptions struct {
+	TimestampFormat  string         // Go time layout of a custom timestamp in log lines
+	TimestampPattern string         // Regex locating the timestamp in log lines
+	TimestampZone    *time.Location // Zone of custom timestamps without one (nil = UTC)
+}
+
 // NewClient connects to the Docker daemon at socketPath (or default if empty).
 func NewClient(socketPath string) (Client, error) {
-	opts := []client.Opt{
+	return NewClientWithOptions(socketPath, ClientOptions{})
+}
+
+// NewClientWithOptions connects to the Docker daemon like NewClient, applying opts.
+func NewClientWithOptions(socketPath string, opts ClientOptions) (Client, error) {
+	timestamps, err := NewTimestampParser(opts.TimestampFormat, opts.TimestampPattern, opts.TimestampZone)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOpts := []client.Opt{
 		client.WithAPIVersionNegotiation(),
 	}
 
 	// Add host option if socket path is specified
 	if socketPath != "" {
-		opts = append(opts, client.WithHost(socketPath))
+		clientOpts = append(clientOpts, client.WithHost(socketPath))
 	}
 
-	cli, err := client.NewClientWithOpts(opts...)
+	cli, err := client.NewClientWithOpts(clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client for socket %s: %w", socketPath, err)
 	}
@@ -90,6 +153,7 @@ func NewClient(socketPath string) (Client, error) {
 	wrapper := &dockerClientWrapper{
 		cli:        cli,
 		socketPath: socketPath,
+		timestamps: timestamps,
 	}
 	return &dockerClient{cli: wrapper}, nil
 }
@@ -147,6 +211,10 @@ func (w *dockerClientWrapper) ListContainers(ctx context.Context, opts FilterOpt
 			continue
 		}
 
+		if !opts.MatchesLabels(containers[i].Labels) {
+			continue
+		}
+
 		result = append(result, Container{
 			ID:     containers[i].ID,
 			Name:   name,
@@ -174,7 +242,15 @@ func (w *dockerClientWrapper) ReadLogsSince(ctx context.Context, containerID str
 	// Close reader after parsing; error not actionable in defer context as stream is already consumed
 	defer func() { _ = reader.Close() }()
 
-	return parseLogStream(reader)
+	return parseLogStreamWith(reader, w.timestamps)
+}
+
+func (w *dockerClientWrapper) ReadLogsAfter(ctx context.Context, containerID string, cursor LogCursor) ([]LogEntry, error) {
+	entries, err := w.ReadLogsSince(ctx, containerID, cursor.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return cursor.Filter(entries), nil
 }
 
 func (w *dockerClientWrapper) ReadLogsLookback(ctx context.Context, containerID string, lookback time.Duration) ([]LogEntry, error) {
@@ -182,6 +258,78 @@ func (w *dockerClientWrapper) ReadLogsLookback(ctx context.Context, containerID
 	return w.ReadLogsSince(ctx, containerID, since)
 }
 
+func (w *dockerClientWrapper) ReadLogsTail(ctx context.Context, containerID string, n int) ([]LogEntry, error) {
+	logOpts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Tail:       strconv.Itoa(n),
+	}
+
+	reader, err := w.cli.ContainerLogs(ctx, containerID, logOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs for container %s: %w", containerID, err)
+	}
+	// Close reader after parsing; error not actionable in defer context as stream is already consumed
+	defer func() { _ = reader.Close() }()
+
+	return parseLogStreamWith(reader, w.timestamps)
+}
+
+func (w *dockerClientWrapper) FollowLogs(ctx context.Context, containerID string) (<-chan LogEntry, error) {
+	logOpts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Follow:     true,
+		Since:      time.Now().Format(time.RFC3339Nano),
+	}
+
+	reader, err := w.cli.ContainerLogs(ctx, containerID, logOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow logs for container %s: %w", containerID, err)
+	}
+
+	return followLogStream(ctx, reader, w.timestamps), nil
+}
+
+func (w *dockerClientWrapper) ReadEventsSince(ctx context.Context, containerID string, since time.Time) ([]Event, error) {
+	args := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("container", containerID),
+	)
+	for _, action := range eventActions {
+		args.Add("event", action)
+	}
+
+	// With an until bound the daemon ends the stream after the past events
+	messages, errs := w.cli.Events(ctx, events.ListOptions{
+		Since:   eventTimestamp(since),
+		Until:   eventTimestamp(time.Now()),
+		Filters: args,
+	})
+
+	var result []Event
+	for {
+		select {
+		case msg := <-messages:
+			if event, ok := normalizeEvent(msg); ok {
+				result = append(result, event)
+			}
+		case err := <-errs:
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("failed to read events for container %s: %w", containerID, err)
+			}
+			return result, nil
+		}
+	}
+}
+
+// eventTimestamp formats t as the fractional Unix timestamp the events API expects.
+func eventTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
 // dockerClient wraps the Docker client with application-specific logic
 type dockerClient struct {
 	cli Client
@@ -203,6 +351,22 @@ func (c *dockerClient) ReadLogsSince(ctx context.Context, containerID string, si
 	return c.cli.ReadLogsSince(ctx, containerID, since)
 }
 
+func (c *dockerClient) ReadLogsAfter(ctx context.Context, containerID string, cursor LogCursor) ([]LogEntry, error) {
+	return c.cli.ReadLogsAfter(ctx, containerID, cursor)
+}
+
 func (c *dockerClient) ReadLogsLookback(ctx context.Context, containerID string, lookback time.Duration) ([]LogEntry, error) {
 	return c.cli.ReadLogsLookback(ctx, containerID, lookback)
 }
+
+func (c *dockerClient) ReadLogsTail(ctx context.Context, containerID string, n int) ([]LogEntry, error) {
+	return c.cli.ReadLogsTail(ctx, containerID, n)
+}
+
+func (c *dockerClient) FollowLogs(ctx context.Context, containerID string) (<-chan LogEntry, error) {
+	return c.cli.FollowLogs(ctx, containerID)
+}
+
+func (c *dockerClient) ReadEventsSince(ctx context.Context, containerID string, since time.Time) ([]Event, error) {
+	return c.cli.ReadEventsSince(ctx, containerID, since)
+}
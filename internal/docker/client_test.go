@@ -16,6 +16,7 @@ const (
 type mockDockerClient struct {
 	containers []Container
 	logs       []LogEntry
+	events     []Event
 	shouldFail bool
 	failOn     string
 }
@@ -45,6 +46,13 @@ func (m *mockDockerClient) ReadLogsSince(_ context.Context, _ string, _ time.Tim
 	return m.logs, nil
 }
 
+func (m *mockDockerClient) ReadLogsAfter(_ context.Context, _ string, cursor LogCursor) ([]LogEntry, error) {
+	if m.shouldFail && m.failOn == failOnLogs {
+		return nil, ErrConnectionFailed
+	}
+	return cursor.Filter(m.logs), nil
+}
+
 func (m *mockDockerClient) ReadLogsLookback(_ context.Context, _ string, _ time.Duration) ([]LogEntry, error) {
 	if m.shouldFail && m.failOn == failOnLogs {
 		return nil, ErrConnectionFailed
@@ -52,6 +60,35 @@ func (m *mockDockerClient) ReadLogsLookback(_ context.Context, _ string, _ time.
 	return m.logs, nil
 }
 
+func (m *mockDockerClient) ReadLogsTail(_ context.Context, _ string, n int) ([]LogEntry, error) {
+	if m.shouldFail && m.failOn == failOnLogs {
+		return nil, ErrConnectionFailed
+	}
+	if n < len(m.logs) {
+		return m.logs[len(m.logs)-n:], nil
+	}
+	return m.logs, nil
+}
+
+func (m *mockDockerClient) FollowLogs(_ context.Context, _ string) (<-chan LogEntry, error) {
+	if m.shouldFail && m.failOn == failOnLogs {
+		return nil, ErrConnectionFailed
+	}
+	entries := make(chan LogEntry, len(m.logs))
+	for _, entry := range m.logs {
+		entries <- entry
+	}
+	close(entries)
+	return entries, nil
+}
+
+func (m *mockDockerClient) ReadEventsSince(_ context.Context, _ string, _ time.Time) ([]Event, error) {
+	if m.shouldFail && m.failOn == failOnLogs {
+		return nil, ErrConnectionFailed
+	}
+	return m.events, nil
+}
+
 func TestClient_ListContainers(t *testing.T) {
 	containers := []Container{
 		{
@@ -319,6 +356,24 @@ func TestClient_ReadLogsLookback(t *testing.T) {
 	}
 }
 
+func TestClient_ReadEventsSince(t *testing.T) {
+	events := []Event{{Time: time.Now(), Action: "die", ExitCode: "1"}}
+	client := NewClientWithInterface(&mockDockerClient{events: events})
+
+	result, err := client.ReadEventsSince(context.Background(), "container1", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0].ExitCode != "1" {
+		t.Errorf("Unexpected events: %+v", result)
+	}
+
+	failing := NewClientWithInterface(&mockDockerClient{shouldFail: true, failOn: failOnLogs})
+	if _, err := failing.ReadEventsSince(context.Background(), "container1", time.Now()); err == nil {
+		t.Error("Expected error from failing client")
+	}
+}
+
 func TestParseLogLine_WithTimestamp(t *testing.T) {
 	line := "2025-01-01T10:00:00.123456789Z This is a test message"
 	entry := parseLogLine(line)
@@ -551,6 +606,33 @@ func TestFilterOptions_NamePattern(t *testing.T) {
 	}
 }
 
+func TestFilterOptions_MatchesLabels(t *testing.T) {
+	labels := map[string]string{"dlia.scan": "true", "team": "web"}
+
+	tests := []struct {
+		name   string
+		filter map[string]string
+		want   bool
+	}{
+		{name: "no label filter", filter: nil, want: true},
+		{name: "single match", filter: map[string]string{"dlia.scan": "true"}, want: true},
+		{name: "all labels match", filter: map[string]string{"dlia.scan": "true", "team": "web"}, want: true},
+		{name: "value mismatch", filter: map[string]string{"dlia.scan": "false"}, want: false},
+		{name: "missing key", filter: map[string]string{"env": "prod"}, want: false},
+		{name: "one of several mismatches", filter: map[string]string{"dlia.scan": "true", "team": "db"}, want: false},
+		{name: "empty value requires empty label", filter: map[string]string{"team": ""}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := FilterOptions{Labels: tt.filter}
+			if got := opts.MatchesLabels(labels); got != tt.want {
+				t.Errorf("MatchesLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestContainer_Fields(t *testing.T) {
 	container := Container{
 		ID:     "test-id",
@@ -0,0 +1,111 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cursorSeparator splits the timestamp from the boundary hashes in an encoded cursor.
+const cursorSeparator = "|"
+
+// LogCursor marks the position of the last processed log entry for a container.
+//
+// Docker's "since" filter is inclusive and many runtimes emit several lines with
+// the same timestamp, so a timestamp alone cannot tell which lines at the boundary
+// were already analyzed. The cursor therefore also records a hash for every entry
+// that shares the boundary timestamp; entries matching those hashes are dropped on
+// the next read.
+type LogCursor struct {
+	Timestamp time.Time
+	Hashes    []string // Hashes of processed entries whose timestamp equals Timestamp
+}
+
+// NewLogCursor builds a cursor pointing just past the last entry in entries.
+// Returns a zero cursor if entries is empty or the last entry has no timestamp.
+func NewLogCursor(entries []LogEntry) (LogCursor, error) {
+	latest, err := GetLatestLogTime(entries)
+	if err != nil {
+		return LogCursor{}, err
+	}
+	if latest.IsZero() {
+		return LogCursor{}, nil
+	}
+
+	cursor := LogCursor{Timestamp: latest}
+	for i := len(entries) - 1; i >= 0; i-- {
+		t, parseErr := parseTimestamp(entries[i].Timestamp)
+		if parseErr != nil || !t.Equal(latest) {
+			break
+		}
+		cursor.Hashes = append(cursor.Hashes, hashLogEntry(entries[i]))
+	}
+
+	return cursor, nil
+}
+
+// ParseLogCursor decodes a cursor previously produced by LogCursor.String.
+// An empty string yields a zero cursor without error.
+func ParseLogCursor(s string) (LogCursor, error) {
+	if s == "" {
+		return LogCursor{}, nil
+	}
+
+	timestampPart, hashPart, _ := strings.Cut(s, cursorSeparator)
+	t, err := time.Parse(time.RFC3339Nano, timestampPart)
+	if err != nil {
+		return LogCursor{}, fmt.Errorf("invalid log cursor %q: %w", s, err)
+	}
+
+	cursor := LogCursor{Timestamp: t}
+	if hashPart != "" {
+		cursor.Hashes = strings.Split(hashPart, ",")
+	}
+	return cursor, nil
+}
+
+// String encodes the cursor as "<RFC3339Nano timestamp>|<hash>,<hash>,...".
+// A zero cursor encodes as an empty string.
+func (c LogCursor) String() string {
+	if c.Timestamp.IsZero() {
+		return ""
+	}
+	return c.Timestamp.UTC().Format(time.RFC3339Nano) + cursorSeparator + strings.Join(c.Hashes, ",")
+}
+
+// Filter drops boundary entries that were already processed according to the cursor:
+// entries stamped exactly at the cursor timestamp whose hash was recorded. Each
+// recorded hash suppresses at most one entry so identical lines emitted in the same
+// instant are not over-filtered. All other entries are kept unchanged.
+func (c LogCursor) Filter(entries []LogEntry) []LogEntry {
+	if len(c.Hashes) == 0 || len(entries) == 0 {
+		return entries
+	}
+
+	remaining := make(map[string]int, len(c.Hashes))
+	for _, h := range c.Hashes {
+		remaining[h]++
+	}
+
+	result := make([]LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if t, err := parseTimestamp(entry.Timestamp); err == nil && t.Equal(c.Timestamp) {
+			h := hashLogEntry(entry)
+			if remaining[h] > 0 {
+				remaining[h]--
+				continue
+			}
+		}
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// hashLogEntry returns a short stable fingerprint of a log entry's content.
+func hashLogEntry(entry LogEntry) string {
+	sum := sha256.Sum256([]byte(entry.Stream + "\x00" + entry.Message))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLogCursor(t *testing.T) {
+	entries := []LogEntry{
+		{Timestamp: "2025-01-01T10:00:00Z", Stream: testStdoutStream, Message: "first"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "second"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "third"},
+	}
+
+	cursor, err := NewLogCursor(entries)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected, _ := time.Parse(time.RFC3339, "2025-01-01T10:00:01Z")
+	if !cursor.Timestamp.Equal(expected) {
+		t.Errorf("Expected cursor timestamp %v, got %v", expected, cursor.Timestamp)
+	}
+	if len(cursor.Hashes) != 2 {
+		t.Errorf("Expected 2 boundary hashes (entries sharing the last timestamp), got %d", len(cursor.Hashes))
+	}
+}
+
+func TestNewLogCursor_EmptyAndInvalid(t *testing.T) {
+	cursor, err := NewLogCursor(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error for empty entries: %v", err)
+	}
+	if cursor.String() != "" {
+		t.Errorf("Expected empty cursor string, got %q", cursor.String())
+	}
+
+	_, err = NewLogCursor([]LogEntry{{Timestamp: "not-a-time", Message: "x"}})
+	if err == nil {
+		t.Error("Expected error for invalid timestamp")
+	}
+}
+
+func TestParseLogCursor_RoundTrip(t *testing.T) {
+	entries := []LogEntry{
+		{Timestamp: "2025-01-01T10:00:00.123456789Z", Stream: testStdoutStream, Message: "boundary"},
+	}
+
+	original, err := NewLogCursor(entries)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parsed, err := ParseLogCursor(original.String())
+	if err != nil {
+		t.Fatalf("Unexpected error parsing cursor: %v", err)
+	}
+
+	if !parsed.Timestamp.Equal(original.Timestamp) {
+		t.Errorf("Expected timestamp %v, got %v", original.Timestamp, parsed.Timestamp)
+	}
+	if len(parsed.Hashes) != 1 || parsed.Hashes[0] != original.Hashes[0] {
+		t.Errorf("Expected hashes %v, got %v", original.Hashes, parsed.Hashes)
+	}
+}
+
+func TestParseLogCursor_Invalid(t *testing.T) {
+	if _, err := ParseLogCursor("garbage|abc"); err == nil {
+		t.Error("Expected error for invalid cursor")
+	}
+
+	cursor, err := ParseLogCursor("")
+	if err != nil {
+		t.Errorf("Expected no error for empty cursor, got %v", err)
+	}
+	if !cursor.Timestamp.IsZero() || len(cursor.Hashes) != 0 {
+		t.Errorf("Expected zero cursor, got %+v", cursor)
+	}
+}
+
+func TestLogCursor_Filter_SkipsBoundaryLines(t *testing.T) {
+	firstScan := []LogEntry{
+		{Timestamp: "2025-01-01T10:00:00Z", Stream: testStdoutStream, Message: "a"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "b"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "c"},
+	}
+
+	cursor, err := NewLogCursor(firstScan)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Docker's since filter is inclusive, so the next read repeats the boundary second
+	secondRead := []LogEntry{
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "b"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "c"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "d"},
+		{Timestamp: "2025-01-01T10:00:02Z", Stream: testStdoutStream, Message: "e"},
+	}
+
+	result := cursor.Filter(secondRead)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 new entries, got %d: %+v", len(result), result)
+	}
+	if result[0].Message != "d" || result[1].Message != "e" {
+		t.Errorf("Expected new entries [d e], got [%s %s]", result[0].Message, result[1].Message)
+	}
+}
+
+func TestLogCursor_Filter_IdenticalLinesSameInstant(t *testing.T) {
+	cursor, err := NewLogCursor([]LogEntry{
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "tick"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only one "tick" was analyzed; the second one emitted in the same instant is new
+	result := cursor.Filter([]LogEntry{
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "tick"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "tick"},
+	})
+
+	if len(result) != 1 {
+		t.Errorf("Expected 1 entry to survive, got %d", len(result))
+	}
+}
+
+func TestLogCursor_Filter_NoHashes(t *testing.T) {
+	entries := []LogEntry{
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: testStdoutStream, Message: "a"},
+	}
+
+	cursor := LogCursor{Timestamp: time.Date(2025, 1, 1, 10, 0, 1, 0, time.UTC)}
+	result := cursor.Filter(entries)
+
+	if len(result) != 1 {
+		t.Errorf("Expected timestamp-only cursor to keep all entries, got %d", len(result))
+	}
+}
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+// StreamEvent is the LogEntry stream of Docker events folded into container logs.
+const StreamEvent = "event"
+
+// eventActions are the container events requested from the events API: the crash
+// signals that never show up in the container's own logs.
+var eventActions = []string{
+	string(events.ActionDie),
+	string(events.ActionOOM),
+	string(events.ActionRestart),
+	string(events.ActionHealthStatus),
+}
+
+// Event is a normalized container lifecycle event from the Docker events API.
+type Event struct {
+	Time     time.Time
+	Action   string // die, oom, restart or health_status
+	ExitCode string // Exit code of die events
+}
+
+// Message describes the event as a log line for the LLM.
+func (e Event) Message() string {
+	switch e.Action {
+	case string(events.ActionDie):
+		if e.ExitCode != "" {
+			return "[docker event] container died (exit code " + e.ExitCode + ")"
+		}
+		return "[docker event] container died"
+	case string(events.ActionOOM):
+		return "[docker event] container ran out of memory (OOM kill)"
+	case string(events.ActionRestart):
+		return "[docker event] container restarted"
+	case string(events.ActionHealthStatus):
+		return "[docker event] health check failed (unhealthy)"
+	default:
+		return "[docker event] " + e.Action
+	}
+}
+
+// LogEntry converts the event to a log entry on the event stream.
+func (e Event) LogEntry() LogEntry {
+	return LogEntry{
+		Timestamp: e.Time.UTC().Format(time.RFC3339Nano),
+		Stream:    StreamEvent,
+		Message:   e.Message(),
+	}
+}
+
+// normalizeEvent converts an events API message. Health status events are only kept
+// when the container became unhealthy; other actions are reported as they are.
+func normalizeEvent(msg events.Message) (Event, bool) {
+	action := string(msg.Action)
+	switch msg.Action {
+	case events.ActionHealthStatusUnhealthy:
+		action = string(events.ActionHealthStatus)
+	case events.ActionHealthStatus, events.ActionHealthStatusHealthy, events.ActionHealthStatusRunning:
+		return Event{}, false
+	}
+
+	eventTime := time.Unix(msg.Time, 0)
+	if msg.TimeNano != 0 {
+		eventTime = time.Unix(0, msg.TimeNano)
+	}
+
+	return Event{
+		Time:     eventTime.UTC(),
+		Action:   action,
+		ExitCode: msg.Actor.Attributes["exitCode"],
+	}, true
+}
+
+// MergeEvents inserts the events (oldest first) into logs in chronological order.
+// Events are placed before the first log line stamped after them; lines without a
+// parsable timestamp keep their position.
+func MergeEvents(logs []LogEntry, evts []Event) []LogEntry {
+	if len(evts) == 0 {
+		return logs
+	}
+
+	merged := make([]LogEntry, 0, len(logs)+len(evts))
+	next := 0
+	for _, entry := range logs {
+		if t, err := parseTimestamp(entry.Timestamp); err == nil {
+			for next < len(evts) && evts[next].Time.Before(t) {
+				merged = append(merged, evts[next].LogEntry())
+				next++
+			}
+		}
+		merged = append(merged, entry)
+	}
+	for ; next < len(evts); next++ {
+		merged = append(merged, evts[next].LogEntry())
+	}
+	return merged
+}
@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestNormalizeEvent(t *testing.T) {
+	at := time.Date(2025, 1, 1, 10, 0, 0, 500, time.UTC)
+
+	die, ok := normalizeEvent(events.Message{
+		Action:   events.ActionDie,
+		Actor:    events.Actor{Attributes: map[string]string{"exitCode": "137"}},
+		Time:     at.Unix(),
+		TimeNano: at.UnixNano(),
+	})
+	if !ok || die.Action != "die" || die.ExitCode != "137" || !die.Time.Equal(at) {
+		t.Errorf("normalizeEvent(die) = %+v, %v", die, ok)
+	}
+
+	unhealthy, ok := normalizeEvent(events.Message{Action: events.ActionHealthStatusUnhealthy, Time: at.Unix()})
+	if !ok || unhealthy.Action != "health_status" || !unhealthy.Time.Equal(at.Truncate(time.Second)) {
+		t.Errorf("normalizeEvent(unhealthy) = %+v, %v", unhealthy, ok)
+	}
+
+	for _, action := range []events.Action{events.ActionHealthStatusHealthy, events.ActionHealthStatusRunning, events.ActionHealthStatus} {
+		if _, ok := normalizeEvent(events.Message{Action: action}); ok {
+			t.Errorf("normalizeEvent(%q) should be dropped", action)
+		}
+	}
+}
+
+func TestEvent_Message(t *testing.T) {
+	tests := map[string]Event{
+		"[docker event] container died (exit code 1)":           {Action: "die", ExitCode: "1"},
+		"[docker event] container died":                         {Action: "die"},
+		"[docker event] container ran out of memory (OOM kill)": {Action: "oom"},
+		"[docker event] container restarted":                    {Action: "restart"},
+		"[docker event] health check failed (unhealthy)":        {Action: "health_status"},
+		"[docker event] pause":                                  {Action: "pause"},
+	}
+
+	for want, event := range tests {
+		if got := event.Message(); got != want {
+			t.Errorf("Message() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestMergeEvents(t *testing.T) {
+	logs := []LogEntry{
+		{Timestamp: "2025-01-01T10:00:00Z", Message: "a"},
+		{Message: "no timestamp"},
+		{Timestamp: "2025-01-01T10:02:00Z", Message: "b"},
+	}
+	evts := []Event{
+		{Time: time.Date(2025, 1, 1, 9, 59, 0, 0, time.UTC), Action: "restart"},
+		{Time: time.Date(2025, 1, 1, 10, 1, 0, 0, time.UTC), Action: "oom"},
+		{Time: time.Date(2025, 1, 1, 10, 3, 0, 0, time.UTC), Action: "die"},
+	}
+
+	merged := MergeEvents(logs, evts)
+
+	var got []string
+	for _, entry := range merged {
+		got = append(got, entry.Message)
+	}
+	want := []string{
+		"[docker event] container restarted",
+		"a",
+		"no timestamp",
+		"[docker event] container ran out of memory (OOM kill)",
+		"b",
+		"[docker event] container died",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("MergeEvents() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MergeEvents()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if merged[3].Stream != StreamEvent || merged[3].Timestamp != "2025-01-01T10:01:00Z" {
+		t.Errorf("Unexpected event entry: %+v", merged[3])
+	}
+
+	if got := MergeEvents(logs, nil); len(got) != len(logs) {
+		t.Errorf("MergeEvents() without events should return the logs, got %d entries", len(got))
+	}
+}
+
+func TestEventTimestamp(t *testing.T) {
+	at := time.Unix(1735725600, 5)
+	if got := eventTimestamp(at); got != "1735725600.000000005" {
+		t.Errorf("eventTimestamp() = %q", got)
+	}
+}
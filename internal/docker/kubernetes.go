@@ -0,0 +1,341 @@
+package docker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serviceAccountDir holds the token and CA certificate Kubernetes mounts into pods.
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// KubernetesOptions configures NewKubernetesClient. Without APIServer the in-cluster
+// configuration is used: the API server from KUBERNETES_SERVICE_HOST/PORT and the
+// pod's service account token and CA certificate.
+type KubernetesOptions struct {
+	ClientOptions
+
+	APIServer     string // API server URL, e.g. http://127.0.0.1:8001 for "kubectl proxy"
+	TokenFile     string // File holding the bearer token (empty = no token, or the service account token in-cluster)
+	CAFile        string // CA certificate of the API server (empty = system roots, or the service account CA in-cluster)
+	Namespace     string // Only list pods of this namespace (empty = all namespaces)
+	LabelSelector string // Kubernetes label selector for the pod list, e.g. "app=web,tier!=batch"
+}
+
+// kubernetesClient reads pod logs through the Kubernetes API. Every container of a
+// pod is a separate Container named "namespace/pod/container"; its ID is prefixed
+// with the pod UID so a recreated pod of the same name starts a fresh scan state.
+type kubernetesClient struct {
+	apiServer     string
+	tokenFile     string
+	namespace     string
+	labelSelector string
+	httpClient    *http.Client
+	timestamps    *TimestampParser
+}
+
+// Compile-time verification that kubernetesClient implements Client
+var _ Client = (*kubernetesClient)(nil)
+
+// NewKubernetesClient creates a Client that lists pod containers and reads their logs
+// through the Kubernetes API.
+func NewKubernetesClient(opts KubernetesOptions) (Client, error) {
+	timestamps, err := NewTimestampParser(opts.TimestampFormat, opts.TimestampPattern, opts.TimestampZone)
+	if err != nil {
+		return nil, err
+	}
+
+	apiServer, tokenFile, caFile := opts.APIServer, opts.TokenFile, opts.CAFile
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, errors.New("kubernetes.api_server is not set and DLIA is not running inside a cluster")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+		if tokenFile == "" {
+			tokenFile = filepath.Join(serviceAccountDir, "token")
+		}
+		if caFile == "" {
+			caFile = filepath.Join(serviceAccountDir, "ca.crt")
+		}
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("unexpected default HTTP transport")
+	}
+	transport = transport.Clone()
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile) //nolint:gosec // CA path comes from the user's config or the service account mount
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Kubernetes CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in Kubernetes CA file %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &kubernetesClient{
+		apiServer:     strings.TrimSuffix(apiServer, "/"),
+		tokenFile:     tokenFile,
+		namespace:     opts.Namespace,
+		labelSelector: opts.LabelSelector,
+		// No client timeout: FollowLogs streams indefinitely; callers bound requests via ctx
+		httpClient: &http.Client{Transport: transport},
+		timestamps: timestamps,
+	}, nil
+}
+
+// get sends a GET request to the API server and returns the response of a 200 reply.
+// The token file is read per request because service account tokens are rotated.
+func (k *kubernetesClient) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	endpoint := k.apiServer + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", path, err)
+	}
+	if k.tokenFile != "" {
+		token, err := os.ReadFile(k.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Kubernetes token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", k.apiServer, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)) //nolint:errcheck // Body is only used for the error message
+		_ = resp.Body.Close()
+		err := fmt.Errorf("the Kubernetes API returned status %d for %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (k *kubernetesClient) Ping(ctx context.Context) error {
+	resp, err := k.get(ctx, "/version", nil)
+	if err != nil {
+		return fmt.Errorf("failed to reach Kubernetes API at %s: %w", k.apiServer, err)
+	}
+	return resp.Body.Close()
+}
+
+func (k *kubernetesClient) Close() error {
+	k.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// podList is the subset of a Kubernetes PodList that DLIA reads.
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string            `json:"name"`
+			Namespace string            `json:"namespace"`
+			UID       string            `json:"uid"`
+			Labels    map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Containers []struct {
+				Name  string `json:"name"`
+				Image string `json:"image"`
+			} `json:"containers"`
+		} `json:"spec"`
+		Status struct {
+			ContainerStatuses []struct {
+				Name  string `json:"name"`
+				State struct {
+					Running    *struct{} `json:"running"`
+					Waiting    *struct{} `json:"waiting"`
+					Terminated *struct{} `json:"terminated"`
+				} `json:"state"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+func (k *kubernetesClient) ListContainers(ctx context.Context, opts FilterOptions) ([]Container, error) {
+	var nameFilter *regexp.Regexp
+	if opts.NamePattern != "" {
+		var err error
+		nameFilter, err = regexp.Compile(opts.NamePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name pattern '%s': %w", opts.NamePattern, err)
+		}
+	}
+
+	path := "/api/v1/pods"
+	if k.namespace != "" {
+		path = "/api/v1/namespaces/" + url.PathEscape(k.namespace) + "/pods"
+	}
+	query := url.Values{}
+	if k.labelSelector != "" {
+		query.Set("labelSelector", k.labelSelector)
+	}
+
+	resp, err := k.get(ctx, path, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var pods podList
+	if err := json.NewDecoder(resp.Body).Decode(&pods); err != nil {
+		return nil, fmt.Errorf("failed to decode pod list: %w", err)
+	}
+
+	var result []Container
+	for _, pod := range pods.Items {
+		states := make(map[string]string, len(pod.Status.ContainerStatuses))
+		for _, status := range pod.Status.ContainerStatuses {
+			switch {
+			case status.State.Running != nil:
+				states[status.Name] = "running"
+			case status.State.Terminated != nil:
+				states[status.Name] = "exited"
+			case status.State.Waiting != nil:
+				states[status.Name] = "restarting"
+			}
+		}
+
+		for _, c := range pod.Spec.Containers {
+			name := pod.Metadata.Namespace + "/" + pod.Metadata.Name + "/" + c.Name
+			state, ok := states[c.Name]
+			if !ok {
+				state = "created"
+			}
+
+			if !opts.IncludeAll && state != "running" {
+				continue
+			}
+			if nameFilter != nil && !nameFilter.MatchString(name) {
+				continue
+			}
+			if !opts.MatchesLabels(pod.Metadata.Labels) {
+				continue
+			}
+
+			result = append(result, Container{
+				ID:     pod.Metadata.UID + "/" + name,
+				Name:   name,
+				State:  state,
+				Image:  c.Image,
+				Labels: pod.Metadata.Labels,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// readLogs reads the logs of a pod container with the given query parameters. The
+// Kubernetes API does not separate the streams, so every entry is stdout.
+func (k *kubernetesClient) readLogs(ctx context.Context, containerID string, query url.Values) (io.ReadCloser, error) {
+	path, err := kubernetesLogPath(containerID, query)
+	if err != nil {
+		return nil, err
+	}
+	query.Set("timestamps", "true")
+
+	resp, err := k.get(ctx, path, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs for container %s: %w", containerID, err)
+	}
+	return resp.Body, nil
+}
+
+// kubernetesLogPath returns the log endpoint of the container with the given ID and
+// sets its container query parameter.
+func kubernetesLogPath(containerID string, query url.Values) (string, error) {
+	parts := strings.Split(containerID, "/")
+	if len(parts) != 4 {
+		return "", fmt.Errorf("%w: %s is not a Kubernetes container ID", ErrNotFound, containerID)
+	}
+	namespace, pod, container := parts[1], parts[2], parts[3]
+
+	query.Set("container", container)
+	return "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods/" + url.PathEscape(pod) + "/log", nil
+}
+
+func (k *kubernetesClient) ReadLogsSince(ctx context.Context, containerID string, since time.Time) ([]LogEntry, error) {
+	query := url.Values{}
+	if !since.IsZero() {
+		// sinceTime has second precision; the exact bound is applied below
+		query.Set("sinceTime", since.UTC().Truncate(time.Second).Format(time.RFC3339))
+	}
+
+	body, err := k.readLogs(ctx, containerID, query)
+	if err != nil {
+		return nil, err
+	}
+	// Close body after parsing; error not actionable in defer context as stream is already consumed
+	defer func() { _ = body.Close() }()
+
+	entries, err := parseLogStreamWith(body, k.timestamps)
+	if err != nil {
+		return nil, err
+	}
+	return EntriesSince(entries, since), nil
+}
+
+func (k *kubernetesClient) ReadLogsAfter(ctx context.Context, containerID string, cursor LogCursor) ([]LogEntry, error) {
+	entries, err := k.ReadLogsSince(ctx, containerID, cursor.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return cursor.Filter(entries), nil
+}
+
+func (k *kubernetesClient) ReadLogsLookback(ctx context.Context, containerID string, lookback time.Duration) ([]LogEntry, error) {
+	return k.ReadLogsSince(ctx, containerID, time.Now().Add(-lookback))
+}
+
+func (k *kubernetesClient) ReadLogsTail(ctx context.Context, containerID string, n int) ([]LogEntry, error) {
+	body, err := k.readLogs(ctx, containerID, url.Values{"tailLines": {strconv.Itoa(n)}})
+	if err != nil {
+		return nil, err
+	}
+	// Close body after parsing; error not actionable in defer context as stream is already consumed
+	defer func() { _ = body.Close() }()
+
+	return parseLogStreamWith(body, k.timestamps)
+}
+
+func (k *kubernetesClient) FollowLogs(ctx context.Context, containerID string) (<-chan LogEntry, error) {
+	// tailLines=0 skips the existing log so only lines written from now on are sent
+	body, err := k.readLogs(ctx, containerID, url.Values{"follow": {"true"}, "tailLines": {"0"}})
+	if err != nil {
+		return nil, err
+	}
+	return followLogStream(ctx, body, k.timestamps), nil
+}
+
+// ReadEventsSince returns no events: Kubernetes events are not mapped to the
+// container crash signals of the Docker events API.
+func (k *kubernetesClient) ReadEventsSince(_ context.Context, _ string, _ time.Time) ([]Event, error) {
+	return nil, nil
+}
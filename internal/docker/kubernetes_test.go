@@ -0,0 +1,184 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPodList = `{"items":[
+ {"metadata":{"name":"web-7d9f","namespace":"shop","uid":"8f14e45f-ceea-467f-a0e6-0a1b2c3d4e5f","labels":{"app":"web"}},
+  "spec":{"containers":[{"name":"nginx","image":"nginx:1.27"},{"name":"sidecar","image":"envoy:1.30"}]},
+  "status":{"containerStatuses":[{"name":"nginx","state":{"running":{}}},{"name":"sidecar","state":{"waiting":{"reason":"CrashLoopBackOff"}}}]}},
+ {"metadata":{"name":"migrate","namespace":"shop","uid":"c9f0f895-fb98-4b91-99f5-1a2b3c4d5e6f","labels":{"app":"migrate"}},
+  "spec":{"containers":[{"name":"migrate","image":"migrate:2"}]},
+  "status":{"containerStatuses":[{"name":"migrate","state":{"terminated":{"exitCode":0}}}]}}
+]}`
+
+// newTestKubernetesServer serves a pod list and pod logs like the Kubernetes API and
+// records the requests it received.
+func newTestKubernetesServer(t *testing.T, logs string) (*httptest.Server, *[]*http.Request) {
+	t.Helper()
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte(`{"gitVersion":"v1.31.0"}`))
+		case strings.HasSuffix(r.URL.Path, "/pods"):
+			_, _ = w.Write([]byte(testPodList))
+		case strings.HasSuffix(r.URL.Path, "/log"):
+			_, _ = w.Write([]byte(logs))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestKubernetesClient_ListContainers(t *testing.T) {
+	server, requests := newTestKubernetesServer(t, "")
+	client, err := NewKubernetesClient(KubernetesOptions{APIServer: server.URL, Namespace: "shop", LabelSelector: "tier=frontend"})
+	if err != nil {
+		t.Fatalf("NewKubernetesClient() error = %v", err)
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	containers, err := client.ListContainers(context.Background(), FilterOptions{})
+	if err != nil {
+		t.Fatalf("ListContainers() error = %v", err)
+	}
+	if len(containers) != 1 {
+		t.Fatalf("Expected only the running container without IncludeAll, got %+v", containers)
+	}
+	want := Container{
+		ID:     "8f14e45f-ceea-467f-a0e6-0a1b2c3d4e5f/shop/web-7d9f/nginx",
+		Name:   "shop/web-7d9f/nginx",
+		State:  "running",
+		Image:  "nginx:1.27",
+		Labels: map[string]string{"app": "web"},
+	}
+	if got := containers[0]; got.ID != want.ID || got.Name != want.Name || got.State != want.State || got.Image != want.Image || got.Labels["app"] != "web" {
+		t.Errorf("ListContainers()[0] = %+v, want %+v", got, want)
+	}
+
+	list := (*requests)[len(*requests)-1]
+	if list.URL.Path != "/api/v1/namespaces/shop/pods" || list.URL.Query().Get("labelSelector") != "tier=frontend" {
+		t.Errorf("Unexpected pod list request: %s", list.URL)
+	}
+
+	all, err := client.ListContainers(context.Background(), FilterOptions{IncludeAll: true})
+	if err != nil {
+		t.Fatalf("ListContainers(IncludeAll) error = %v", err)
+	}
+	states := make(map[string]string, len(all))
+	for _, c := range all {
+		states[c.Name] = c.State
+	}
+	if states["shop/web-7d9f/sidecar"] != "restarting" || states["shop/migrate/migrate"] != "exited" || len(states) != 3 {
+		t.Errorf("Unexpected states with IncludeAll: %v", states)
+	}
+
+	filtered, err := client.ListContainers(context.Background(), FilterOptions{IncludeAll: true, NamePattern: "/migrate$", Labels: map[string]string{"app": "migrate"}})
+	if err != nil {
+		t.Fatalf("ListContainers(filtered) error = %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Name != "shop/migrate/migrate" {
+		t.Errorf("Expected name and label filters to apply, got %+v", filtered)
+	}
+}
+
+func TestKubernetesClient_ReadLogs(t *testing.T) {
+	logs := "2025-01-15T08:30:00.100000000Z starting\n" +
+		"2025-01-15T08:30:00.900000000Z listening on :8080\n" +
+		"2025-01-15T08:30:05.000000000Z GET /health 200\n"
+	server, requests := newTestKubernetesServer(t, logs)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("secret-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewKubernetesClient(KubernetesOptions{APIServer: server.URL, TokenFile: tokenFile})
+	if err != nil {
+		t.Fatalf("NewKubernetesClient() error = %v", err)
+	}
+	id := "8f14e45f-ceea-467f-a0e6-0a1b2c3d4e5f/shop/web-7d9f/nginx"
+
+	since := time.Date(2025, 1, 15, 8, 30, 0, 500_000_000, time.UTC)
+	entries, err := client.ReadLogsSince(context.Background(), id, since)
+	if err != nil {
+		t.Fatalf("ReadLogsSince() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Message != "listening on :8080" || entries[0].Stream != "stdout" {
+		t.Errorf("ReadLogsSince() = %+v, want the 2 entries at or after since", entries)
+	}
+
+	req := (*requests)[len(*requests)-1]
+	query := req.URL.Query()
+	if req.URL.Path != "/api/v1/namespaces/shop/pods/web-7d9f/log" || query.Get("container") != "nginx" ||
+		query.Get("timestamps") != "true" || query.Get("sinceTime") != "2025-01-15T08:30:00Z" {
+		t.Errorf("Unexpected log request: %s", req.URL)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer secret-token" {
+		t.Errorf("Authorization = %q, want the token from the token file", got)
+	}
+
+	tail, err := client.ReadLogsTail(context.Background(), id, 2)
+	if err != nil {
+		t.Fatalf("ReadLogsTail() error = %v", err)
+	}
+	if len(tail) != 3 || (*requests)[len(*requests)-1].URL.Query().Get("tailLines") != "2" {
+		t.Errorf("ReadLogsTail() should pass tailLines to the API, got %d entries from %s", len(tail), (*requests)[len(*requests)-1].URL)
+	}
+
+	if _, err := client.ReadLogsSince(context.Background(), "abc123def456", since); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a non-Kubernetes ID, got %v", err)
+	}
+
+	events, err := client.ReadEventsSince(context.Background(), id, since)
+	if err != nil || len(events) != 0 {
+		t.Errorf("ReadEventsSince() = %v, %v, want no events", events, err)
+	}
+}
+
+func TestKubernetesClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"kind":"Status","message":"pods is forbidden"}`, http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client, err := NewKubernetesClient(KubernetesOptions{APIServer: server.URL})
+	if err != nil {
+		t.Fatalf("NewKubernetesClient() error = %v", err)
+	}
+	_, err = client.ListContainers(context.Background(), FilterOptions{})
+	if err == nil || !strings.Contains(err.Error(), "status 403") || !strings.Contains(err.Error(), "pods is forbidden") {
+		t.Errorf("Expected the API status and message in the error, got %v", err)
+	}
+}
+
+func TestNewKubernetesClient_NotInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+
+	_, err := NewKubernetesClient(KubernetesOptions{})
+	if err == nil || !strings.Contains(err.Error(), "kubernetes.api_server") {
+		t.Errorf("Expected an error naming kubernetes.api_server outside a cluster, got %v", err)
+	}
+
+	_, err = NewKubernetesClient(KubernetesOptions{APIServer: "https://k8s.example", CAFile: filepath.Join(t.TempDir(), "missing.crt")})
+	if err == nil {
+		t.Error("Expected an error for a missing CA file")
+	}
+}
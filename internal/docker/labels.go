@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Container labels that override DLIA settings for a single container, e.g. in a
+// compose file. Label values take precedence over the global configuration.
+const (
+	LabelPrefix       = "dlia."
+	LabelLookback     = "dlia.lookback"     // Go duration, e.g. "6h"; incremental scans read at most that far back
+	LabelSkip         = "dlia.skip"         // Boolean; "true" excludes the container from scans
+	LabelInstructions = "dlia.instructions" // Extra system-prompt instructions for this container
+	LabelScan         = "dlia.scan"         // Conventional opt-in label for --label filters; no effect by itself
+)
+
+// LabelOverrides holds the per-container settings read from dlia.* labels.
+// Zero values mean "not set".
+type LabelOverrides struct {
+	Lookback     time.Duration
+	Skip         bool
+	Instructions string
+}
+
+// ParseLabelOverrides reads the recognized dlia.* labels. Unknown dlia.* keys and
+// invalid values are ignored and reported as warnings, sorted by label key, so a
+// typo never aborts a scan.
+func ParseLabelOverrides(labels map[string]string) (LabelOverrides, []string) {
+	var overrides LabelOverrides
+	var warnings []string
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		if strings.HasPrefix(key, LabelPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.TrimSpace(labels[key])
+		switch key {
+		case LabelLookback:
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				warnings = append(warnings, fmt.Sprintf("ignoring label %s=%q: expected a positive duration such as 6h", key, value))
+				continue
+			}
+			overrides.Lookback = d
+		case LabelSkip:
+			skip, err := strconv.ParseBool(value)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("ignoring label %s=%q: expected true or false", key, value))
+				continue
+			}
+			overrides.Skip = skip
+		case LabelInstructions:
+			overrides.Instructions = value
+		case LabelScan:
+			// Matched by --label filters, not an override
+		default:
+			warnings = append(warnings, fmt.Sprintf("ignoring unknown label %s", key))
+		}
+	}
+
+	return overrides, warnings
+}
@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLabelOverrides(t *testing.T) {
+	overrides, warnings := ParseLabelOverrides(map[string]string{
+		"dlia.lookback":              "6h",
+		"dlia.skip":                  "false",
+		"dlia.instructions":          "  focus on auth ",
+		"dlia.scan":                  "true",
+		"com.docker.compose.project": "shop",
+	})
+
+	if overrides.Lookback != 6*time.Hour || overrides.Skip || overrides.Instructions != "focus on auth" {
+		t.Errorf("unexpected overrides: %+v", overrides)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestParseLabelOverrides_InvalidAndUnknown(t *testing.T) {
+	overrides, warnings := ParseLabelOverrides(map[string]string{
+		"dlia.lookback": "soon",
+		"dlia.skip":     "maybe",
+		"dlia.lookbak":  "1h",
+	})
+
+	if overrides != (LabelOverrides{}) {
+		t.Errorf("invalid labels should be ignored, got %+v", overrides)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %v", warnings)
+	}
+	for i, key := range []string{"dlia.lookback", "dlia.lookbak", "dlia.skip"} {
+		if !strings.Contains(warnings[i], key) {
+			t.Errorf("warning %d = %q, want it to mention %s", i, warnings[i], key)
+		}
+	}
+}
+
+func TestParseLabelOverrides_Skip(t *testing.T) {
+	overrides, _ := ParseLabelOverrides(map[string]string{"dlia.skip": "TRUE"})
+	if !overrides.Skip {
+		t.Error("dlia.skip=TRUE should skip the container")
+	}
+}
@@ -2,13 +2,17 @@ package docker
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"strings"
 	"time"
 )
 
-const streamStdout = "stdout"
+const (
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
 
 // LogsOptions contains options for reading container logs
 type LogsOptions struct {
@@ -20,31 +24,26 @@ type LogsOptions struct {
 
 // parseLogStream parses the Docker log stream into LogEntry objects
 func parseLogStream(reader io.Reader) ([]LogEntry, error) {
+	return parseLogStreamWith(reader, nil)
+}
+
+// parseLogStreamWith parses the Docker log stream, extracting timestamps with parser.
+func parseLogStreamWith(reader io.Reader, parser *TimestampParser) ([]LogEntry, error) {
 	var entries []LogEntry
 	scanner := bufio.NewScanner(reader)
 
 	// Docker multiplexes stdout/stderr with 8-byte headers
 	// We need to skip the header and parse the content
+	stream := streamStdout
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Docker API returns logs with an 8-byte header for stream multiplexing
-		// Format: [8 bytes header][log content with timestamp]
-		// We need to handle both cases (with and without header)
-
-		// Skip the 8-byte header if present (binary data)
-		// The header format is: [STREAM_TYPE][0x00][0x00][0x00][SIZE (4 bytes)]
-		if len(line) > 8 {
-			// Check if line starts with binary header (stream type 1 or 2)
-			if line[0] == 1 || line[0] == 2 {
-				line = line[8:] // Skip header
-			}
-		}
+		var content string
+		content, stream = splitStreamHeader(scanner.Text(), stream)
 
 		// Parse timestamp and message
 		// Format: "2025-11-30T19:00:00.123456789Z message here"
-		entry := parseLogLine(line)
+		entry := parser.ParseLine(content)
 		if entry != nil {
+			entry.Stream = stream
 			entries = append(entries, *entry)
 		}
 	}
@@ -56,6 +55,96 @@ func parseLogStream(reader io.Reader) ([]LogEntry, error) {
 	return entries, nil
 }
 
+// followLogStream parses a following Docker log stream in a goroutine and sends each
+// entry on the returned channel. The channel is closed and reader is closed when the
+// stream ends or ctx is cancelled; closing reader on cancellation also unblocks a
+// pending read so the goroutine never outlives ctx.
+func followLogStream(ctx context.Context, reader io.ReadCloser, parser *TimestampParser) <-chan LogEntry {
+	entries := make(chan LogEntry)
+
+	go func() {
+		defer close(entries)
+		// Close error not actionable: the stream is being abandoned either way
+		defer func() { _ = reader.Close() }()
+		stop := context.AfterFunc(ctx, func() { _ = reader.Close() })
+		defer stop()
+
+		scanner := bufio.NewScanner(reader)
+		stream := streamStdout
+		for scanner.Scan() {
+			var content string
+			content, stream = splitStreamHeader(scanner.Text(), stream)
+			entry := parser.ParseLine(content)
+			entry.Stream = stream
+			select {
+			case entries <- *entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return entries
+}
+
+// splitStreamHeader removes the 8-byte multiplexing header Docker prepends to each
+// frame of a non-TTY log stream and returns the content with the frame's stream.
+// Lines without a header (TTY containers, or continuation lines of a frame that
+// contained newlines) keep the previous stream.
+func splitStreamHeader(line, previous string) (content, stream string) {
+	// Docker API returns logs with an 8-byte header for stream multiplexing
+	// Format: [8 bytes header][log content with timestamp]
+	// We need to handle both cases (with and without header)
+
+	// Skip the 8-byte header if present (binary data)
+	// The header format is: [STREAM_TYPE][0x00][0x00][0x00][SIZE (4 bytes)]
+	if len(line) > 8 {
+		// Check if line starts with binary header (stream type 1 or 2)
+		switch line[0] {
+		case 1:
+			return line[8:], streamStdout
+		case 2:
+			return line[8:], streamStderr
+		}
+	}
+	return line, previous
+}
+
+// ParseLogFile parses a captured log file (e.g. output of "docker logs --timestamps")
+// into LogEntry objects. Lines without a leading RFC3339 timestamp are kept as
+// untimestamped messages; blank lines are skipped.
+func ParseLogFile(reader io.Reader) ([]LogEntry, error) {
+	var parser *TimestampParser
+	return parser.ParseLogFile(reader)
+}
+
+// ParseLogFile parses a captured log file like the package-level ParseLogFile, but
+// also recognizes the parser's configured timestamp format.
+func (p *TimestampParser) ParseLogFile(reader io.Reader) ([]LogEntry, error) {
+	var entries []LogEntry
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entry := p.ParseLine(line)
+		if _, err := parseTimestamp(entry.Timestamp); err != nil {
+			entry.Timestamp = ""
+			entry.Message = line
+		}
+		entries = append(entries, *entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading log file at line %d: %w", len(entries), err)
+	}
+
+	return entries, nil
+}
+
 // parseLogLine parses a single log line with timestamp
 func parseLogLine(line string) *LogEntry {
 	// Find the space after timestamp
@@ -82,7 +171,9 @@ func parseLogLine(line string) *LogEntry {
 	}
 }
 
-// GetLatestLogTime returns the timestamp of the most recent log entry
+// GetLatestLogTime returns the timestamp of the most recent log entry.
+// Entries parsed with a TimestampParser carry normalized RFC3339Nano timestamps,
+// so state advances from the configured format as well.
 func GetLatestLogTime(entries []LogEntry) (time.Time, error) {
 	if len(entries) == 0 {
 		return time.Time{}, nil
@@ -94,15 +185,33 @@ func GetLatestLogTime(entries []LogEntry) (time.Time, error) {
 		return time.Time{}, nil
 	}
 
-	// Parse the timestamp
-	t, err := time.Parse(time.RFC3339Nano, lastEntry.Timestamp)
+	t, err := parseTimestamp(lastEntry.Timestamp)
 	if err != nil {
-		// Try alternative formats
-		t, err = time.Parse(time.RFC3339, lastEntry.Timestamp)
-		if err != nil {
-			return time.Time{}, fmt.Errorf("failed to parse timestamp '%s' in log entry %d: %w", lastEntry.Timestamp, len(entries)-1, err)
-		}
+		return time.Time{}, fmt.Errorf("failed to parse timestamp '%s' in log entry %d: %w", lastEntry.Timestamp, len(entries)-1, err)
 	}
 
 	return t, nil
 }
+
+// EntriesSince returns the entries stamped at or after since, mirroring Docker's
+// inclusive since filter. Entries without a parseable timestamp are kept.
+func EntriesSince(entries []LogEntry, since time.Time) []LogEntry {
+	result := make([]LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if t, err := parseTimestamp(entry.Timestamp); err == nil && t.Before(since) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// parseTimestamp parses a Docker log timestamp, accepting RFC3339Nano and RFC3339.
+func parseTimestamp(timestamp string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		// Try alternative formats
+		t, err = time.Parse(time.RFC3339, timestamp)
+	}
+	return t, err
+}
@@ -3,6 +3,7 @@ package docker
 import (
 	"bytes"
 	"context"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -100,16 +101,19 @@ func TestParseLogStream_WithDockerHeader(t *testing.T) {
 		name       string
 		streamType byte
 		logLine    string
+		wantStream string
 	}{
 		{
 			name:       "stdout stream",
 			streamType: 1,
 			logLine:    "2025-01-01T10:00:00Z stdout message",
+			wantStream: streamStdout,
 		},
 		{
 			name:       "stderr stream",
 			streamType: 2,
 			logLine:    "2025-01-01T10:00:00Z stderr message",
+			wantStream: streamStderr,
 		},
 	}
 
@@ -135,10 +139,37 @@ func TestParseLogStream_WithDockerHeader(t *testing.T) {
 			if !strings.Contains(entries[0].Message, "message") {
 				t.Errorf("Expected message to contain 'message', got: %s", entries[0].Message)
 			}
+			if entries[0].Stream != tt.wantStream {
+				t.Errorf("Expected stream %q, got %q", tt.wantStream, entries[0].Stream)
+			}
 		})
 	}
 }
 
+func TestParseLogStream_HeaderlessLinesKeepStream(t *testing.T) {
+	stderr := string([]byte{2, 0, 0, 0, 0, 0, 0, 0})
+	stdout := string([]byte{1, 0, 0, 0, 0, 0, 0, 0})
+	input := "2025-01-01T10:00:00Z no header\n" +
+		stderr + "2025-01-01T10:00:01Z panic: boom\n" +
+		"2025-01-01T10:00:01Z goroutine 1 [running]\n" +
+		stdout + "2025-01-01T10:00:02Z recovered\n"
+
+	entries, err := parseLogStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{streamStdout, streamStderr, streamStderr, streamStdout}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, stream := range want {
+		if entries[i].Stream != stream {
+			t.Errorf("entry %d: stream = %q, want %q", i, entries[i].Stream, stream)
+		}
+	}
+}
+
 func TestParseLogLine_Various(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -408,6 +439,32 @@ func TestParseLogStream_UTF8Content(t *testing.T) {
 	}
 }
 
+func TestParseLogFile(t *testing.T) {
+	input := "2025-01-01T10:00:00.123456789Z first message\r\n" +
+		"\n" +
+		"plain line without timestamp\n" +
+		"2025-01-01T10:00:01Z second message\n"
+
+	entries, err := ParseLogFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseLogFile() error = %v", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries (blank line skipped), got %d", len(entries))
+	}
+
+	if entries[0].Timestamp != "2025-01-01T10:00:00.123456789Z" || entries[0].Message != "first message" {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Timestamp != "" || entries[1].Message != "plain line without timestamp" {
+		t.Errorf("Line without timestamp should be kept whole, got %+v", entries[1])
+	}
+	if entries[2].Stream != streamStdout {
+		t.Errorf("Expected stream %q, got %q", streamStdout, entries[2].Stream)
+	}
+}
+
 func TestLogsOptions_Fields(t *testing.T) {
 	// Test that LogsOptions struct fields are accessible
 	opts := LogsOptions{
@@ -502,3 +559,85 @@ func TestReadLogsLookback_CalculatesCorrectTime(t *testing.T) {
 	expectedSince := now.Add(-lookback)
 	_ = expectedSince // Used in calculation but we can't directly verify with current mock
 }
+
+func TestFollowLogStream(t *testing.T) {
+	input := "2025-01-01T10:00:00Z first\n2025-01-01T10:00:01Z second\n"
+	reader := io.NopCloser(strings.NewReader(input))
+
+	var got []LogEntry
+	for entry := range followLogStream(context.Background(), reader, nil) {
+		got = append(got, entry)
+	}
+
+	if len(got) != 2 || got[0].Message != "first" || got[1].Message != "second" {
+		t.Errorf("Unexpected entries: %+v", got)
+	}
+}
+
+func TestFollowLogStream_CancelClosesStream(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close() //nolint:errcheck // test cleanup
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entries := followLogStream(ctx, pr, nil)
+
+	if _, err := pw.Write([]byte("2025-01-01T10:00:00Z first\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if entry := <-entries; entry.Message != "first" {
+		t.Errorf("Expected first entry, got %+v", entry)
+	}
+
+	// The goroutine is blocked reading; cancellation must close the reader and the channel
+	cancel()
+	select {
+	case _, ok := <-entries:
+		if ok {
+			t.Error("Expected channel to be closed after cancellation")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("FollowLogs goroutine did not stop after cancellation")
+	}
+
+	if _, err := pw.Write([]byte("late\n")); err == nil {
+		t.Error("Expected reader to be closed after cancellation")
+	}
+}
+
+func TestReadLogsTail(t *testing.T) {
+	mock := &mockDockerClient{
+		logs: []LogEntry{
+			{Timestamp: "2025-01-01T10:00:00Z", Message: "log 1"},
+			{Timestamp: "2025-01-01T10:01:00Z", Message: "log 2"},
+			{Timestamp: "2025-01-01T10:02:00Z", Message: "log 3"},
+		},
+	}
+	client := NewClientWithInterface(mock)
+
+	result, err := client.ReadLogsTail(context.Background(), "container1", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 2 || result[0].Message != "log 2" {
+		t.Errorf("Expected the last 2 entries, got %+v", result)
+	}
+}
+
+func TestEntriesSince(t *testing.T) {
+	entries := []LogEntry{
+		{Timestamp: "2025-01-01T09:59:59Z", Message: "before"},
+		{Timestamp: "2025-01-01T10:00:00Z", Message: "boundary"},
+		{Timestamp: "", Message: "untimestamped"},
+		{Timestamp: "2025-01-01T10:00:01.5Z", Message: "after"},
+	}
+
+	result := EntriesSince(entries, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	var got []string
+	for _, e := range result {
+		got = append(got, e.Message)
+	}
+	if strings.Join(got, ",") != "boundary,untimestamped,after" {
+		t.Errorf("EntriesSince() = %v, want [boundary untimestamped after]", got)
+	}
+}
@@ -0,0 +1,25 @@
+package docker
+
+import "regexp"
+
+// GroupMultiline merges continuation lines into the preceding entry so that a stack
+// trace becomes one logical entry. A line is a continuation when continuation matches
+// its message and it is on the same stream as the preceding entry; the merged entry
+// keeps the timestamp of its first line. A nil continuation returns entries unchanged.
+func GroupMultiline(entries []LogEntry, continuation *regexp.Regexp) []LogEntry {
+	if continuation == nil || len(entries) == 0 {
+		return entries
+	}
+
+	grouped := make([]LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		last := len(grouped) - 1
+		if last >= 0 && grouped[last].Stream == entry.Stream && continuation.MatchString(entry.Message) {
+			grouped[last].Message += "\n" + entry.Message
+			continue
+		}
+		grouped = append(grouped, entry)
+	}
+
+	return grouped
+}
@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGroupMultiline(t *testing.T) {
+	continuation := regexp.MustCompile(`^(\s|at |Caused by:)`)
+	entries := []LogEntry{
+		{Timestamp: "2025-01-01T10:00:00Z", Stream: "stderr", Message: "Exception in thread main java.lang.NullPointerException"},
+		{Timestamp: "2025-01-01T10:00:00.1Z", Stream: "stderr", Message: "\tat com.example.App.run(App.java:42)"},
+		{Timestamp: "2025-01-01T10:00:00.2Z", Stream: "stderr", Message: "Caused by: java.io.IOException"},
+		{Timestamp: "2025-01-01T10:00:01Z", Stream: "stdout", Message: "  indented but on another stream"},
+		{Timestamp: "2025-01-01T10:00:02Z", Stream: "stdout", Message: "next request"},
+	}
+
+	grouped := GroupMultiline(entries, continuation)
+
+	if len(grouped) != 3 {
+		t.Fatalf("expected 3 logical entries, got %d: %+v", len(grouped), grouped)
+	}
+	want := "Exception in thread main java.lang.NullPointerException\n\tat com.example.App.run(App.java:42)\nCaused by: java.io.IOException"
+	if grouped[0].Message != want {
+		t.Errorf("merged message = %q, want %q", grouped[0].Message, want)
+	}
+	if grouped[0].Timestamp != "2025-01-01T10:00:00Z" {
+		t.Errorf("merged entry should keep the first timestamp, got %s", grouped[0].Timestamp)
+	}
+	if grouped[1].Message != "  indented but on another stream" {
+		t.Errorf("continuation on another stream should not be merged, got %q", grouped[1].Message)
+	}
+	if entries[0].Message != "Exception in thread main java.lang.NullPointerException" {
+		t.Error("input entries must not be modified")
+	}
+}
+
+func TestGroupMultiline_Disabled(t *testing.T) {
+	entries := []LogEntry{{Message: "a"}, {Message: " b"}}
+	if got := GroupMultiline(entries, nil); len(got) != 2 {
+		t.Errorf("nil pattern should keep entries unchanged, got %+v", got)
+	}
+}
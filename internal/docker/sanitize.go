@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeUTF8 makes the messages of entries valid UTF-8 so that binary output cannot
+// corrupt reports, prompts or the JSON of LLM requests. Invalid byte sequences are
+// replaced with U+FFFD, or written as \xNN escapes when escape is set, which keeps
+// the bytes visible for the LLM. entries is not modified; the returned count is the
+// number of entries that were changed.
+func SanitizeUTF8(entries []LogEntry, escape bool) ([]LogEntry, int) {
+	var sanitized []LogEntry
+	count := 0
+	for i, entry := range entries {
+		if utf8.ValidString(entry.Message) {
+			continue
+		}
+		if sanitized == nil {
+			sanitized = make([]LogEntry, len(entries))
+			copy(sanitized, entries)
+		}
+		if escape {
+			sanitized[i].Message = escapeInvalidUTF8(entry.Message)
+		} else {
+			sanitized[i].Message = strings.ToValidUTF8(entry.Message, string(utf8.RuneError))
+		}
+		count++
+	}
+
+	if sanitized == nil {
+		return entries, 0
+	}
+	return sanitized, count
+}
+
+// escapeInvalidUTF8 writes every byte of an invalid sequence as \xNN.
+func escapeInvalidUTF8(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 8)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size <= 1 {
+			fmt.Fprintf(&sb, `\x%02x`, s[i])
+			i++
+			continue
+		}
+		sb.WriteString(s[i : i+size])
+		i += size
+	}
+	return sb.String()
+}
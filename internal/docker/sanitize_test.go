@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeUTF8(t *testing.T) {
+	entries := []LogEntry{
+		{Timestamp: "2025-01-01T00:00:00Z", Stream: "stdout", Message: "plain line ✓"},
+		{Timestamp: "2025-01-01T00:00:01Z", Stream: "stdout", Message: "binary \xff\xfe\x00 payload"},
+		{Timestamp: "2025-01-01T00:00:02Z", Stream: "stderr", Message: "truncated \xe2\x82"},
+	}
+
+	tests := []struct {
+		name   string
+		escape bool
+		want   []string
+	}{
+		{
+			name: "replace",
+			want: []string{"plain line ✓", "binary �\x00 payload", "truncated �"},
+		},
+		{
+			name:   "escape",
+			escape: true,
+			want:   []string{"plain line ✓", `binary \xff\xfe` + "\x00 payload", `truncated \xe2\x82`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := SanitizeUTF8(entries, tt.escape)
+			if count != 2 {
+				t.Errorf("SanitizeUTF8() count = %d, want 2", count)
+			}
+			for i, entry := range got {
+				if entry.Message != tt.want[i] {
+					t.Errorf("entry %d = %q, want %q", i, entry.Message, tt.want[i])
+				}
+				if !utf8.ValidString(entry.Message) {
+					t.Errorf("entry %d is not valid UTF-8: %q", i, entry.Message)
+				}
+				if entry.Timestamp != entries[i].Timestamp || entry.Stream != entries[i].Stream {
+					t.Errorf("entry %d lost its metadata: %+v", i, entry)
+				}
+			}
+
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var decoded []LogEntry
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			for i := range decoded {
+				if decoded[i].Message != got[i].Message {
+					t.Errorf("entry %d did not survive a JSON round trip: %q, want %q", i, decoded[i].Message, got[i].Message)
+				}
+			}
+		})
+	}
+
+	if entries[1].Message != "binary \xff\xfe\x00 payload" {
+		t.Error("SanitizeUTF8() must not modify its input")
+	}
+}
+
+func TestSanitizeUTF8_ValidInputUnchanged(t *testing.T) {
+	entries := []LogEntry{{Message: "ok"}, {Message: "grüße"}}
+
+	got, count := SanitizeUTF8(entries, false)
+	if count != 0 {
+		t.Errorf("SanitizeUTF8() count = %d, want 0", count)
+	}
+	if &got[0] != &entries[0] {
+		t.Error("Valid input should be returned without copying")
+	}
+}
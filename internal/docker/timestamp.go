@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TimestampParser extracts timestamps from log lines for containers that log with a
+// custom prefix such as "[2023-01-01 10:00:00]". It is only consulted for lines
+// without Docker's RFC3339 prefix (e.g. captured log files): Docker's timestamp is
+// always kept, as it is the one the cursor and the since filter of the daemon use.
+// Extracted timestamps are normalized to RFC3339Nano so GetLatestLogTime and
+// LogCursor advance state from the configured format.
+//
+// A nil *TimestampParser is valid and only understands Docker's format.
+type TimestampParser struct {
+	layout  string         // Go time layout of the timestamp
+	pattern *regexp.Regexp // Locates the timestamp; first capture group if present, else whole match
+	loc     *time.Location // Zone of timestamps whose layout has none
+}
+
+// NewTimestampParser creates a parser from a Go time layout and/or a regex locating the
+// timestamp in each line. With only a pattern, the matched text is parsed as RFC3339.
+// With only a layout, the timestamp must start the message and span as many
+// space-separated fields as the layout. Times without a zone are read in loc (UTC
+// when nil). Returns nil when layout and pattern are both empty.
+func NewTimestampParser(layout, pattern string, loc *time.Location) (*TimestampParser, error) {
+	if layout == "" && pattern == "" {
+		return nil, nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	p := &TimestampParser{layout: layout, loc: loc}
+	if p.layout == "" {
+		p.layout = time.RFC3339Nano
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp pattern %q: %w", pattern, err)
+		}
+		p.pattern = re
+	}
+
+	return p, nil
+}
+
+// ParseLine parses a single log line. A leading Docker timestamp is split off and
+// used as is. Without one, the configured format is looked up in the line and, when
+// found, becomes the entry timestamp and is removed from the message.
+func (p *TimestampParser) ParseLine(line string) *LogEntry {
+	entry := parseLogLine(line)
+	if p == nil {
+		return entry
+	}
+
+	if _, err := parseTimestamp(entry.Timestamp); err == nil {
+		return entry
+	}
+
+	entry.Timestamp = ""
+	entry.Message = line
+	if t, message, ok := p.extract(line); ok {
+		entry.Timestamp = t.UTC().Format(time.RFC3339Nano)
+		entry.Message = message
+	}
+
+	return entry
+}
+
+// extract locates and parses the configured timestamp in message, returning the time
+// and the message with the timestamp text removed.
+func (p *TimestampParser) extract(message string) (time.Time, string, bool) {
+	raw, start, end := p.locate(message)
+	if raw == "" {
+		return time.Time{}, "", false
+	}
+
+	t, err := time.ParseInLocation(p.layout, raw, p.loc)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	return t, strings.TrimSpace(message[:start] + message[end:]), true
+}
+
+// locate returns the raw timestamp text and the byte range to remove from message.
+func (p *TimestampParser) locate(message string) (raw string, start, end int) {
+	if p.pattern != nil {
+		idx := p.pattern.FindStringSubmatchIndex(message)
+		if idx == nil {
+			return "", 0, 0
+		}
+		if len(idx) >= 4 && idx[2] >= 0 {
+			return message[idx[2]:idx[3]], idx[0], idx[1]
+		}
+		return message[idx[0]:idx[1]], idx[0], idx[1]
+	}
+
+	fields := strings.Count(p.layout, " ") + 1
+	parts := strings.SplitN(message, " ", fields+1)
+	if len(parts) < fields {
+		return "", 0, 0
+	}
+	raw = strings.Join(parts[:fields], " ")
+	return raw, 0, len(raw)
+}
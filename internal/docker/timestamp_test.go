@@ -0,0 +1,135 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTimestampParser(t *testing.T) {
+	p, err := NewTimestampParser("", "", nil)
+	if err != nil || p != nil {
+		t.Errorf("NewTimestampParser(\"\", \"\", nil) = %v, %v; want nil, nil", p, err)
+	}
+
+	if _, err := NewTimestampParser("", "([", nil); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestTimestampParser_ParseLine(t *testing.T) {
+	bracketed, err := NewTimestampParser("2006-01-02 15:04:05", `^\[([^\]]+)\]`, nil)
+	if err != nil {
+		t.Fatalf("NewTimestampParser() error = %v", err)
+	}
+	prefixed, err := NewTimestampParser("2006-01-02 15:04:05", "", nil)
+	if err != nil {
+		t.Fatalf("NewTimestampParser() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		parser        *TimestampParser
+		line          string
+		wantTimestamp string
+		wantMessage   string
+	}{
+		{
+			name:          "bracketed prefix without docker timestamp",
+			parser:        bracketed,
+			line:          "[2023-01-01 10:00:00] server started",
+			wantTimestamp: "2023-01-01T10:00:00Z",
+			wantMessage:   "server started",
+		},
+		{
+			name:          "docker timestamp wins over bracketed prefix",
+			parser:        bracketed,
+			line:          "2025-01-01T10:00:00.5Z [2023-01-01 10:00:00] server started",
+			wantTimestamp: "2025-01-01T10:00:00.5Z",
+			wantMessage:   "[2023-01-01 10:00:00] server started",
+		},
+		{
+			name:          "layout only spans multiple fields",
+			parser:        prefixed,
+			line:          "2023-01-01 10:00:00 server started",
+			wantTimestamp: "2023-01-01T10:00:00Z",
+			wantMessage:   "server started",
+		},
+		{
+			name:          "no match falls back to docker timestamp",
+			parser:        bracketed,
+			line:          "2025-01-01T10:00:00.5Z plain message",
+			wantTimestamp: "2025-01-01T10:00:00.5Z",
+			wantMessage:   "plain message",
+		},
+		{
+			name:          "no match and no docker timestamp",
+			parser:        bracketed,
+			line:          "plain message",
+			wantTimestamp: "",
+			wantMessage:   "plain message",
+		},
+		{
+			name:          "nil parser keeps docker behavior",
+			parser:        nil,
+			line:          "2025-01-01T10:00:00Z [2023-01-01 10:00:00] server started",
+			wantTimestamp: "2025-01-01T10:00:00Z",
+			wantMessage:   "[2023-01-01 10:00:00] server started",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := tt.parser.ParseLine(tt.line)
+			if entry.Timestamp != tt.wantTimestamp {
+				t.Errorf("Timestamp = %q, want %q", entry.Timestamp, tt.wantTimestamp)
+			}
+			if entry.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", entry.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestTimestampParser_Location(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	parser, err := NewTimestampParser("2006-01-02 15:04:05", `^\[([^\]]+)\]`, cet)
+	if err != nil {
+		t.Fatalf("NewTimestampParser() error = %v", err)
+	}
+
+	entry := parser.ParseLine("[2023-01-01 10:00:00] server started")
+	if entry.Timestamp != "2023-01-01T09:00:00Z" {
+		t.Errorf("Timestamp = %q, want the zoneless time read in the given zone", entry.Timestamp)
+	}
+}
+
+func TestTimestampParser_AdvancesCursor(t *testing.T) {
+	parser, err := NewTimestampParser("2006-01-02 15:04:05", `^\[([^\]]+)\]`, nil)
+	if err != nil {
+		t.Fatalf("NewTimestampParser() error = %v", err)
+	}
+
+	input := "[2023-01-01 10:00:00] first\n[2023-01-01 10:00:05] second\n"
+	entries, err := parser.ParseLogFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseLogFile() error = %v", err)
+	}
+
+	latest, err := GetLatestLogTime(entries)
+	if err != nil {
+		t.Fatalf("GetLatestLogTime() error = %v", err)
+	}
+	want := time.Date(2023, 1, 1, 10, 0, 5, 0, time.UTC)
+	if !latest.Equal(want) {
+		t.Errorf("GetLatestLogTime() = %v, want %v", latest, want)
+	}
+
+	cursor, err := NewLogCursor(entries)
+	if err != nil {
+		t.Fatalf("NewLogCursor() error = %v", err)
+	}
+	if len(cursor.Filter(entries)) != 1 {
+		t.Error("Expected cursor to drop the already processed boundary entry")
+	}
+}
@@ -1,5 +1,8 @@
 package docker
 
+// ComposeProjectLabel is the label docker compose sets to the project name.
+const ComposeProjectLabel = "com.docker.compose.project"
+
 // Container represents a Docker container with relevant metadata
 type Container struct {
 	ID     string
@@ -18,6 +21,18 @@ type LogEntry struct {
 
 // FilterOptions contains options for filtering containers
 type FilterOptions struct {
-	NamePattern string // Regex pattern for container names
-	IncludeAll  bool   // Include stopped containers
+	NamePattern string            // Regex pattern for container names
+	IncludeAll  bool              // Include stopped containers
+	Labels      map[string]string // Required labels; all must match (AND)
+}
+
+// MatchesLabels reports whether labels contain every key/value pair of o.Labels.
+// An empty o.Labels matches any container.
+func (o FilterOptions) MatchesLabels(labels map[string]string) bool {
+	for key, want := range o.Labels {
+		if got, ok := labels[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,42 @@
+// Package fsutil provides file helpers shared by the report and knowledge base writers.
+package fsutil
+
+import (
+	"fmt"
+	"os"
+)
+
+// WriteFileAtomic writes data to path.tmp, syncs it and renames it over path, so
+// readers and interrupted runs see either the old or the new content, never a
+// truncated file. The temp file is removed if any step fails.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm) //nolint:gosec // Callers pass paths built from config dirs and sanitized names
+	if err != nil {
+		return fmt.Errorf("failed to create temp file %s: %w", tmpPath, err)
+	}
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()    // Best effort cleanup
+		_ = os.Remove(tmpPath) // Best effort cleanup
+		return fmt.Errorf("failed to write temp file %s: %w", tmpPath, err)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()    // Best effort cleanup
+		_ = os.Remove(tmpPath) // Best effort cleanup
+		return fmt.Errorf("failed to sync temp file %s: %w", tmpPath, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpPath) // Best effort cleanup
+		return fmt.Errorf("failed to close temp file %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath) // Best effort cleanup
+		return fmt.Errorf("failed to rename temp file %s to %s: %w", tmpPath, path, err)
+	}
+
+	return nil
+}
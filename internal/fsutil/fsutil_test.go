@@ -0,0 +1,51 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.md")
+
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("new content"), 0o600); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new content" {
+		t.Errorf("content = %q, want %q", data, "new content")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the target file, got %d entries (temp file left behind?)", len(entries))
+	}
+}
+
+func TestWriteFileAtomic_RenameFailureCleansUp(t *testing.T) {
+	dir := t.TempDir()
+	// A non-empty directory at the target path makes the rename fail
+	target := filepath.Join(dir, "target")
+	if err := os.MkdirAll(filepath.Join(target, "child"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(target, []byte("data"), 0o600); err == nil {
+		t.Fatal("expected an error when the target is a directory")
+	}
+	if _, err := os.Stat(target + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temp file should be removed after a failed write")
+	}
+}
@@ -0,0 +1,143 @@
+package knowledge
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContainerEntries returns the scan entries of a container's knowledge base files,
+// sorted oldest-first. A container without a knowledge base file has no entries.
+func ContainerEntries(kbDir, container string) ([]Entry, error) {
+	files, err := serviceFiles(kbDir, container)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		_, fileEntries, err := ReadServiceEntries(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// LatestAnalyses returns the analysis text of each container's newest scan entry,
+// keyed by container name. Containers whose newest entry is older than since are
+// left out; a zero since keeps all containers.
+func LatestAnalyses(kbDir string, since time.Time) (map[string]string, error) {
+	entries, err := Search(kbDir, SearchOptions{Since: since})
+	if err != nil {
+		return nil, err
+	}
+
+	// Search returns entries newest-first, so the first one per container wins
+	latest := make(map[string]string)
+	for _, entry := range entries {
+		if _, seen := latest[entry.ContainerName]; !seen {
+			latest[entry.ContainerName] = entry.Analysis()
+		}
+	}
+	return latest, nil
+}
+
+// Analysis returns the entry text without its "### Scan:", "**Status:**" and
+// "**Last Seen:**" header lines.
+func (e Entry) Analysis() string {
+	var lines []string
+	for _, line := range strings.Split(e.Content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "### Scan:") || strings.HasPrefix(trimmed, "**Status:**") ||
+			strings.HasPrefix(trimmed, lastSeenPrefix) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// NearestEntry returns the index of the entry whose timestamp is closest to t,
+// preferring the earlier entry on a tie. Returns -1 for no entries.
+func NearestEntry(entries []Entry, t time.Time) int {
+	nearest := -1
+	var best time.Duration
+	for i, entry := range entries {
+		d := entry.Timestamp.Sub(t)
+		if d < 0 {
+			d = -d
+		}
+		if nearest == -1 || d < best {
+			nearest, best = i, d
+		}
+	}
+
+	return nearest
+}
+
+// DiffLines compares two texts line by line and returns the diff with each line
+// prefixed by "  " (unchanged), "- " (only in from) or "+ " (only in to).
+func DiffLines(from, to string) []string {
+	a := splitNonEmptyLines(from)
+	b := splitNonEmptyLines(to)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]string, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, "+ "+b[j])
+	}
+
+	return diff
+}
+
+// splitNonEmptyLines splits text into lines, dropping blank ones so paragraph
+// spacing changes do not show up as differences.
+func splitNonEmptyLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if trimmed := strings.TrimRight(line, " \t\r"); strings.TrimSpace(trimmed) != "" {
+			lines = append(lines, trimmed)
+		}
+	}
+	return lines
+}
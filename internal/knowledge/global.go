@@ -21,7 +21,7 @@ func UpdateGlobalSummary(results map[string]*chunking.AnalyzeResult, cfg *config
 	}
 
 	sortedKeys := sortedServiceNames(results)
-	content := buildGlobalSummaryContent(results, sortedKeys)
+	content := buildGlobalSummaryContent(results, sortedKeys, cfg.Output.GroupByComposeProject, cfg.DisplayLocation())
 
 	filePath := filepath.Join(cfg.Output.KnowledgeBaseDir, "global_summary.md")
 
@@ -40,20 +40,24 @@ func sortedServiceNames(results map[string]*chunking.AnalyzeResult) []string {
 }
 
 // buildGlobalSummaryContent assembles the complete markdown content for the global summary.
-func buildGlobalSummaryContent(results map[string]*chunking.AnalyzeResult, sortedKeys []string) string {
+func buildGlobalSummaryContent(results map[string]*chunking.AnalyzeResult, sortedKeys []string, groupByProject bool, loc *time.Location) string {
 	var sb strings.Builder
 
-	writeHeader(&sb)
+	writeHeader(&sb, loc)
 	writeHealthOverview(&sb, results)
-	writeServiceStatusTable(&sb, results, sortedKeys)
+	if groupByProject {
+		writeGroupedServiceStatusTables(&sb, results, sortedKeys)
+	} else {
+		writeServiceStatusTable(&sb, results, sortedKeys)
+	}
 	writeCriticalIssuesSection(&sb, results, sortedKeys)
 
 	return sb.String()
 }
 
 // writeHeader writes the document title and timestamp.
-func writeHeader(sb *strings.Builder) {
-	timestamp := time.Now().Format(time.RFC1123)
+func writeHeader(sb *strings.Builder, loc *time.Location) {
+	timestamp := time.Now().In(loc).Format(time.RFC1123)
 	sb.WriteString("# 🌍 Global System Summary\n\n")
 	fmt.Fprintf(sb, "**Last Updated:** %s\n\n", timestamp)
 }
@@ -74,7 +78,7 @@ func writeHealthOverview(sb *strings.Builder, results map[string]*chunking.Analy
 func countServicesWithIssues(results map[string]*chunking.AnalyzeResult) int {
 	count := 0
 	for _, res := range results {
-		if hasIssues(res.Analysis) {
+		if ResultSeverity(res) == SeverityCritical {
 			count++
 		}
 	}
@@ -85,23 +89,76 @@ func countServicesWithIssues(results map[string]*chunking.AnalyzeResult) int {
 // writeServiceStatusTable writes the service status table in markdown format.
 func writeServiceStatusTable(sb *strings.Builder, results map[string]*chunking.AnalyzeResult, sortedKeys []string) {
 	sb.WriteString("## Service Status\n\n")
+	writeServiceRows(sb, results, sortedKeys)
+}
+
+// writeServiceRows writes a status table for the given services.
+func writeServiceRows(sb *strings.Builder, results map[string]*chunking.AnalyzeResult, names []string) {
 	sb.WriteString("| Service | Status | Last Analysis |\n")
 	sb.WriteString("|---------|--------|---------------|\n")
 
-	for _, name := range sortedKeys {
+	for _, name := range names {
 		res := results[name]
-		status := determineServiceStatus(res.Analysis)
+		status := determineServiceStatus(res)
 		summary := extractSummary(res.Analysis)
 		fmt.Fprintf(sb, "| %s | %s | %s |\n", name, status, summary)
 	}
 }
 
-// determineServiceStatus returns an emoji status indicator based on analysis content.
-func determineServiceStatus(analysis string) string {
-	switch {
-	case hasIssues(analysis):
+// writeGroupedServiceStatusTables writes one status table per compose project, each
+// headed by the project's rollup status (its most severe service). Standalone
+// containers without a project are listed last.
+func writeGroupedServiceStatusTables(sb *strings.Builder, results map[string]*chunking.AnalyzeResult, sortedKeys []string) {
+	sb.WriteString("## Service Status\n\n")
+
+	groups := make(map[string][]string)
+	for _, name := range sortedKeys {
+		project := results[name].ComposeProject
+		groups[project] = append(groups[project], name)
+	}
+
+	projects := make([]string, 0, len(groups))
+	for project := range groups {
+		if project != "" {
+			projects = append(projects, project)
+		}
+	}
+	sort.Strings(projects)
+	if _, ok := groups[""]; ok {
+		projects = append(projects, "")
+	}
+
+	for _, project := range projects {
+		names := groups[project]
+
+		rollup := SeverityHealthy
+		for _, name := range names {
+			if s := ResultSeverity(results[name]); s > rollup {
+				rollup = s
+			}
+		}
+
+		title := "📦 " + project
+		if project == "" {
+			title = "Standalone containers"
+		}
+		fmt.Fprintf(sb, "### %s — %s\n\n", title, statusForSeverity(rollup))
+		writeServiceRows(sb, results, names)
+		sb.WriteString("\n")
+	}
+}
+
+// determineServiceStatus returns an emoji status indicator for an analysis result.
+func determineServiceStatus(res *chunking.AnalyzeResult) string {
+	return statusForSeverity(ResultSeverity(res))
+}
+
+// statusForSeverity returns the emoji status indicator for a severity.
+func statusForSeverity(s Severity) string {
+	switch s {
+	case SeverityCritical:
 		return "🔴 Issues"
-	case hasWarnings(analysis):
+	case SeverityWarning:
 		return "🟡 Warning"
 	default:
 		return "🟢 OK"
@@ -116,7 +173,7 @@ func writeCriticalIssuesSection(sb *strings.Builder, results map[string]*chunkin
 
 	for _, name := range sortedKeys {
 		res := results[name]
-		if hasIssues(res.Analysis) {
+		if ResultSeverity(res) == SeverityCritical {
 			hasCritical = true
 			fmt.Fprintf(sb, "### %s\n", name)
 			sb.WriteString(extractErrors(res.Analysis))
@@ -0,0 +1,211 @@
+package knowledge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/sanitize"
+)
+
+// kbHeaderPrefix starts the first line of every service knowledge base file.
+const kbHeaderPrefix = "# Knowledge Base: "
+
+// Status filter values accepted by SearchOptions.Status
+const (
+	StatusFilterHealthy  = "healthy"
+	StatusFilterWarnings = "warnings"
+	StatusFilterIssues   = "issues"
+)
+
+// Entry is a single "### Scan:" block from a service knowledge base file.
+type Entry struct {
+	ContainerName string
+	Timestamp     time.Time
+	Status        string // Status marker as written, e.g. "🔴 Issues Detected"
+	Content       string // Full entry text including header lines
+}
+
+// SearchOptions controls which knowledge base entries Search returns.
+type SearchOptions struct {
+	Query     string    // Case-insensitive substring to match in the entry text (empty matches all)
+	Status    string    // Optional status filter: healthy, warnings or issues
+	Since     time.Time // Only entries at or after this time (zero = no limit)
+	Container string    // Optional container name; restricts the search to its KB file
+}
+
+// Search scans the service knowledge base files in kbDir and returns matching
+// entries sorted newest-first.
+func Search(kbDir string, opts SearchOptions) ([]Entry, error) {
+	statusMarker, err := statusMarkerFor(opts.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := serviceFiles(kbDir, opts.Container)
+	if err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(opts.Query)
+
+	var matches []Entry
+	for _, file := range files {
+		_, entries, err := ReadServiceEntries(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if statusMarker != "" && entry.Status != statusMarker {
+				continue
+			}
+			if !opts.Since.IsZero() && entry.Timestamp.Before(opts.Since) {
+				continue
+			}
+			if query != "" && !strings.Contains(strings.ToLower(entry.Content), query) {
+				continue
+			}
+			matches = append(matches, entry)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Timestamp.After(matches[j].Timestamp)
+	})
+
+	return matches, nil
+}
+
+// ReadServiceEntries reads a service knowledge base file in either format and returns
+// the container name together with all scan entries in file order.
+func ReadServiceEntries(file string) (string, []Entry, error) {
+	if filepath.Ext(file) == ".json" {
+		entries, err := readJSONFile(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return "", nil, fmt.Errorf("failed to read KB file %s: %w", file, err)
+			}
+			return "", nil, err
+		}
+		containerName := sanitize.Original(strings.TrimSuffix(filepath.Base(file), ".json"))
+		return containerName, jsonToEntries(containerName, entries), nil
+	}
+
+	data, err := os.ReadFile(file) //nolint:gosec // path is constructed from config dir + sanitized container name via internal/sanitize
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read KB file %s: %w", file, err)
+	}
+
+	containerName := containerNameFromKB(file, string(data))
+	return containerName, parseEntries(containerName, string(data)), nil
+}
+
+// statusMarkerFor maps a status filter value to the marker written by UpdateServiceKB.
+func statusMarkerFor(status string) (string, error) {
+	switch strings.ToLower(status) {
+	case "":
+		return "", nil
+	case StatusFilterHealthy:
+		return statusHealthy, nil
+	case StatusFilterWarnings:
+		return statusWarnings, nil
+	case StatusFilterIssues:
+		return statusIssuesDetected, nil
+	default:
+		return "", fmt.Errorf("invalid status filter %q (expected %s, %s or %s)",
+			status, StatusFilterHealthy, StatusFilterWarnings, StatusFilterIssues)
+	}
+}
+
+// ServiceFileExtensions are the knowledge base file extensions, one per
+// output.knowledge_format. A container may have both after the format was changed.
+var ServiceFileExtensions = []string{".md", ".json"}
+
+// serviceFiles lists the knowledge base files to search, optionally restricted to one container.
+func serviceFiles(kbDir, container string) ([]string, error) {
+	servicesDir := filepath.Join(kbDir, "services")
+
+	var files []string
+	for _, ext := range ServiceFileExtensions {
+		if container != "" {
+			file := serviceFilePath(servicesDir, container, ext)
+			if legacy := legacyServiceFile(servicesDir, container, ext); legacy != "" {
+				file = legacy
+			}
+			if _, err := os.Stat(file); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to access KB file %s: %w", file, err)
+			}
+			files = append(files, file)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(servicesDir, "*"+ext))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list KB files in %s: %w", servicesDir, err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// containerNameFromKB returns the container name from the file header, falling back
+// to the container name encoded in the file name for files without a header.
+func containerNameFromKB(file, content string) string {
+	firstLine, _, _ := strings.Cut(content, "\n")
+	if strings.HasPrefix(firstLine, kbHeaderPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(firstLine, kbHeaderPrefix))
+	}
+
+	return sanitize.Original(strings.TrimSuffix(filepath.Base(file), ".md"))
+}
+
+// parseEntries splits a service knowledge base file into its scan entries.
+// Entries without a parseable "### Scan:" timestamp are skipped.
+func parseEntries(containerName, content string) []Entry {
+	const headerMarker = "## Service History\n"
+
+	if idx := strings.Index(content, headerMarker); idx != -1 {
+		content = content[idx+len(headerMarker):]
+	}
+
+	var entries []Entry
+	for _, block := range strings.Split(content, "---\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+
+		timestamp, err := time.Parse(time.RFC3339, extractEntryTimestamp(block))
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, Entry{
+			ContainerName: containerName,
+			Timestamp:     timestamp,
+			Status:        extractEntryStatus(block),
+			Content:       strings.TrimSpace(block),
+		})
+	}
+
+	return entries
+}
+
+// extractEntryStatus returns the status marker from an entry's "**Status:**" line.
+func extractEntryStatus(entry string) string {
+	for _, line := range strings.Split(entry, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "**Status:**") {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, "**Status:**"))
+		}
+	}
+	return ""
+}
@@ -1,22 +1,31 @@
 package knowledge
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/zorak1103/dlia/internal/chunking"
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/fsutil"
 	"github.com/zorak1103/dlia/internal/sanitize"
 )
 
 const (
 	statusHealthy        = "🟢 Healthy"
+	statusWarnings       = "🟡 Warnings"
 	statusIssuesDetected = "🔴 Issues Detected"
 )
 
+// lastSeenPrefix starts the line recording when an unchanged entry was last confirmed
+// (output.knowledge_skip_unchanged).
+const lastSeenPrefix = "**Last Seen:**"
+
 // UpdateServiceKB appends analysis results to the container's knowledge base file.
 func UpdateServiceKB(containerName string, analysis *chunking.AnalyzeResult, cfg *config.Config) error {
 	kbDir := filepath.Join(cfg.Output.KnowledgeBaseDir, "services")
@@ -24,23 +33,20 @@ func UpdateServiceKB(containerName string, analysis *chunking.AnalyzeResult, cfg
 		return fmt.Errorf("failed to create KB services directory: %w", err)
 	}
 
-	filePath := filepath.Clean(filepath.Join(kbDir, sanitize.Name(containerName)+".md"))
-
-	// Determine status based on analysis content (simple heuristic)
-	status := statusHealthy
-	if strings.Contains(strings.ToLower(analysis.Analysis), "critical") ||
-		strings.Contains(strings.ToLower(analysis.Analysis), "error") {
-		status = statusIssuesDetected
-	} else if strings.Contains(strings.ToLower(analysis.Analysis), "warning") {
-		status = "🟡 Warnings"
+	if cfg.Output.KnowledgeFormat == "json" {
+		return updateServiceKBJSON(serviceFilePath(kbDir, containerName, ".json"), analysis, cfg)
 	}
 
-	timestamp := time.Now().Format(time.RFC3339)
+	filePath := migrateServiceFile(kbDir, containerName, ".md")
+
+	status := serviceStatus(analysis)
+	timestamp := time.Now().In(cfg.DisplayLocation()).Format(time.RFC3339)
+	entryAnalysis := chunking.TruncateAnalysis(analysis.Analysis, cfg.Output.MaxReportBytes)
 
 	// Prepare new entry
 	newEntry := fmt.Sprintf("\n### Scan: %s\n", timestamp)
 	newEntry += fmt.Sprintf("**Status:** %s\n\n", status)
-	newEntry += analysis.Analysis + "\n\n"
+	newEntry += entryAnalysis + "\n\n"
 	newEntry += "---\n"
 
 	// Read existing file or create header
@@ -57,17 +63,88 @@ func UpdateServiceKB(containerName string, analysis *chunking.AnalyzeResult, cfg
 	retentionDuration := time.Duration(cfg.Output.KnowledgeRetentionDays) * 24 * time.Hour
 	content = pruneEntries(content, retentionDuration)
 
+	// Confirm an unchanged newest entry instead of appending a duplicate
+	touched := false
+	if cfg.Output.KnowledgeSkipUnchanged {
+		content, touched = touchLatestEntry(content, status, entryAnalysis, timestamp)
+	}
+
 	// Append new entry
-	content += newEntry
+	if !touched {
+		content += newEntry
+	}
+
+	// Cap the number of entries (applied after the time-based prune)
+	content = capEntries(content, cfg.Output.KnowledgeMaxEntries)
 
-	// Write back
-	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil { //nolint:gosec // path is constructed from config dir + sanitized container name via internal/sanitize
+	// Write back atomically so an interrupted append cannot corrupt the KB
+	if err := fsutil.WriteFileAtomic(filePath, []byte(content), 0o600); err != nil {
 		return fmt.Errorf("failed to write KB file: %w", err)
 	}
 
 	return nil
 }
 
+// serviceFilePath returns the KB file of containerName with extension ext in servicesDir.
+func serviceFilePath(servicesDir, containerName, ext string) string {
+	return filepath.Clean(filepath.Join(servicesDir, sanitize.Name(containerName)+ext))
+}
+
+// legacyServiceFile returns the Markdown KB file older versions wrote for containerName
+// ("/" mapped to "_") while the container has no file under its current name, or ""
+// if there is none. Only a file whose header names containerName qualifies, so
+// "org/app" never takes over the history of a container named "org_app". JSON files
+// have no header and are never matched.
+func legacyServiceFile(servicesDir, containerName, ext string) string {
+	path := serviceFilePath(servicesDir, containerName, ext)
+	legacy := filepath.Clean(filepath.Join(servicesDir, sanitize.LegacyName(containerName)+ext))
+	if ext != ".md" || legacy == path {
+		return ""
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return ""
+	}
+
+	// Path is safe: constructed from config dir + sanitized container name
+	f, err := os.Open(legacy)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+	header, _ := bufio.NewReader(f).ReadString('\n')
+	if strings.TrimSpace(header) != "# Knowledge Base: "+containerName {
+		return ""
+	}
+	return legacy
+}
+
+// migrateServiceFile returns the KB file UpdateServiceKB writes for containerName,
+// first renaming its legacy file (see legacyServiceFile) so the container keeps its
+// history. If the rename fails, the legacy file is used.
+func migrateServiceFile(servicesDir, containerName, ext string) string {
+	path := serviceFilePath(servicesDir, containerName, ext)
+	legacy := legacyServiceFile(servicesDir, containerName, ext)
+	if legacy == "" {
+		return path
+	}
+	if err := os.Rename(legacy, path); err != nil {
+		return legacy
+	}
+	return path
+}
+
+// serviceStatus determines the status marker for an analysis result (see ResultSeverity).
+func serviceStatus(analysis *chunking.AnalyzeResult) string {
+	switch ResultSeverity(analysis) {
+	case SeverityCritical:
+		return statusIssuesDetected
+	case SeverityWarning:
+		return statusWarnings
+	default:
+		return statusHealthy
+	}
+}
+
 func pruneEntries(content string, retention time.Duration) string {
 	const headerMarker = "## Service History\n"
 
@@ -97,8 +174,138 @@ func pruneEntries(content string, retention time.Duration) string {
 	return builder.String()
 }
 
+// capEntries keeps only the newest maxEntries scan entries. Entries whose timestamp
+// cannot be parsed are always preserved and do not count towards the cap.
+// A maxEntries of 0 or less disables the cap.
+func capEntries(content string, maxEntries int) string {
+	const headerMarker = "## Service History\n"
+
+	if maxEntries <= 0 {
+		return content
+	}
+
+	headerEnd := strings.Index(content, headerMarker)
+	if headerEnd == -1 {
+		return content
+	}
+
+	headerSection := content[:headerEnd+len(headerMarker)]
+	entriesSection := content[headerEnd+len(headerMarker):]
+
+	var entries []string
+	var timestamps []time.Time
+	for _, entry := range strings.Split(entriesSection, "---\n") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		entries = append(entries, entry)
+
+		if t, err := time.Parse(time.RFC3339, extractEntryTimestamp(entry)); err == nil {
+			timestamps = append(timestamps, t)
+		}
+	}
+
+	if len(timestamps) <= maxEntries {
+		return content
+	}
+
+	// Entries older than the newest maxEntries timestamps are dropped
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].After(timestamps[j]) })
+	oldestKept := timestamps[maxEntries-1]
+	budget := maxEntries
+
+	// Walk newest-first so ties at the boundary keep the most recently appended entries
+	keep := make([]bool, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		t, err := time.Parse(time.RFC3339, extractEntryTimestamp(entries[i]))
+		switch {
+		case err != nil:
+			keep[i] = true
+		case budget > 0 && !t.Before(oldestKept):
+			keep[i] = true
+			budget--
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(headerSection)
+
+	for i, entry := range entries {
+		if keep[i] {
+			builder.WriteString(entry)
+			builder.WriteString("---\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// touchLatestEntry sets the "**Last Seen:**" line of the newest entry to timestamp when
+// that entry has the given status and analysis. Reports whether it matched.
+func touchLatestEntry(content, status, analysis, timestamp string) (string, bool) {
+	const headerMarker = "## Service History\n"
+
+	headerEnd := strings.Index(content, headerMarker)
+	if headerEnd == -1 {
+		return content, false
+	}
+
+	headerSection := content[:headerEnd+len(headerMarker)]
+	entriesSection := content[headerEnd+len(headerMarker):]
+
+	var entries []string
+	for _, entry := range strings.Split(entriesSection, "---\n") {
+		if strings.TrimSpace(entry) != "" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return content, false
+	}
+
+	latest := entries[len(entries)-1]
+	if extractEntryStatus(latest) != status || (Entry{Content: latest}).Analysis() != strings.TrimSpace(analysis) {
+		return content, false
+	}
+	entries[len(entries)-1] = setEntryLastSeen(latest, timestamp)
+
+	var builder strings.Builder
+	builder.WriteString(headerSection)
+	for _, entry := range entries {
+		builder.WriteString(entry)
+		builder.WriteString("---\n")
+	}
+
+	return builder.String(), true
+}
+
+// setEntryLastSeen replaces the "**Last Seen:**" line of entry, or adds one below
+// its "**Status:**" line.
+func setEntryLastSeen(entry, timestamp string) string {
+	lastSeen := lastSeenPrefix + " " + timestamp
+
+	lines := strings.Split(entry, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), lastSeenPrefix) {
+			lines[i] = lastSeen
+			return strings.Join(lines, "\n")
+		}
+	}
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "**Status:**") {
+			lines = slices.Insert(lines, i+1, lastSeen)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isEntryExpired reports whether entry was last seen, or else scanned, before cutoff.
 func isEntryExpired(entry string, cutoff time.Time) bool {
-	timestamp := extractEntryTimestamp(entry)
+	timestamp := extractEntryLastSeen(entry)
+	if timestamp == "" {
+		timestamp = extractEntryTimestamp(entry)
+	}
 	if timestamp == "" {
 		return false
 	}
@@ -120,3 +327,15 @@ func extractEntryTimestamp(entry string) string {
 	}
 	return ""
 }
+
+// extractEntryLastSeen returns the "**Last Seen:**" timestamp of entry, or "" if it
+// has none.
+func extractEntryLastSeen(entry string) string {
+	for _, line := range strings.Split(entry, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, lastSeenPrefix) {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, lastSeenPrefix))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/chunking"
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/fsutil"
+)
+
+// jsonEntry is one scan entry of a JSON knowledge base file (output.knowledge_format: json).
+// The file holds a JSON array of these, oldest first.
+type jsonEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Status    string    `json:"status"`
+	Analysis  string    `json:"analysis"`
+	Tokens    int       `json:"tokens"`
+	// SkipReason says why the LLM was not called (e.g. "below_min_log_lines"); empty when it was
+	SkipReason string `json:"skip_reason,omitempty"`
+	// LastSeen is when a later scan last found the same status and analysis
+	// (output.knowledge_skip_unchanged); zero when no later scan matched
+	LastSeen time.Time `json:"last_seen,omitzero"`
+}
+
+// updateServiceKBJSON appends an entry to a JSON knowledge base file, applying the
+// same retention and entry cap as the Markdown format.
+func updateServiceKBJSON(filePath string, analysis *chunking.AnalyzeResult, cfg *config.Config) error {
+	entries, err := readJSONFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	retentionDuration := time.Duration(cfg.Output.KnowledgeRetentionDays) * 24 * time.Hour
+	entries = pruneJSONEntries(entries, retentionDuration)
+
+	entry := jsonEntry{
+		Timestamp:  time.Now().Truncate(time.Second),
+		Status:     serviceStatus(analysis),
+		Analysis:   chunking.TruncateAnalysis(analysis.Analysis, cfg.Output.MaxReportBytes),
+		Tokens:     analysis.TokensUsed,
+		SkipReason: analysis.SkipReason,
+	}
+	if latest := len(entries) - 1; cfg.Output.KnowledgeSkipUnchanged && latest >= 0 && sameJSONEntry(entries[latest], entry) {
+		// Confirm the unchanged newest entry instead of appending a duplicate
+		entries[latest].LastSeen = entry.Timestamp
+	} else {
+		entries = append(entries, entry)
+	}
+	entries = capJSONEntries(entries, cfg.Output.KnowledgeMaxEntries)
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode KB file: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(filePath, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write KB file: %w", err)
+	}
+
+	return nil
+}
+
+// sameJSONEntry reports whether b has the status and analysis of a.
+func sameJSONEntry(a, b jsonEntry) bool {
+	return a.Status == b.Status && a.SkipReason == b.SkipReason &&
+		strings.TrimSpace(a.Analysis) == strings.TrimSpace(b.Analysis)
+}
+
+// pruneJSONEntries drops entries last seen, or else scanned, before retention.
+func pruneJSONEntries(entries []jsonEntry, retention time.Duration) []jsonEntry {
+	cutoff := time.Now().Add(-retention)
+	kept := entries[:0]
+	for _, entry := range entries {
+		seen := entry.Timestamp
+		if !entry.LastSeen.IsZero() {
+			seen = entry.LastSeen
+		}
+		if !seen.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// capJSONEntries keeps only the newest maxEntries entries; 0 or less disables the cap.
+// Entries with equal timestamps keep their file order.
+func capJSONEntries(entries []jsonEntry, maxEntries int) []jsonEntry {
+	if maxEntries <= 0 || len(entries) <= maxEntries {
+		return entries
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries[len(entries)-maxEntries:]
+}
+
+// readJSONFile reads the entries of a JSON knowledge base file. A missing file
+// returns an error satisfying os.IsNotExist.
+func readJSONFile(filePath string) ([]jsonEntry, error) {
+	data, err := os.ReadFile(filePath) //nolint:gosec // path is constructed from config dir + sanitized container name via internal/sanitize
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []jsonEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse KB file %s: %w", filePath, err)
+	}
+	return entries, nil
+}
+
+// jsonToEntries converts JSON entries to Entry values. Content is rendered like a
+// Markdown entry so searching and Entry.Analysis work the same for both formats.
+func jsonToEntries(containerName string, entries []jsonEntry) []Entry {
+	result := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		header := fmt.Sprintf("### Scan: %s\n**Status:** %s\n", e.Timestamp.Format(time.RFC3339), e.Status)
+		if !e.LastSeen.IsZero() {
+			header += fmt.Sprintf("%s %s\n", lastSeenPrefix, e.LastSeen.Format(time.RFC3339))
+		}
+		result = append(result, Entry{
+			ContainerName: containerName,
+			Timestamp:     e.Timestamp,
+			Status:        e.Status,
+			Content:       header + "\n" + e.Analysis,
+		})
+	}
+	return result
+}
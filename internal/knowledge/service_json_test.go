@@ -0,0 +1,217 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/chunking"
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+func jsonKBConfig(dir string) *config.Config {
+	return &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir:       dir,
+			KnowledgeRetentionDays: 30,
+			KnowledgeFormat:        "json",
+		},
+	}
+}
+
+func TestUpdateServiceKB_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := jsonKBConfig(tmpDir)
+
+	if err := UpdateServiceKB("web", &chunking.AnalyzeResult{Analysis: "All good", TokensUsed: 42}, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB() error = %v", err)
+	}
+	if err := UpdateServiceKB("web", &chunking.AnalyzeResult{Analysis: "Critical error in handler", TokensUsed: 7}, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB() error = %v", err)
+	}
+
+	filePath := filepath.Join(tmpDir, "services", "web.json")
+	if _, err := os.Stat(filepath.Join(tmpDir, "services", "web.md")); !os.IsNotExist(err) {
+		t.Error("JSON format should not write a Markdown file")
+	}
+
+	entries, err := readJSONFile(filePath)
+	if err != nil {
+		t.Fatalf("readJSONFile() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Status != statusHealthy || entries[0].Tokens != 42 {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Status != statusIssuesDetected || entries[1].Analysis != "Critical error in handler" {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+
+	data, err := os.ReadFile(filePath) //nolint:gosec // test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw []map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("KB file is not a JSON array: %v", err)
+	}
+	for _, key := range []string{"timestamp", "status", "analysis", "tokens"} {
+		if _, ok := raw[0][key]; !ok {
+			t.Errorf("entry is missing %q field", key)
+		}
+	}
+}
+
+func TestUpdateServiceKB_JSONSkipReason(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := jsonKBConfig(tmpDir)
+
+	skipped := &chunking.AnalyzeResult{
+		Analysis:   chunking.BelowThresholdAnalysis,
+		LLMSkipped: true,
+		SkipReason: chunking.SkipReasonBelowThreshold,
+	}
+	if err := UpdateServiceKB("web", skipped, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB() error = %v", err)
+	}
+	if err := UpdateServiceKB("web", &chunking.AnalyzeResult{Analysis: "All good", TokensUsed: 42}, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "services", "web.json")) //nolint:gosec // test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw []map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("KB file is not a JSON array: %v", err)
+	}
+	if raw[0]["skip_reason"] != chunking.SkipReasonBelowThreshold {
+		t.Errorf("skip_reason = %v, want %q", raw[0]["skip_reason"], chunking.SkipReasonBelowThreshold)
+	}
+	if _, ok := raw[1]["skip_reason"]; ok {
+		t.Error("skip_reason must be omitted when the LLM was called")
+	}
+}
+
+func TestUpdateServiceKB_JSONRetentionAndCap(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := jsonKBConfig(tmpDir)
+	cfg.Output.KnowledgeMaxEntries = 2
+
+	servicesDir := filepath.Join(tmpDir, "services")
+	if err := os.MkdirAll(servicesDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Truncate(time.Second)
+	existing := []jsonEntry{
+		{Timestamp: now.Add(-60 * 24 * time.Hour), Status: statusHealthy, Analysis: "expired"},
+		{Timestamp: now.Add(-2 * time.Hour), Status: statusHealthy, Analysis: "older"},
+		{Timestamp: now.Add(-1 * time.Hour), Status: statusWarnings, Analysis: "recent"},
+	}
+	data, err := json.Marshal(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(servicesDir, "api.json")
+	if err := os.WriteFile(filePath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpdateServiceKB("api", &chunking.AnalyzeResult{Analysis: "newest"}, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB() error = %v", err)
+	}
+
+	entries, err := readJSONFile(filePath)
+	if err != nil {
+		t.Fatalf("readJSONFile() error = %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Analysis)
+	}
+	if strings.Join(got, ",") != "recent,newest" {
+		t.Errorf("entries = %v, want [recent newest]", got)
+	}
+}
+
+func TestUpdateServiceKB_JSONSkipUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := jsonKBConfig(tmpDir)
+	cfg.Output.KnowledgeSkipUnchanged = true
+
+	for _, analysis := range []string{"All good", "All good", "Critical error in handler"} {
+		if err := UpdateServiceKB("web", &chunking.AnalyzeResult{Analysis: analysis}, cfg); err != nil {
+			t.Fatalf("UpdateServiceKB() error = %v", err)
+		}
+	}
+
+	entries, err := readJSONFile(filepath.Join(tmpDir, "services", "web.json"))
+	if err != nil {
+		t.Fatalf("readJSONFile() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected unchanged scans merged into 2 entries, got %d", len(entries))
+	}
+	if entries[0].LastSeen.IsZero() {
+		t.Error("merged entry should record when it was last seen")
+	}
+	if !entries[1].LastSeen.IsZero() {
+		t.Error("changed entry should not have a last seen time")
+	}
+
+	converted := jsonToEntries("web", entries)
+	if got := converted[0].Analysis(); got != "All good" {
+		t.Errorf("Analysis() = %q, want %q", got, "All good")
+	}
+}
+
+func TestSearch_MixedFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	mdCfg := &config.Config{Output: config.OutputConfig{KnowledgeBaseDir: tmpDir, KnowledgeRetentionDays: 30}}
+	jsonCfg := jsonKBConfig(tmpDir)
+
+	if err := UpdateServiceKB("db", &chunking.AnalyzeResult{Analysis: "Warning: slow query"}, mdCfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateServiceKB("db", &chunking.AnalyzeResult{Analysis: "Critical error: disk full"}, jsonCfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateServiceKB("cache", &chunking.AnalyzeResult{Analysis: "Warning: evictions"}, jsonCfg); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Search(tmpDir, SearchOptions{Query: "warning"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 matching entries across formats, got %d", len(entries))
+	}
+
+	dbEntries, err := ContainerEntries(tmpDir, "db")
+	if err != nil {
+		t.Fatalf("ContainerEntries() error = %v", err)
+	}
+	if len(dbEntries) != 2 {
+		t.Fatalf("expected entries from both db files, got %d", len(dbEntries))
+	}
+	for _, e := range dbEntries {
+		if e.ContainerName != "db" {
+			t.Errorf("ContainerName = %q, want db", e.ContainerName)
+		}
+	}
+
+	jsonOnly, err := Search(tmpDir, SearchOptions{Container: "cache"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(jsonOnly) != 1 || jsonOnly[0].Analysis() != "Warning: evictions" {
+		t.Errorf("unexpected JSON entry: %+v", jsonOnly)
+	}
+}
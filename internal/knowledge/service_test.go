@@ -10,6 +10,7 @@ import (
 
 	"github.com/zorak1103/dlia/internal/chunking"
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/llm"
 	"github.com/zorak1103/dlia/internal/sanitize"
 )
 
@@ -355,22 +356,22 @@ func TestSanitizeName(t *testing.T) {
 		{
 			name:  "single slash",
 			input: "project/container",
-			want:  "project_container",
+			want:  "project~container",
 		},
 		{
 			name:  "multiple slashes",
 			input: "org/project/container",
-			want:  "org_project_container",
+			want:  "org~project~container",
 		},
 		{
 			name:  "trailing slash",
 			input: "container/",
-			want:  "container_",
+			want:  "container~",
 		},
 		{
 			name:  "leading slash",
 			input: "/container",
-			want:  "_container",
+			want:  "~container",
 		},
 		{
 			name:  "empty string",
@@ -389,6 +390,82 @@ func TestSanitizeName(t *testing.T) {
 	}
 }
 
+func TestUpdateServiceKB_MigratesLegacyFileName(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir:       tmpDir,
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	servicesDir := filepath.Join(tmpDir, "services")
+	if err := os.MkdirAll(servicesDir, 0o750); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	legacy := filepath.Join(servicesDir, "org_app.md")
+	old := "# Knowledge Base: org/app\n\n## Service History\n\n### Scan: " + time.Now().Format(time.RFC3339) + "\n**Status:** 🟢 Healthy\n\nOld scan.\n\n---\n"
+	if err := os.WriteFile(legacy, []byte(old), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := UpdateServiceKB("org/app", &chunking.AnalyzeResult{Analysis: "New scan."}, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB() error = %v", err)
+	}
+
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Expected legacy file to be renamed, stat error = %v", err)
+	}
+	// #nosec G304 - reading from controlled test temp directory
+	content, err := os.ReadFile(filepath.Join(servicesDir, sanitize.Name("org/app")+".md"))
+	if err != nil {
+		t.Fatalf("Failed to read KB file: %v", err)
+	}
+	if !strings.Contains(string(content), "Old scan.") || !strings.Contains(string(content), "New scan.") {
+		t.Errorf("Expected history to be kept after migration, got:\n%s", content)
+	}
+}
+
+func TestUpdateServiceKB_KeepsSimilarNamesApart(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir:       tmpDir,
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	// org_app is a container of its own; its file must not become the history of org/app
+	writeTestKB(t, tmpDir, "org_app.md", "# Knowledge Base: org_app\n\n## Service History\n\n### Scan: "+
+		time.Now().Format(time.RFC3339)+"\n**Status:** 🟢 Healthy\n\nUnderscore scan.\n\n---\n")
+
+	if err := UpdateServiceKB("org/app", &chunking.AnalyzeResult{Analysis: "Slash scan."}, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB(org/app) error = %v", err)
+	}
+	if err := UpdateServiceKB("org_app", &chunking.AnalyzeResult{Analysis: "Second underscore scan."}, cfg); err != nil {
+		t.Fatalf("UpdateServiceKB(org_app) error = %v", err)
+	}
+
+	servicesDir := filepath.Join(tmpDir, "services")
+	// #nosec G304 - reading from controlled test temp directory
+	underscore, err := os.ReadFile(filepath.Join(servicesDir, "org_app.md"))
+	if err != nil {
+		t.Fatalf("Failed to read org_app KB file: %v", err)
+	}
+	if !strings.Contains(string(underscore), "Underscore scan.") || !strings.Contains(string(underscore), "Second underscore scan.") ||
+		strings.Contains(string(underscore), "Slash scan.") {
+		t.Errorf("org_app KB file mixes histories:\n%s", underscore)
+	}
+	// #nosec G304 - reading from controlled test temp directory
+	slash, err := os.ReadFile(filepath.Join(servicesDir, sanitize.Name("org/app")+".md"))
+	if err != nil {
+		t.Fatalf("Failed to read org/app KB file: %v", err)
+	}
+	if !strings.Contains(string(slash), "Slash scan.") || strings.Contains(string(slash), "Underscore scan.") {
+		t.Errorf("org/app KB file mixes histories:\n%s", slash)
+	}
+}
+
 func TestUpdateServiceKB_CreateDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -1115,3 +1192,549 @@ func BenchmarkUpdateGlobalSummary(b *testing.B) {
 		_ = UpdateGlobalSummary(results, cfg)
 	}
 }
+
+func TestClassifySeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis string
+		want     Severity
+	}{
+		{"healthy", "All systems operational", SeverityHealthy},
+		{"warning", "Warning: high memory", SeverityWarning},
+		{"error", "Error occurred", SeverityCritical},
+		{"critical beats warning", "Warning and CRITICAL failure", SeverityCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifySeverity(tt.analysis); got != tt.want {
+				t.Errorf("ClassifySeverity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultSeverity(t *testing.T) {
+	healthyText := "**Summary**: All services running normally\n**Errors**: None\n**Recommendations**: no errors to address"
+
+	tests := []struct {
+		name   string
+		result *chunking.AnalyzeResult
+		want   Severity
+	}{
+		{"structured healthy mentioning errors", &chunking.AnalyzeResult{
+			Analysis:   healthyText,
+			Structured: &llm.StructuredAnalysis{Severity: "healthy"},
+		}, SeverityHealthy},
+		{"structured warning", &chunking.AnalyzeResult{
+			Analysis:   "All good",
+			Structured: &llm.StructuredAnalysis{Severity: "Warning"},
+		}, SeverityWarning},
+		{"free text falls back to keywords", &chunking.AnalyzeResult{Analysis: healthyText}, SeverityCritical},
+		{"unknown structured severity falls back to keywords", &chunking.AnalyzeResult{
+			Analysis:   "Warning: slow queries",
+			Structured: &llm.StructuredAnalysis{Severity: "meh"},
+		}, SeverityWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResultSeverity(tt.result); got != tt.want {
+				t.Errorf("ResultSeverity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxSeverity(t *testing.T) {
+	if got := MaxSeverity(nil); got != SeverityHealthy {
+		t.Errorf("MaxSeverity(nil) = %v, want %v", got, SeverityHealthy)
+	}
+
+	analyses := map[string]string{
+		"web":   "All good",
+		"db":    "Warning: slow queries",
+		"cache": "Running smoothly",
+	}
+	if got := MaxSeverity(analyses); got != SeverityWarning {
+		t.Errorf("MaxSeverity() = %v, want %v", got, SeverityWarning)
+	}
+
+	analyses["api"] = "Critical: out of memory"
+	if got := MaxSeverity(analyses); got != SeverityCritical {
+		t.Errorf("MaxSeverity() = %v, want %v", got, SeverityCritical)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Severity
+		wantErr bool
+	}{
+		{"", SeverityHealthy, false},
+		{"healthy", SeverityHealthy, false},
+		{"Warning", SeverityWarning, false},
+		{" critical ", SeverityCritical, false},
+		{"fatal", SeverityHealthy, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSeverity(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSeverity(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSeverity(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, s := range []Severity{SeverityHealthy, SeverityWarning, SeverityCritical} {
+		if parsed, err := ParseSeverity(s.String()); err != nil || parsed != s {
+			t.Errorf("ParseSeverity(%q) did not round-trip to %v", s.String(), s)
+		}
+	}
+}
+
+func writeTestKB(t *testing.T, kbDir, fileName, content string) {
+	t.Helper()
+
+	servicesDir := filepath.Join(kbDir, "services")
+	if err := os.MkdirAll(servicesDir, 0o750); err != nil {
+		t.Fatalf("Failed to create services dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(servicesDir, fileName), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write KB file: %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	kbDir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entry := func(ts time.Time, status, body string) string {
+		return fmt.Sprintf("\n### Scan: %s\n**Status:** %s\n\n%s\n\n---\n", ts.Format(time.RFC3339), status, body)
+	}
+
+	writeTestKB(t, kbDir, "my~app.md", "# Knowledge Base: my/app\n\n## Service History\n"+
+		entry(now.Add(-48*time.Hour), statusIssuesDetected, "Error: connection refused to db")+
+		entry(now.Add(-1*time.Hour), statusWarnings, "Warning: slow connection to cache"))
+	writeTestKB(t, kbDir, "web.md", "# Knowledge Base: web\n\n## Service History\n"+
+		entry(now.Add(-2*time.Hour), statusHealthy, "All good, connection pool stable"))
+
+	t.Run("query matches newest first", func(t *testing.T) {
+		entries, err := Search(kbDir, SearchOptions{Query: "CONNECTION"})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(entries) != 3 {
+			t.Fatalf("Search() returned %d entries, want 3", len(entries))
+		}
+		if entries[0].ContainerName != "my/app" || entries[0].Status != statusWarnings {
+			t.Errorf("First entry = %s/%s, want newest my/app warning", entries[0].ContainerName, entries[0].Status)
+		}
+		if entries[1].ContainerName != "web" || entries[2].Status != statusIssuesDetected {
+			t.Errorf("Entries not sorted newest-first: %+v", entries)
+		}
+	})
+
+	t.Run("status filter", func(t *testing.T) {
+		entries, err := Search(kbDir, SearchOptions{Status: StatusFilterIssues})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(entries) != 1 || entries[0].Status != statusIssuesDetected {
+			t.Errorf("Search(status=issues) = %+v, want the single issues entry", entries)
+		}
+	})
+
+	t.Run("since filter", func(t *testing.T) {
+		entries, err := Search(kbDir, SearchOptions{Since: now.Add(-24 * time.Hour)})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(entries) != 2 {
+			t.Errorf("Search(since=24h) returned %d entries, want 2", len(entries))
+		}
+	})
+
+	t.Run("container filter uses sanitized name", func(t *testing.T) {
+		entries, err := Search(kbDir, SearchOptions{Container: "my/app"})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(entries) != 2 {
+			t.Errorf("Search(container=my/app) returned %d entries, want 2", len(entries))
+		}
+
+		entries, err = Search(kbDir, SearchOptions{Container: "missing"})
+		if err != nil || len(entries) != 0 {
+			t.Errorf("Search(container=missing) = %v, %v; want no entries", entries, err)
+		}
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		if _, err := Search(kbDir, SearchOptions{Status: "broken"}); err == nil {
+			t.Error("Search() expected error for invalid status filter")
+		}
+	})
+}
+
+func TestSearch_LegacyFileIsNotRenamed(t *testing.T) {
+	kbDir := t.TempDir()
+	writeTestKB(t, kbDir, "org_app.md", "# Knowledge Base: org/app\n\n## Service History\n\n### Scan: "+
+		time.Now().UTC().Format(time.RFC3339)+"\n**Status:** 🟢 Healthy\n\nOld scan.\n\n---\n")
+
+	entries, err := Search(kbDir, SearchOptions{Container: "org/app"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Search() returned %d entries, want the legacy file's 1", len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(kbDir, "services", "org_app.md")); err != nil {
+		t.Errorf("Search must not rename the legacy file, stat error = %v", err)
+	}
+
+	entries, err = Search(kbDir, SearchOptions{Container: "org/other"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Search() returned %d entries for a container without a KB file", len(entries))
+	}
+}
+
+func TestSearch_EmptyKnowledgeBase(t *testing.T) {
+	entries, err := Search(t.TempDir(), SearchOptions{Query: "anything"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Search() on empty KB returned %d entries", len(entries))
+	}
+}
+
+func TestCapEntries(t *testing.T) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	buildKB := func(headers ...string) string {
+		var sb strings.Builder
+		sb.WriteString("# Knowledge Base: test-container\n\n## Service History\n")
+		for i, h := range headers {
+			fmt.Fprintf(&sb, "\n### Scan: %s\n**Status:** 🟢 Healthy\n\nEntry %d\n\n---\n", h, i)
+		}
+		return sb.String()
+	}
+	ts := func(minutes int) string {
+		return base.Add(time.Duration(minutes) * time.Minute).Format(time.RFC3339)
+	}
+
+	t.Run("keeps newest entries", func(t *testing.T) {
+		content := buildKB(ts(0), ts(1), ts(2), ts(3), ts(4))
+		result := capEntries(content, 2)
+
+		if got := strings.Count(result, "### Scan:"); got != 2 {
+			t.Fatalf("Expected 2 entries, got %d", got)
+		}
+		if !strings.Contains(result, "Entry 3") || !strings.Contains(result, "Entry 4") {
+			t.Errorf("Expected newest entries to be kept, got:\n%s", result)
+		}
+		if !strings.HasPrefix(result, "# Knowledge Base: test-container\n\n## Service History\n") {
+			t.Error("Header should be preserved")
+		}
+	})
+
+	t.Run("zero means unlimited", func(t *testing.T) {
+		content := buildKB(ts(0), ts(1), ts(2))
+		if result := capEntries(content, 0); result != content {
+			t.Error("Expected content unchanged with cap 0")
+		}
+	})
+
+	t.Run("under cap unchanged", func(t *testing.T) {
+		content := buildKB(ts(0), ts(1))
+		if result := capEntries(content, 5); result != content {
+			t.Error("Expected content unchanged when under cap")
+		}
+	})
+
+	t.Run("malformed timestamps preserved and not counted", func(t *testing.T) {
+		content := buildKB("invalid-timestamp", ts(0), ts(1), ts(2))
+		result := capEntries(content, 1)
+
+		if got := strings.Count(result, "### Scan:"); got != 2 {
+			t.Fatalf("Expected 2 entries (1 malformed + 1 newest), got %d", got)
+		}
+		if !strings.Contains(result, "invalid-timestamp") || !strings.Contains(result, "Entry 3") {
+			t.Errorf("Expected malformed and newest entries to be kept, got:\n%s", result)
+		}
+	})
+}
+
+func TestUpdateServiceKB_MaxEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir:       tmpDir,
+			KnowledgeRetentionDays: 30,
+			KnowledgeMaxEntries:    2,
+		},
+	}
+
+	for i := 0; i < 4; i++ {
+		result := &chunking.AnalyzeResult{Analysis: fmt.Sprintf("Analysis %d", i)}
+		if err := UpdateServiceKB("capped", result, cfg); err != nil {
+			t.Fatalf("UpdateServiceKB() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "services", "capped.md"))
+	if err != nil {
+		t.Fatalf("Failed to read KB file: %v", err)
+	}
+
+	content := string(data)
+	if got := strings.Count(content, "### Scan:"); got != 2 {
+		t.Errorf("Expected KB capped at 2 entries, got %d", got)
+	}
+	if !strings.Contains(content, "Analysis 3") {
+		t.Error("Expected newest entry to be kept")
+	}
+}
+
+func TestUpdateServiceKB_MaxReportBytes(t *testing.T) {
+	for _, format := range []string{"md", "json"} {
+		t.Run(format, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &config.Config{
+				Output: config.OutputConfig{
+					KnowledgeBaseDir:       tmpDir,
+					KnowledgeRetentionDays: 30,
+					KnowledgeFormat:        format,
+					MaxReportBytes:         500,
+				},
+			}
+
+			result := &chunking.AnalyzeResult{Analysis: strings.Repeat("x", 5000) + "\nCritical: disk full"}
+			if err := UpdateServiceKB("big", result, cfg); err != nil {
+				t.Fatalf("UpdateServiceKB() error = %v", err)
+			}
+
+			_, entries, err := ReadServiceEntries(filepath.Join(tmpDir, "services", "big."+format))
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("ReadServiceEntries() = %d entries, %v", len(entries), err)
+			}
+			analysis := entries[0].Analysis()
+			if len(analysis) > cfg.Output.MaxReportBytes || !strings.HasSuffix(analysis, "[truncated]") {
+				t.Errorf("Expected the entry analysis capped at %d bytes with a marker, got %d bytes", cfg.Output.MaxReportBytes, len(analysis))
+			}
+			if got := extractEntryStatus(entries[0].Content); got != "🔴 Issues Detected" {
+				t.Errorf("Status = %q, want it detected from the full analysis", got)
+			}
+		})
+	}
+}
+
+func TestUpdateServiceKB_SkipUnchanged(t *testing.T) {
+	for _, skip := range []bool{false, true} {
+		t.Run(fmt.Sprintf("skip_unchanged=%t", skip), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &config.Config{
+				Output: config.OutputConfig{
+					KnowledgeBaseDir:       tmpDir,
+					KnowledgeRetentionDays: 30,
+					KnowledgeSkipUnchanged: skip,
+				},
+			}
+
+			for _, analysis := range []string{"All requests served", "All requests served", "All requests served\n", "Warning: slow query"} {
+				if err := UpdateServiceKB("stable", &chunking.AnalyzeResult{Analysis: analysis}, cfg); err != nil {
+					t.Fatalf("UpdateServiceKB() error = %v", err)
+				}
+			}
+
+			_, entries, err := ReadServiceEntries(filepath.Join(tmpDir, "services", "stable.md"))
+			if err != nil {
+				t.Fatalf("ReadServiceEntries() error = %v", err)
+			}
+
+			if !skip {
+				if len(entries) != 4 {
+					t.Errorf("Expected every scan appended by default, got %d entries", len(entries))
+				}
+				return
+			}
+			if len(entries) != 2 {
+				t.Fatalf("Expected unchanged scans merged into 2 entries, got %d", len(entries))
+			}
+			if !strings.Contains(entries[0].Content, lastSeenPrefix) {
+				t.Errorf("Expected merged entry to record when it was last seen:\n%s", entries[0].Content)
+			}
+			if got := entries[0].Analysis(); got != "All requests served" {
+				t.Errorf("Analysis() = %q, want the analysis without the last seen line", got)
+			}
+			if strings.Contains(entries[1].Content, lastSeenPrefix) {
+				t.Error("A changed analysis must start a new entry without a last seen line")
+			}
+		})
+	}
+}
+
+func TestPruneEntries_LastSeen(t *testing.T) {
+	old := time.Now().Add(-40 * 24 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	content := "# Knowledge Base: web\n\n## Service History\n" +
+		"\n### Scan: " + old + "\n**Status:** 🟢 Healthy\n" + lastSeenPrefix + " " + recent + "\n\nStill fine\n\n---\n" +
+		"\n### Scan: " + old + "\n**Status:** 🟢 Healthy\n\nGone\n\n---\n"
+
+	pruned := pruneEntries(content, 30*24*time.Hour)
+	if !strings.Contains(pruned, "Still fine") {
+		t.Error("An entry last seen within the retention period must be kept")
+	}
+	if strings.Contains(pruned, "Gone") {
+		t.Error("An entry scanned and not seen since the retention period must be pruned")
+	}
+}
+
+func TestUpdateGlobalSummary_GroupByComposeProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir:      tmpDir,
+			GroupByComposeProject: true,
+		},
+	}
+
+	results := map[string]*chunking.AnalyzeResult{
+		"shop-web":   {Analysis: "All good.", ComposeProject: "shop"},
+		"shop-db":    {Analysis: "Critical database error detected.", ComposeProject: "shop"},
+		"blog-web":   {Analysis: "Warning: slow responses.", ComposeProject: "blog"},
+		"standalone": {Analysis: "All good."},
+	}
+
+	if err := UpdateGlobalSummary(results, cfg); err != nil {
+		t.Fatalf("UpdateGlobalSummary() error = %v", err)
+	}
+
+	// #nosec G304 - reading from controlled test temp directory
+	content, err := os.ReadFile(filepath.Join(tmpDir, "global_summary.md"))
+	if err != nil {
+		t.Fatalf("Failed to read global summary file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, expected := range []string{
+		"### 📦 blog — 🟡 Warning",
+		"### 📦 shop — 🔴 Issues",
+		"### Standalone containers — 🟢 OK",
+	} {
+		if !strings.Contains(contentStr, expected) {
+			t.Errorf("Expected content to contain %q\nGot:\n%s", expected, contentStr)
+		}
+	}
+
+	blog := strings.Index(contentStr, "📦 blog")
+	shop := strings.Index(contentStr, "📦 shop")
+	standalone := strings.Index(contentStr, "Standalone containers")
+	if blog >= shop || shop >= standalone {
+		t.Error("Expected projects sorted alphabetically with standalone containers last")
+	}
+
+	// The flat table is the default
+	cfg.Output.GroupByComposeProject = false
+	if err := UpdateGlobalSummary(results, cfg); err != nil {
+		t.Fatalf("UpdateGlobalSummary() error = %v", err)
+	}
+	// #nosec G304 - reading from controlled test temp directory
+	content, err = os.ReadFile(filepath.Join(tmpDir, "global_summary.md"))
+	if err != nil {
+		t.Fatalf("Failed to read global summary file: %v", err)
+	}
+	if strings.Contains(string(content), "📦") {
+		t.Error("Expected no project grouping when group_by_compose_project is disabled")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := DiffLines("Summary\nAll good\nNo errors", "Summary\nError: timeout\nNo errors")
+	want := []string{"  Summary", "- All good", "+ Error: timeout", "  No errors"}
+
+	if strings.Join(diff, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DiffLines() = %q, want %q", diff, want)
+	}
+}
+
+func TestEntryAnalysis(t *testing.T) {
+	entry := Entry{Content: "### Scan: 2025-01-01T00:00:00Z\n**Status:** 🟢 Healthy\n\nAll good."}
+
+	if got := entry.Analysis(); got != "All good." {
+		t.Errorf("Analysis() = %q, want %q", got, "All good.")
+	}
+}
+
+func TestUpdateServiceKB_CollidingNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			KnowledgeBaseDir:       tmpDir,
+			KnowledgeRetentionDays: 30,
+		},
+	}
+
+	for _, name := range []string{"org/app", "org_app"} {
+		if err := UpdateServiceKB(name, &chunking.AnalyzeResult{Analysis: "Analysis of " + name}, cfg); err != nil {
+			t.Fatalf("UpdateServiceKB(%q) error = %v", name, err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(tmpDir, "services", "*.md"))
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 distinct KB files, got %v", files)
+	}
+
+	for _, name := range []string{"org/app", "org_app"} {
+		entries, err := ContainerEntries(tmpDir, name)
+		if err != nil {
+			t.Fatalf("ContainerEntries(%q) error = %v", name, err)
+		}
+		if len(entries) != 1 || entries[0].ContainerName != name || !strings.Contains(entries[0].Content, "Analysis of "+name) {
+			t.Errorf("Expected one entry for %q only, got %+v", name, entries)
+		}
+	}
+}
+
+func TestLatestAnalyses(t *testing.T) {
+	kbDir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entry := func(ts time.Time, status, body string) string {
+		return fmt.Sprintf("\n### Scan: %s\n**Status:** %s\n\n%s\n\n---\n", ts.Format(time.RFC3339), status, body)
+	}
+
+	writeTestKB(t, kbDir, "my~app.md", "# Knowledge Base: my/app\n\n## Service History\n"+
+		entry(now.Add(-3*time.Hour), statusIssuesDetected, "Error: connection refused to db")+
+		entry(now.Add(-1*time.Hour), statusWarnings, "Warning: slow connection to cache"))
+	writeTestKB(t, kbDir, "web.md", "# Knowledge Base: web\n\n## Service History\n"+
+		entry(now.Add(-48*time.Hour), statusHealthy, "All good"))
+
+	latest, err := LatestAnalyses(kbDir, time.Time{})
+	if err != nil {
+		t.Fatalf("LatestAnalyses() error = %v", err)
+	}
+	if len(latest) != 2 || latest["my/app"] != "Warning: slow connection to cache" || latest["web"] != "All good" {
+		t.Errorf("LatestAnalyses() = %v, want the newest analysis per container", latest)
+	}
+
+	recent, err := LatestAnalyses(kbDir, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("LatestAnalyses() error = %v", err)
+	}
+	if _, ok := recent["web"]; ok || len(recent) != 1 {
+		t.Errorf("LatestAnalyses(since 24h) = %v, want only my/app", recent)
+	}
+}
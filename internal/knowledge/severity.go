@@ -0,0 +1,84 @@
+package knowledge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zorak1103/dlia/internal/chunking"
+)
+
+// Severity classifies how serious the findings of an analysis are.
+// Values are ordered so that a higher value means a more severe result.
+type Severity int
+
+// Severity levels, from least to most severe.
+const (
+	SeverityHealthy Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+// String returns the lowercase config name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "healthy"
+	}
+}
+
+// ParseSeverity converts a config value ("healthy", "warning", "critical") to a Severity.
+// An empty string is treated as SeverityHealthy.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "healthy":
+		return SeverityHealthy, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return SeverityHealthy, fmt.Errorf("unknown severity %q (expected healthy, warning or critical)", s)
+	}
+}
+
+// ClassifySeverity determines the severity of a single analysis using the
+// same keyword heuristics as the knowledge base status markers.
+func ClassifySeverity(analysis string) Severity {
+	switch {
+	case hasIssues(analysis):
+		return SeverityCritical
+	case hasWarnings(analysis):
+		return SeverityWarning
+	default:
+		return SeverityHealthy
+	}
+}
+
+// ResultSeverity returns the severity the model reported for result when it answered
+// with structured output, and falls back to ClassifySeverity for free-text analyses.
+// Keywords alone misread healthy structured answers, e.g. an empty "**Errors**:" heading.
+func ResultSeverity(result *chunking.AnalyzeResult) Severity {
+	if result.Structured != nil && result.Structured.Severity != "" {
+		if s, err := ParseSeverity(result.Structured.Severity); err == nil {
+			return s
+		}
+	}
+
+	return ClassifySeverity(result.Analysis)
+}
+
+// MaxSeverity returns the highest severity across all container analyses.
+func MaxSeverity(containerAnalyses map[string]string) Severity {
+	highest := SeverityHealthy
+	for _, analysis := range containerAnalyses {
+		if s := ClassifySeverity(analysis); s > highest {
+			highest = s
+		}
+	}
+
+	return highest
+}
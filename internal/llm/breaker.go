@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is wrapped into errors of calls rejected, or failures that opened
+// the circuit, while the LLM endpoint is considered unavailable.
+var ErrCircuitOpen = errors.New("LLM circuit breaker open")
+
+// unavailableError marks failures that mean the endpoint is down rather than that it
+// rejected the request: network errors and 5xx responses.
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string { return e.err.Error() }
+
+func (e *unavailableError) Unwrap() error { return e.err }
+
+// CircuitBreaker stops calling an LLM endpoint that keeps failing. After threshold
+// consecutive failed requests (network errors or 5xx responses after retries) the
+// circuit opens and calls fail immediately until cooldown has passed. Then it is
+// half-open: one trial request is let through, which closes the circuit on success
+// or opens it again on failure. Safe for concurrent use; a nil *CircuitBreaker never
+// rejects a call.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int       // Consecutive failures
+	openUntil time.Time // Zero while closed
+	trial     bool      // A half-open trial request is in flight
+}
+
+// NewCircuitBreaker creates a breaker that opens after threshold consecutive failures
+// for cooldown. Returns nil (disabled) when threshold is 0 or less.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow returns an error wrapping ErrCircuitOpen if the call must not be made.
+func (b *CircuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if remaining := time.Until(b.openUntil); remaining > 0 {
+		return fmt.Errorf("%w: %d consecutive failures, retrying in %s", ErrCircuitOpen, b.failures, remaining.Round(time.Second))
+	}
+	if b.trial {
+		return fmt.Errorf("%w: waiting for the trial request to complete", ErrCircuitOpen)
+	}
+	b.trial = true
+	return nil
+}
+
+// RecordSuccess closes the circuit.
+func (b *CircuitBreaker) RecordSuccess() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.trial = false
+}
+
+// cancelTrial lets a new trial request through after one that was canceled by its
+// caller and therefore says nothing about the endpoint.
+func (b *CircuitBreaker) cancelTrial() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// RecordFailure counts a failed call and reports whether the circuit is now open.
+func (b *CircuitBreaker) RecordFailure() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b := NewCircuitBreaker(2, time.Hour)
+
+	if b.RecordFailure() {
+		t.Fatal("circuit must stay closed below the threshold")
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() = %v, want nil while closed", err)
+	}
+	if !b.RecordFailure() {
+		t.Fatal("circuit must open at the threshold")
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow() = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	b := NewCircuitBreaker(2, time.Hour)
+	b.RecordFailure()
+	b.RecordSuccess()
+	if b.RecordFailure() {
+		t.Error("failures must be consecutive to open the circuit")
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	b := NewCircuitBreaker(1, time.Millisecond)
+	b.RecordFailure()
+	time.Sleep(5 * time.Millisecond)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() = %v, want a trial request after the cooldown", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow() = %v, want only one trial request at a time", err)
+	}
+
+	b.RecordSuccess()
+	if err := b.Allow(); err != nil {
+		t.Errorf("Allow() = %v, want closed circuit after a successful trial", err)
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	b := NewCircuitBreaker(0, time.Minute)
+	if b != nil {
+		t.Fatal("threshold 0 must disable the breaker")
+	}
+	b.RecordFailure()
+	if err := b.Allow(); err != nil {
+		t.Errorf("nil breaker must never reject, got %v", err)
+	}
+}
+
+func TestClient_CircuitBreakerFailsFast(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, "key", "gpt-4o", ClientOptions{
+		CircuitBreaker: NewCircuitBreaker(1, time.Hour),
+	})
+
+	_, _, err := client.Analyze(context.Background(), "c", "system", "user")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the failure that opens the circuit to wrap ErrCircuitOpen, got %v", err)
+	}
+	sent := requests.Load()
+
+	_, _, err = client.Analyze(context.Background(), "c", "system", "user")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected fast failure, got %v", err)
+	}
+	if requests.Load() != sent {
+		t.Errorf("Expected no request while the circuit is open, got %d more", requests.Load()-sent)
+	}
+}
+
+func TestClient_CircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, "key", "gpt-4o", ClientOptions{
+		CircuitBreaker: NewCircuitBreaker(1, time.Hour),
+	})
+
+	for range 2 {
+		_, _, err := client.Analyze(context.Background(), "c", "system", "user")
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected a plain request error, got %v", err)
+		}
+	}
+}
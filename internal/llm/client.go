@@ -5,9 +5,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/zorak1103/dlia/internal/llmlogger"
@@ -76,21 +80,187 @@ type clientImpl struct {
 	model      string
 	httpClient *http.Client
 	logger     *llmlogger.Logger
+
+	analysisMaxTokens     int
+	chunkSummaryMaxTokens int
+	analysisTemperature   float64
+	summaryTemperature    float64
+	topP                  float64
+	frequencyPenalty      float64
+	azure                 *AzureOptions
+	ollama                bool
+	limiter               *RateLimiter
+	breaker               *CircuitBreaker
+	keys                  *KeyRing
 }
 
 // Compile-time verification that clientImpl implements Client
 var _ Client = (*clientImpl)(nil)
 
+// DefaultRequestTimeout is the HTTP timeout used when no timeout is configured.
+const DefaultRequestTimeout = 120 * time.Second // 2 minutes for long responses
+
+// Default max_tokens for analysis and chunk summary requests.
+const (
+	DefaultAnalysisMaxTokens     = 4000
+	DefaultChunkSummaryMaxTokens = 2000
+)
+
+// DefaultTemperature is the sampling temperature used for analyses and chunk
+// summaries when none is configured.
+const DefaultTemperature = 0.3
+
+// ClientOptions holds optional settings for NewClientWithOptions.
+// Zero values select the defaults.
+type ClientOptions struct {
+	RequestTimeout        time.Duration   // HTTP timeout per request (default: DefaultRequestTimeout)
+	AnalysisMaxTokens     int             // max_tokens for Analyze (default: DefaultAnalysisMaxTokens)
+	ChunkSummaryMaxTokens int             // max_tokens for SummarizeChunk (default: DefaultChunkSummaryMaxTokens)
+	AnalysisTemperature   *float64        // temperature for analyses (default: DefaultTemperature)
+	SummaryTemperature    *float64        // temperature for SummarizeChunk (default: DefaultTemperature)
+	TopP                  float64         // top_p for every request (default: 0, the provider's default)
+	FrequencyPenalty      float64         // frequency_penalty for every request (default: 0, no penalty)
+	Azure                 *AzureOptions   // Use Azure OpenAI request conventions when set
+	Ollama                bool            // Use Ollama's native /api/chat endpoint
+	RateLimiter           *RateLimiter    // Paces every outbound request (default: unlimited)
+	CircuitBreaker        *CircuitBreaker // Fails fast while the endpoint is down (default: disabled)
+	KeyRing               *KeyRing        // API keys to rotate through instead of apiKey (default: apiKey only)
+}
+
+// AzureOptions selects Azure OpenAI request conventions: the deployment is part of
+// the URL path, api-version is a query parameter and the key is sent as "api-key".
+type AzureOptions struct {
+	Deployment string
+	APIVersion string
+}
+
 // NewClient connects to an OpenAI-compatible API at baseURL using the specified model.
 func NewClient(baseURL, apiKey, model string) Client {
+	return NewClientWithOptions(baseURL, apiKey, model, ClientOptions{})
+}
+
+// NewClientWithOptions is like NewClient but applies the given options.
+func NewClientWithOptions(baseURL, apiKey, model string, opts ClientOptions) Client {
+	timeout := opts.RequestTimeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	analysisMaxTokens := opts.AnalysisMaxTokens
+	if analysisMaxTokens <= 0 {
+		analysisMaxTokens = DefaultAnalysisMaxTokens
+	}
+	chunkSummaryMaxTokens := opts.ChunkSummaryMaxTokens
+	if chunkSummaryMaxTokens <= 0 {
+		chunkSummaryMaxTokens = DefaultChunkSummaryMaxTokens
+	}
+
+	// Pointers, because 0 is a meaningful temperature (deterministic sampling)
+	analysisTemperature, summaryTemperature := DefaultTemperature, DefaultTemperature
+	if opts.AnalysisTemperature != nil {
+		analysisTemperature = *opts.AnalysisTemperature
+	}
+	if opts.SummaryTemperature != nil {
+		summaryTemperature = *opts.SummaryTemperature
+	}
+
 	return &clientImpl{
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		model:   model,
 		httpClient: &http.Client{
-			Timeout: 120 * time.Second, // 2 minutes for long responses
+			Timeout: timeout,
 		},
+		analysisMaxTokens:     analysisMaxTokens,
+		chunkSummaryMaxTokens: chunkSummaryMaxTokens,
+		analysisTemperature:   analysisTemperature,
+		summaryTemperature:    summaryTemperature,
+		topP:                  opts.TopP,
+		frequencyPenalty:      opts.FrequencyPenalty,
+		azure:                 opts.Azure,
+		ollama:                opts.Ollama,
+		limiter:               opts.RateLimiter,
+		breaker:               opts.CircuitBreaker,
+		keys:                  opts.KeyRing,
+	}
+}
+
+// chatEndpoint returns the chat completions URL for the configured provider.
+func (c *clientImpl) chatEndpoint() string {
+	base := strings.TrimSuffix(c.baseURL, "/")
+	if c.ollama {
+		return base + "/api/chat"
+	}
+	if c.azure == nil {
+		return base + "/chat/completions"
+	}
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		base, url.PathEscape(c.azure.Deployment), url.QueryEscape(c.azure.APIVersion))
+}
+
+// currentKey returns the API key for the next request and its key ring index
+// (-1 without a key ring).
+func (c *clientImpl) currentKey() (string, int) {
+	if c.keys == nil {
+		return c.apiKey, -1
+	}
+	return c.keys.Key()
+}
+
+// setAuthHeader adds the API key using the provider's header convention.
+func (c *clientImpl) setAuthHeader(req *http.Request, apiKey string) {
+	if apiKey == "" {
+		return
+	}
+	if c.azure != nil {
+		req.Header.Set("api-key", apiKey)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+}
+
+// keyRejected reports whether a failed response means the API key itself is unusable
+// for this run: HTTP 401, or HTTP 429 with a quota error code.
+func keyRejected(statusCode int, err error) bool {
+	if statusCode == http.StatusUnauthorized {
+		return true
+	}
+	var apiErr *APIError
+	return statusCode == http.StatusTooManyRequests && errors.As(err, &apiErr) &&
+		(quotaErrorCodes[apiErr.Code] || quotaErrorCodes[apiErr.Type])
+}
+
+// statusError converts a non-200 response into an error. HTTP 429 errors wrap
+// ErrQuotaExceeded.
+func (c *clientImpl) statusError(endpoint string, statusCode int, respBody []byte) error {
+	if apiErr := c.ollamaError(respBody); apiErr != nil {
+		if statusCode == http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %w", ErrQuotaExceeded, apiErr)
+		}
+		return apiErr
+	}
+	var apiResp ChatResponse
+	if unmarshalErr := json.Unmarshal(respBody, &apiResp); unmarshalErr == nil && apiResp.Error != nil {
+		if statusCode == http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %w", ErrQuotaExceeded, apiResp.Error)
+		}
+		return apiResp.Error
 	}
+	if statusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: API %s returned status %d for model %s: %s", ErrQuotaExceeded, endpoint, statusCode, c.model, string(respBody))
+	}
+	return fmt.Errorf("API %s returned status %d for model %s: %s", endpoint, statusCode, c.model, string(respBody))
+}
+
+// ollamaError returns the error of an Ollama error response, or nil for other providers.
+func (c *clientImpl) ollamaError(body []byte) error {
+	if !c.ollama {
+		return nil
+	}
+	if apiErr := parseOllamaError(body); apiErr != nil {
+		return apiErr
+	}
+	return nil
 }
 
 func (c *clientImpl) SetLogger(logger *llmlogger.Logger) {
@@ -108,6 +278,10 @@ type retryResult struct {
 func (c *clientImpl) executeWithRetry(httpReq *http.Request, maxRetries int) (body []byte, statusCode int, err error) {
 	var lastErr error
 	for attempt := 0; attempt < maxRetries; attempt++ {
+		if err := c.limiter.Wait(httpReq.Context()); err != nil {
+			return nil, 0, fmt.Errorf("waiting for rate limiter: %w", err)
+		}
+
 		result := c.executeRequest(httpReq)
 		if result.err == nil && result.statusCode == http.StatusOK {
 			return result.body, result.statusCode, nil
@@ -121,19 +295,45 @@ func (c *clientImpl) executeWithRetry(httpReq *http.Request, maxRetries int) (bo
 			return result.body, result.statusCode, nil
 		}
 
-		// Retry on network errors or 5xx status codes
-		if result.err != nil || result.statusCode >= 500 {
-			lastErr = result.err
-			time.Sleep(time.Duration(attempt+1) * time.Second)
-			continue
+		if !c.retryable(httpReq, result) {
+			// Client errors and malformed requests fail the same way on every attempt
+			if result.err != nil {
+				return nil, 0, result.err
+			}
+			return result.body, result.statusCode, nil
 		}
 
-		// Non-retryable error (4xx)
-		return result.body, result.statusCode, nil
+		lastErr = result.err
+		slog.Info("retrying LLM request", "url", httpReq.URL.Redacted(), "model", c.model,
+			"attempt", attempt+1, "max_attempts", maxRetries, "status", result.statusCode, "error", result.err)
+		time.Sleep(time.Duration(attempt+1) * time.Second)
 	}
 	return nil, 0, lastErr
 }
 
+// retryable reports whether a failed attempt may succeed when repeated: network
+// errors and timeouts (unless the request's context is done), and HTTP 429, 500, 502,
+// 503 and 504 unless the body carries an APIError code that no retry can fix (see
+// nonRetryableErrorCodes). Every other status, e.g. 400, 401, 403 and 404, is final.
+func (c *clientImpl) retryable(httpReq *http.Request, result retryResult) bool {
+	if result.err != nil {
+		return httpReq.Context().Err() == nil
+	}
+
+	switch result.statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(c.statusError(httpReq.URL.Redacted(), result.statusCode, result.body), &apiErr) {
+		return !nonRetryableErrorCodes[apiErr.Code] && !nonRetryableErrorCodes[apiErr.Type]
+	}
+	return true
+}
+
 // executeRequest performs a single HTTP request and returns the result.
 func (c *clientImpl) executeRequest(httpReq *http.Request) retryResult {
 	resp, err := c.httpClient.Do(httpReq)
@@ -151,40 +351,71 @@ func (c *clientImpl) executeRequest(httpReq *http.Request) retryResult {
 }
 
 func (c *clientImpl) ChatCompletion(ctx context.Context, messages []ChatMessage, temperature float64, maxTokens int) (*ChatResponse, error) {
-	req := ChatRequest{
-		Model:       c.model,
-		Messages:    messages,
-		Temperature: temperature,
-		MaxTokens:   maxTokens,
+	return c.sendChatRequest(ctx, c.newChatRequest(messages, temperature, maxTokens))
+}
+
+// newChatRequest builds a request for the client's model with the configured
+// top_p and frequency_penalty.
+func (c *clientImpl) newChatRequest(messages []ChatMessage, temperature float64, maxTokens int) ChatRequest {
+	return ChatRequest{
+		Model:            c.model,
+		Messages:         messages,
+		Temperature:      temperature,
+		MaxTokens:        maxTokens,
+		TopP:             c.topP,
+		FrequencyPenalty: c.frequencyPenalty,
 	}
+}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal chat completion request for model %s: %w", c.model, err)
+// sendChatRequest posts a fully built request to the chat completions endpoint,
+// guarded by the circuit breaker.
+func (c *clientImpl) sendChatRequest(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("request for model %s not sent: %w", c.model, err)
 	}
 
-	endpoint := c.baseURL + "/chat/completions"
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request to %s for model %s: %w", endpoint, c.model, err)
+	resp, err := c.postChatRequest(ctx, req)
+	var unavailable *unavailableError
+	switch {
+	case ctx.Err() != nil:
+		c.breaker.cancelTrial()
+	case errors.As(err, &unavailable):
+		if c.breaker.RecordFailure() {
+			return nil, fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+		}
+	default:
+		// Any response, including a rejected request, shows the endpoint is up
+		c.breaker.RecordSuccess()
 	}
+	return resp, err
+}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+// postChatRequest sends req and decodes the response. Errors of an unreachable or
+// failing endpoint are wrapped in unavailableError.
+func (c *clientImpl) postChatRequest(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	var body []byte
+	var err error
+	if c.ollama {
+		body, err = json.Marshal(newOllamaChatRequest(req))
+	} else {
+		body, err = json.Marshal(req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chat completion request for model %s: %w", c.model, err)
 	}
 
-	respBody, statusCode, err := c.executeWithRetry(httpReq, 3)
+	endpoint := c.chatEndpoint()
+	respBody, err := c.postWithKeyFailover(ctx, endpoint, body)
 	if err != nil {
-		return nil, fmt.Errorf("request to %s for model %s failed: %w", endpoint, c.model, err)
+		return nil, err
 	}
 
-	if statusCode != http.StatusOK {
-		var apiResp ChatResponse
-		if unmarshalErr := json.Unmarshal(respBody, &apiResp); unmarshalErr == nil && apiResp.Error != nil {
-			return nil, apiResp.Error
+	if c.ollama {
+		chatResp, err := parseOllamaResponse(respBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse response from %s for model %s: %w", endpoint, c.model, err)
 		}
-		return nil, fmt.Errorf("API %s returned status %d for model %s: %s", endpoint, statusCode, c.model, string(respBody))
+		return chatResp, nil
 	}
 
 	var chatResp ChatResponse
@@ -199,20 +430,49 @@ func (c *clientImpl) ChatCompletion(ctx context.Context, messages []ChatMessage,
 	return &chatResp, nil
 }
 
+// postWithKeyFailover posts body to endpoint and returns the body of a successful
+// response. When the API rejects the key (see keyRejected) and the key ring has another
+// usable key, the request is repeated with that key.
+func (c *clientImpl) postWithKeyFailover(ctx context.Context, endpoint string, body []byte) ([]byte, error) {
+	for {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP request to %s for model %s: %w", endpoint, c.model, err)
+		}
+
+		apiKey, keyIndex := c.currentKey()
+		httpReq.Header.Set("Content-Type", "application/json")
+		c.setAuthHeader(httpReq, apiKey)
+
+		respBody, statusCode, err := c.executeWithRetry(httpReq, 3)
+		if err != nil {
+			return nil, &unavailableError{fmt.Errorf("request to %s for model %s failed: %w", endpoint, c.model, err)}
+		}
+		if statusCode == http.StatusOK {
+			return respBody, nil
+		}
+
+		err = c.statusError(endpoint, statusCode, respBody)
+		if statusCode >= http.StatusInternalServerError {
+			return nil, &unavailableError{err}
+		}
+		if keyIndex < 0 || !keyRejected(statusCode, err) || !c.keys.MarkExhausted(keyIndex) {
+			return nil, err
+		}
+		slog.Warn("LLM API key rejected, switching to the next key", "key", keyIndex+1, "keys", c.keys.Len(),
+			"status", statusCode, "error", err)
+	}
+}
+
 func (c *clientImpl) Analyze(ctx context.Context, containerName, systemPrompt, userPrompt string) (string, *TokenUsage, error) {
 	messages := []ChatMessage{
 		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: userPrompt},
 	}
 
-	req := ChatRequest{
-		Model:       c.model,
-		Messages:    messages,
-		Temperature: 0.3,
-		MaxTokens:   4000,
-	}
+	req := c.newChatRequest(messages, c.analysisTemperature, c.analysisMaxTokens)
 
-	resp, err := c.ChatCompletion(ctx, req.Messages, req.Temperature, req.MaxTokens)
+	resp, err := c.sendChatRequest(ctx, req)
 	if err != nil {
 		return "", nil, err
 	}
@@ -225,7 +485,7 @@ func (c *clientImpl) Analyze(ctx context.Context, containerName, systemPrompt, u
 	if c.logger != nil {
 		if logErr := c.logger.LogInteraction(containerName, userPrompt, req, resp); logErr != nil {
 			// Log error but don't fail the analysis
-			fmt.Printf("Warning: failed to log LLM interaction: %v\n", logErr)
+			slog.Warn("failed to log LLM interaction", "container", containerName, "error", logErr)
 		}
 	}
 
@@ -238,14 +498,9 @@ func (c *clientImpl) SummarizeChunk(ctx context.Context, containerName, systemPr
 		{Role: "user", Content: chunkPrompt},
 	}
 
-	req := ChatRequest{
-		Model:       c.model,
-		Messages:    messages,
-		Temperature: 0.3,
-		MaxTokens:   2000,
-	}
+	req := c.newChatRequest(messages, c.summaryTemperature, c.chunkSummaryMaxTokens)
 
-	resp, err := c.ChatCompletion(ctx, req.Messages, req.Temperature, req.MaxTokens)
+	resp, err := c.sendChatRequest(ctx, req)
 	if err != nil {
 		return "", err
 	}
@@ -258,7 +513,7 @@ func (c *clientImpl) SummarizeChunk(ctx context.Context, containerName, systemPr
 	if c.logger != nil {
 		if logErr := c.logger.LogInteraction(containerName, chunkPrompt, req, resp); logErr != nil {
 			// Log error but don't fail the summarization
-			fmt.Printf("Warning: failed to log LLM interaction: %v\n", logErr)
+			slog.Warn("failed to log LLM interaction", "container", containerName, "error", logErr)
 		}
 	}
 
@@ -7,6 +7,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -41,6 +44,27 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientWithOptions(t *testing.T) {
+	client := NewClientWithOptions("https://api.openai.com/v1", "test-key", "gpt-4", ClientOptions{
+		RequestTimeout: 10 * time.Minute,
+	})
+
+	impl, ok := client.(*clientImpl)
+	if !ok {
+		t.Fatal("Expected client to be *clientImpl")
+	}
+
+	if impl.httpClient.Timeout != 10*time.Minute {
+		t.Errorf("Expected timeout 10m, got %v", impl.httpClient.Timeout)
+	}
+
+	// Zero options fall back to the default timeout
+	impl = NewClientWithOptions("https://api.openai.com/v1", "test-key", "gpt-4", ClientOptions{}).(*clientImpl)
+	if impl.httpClient.Timeout != DefaultRequestTimeout {
+		t.Errorf("Expected default timeout %v, got %v", DefaultRequestTimeout, impl.httpClient.Timeout)
+	}
+}
+
 func TestClient_Analyze(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -290,6 +314,232 @@ func TestClient_SummarizeChunk(t *testing.T) {
 	}
 }
 
+func TestNewClientWithOptions_MaxTokens(t *testing.T) {
+	var maxTokens []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		maxTokens = append(maxTokens, req.MaxTokens)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ // nolint:errcheck,gosec
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: "ok"}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, "test-key", "test-model", ClientOptions{
+		AnalysisMaxTokens:     1000,
+		ChunkSummaryMaxTokens: 300,
+	})
+	ctx := context.Background()
+
+	if _, _, err := client.Analyze(ctx, "c", "system", "user"); err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if _, err := client.SummarizeChunk(ctx, "c", "system", "chunk"); err != nil {
+		t.Fatalf("SummarizeChunk() error = %v", err)
+	}
+
+	if len(maxTokens) != 2 || maxTokens[0] != 1000 || maxTokens[1] != 300 {
+		t.Errorf("Expected max_tokens [1000 300], got %v", maxTokens)
+	}
+}
+
+func TestNewClientWithOptions_Sampling(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ // nolint:errcheck,gosec
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: "ok"}}},
+		})
+	}))
+	defer server.Close()
+
+	analysisTemperature, summaryTemperature := 0.0, 0.2
+	client := NewClientWithOptions(server.URL, "test-key", "test-model", ClientOptions{
+		AnalysisTemperature: &analysisTemperature,
+		SummaryTemperature:  &summaryTemperature,
+		TopP:                0.9,
+		FrequencyPenalty:    0.5,
+	})
+	ctx := context.Background()
+
+	if _, _, err := client.Analyze(ctx, "c", "system", "user"); err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if _, err := client.SummarizeChunk(ctx, "c", "system", "chunk"); err != nil {
+		t.Fatalf("SummarizeChunk() error = %v", err)
+	}
+	// The plain "ok" reply is not JSON; only the request matters here
+	_, _, _, _ = client.(*clientImpl).AnalyzeStructured(ctx, "c", "system", "user")
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requests))
+	}
+	// A temperature of 0 must be sent rather than omitted, or the API default applies
+	for i, want := range []float64{0, 0.2, 0} {
+		if got, ok := requests[i]["temperature"]; !ok || got != want {
+			t.Errorf("Request %d: expected temperature %g, got %v", i, want, got)
+		}
+		if requests[i]["top_p"] != 0.9 || requests[i]["frequency_penalty"] != 0.5 {
+			t.Errorf("Request %d: expected top_p 0.9 and frequency_penalty 0.5, got %v and %v",
+				i, requests[i]["top_p"], requests[i]["frequency_penalty"])
+		}
+	}
+}
+
+func TestClient_AzureRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/my deploy/chat/completions" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2024-06-01" {
+			t.Errorf("Expected api-version 2024-06-01, got %q", got)
+		}
+		if got := r.Header.Get("api-key"); got != "azure-key" {
+			t.Errorf("Expected api-key header, got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Expected no Authorization header, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ // nolint:errcheck,gosec
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: "ok"}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL+"/", "azure-key", "gpt-4o", ClientOptions{
+		Azure: &AzureOptions{Deployment: "my deploy", APIVersion: "2024-06-01"},
+	})
+
+	content, _, err := client.Analyze(context.Background(), "c", "system", "user")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if content != "ok" {
+		t.Errorf("Expected content ok, got %q", content)
+	}
+}
+
+func TestClient_QuotaExceeded(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"json error body", `{"error":{"message":"You exceeded your current quota","type":"insufficient_quota","code":"insufficient_quota"}}`},
+		{"plain body", "slow down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "test-model")
+			_, _, err := client.Analyze(context.Background(), "c", "system", "user")
+
+			if !IsQuotaError(err) {
+				t.Errorf("Expected quota error, got: %v", err)
+			}
+			if !errors.Is(err, ErrQuotaExceeded) {
+				t.Errorf("Expected error to wrap ErrQuotaExceeded, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_KeyFailover(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantKeys   string
+		wantQuota  bool
+		wantFailed bool
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"message":"Incorrect API key","code":"invalid_api_key"}}`, "k1,k2", false, false},
+		{"quota code", http.StatusTooManyRequests, `{"error":{"message":"You exceeded your current quota","code":"insufficient_quota"}}`, "k1,k2", false, false},
+		{"429 without quota code", http.StatusTooManyRequests, "slow down", "k1,k1,k1", true, true},
+		{"other client error", http.StatusBadRequest, `{"error":{"message":"bad request"}}`, "k1", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var keys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				mu.Lock()
+				keys = append(keys, key)
+				mu.Unlock()
+				if key == "k1" {
+					w.WriteHeader(tt.status)
+					_, _ = w.Write([]byte(tt.body))
+					return
+				}
+				_ = json.NewEncoder(w).Encode(ChatResponse{Choices: []Choice{{Message: ChatMessage{Content: "ok"}}}})
+			}))
+			defer server.Close()
+
+			ring := NewKeyRing([]string{"k1", "k2"})
+			client := NewClientWithOptions(server.URL, "", "test-model", ClientOptions{KeyRing: ring})
+			_, _, err := client.Analyze(context.Background(), "c", "system", "user")
+
+			if (err != nil) != tt.wantFailed {
+				t.Fatalf("Analyze() error = %v, wantFailed %v", err, tt.wantFailed)
+			}
+			if IsQuotaError(err) != tt.wantQuota {
+				t.Errorf("IsQuotaError(%v) = %v, want %v", err, !tt.wantQuota, tt.wantQuota)
+			}
+			if got := strings.Join(keys, ","); got != tt.wantKeys {
+				t.Errorf("Keys sent = %s, want %s", got, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestClient_KeyFailover_AllExhausted(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"quota","code":"insufficient_quota"}}`))
+	}))
+	defer server.Close()
+
+	ring := NewKeyRing([]string{"k1", "k2"})
+	client := NewClientWithOptions(server.URL, "", "test-model", ClientOptions{KeyRing: ring})
+	_, _, err := client.Analyze(context.Background(), "c", "system", "user")
+	if !IsQuotaError(err) {
+		t.Fatalf("Expected quota error once every key is exhausted, got: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected one request per key, got %d", requests)
+	}
+
+	// Exhausted keys stay exhausted for the run: the next call does not rotate again
+	_, _, _ = client.Analyze(context.Background(), "c", "system", "user")
+	if requests != 3 {
+		t.Errorf("Expected a single request once all keys are exhausted, got %d in total", requests)
+	}
+}
+
 func TestClient_RetryLogic(t *testing.T) {
 	attemptCount := 0
 
@@ -342,6 +592,66 @@ func TestClient_RetryLogic(t *testing.T) {
 	}
 }
 
+func TestClient_NonRetryableErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"bad request", http.StatusBadRequest, `{"error":{"message":"'messages' is required","type":"invalid_request_error"}}`},
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"message":"Incorrect API key","code":"invalid_api_key"}}`},
+		{"forbidden", http.StatusForbidden, "forbidden"},
+		{"not found", http.StatusNotFound, `{"error":{"message":"The model does not exist","code":"model_not_found"}}`},
+		{"not implemented", http.StatusNotImplemented, "not implemented"},
+		{"server error with malformed request code", http.StatusInternalServerError, `{"error":{"message":"too long","code":"context_length_exceeded"}}`},
+		{"exhausted quota", http.StatusTooManyRequests, `{"error":{"message":"You exceeded your current quota","code":"insufficient_quota"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "test-model")
+			_, _, err := client.Analyze(context.Background(), "c", "system", "user")
+
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if got := requests.Load(); got != 1 {
+				t.Errorf("Expected exactly one request without retries, got %d", got)
+			}
+		})
+	}
+}
+
+func TestClient_RetryRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"Rate limit reached","code":"rate_limit_exceeded"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ChatResponse{Choices: []Choice{{Message: ChatMessage{Content: "ok"}}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "test-model")
+	content, _, err := client.Analyze(context.Background(), "c", "system", "user")
+	if err != nil || content != "ok" {
+		t.Fatalf("Analyze() = %q, %v; want success after a rate limit retry", content, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
 func TestClient_MaxRetriesExceeded(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -0,0 +1,83 @@
+package llm
+
+import "strings"
+
+// DefaultContextWindow is the context window assumed for models missing from the
+// table below. It matches the former llm.max_tokens default, so configurations that
+// relied on it keep their token budget.
+const DefaultContextWindow = 128000
+
+// contextWindows maps model name prefixes to their context window in tokens. The
+// longest matching prefix wins, so "gpt-4o" takes precedence over "gpt-4" and
+// "gpt-4-turbo" over "gpt-4".
+var contextWindows = map[string]int{
+	// OpenAI
+	"gpt-3.5-turbo": 16385,
+	"gpt-4":         8192,
+	"gpt-4-32k":     32768,
+	"gpt-4-turbo":   128000,
+	"gpt-4o":        128000,
+	"gpt-4.1":       1047576,
+	"gpt-5":         400000,
+	"o1":            200000,
+	"o3":            200000,
+	"o4-mini":       200000,
+	// Anthropic
+	"claude-2":        100000,
+	"claude-3":        200000,
+	"claude-3-5":      200000,
+	"claude-3.5":      200000,
+	"claude-3-7":      200000,
+	"claude-3.7":      200000,
+	"claude-sonnet-4": 200000,
+	"claude-opus-4":   200000,
+	"claude-haiku-4":  200000,
+	// Google
+	"gemini-pro":     32760,
+	"gemini-1.5":     1048576,
+	"gemini-pro-1.5": 1048576,
+	"gemini-2":       1048576,
+	"gemini-2.5":     1048576,
+	// Mistral
+	"mistral":       32768,
+	"mistral-large": 128000,
+	"mixtral":       32768,
+	"codestral":     256000,
+	// Meta and other open models commonly served by Ollama
+	"llama2":        4096,
+	"llama3":        8192,
+	"llama3.1":      128000,
+	"llama3.2":      128000,
+	"llama-3.1":     128000,
+	"llama-3.2":     128000,
+	"llama-3.3":     128000,
+	"codellama":     16384,
+	"qwen2.5":       32768,
+	"deepseek-chat": 64000,
+	"deepseek-r1":   64000,
+}
+
+// ContextWindow returns the context window of a model in tokens and whether the
+// model was found in the built-in table. Provider prefixes such as
+// "anthropic/claude-3.5-sonnet" (OpenRouter) and tags such as "llama3.2:3b"
+// (Ollama) are ignored. Unknown models return DefaultContextWindow and false.
+func ContextWindow(model string) (int, bool) {
+	name := strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+
+	best, window := "", 0
+	for prefix, size := range contextWindows {
+		if strings.HasPrefix(name, prefix) && len(prefix) > len(best) {
+			best, window = prefix, size
+		}
+	}
+	if best == "" {
+		return DefaultContextWindow, false
+	}
+	return window, true
+}
@@ -0,0 +1,30 @@
+package llm
+
+import "testing"
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model     string
+		want      int
+		wantKnown bool
+	}{
+		{"gpt-4o-mini", 128000, true},
+		{"gpt-4", 8192, true},
+		{"gpt-4-turbo-preview", 128000, true},
+		{"GPT-3.5-Turbo", 16385, true},
+		{"anthropic/claude-3.5-sonnet", 200000, true},
+		{"claude-3-5-haiku-20241022", 200000, true},
+		{"google/gemini-pro-1.5", 1048576, true},
+		{"llama3.2:3b", 128000, true},
+		{"llama3", 8192, true},
+		{"my-custom-model", 128000, false}, // The former llm.max_tokens default
+		{"", DefaultContextWindow, false},
+	}
+
+	for _, tt := range tests {
+		got, known := ContextWindow(tt.model)
+		if got != tt.want || known != tt.wantKnown {
+			t.Errorf("ContextWindow(%q) = %d, %v; want %d, %v", tt.model, got, known, tt.want, tt.wantKnown)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"strings"
+	"sync"
+)
+
+// KeyRing rotates between several API keys. A key the API rejects as unauthorized or
+// out of quota is marked exhausted and skipped for the rest of the run, so requests
+// fail over to the next key. Safe for concurrent use; a nil *KeyRing holds no keys.
+type KeyRing struct {
+	mu        sync.Mutex
+	keys      []string
+	exhausted []bool
+	current   int
+}
+
+// NewKeyRing creates a key ring starting with the first key. Returns nil when keys
+// is empty.
+func NewKeyRing(keys []string) *KeyRing {
+	if len(keys) == 0 {
+		return nil
+	}
+	return &KeyRing{keys: keys, exhausted: make([]bool, len(keys))}
+}
+
+var (
+	sharedKeyRingsMu sync.Mutex
+	sharedKeyRings   = map[string]*KeyRing{}
+)
+
+// SharedKeyRing returns the process-wide key ring for keys, so every client of a scan
+// skips keys that another client already found exhausted. A new process, i.e. the
+// next scan, starts with all keys available again. Returns nil when keys is empty.
+func SharedKeyRing(keys []string) *KeyRing {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	id := strings.Join(keys, "\x00")
+
+	sharedKeyRingsMu.Lock()
+	defer sharedKeyRingsMu.Unlock()
+
+	ring, ok := sharedKeyRings[id]
+	if !ok {
+		ring = NewKeyRing(keys)
+		sharedKeyRings[id] = ring
+	}
+	return ring
+}
+
+// Key returns the key to use and its index. Once every key is exhausted it keeps
+// returning the last one tried, whose error then reaches the caller.
+func (r *KeyRing) Key() (string, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.keys[r.current], r.current
+}
+
+// Len returns the number of keys in the ring.
+func (r *KeyRing) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.keys)
+}
+
+// MarkExhausted marks the key at index as exhausted and moves on to the next usable
+// key. Reports whether such a key remains.
+func (r *KeyRing) MarkExhausted(index int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exhausted[index] = true
+	for i := range r.keys {
+		next := (r.current + i) % len(r.keys)
+		if !r.exhausted[next] {
+			r.current = next
+			return true
+		}
+	}
+	return false
+}
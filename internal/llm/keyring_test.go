@@ -0,0 +1,59 @@
+package llm
+
+import "testing"
+
+func TestKeyRing(t *testing.T) {
+	t.Parallel()
+
+	if NewKeyRing(nil) != nil {
+		t.Error("NewKeyRing(nil) should return nil")
+	}
+
+	ring := NewKeyRing([]string{"k1", "k2", "k3"})
+	if key, index := ring.Key(); key != "k1" || index != 0 {
+		t.Fatalf("Key() = %q, %d, want k1, 0", key, index)
+	}
+
+	if !ring.MarkExhausted(0) {
+		t.Fatal("MarkExhausted(0) should leave usable keys")
+	}
+	if key, _ := ring.Key(); key != "k2" {
+		t.Errorf("Key() after exhausting k1 = %q, want k2", key)
+	}
+
+	// A concurrent request that still used k1 must not skip k2
+	if !ring.MarkExhausted(0) {
+		t.Fatal("MarkExhausted(0) again should leave usable keys")
+	}
+	if key, _ := ring.Key(); key != "k2" {
+		t.Errorf("Key() after exhausting k1 twice = %q, want k2", key)
+	}
+
+	if !ring.MarkExhausted(1) {
+		t.Fatal("MarkExhausted(1) should leave k3")
+	}
+	if key, _ := ring.Key(); key != "k3" {
+		t.Errorf("Key() after exhausting k2 = %q, want k3", key)
+	}
+	if ring.MarkExhausted(2) {
+		t.Error("MarkExhausted() should report no usable key once all are exhausted")
+	}
+	if key, _ := ring.Key(); key != "k3" {
+		t.Errorf("Key() with all keys exhausted = %q, want the last one tried (k3)", key)
+	}
+}
+
+func TestSharedKeyRing(t *testing.T) {
+	t.Parallel()
+
+	if SharedKeyRing(nil) != nil {
+		t.Error("SharedKeyRing(nil) should return nil")
+	}
+	a := SharedKeyRing([]string{"shared-a", "shared-b"})
+	if a != SharedKeyRing([]string{"shared-a", "shared-b"}) {
+		t.Error("SharedKeyRing() should return the same ring for the same keys")
+	}
+	if a == SharedKeyRing([]string{"shared-b", "shared-a"}) {
+		t.Error("SharedKeyRing() should return separate rings for a different failover order")
+	}
+}
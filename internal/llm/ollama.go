@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ollamaChatRequest is the request body of Ollama's native /api/chat endpoint.
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   string          `json:"format,omitempty"` // "json" for JSON mode
+	Tools    []Tool          `json:"tools,omitempty"`
+	Options  ollamaOptions   `json:"options"`
+}
+
+// ollamaMessage is a chat message in Ollama's format. Tool call arguments are JSON
+// objects rather than the JSON-encoded strings of the OpenAI API.
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// ollamaOptions holds the sampling parameters; Ollama calls max_tokens num_predict.
+type ollamaOptions struct {
+	Temperature      float64 `json:"temperature"`
+	TopP             float64 `json:"top_p,omitempty"`
+	FrequencyPenalty float64 `json:"frequency_penalty,omitempty"`
+	NumPredict       int     `json:"num_predict,omitempty"`
+}
+
+// ollamaResponseLine is one line of the line-delimited JSON response. Streamed
+// lines carry content fragments; the final line has done=true and the token counts.
+type ollamaResponseLine struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+// newOllamaChatRequest maps an OpenAI-style request to Ollama's /api/chat format.
+// Ollama has no tool_choice; the offered tools are passed as they are.
+func newOllamaChatRequest(req ChatRequest) ollamaChatRequest {
+	messages := make([]ollamaMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, ollamaMessage{Role: m.Role, Content: m.Content})
+	}
+
+	ollamaReq := ollamaChatRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   true,
+		Tools:    req.Tools,
+		Options: ollamaOptions{
+			Temperature:      req.Temperature,
+			TopP:             req.TopP,
+			FrequencyPenalty: req.FrequencyPenalty,
+			NumPredict:       req.MaxTokens,
+		},
+	}
+	if req.ResponseFormat != nil && req.ResponseFormat.Type == responseFormatJSON {
+		ollamaReq.Format = "json"
+	}
+	return ollamaReq
+}
+
+// parseOllamaResponse assembles the line-delimited JSON of an /api/chat response
+// into a ChatResponse. Content fragments are concatenated and the token usage is
+// taken from prompt_eval_count and eval_count of the final line.
+func parseOllamaResponse(body []byte) (*ChatResponse, error) {
+	var (
+		content   strings.Builder
+		toolCalls []ToolCall
+		resp      = &ChatResponse{Object: "chat.completion"}
+		done      bool
+		reason    string
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(body)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaResponseLine
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return nil, fmt.Errorf("failed to parse Ollama response line: %w", err)
+		}
+		if chunk.Error != "" {
+			return nil, &APIError{Message: chunk.Error}
+		}
+
+		resp.Model = chunk.Model
+		content.WriteString(chunk.Message.Content)
+		for _, call := range chunk.Message.ToolCalls {
+			tc := ToolCall{Type: "function"}
+			tc.Function.Name = call.Function.Name
+			tc.Function.Arguments = string(call.Function.Arguments)
+			toolCalls = append(toolCalls, tc)
+		}
+		if chunk.Done {
+			done = true
+			reason = chunk.DoneReason
+			resp.Usage = TokenUsage{
+				PromptTokens:     chunk.PromptEvalCount,
+				CompletionTokens: chunk.EvalCount,
+				TotalTokens:      chunk.PromptEvalCount + chunk.EvalCount,
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Ollama response: %w", err)
+	}
+	if !done {
+		return nil, errors.New("incomplete Ollama response: missing final line with done=true")
+	}
+
+	resp.Choices = []Choice{{
+		Message:      ChatMessage{Role: "assistant", Content: content.String(), ToolCalls: toolCalls},
+		FinishReason: reason,
+	}}
+	return resp, nil
+}
+
+// parseOllamaError extracts the message of an Ollama error body ({"error": "..."}).
+func parseOllamaError(body []byte) *APIError {
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error == "" {
+		return nil
+	}
+	return &APIError{Message: errResp.Error}
+}
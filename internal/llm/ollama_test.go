@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_OllamaRequest(t *testing.T) {
+	var got ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no Authorization header without API key, got %q", auth)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("Invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = io.WriteString(w, `{"model":"llama3.1","message":{"role":"assistant","content":"All "},"done":false}
+{"model":"llama3.1","message":{"role":"assistant","content":"good"},"done":false}
+{"model":"llama3.1","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":120,"eval_count":30}
+`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL+"/", "", "llama3.1", ClientOptions{Ollama: true, AnalysisMaxTokens: 500})
+
+	content, usage, err := client.Analyze(context.Background(), "c", "system", "user")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if content != "All good" {
+		t.Errorf("Expected concatenated content, got %q", content)
+	}
+	if usage.PromptTokens != 120 || usage.CompletionTokens != 30 || usage.TotalTokens != 150 {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+
+	if got.Model != "llama3.1" || len(got.Messages) != 2 || got.Messages[0].Role != "system" || got.Messages[1].Content != "user" {
+		t.Errorf("Unexpected request: %+v", got)
+	}
+	if got.Options.NumPredict != 500 || got.Options.Temperature != 0.3 {
+		t.Errorf("Expected num_predict 500 and temperature 0.3, got %+v", got.Options)
+	}
+}
+
+func TestClient_OllamaError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantQuota bool
+	}{
+		{"model not found", http.StatusNotFound, false},
+		{"rate limited", http.StatusTooManyRequests, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, `{"error":"model 'llama9' not found"}`)
+			}))
+			defer server.Close()
+
+			client := NewClientWithOptions(server.URL, "", "llama9", ClientOptions{Ollama: true})
+			_, _, err := client.Analyze(context.Background(), "c", "system", "user")
+			if err == nil || !strings.Contains(err.Error(), "model 'llama9' not found") {
+				t.Fatalf("Expected Ollama error message, got %v", err)
+			}
+			if errors.Is(err, ErrQuotaExceeded) != tt.wantQuota {
+				t.Errorf("errors.Is(err, ErrQuotaExceeded) = %v, want %v", !tt.wantQuota, tt.wantQuota)
+			}
+		})
+	}
+}
+
+func TestNewOllamaChatRequest_JSONMode(t *testing.T) {
+	req := newOllamaChatRequest(ChatRequest{
+		Model:          "llama3.1",
+		Messages:       []ChatMessage{{Role: "user", Content: "hi"}},
+		ResponseFormat: &ResponseFormat{Type: responseFormatJSON},
+		Tools:          []Tool{analysisTool},
+	})
+	if req.Format != "json" {
+		t.Errorf("Expected format json, got %q", req.Format)
+	}
+	if !req.Stream {
+		t.Error("Expected streaming request")
+	}
+	if len(req.Tools) != 1 || req.Tools[0].Function.Name != analysisToolName {
+		t.Errorf("Expected tools to be passed through, got %+v", req.Tools)
+	}
+}
+
+func TestParseOllamaResponse(t *testing.T) {
+	t.Run("tool call arguments", func(t *testing.T) {
+		body := `{"model":"m","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"report_analysis","arguments":{"severity":"healthy","summary":"ok","errors":[]}}}]},"done":true,"prompt_eval_count":5,"eval_count":7}`
+		resp, err := parseOllamaResponse([]byte(body))
+		if err != nil {
+			t.Fatalf("parseOllamaResponse() error = %v", err)
+		}
+		calls := resp.Choices[0].Message.ToolCalls
+		if len(calls) != 1 || calls[0].Function.Name != analysisToolName {
+			t.Fatalf("Unexpected tool calls: %+v", calls)
+		}
+		if _, err := ParseStructuredAnalysis(calls[0].Function.Arguments); err != nil {
+			t.Errorf("Arguments should be a JSON string, got %q: %v", calls[0].Function.Arguments, err)
+		}
+		if resp.Usage.TotalTokens != 12 {
+			t.Errorf("Expected 12 total tokens, got %d", resp.Usage.TotalTokens)
+		}
+	})
+
+	t.Run("error line", func(t *testing.T) {
+		_, err := parseOllamaResponse([]byte(`{"message":{"content":"par"},"done":false}` + "\n" + `{"error":"out of memory"}`))
+		if err == nil || !strings.Contains(err.Error(), "out of memory") {
+			t.Errorf("Expected streamed error, got %v", err)
+		}
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		_, err := parseOllamaResponse([]byte(`{"message":{"content":"par"},"done":false}`))
+		if err == nil || !strings.Contains(err.Error(), "incomplete") {
+			t.Errorf("Expected incomplete response error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces outbound requests to a fixed number per minute. It is a token
+// bucket holding a single token: the first request passes immediately and each
+// following one waits until the bucket has refilled. Safe for concurrent use; a nil
+// *RateLimiter never blocks.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // Refill time of one token
+	next     time.Time     // Earliest time the next request may start
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerMinute requests per minute.
+// Returns nil (unlimited) when requestsPerMinute is 0 or less.
+func NewRateLimiter(requestsPerMinute int) *RateLimiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Minute / time.Duration(requestsPerMinute)}
+}
+
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = map[int]*RateLimiter{}
+)
+
+// SharedRateLimiter returns the process-wide limiter for requestsPerMinute, so every
+// client configured with the same limit draws from one bucket and concurrent scan
+// workers together stay under the limit. Returns nil (unlimited) for 0 or less.
+func SharedRateLimiter(requestsPerMinute int) *RateLimiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+
+	limiter, ok := sharedLimiters[requestsPerMinute]
+	if !ok {
+		limiter = NewRateLimiter(requestsPerMinute)
+		sharedLimiters[requestsPerMinute] = limiter
+	}
+	return limiter
+}
+
+// Wait blocks until the next request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
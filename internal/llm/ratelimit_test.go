@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter_Unlimited(t *testing.T) {
+	if NewRateLimiter(0) != nil || SharedRateLimiter(-1) != nil {
+		t.Error("Expected nil limiter for a non-positive limit")
+	}
+
+	var limiter *RateLimiter
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("nil limiter Wait() error = %v", err)
+	}
+}
+
+func TestSharedRateLimiter_SameInstance(t *testing.T) {
+	if SharedRateLimiter(42) != SharedRateLimiter(42) {
+		t.Error("Expected the same limiter for the same limit")
+	}
+	if SharedRateLimiter(42) == SharedRateLimiter(43) {
+		t.Error("Expected different limiters for different limits")
+	}
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ // nolint:errcheck,gosec
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: "ok"}}},
+		})
+	}))
+	defer server.Close()
+
+	// 600 requests per minute = one request every 100ms
+	const requests = 4
+	client := NewClientWithOptions(server.URL, "test-key", "test-model", ClientOptions{
+		RateLimiter: NewRateLimiter(600),
+	})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := client.Analyze(context.Background(), "c", "system", "user"); err != nil {
+				t.Errorf("Analyze() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed, minimum := time.Since(start), (requests-1)*100*time.Millisecond; elapsed < minimum {
+		t.Errorf("Expected %d requests to take at least %v, took %v", requests, minimum, elapsed)
+	}
+}
+
+func TestRateLimiter_ContextCancellation(t *testing.T) {
+	limiter := NewRateLimiter(1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
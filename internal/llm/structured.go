@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// responseFormatJSON is the response_format type that enables JSON mode.
+const responseFormatJSON = "json_object"
+
+// structuredOutputInstructions is appended to the system prompt in JSON mode.
+// OpenAI-compatible APIs require the word "JSON" to appear in the messages.
+const structuredOutputInstructions = `
+
+Respond with a single JSON object and nothing else, using this schema:
+{
+  "severity": "healthy" | "warning" | "critical",
+  "summary": "one or two sentence overview",
+  "errors": ["each distinct error or problem found"],
+  "recommendations": ["each suggested action"]
+}
+Use empty arrays when there are no errors or recommendations.`
+
+// StructuredAnalysis is the machine-parseable analysis returned in JSON mode.
+type StructuredAnalysis struct {
+	Severity        string   `json:"severity"` // healthy, warning or critical
+	Summary         string   `json:"summary"`
+	Errors          []string `json:"errors"`
+	Recommendations []string `json:"recommendations"`
+}
+
+// StructuredAnalyzer is implemented by clients that can request JSON output.
+// The pipeline type-asserts for it so plain ClientInterface implementations keep working.
+type StructuredAnalyzer interface {
+	// AnalyzeStructured performs analysis with response_format json_object.
+	// Returns the raw response content, the parsed analysis (nil if the model did not
+	// return valid JSON), and token usage.
+	AnalyzeStructured(ctx context.Context, containerName, systemPrompt, userPrompt string) (string, *StructuredAnalysis, *TokenUsage, error)
+}
+
+// Compile-time verification that clientImpl implements StructuredAnalyzer
+var _ StructuredAnalyzer = (*clientImpl)(nil)
+
+func (c *clientImpl) AnalyzeStructured(ctx context.Context, containerName, systemPrompt, userPrompt string) (string, *StructuredAnalysis, *TokenUsage, error) {
+	req := c.newChatRequest([]ChatMessage{
+		{Role: "system", Content: systemPrompt + structuredOutputInstructions},
+		{Role: "user", Content: userPrompt},
+	}, c.analysisTemperature, c.analysisMaxTokens)
+	req.ResponseFormat = &ResponseFormat{Type: responseFormatJSON}
+
+	resp, err := c.sendChatRequest(ctx, req)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", nil, nil, fmt.Errorf("no choices in response for container %s from model %s", containerName, c.model)
+	}
+
+	// Log the interaction if logger is configured
+	if c.logger != nil {
+		if logErr := c.logger.LogInteraction(containerName, userPrompt, req, resp); logErr != nil {
+			// Log error but don't fail the analysis
+			slog.Warn("failed to log LLM interaction", "container", containerName, "error", logErr)
+		}
+	}
+
+	content := resp.Choices[0].Message.Content
+	parsed, parseErr := ParseStructuredAnalysis(content)
+	if parseErr != nil {
+		// Model ignored JSON mode; callers fall back to the prose content
+		return content, nil, &resp.Usage, nil
+	}
+
+	return content, parsed, &resp.Usage, nil
+}
+
+// ParseStructuredAnalysis decodes a JSON analysis, tolerating a surrounding
+// Markdown code fence. Severity is normalized to lowercase; an unknown severity
+// is rejected so callers can fall back to keyword classification.
+func ParseStructuredAnalysis(content string) (*StructuredAnalysis, error) {
+	trimmed := strings.TrimSpace(content)
+	trimmed = strings.TrimPrefix(trimmed, "```json")
+	trimmed = strings.TrimPrefix(trimmed, "```")
+	trimmed = strings.TrimSuffix(trimmed, "```")
+
+	var analysis StructuredAnalysis
+	if err := json.Unmarshal([]byte(strings.TrimSpace(trimmed)), &analysis); err != nil {
+		return nil, fmt.Errorf("response is not a JSON analysis: %w", err)
+	}
+
+	analysis.Severity = strings.ToLower(strings.TrimSpace(analysis.Severity))
+	switch analysis.Severity {
+	case "healthy", "warning", "critical":
+	default:
+		return nil, fmt.Errorf("unknown severity %q in JSON analysis", analysis.Severity)
+	}
+
+	return &analysis, nil
+}
+
+// Markdown renders the structured analysis in the same shape as the prose prompt
+// output (**Summary**, **Errors**, ...) so reports and knowledge base parsing work unchanged.
+// The Errors section is omitted when empty to avoid false keyword matches.
+func (a *StructuredAnalysis) Markdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "**Severity**: %s\n\n", a.Severity)
+	fmt.Fprintf(&sb, "**Summary**: %s\n", a.Summary)
+
+	if len(a.Errors) > 0 {
+		sb.WriteString("\n**Errors**:\n")
+		for _, e := range a.Errors {
+			fmt.Fprintf(&sb, "- %s\n", e)
+		}
+	}
+
+	if len(a.Recommendations) > 0 {
+		sb.WriteString("\n**Recommendations**:\n")
+		for _, r := range a.Recommendations {
+			fmt.Fprintf(&sb, "- %s\n", r)
+		}
+	}
+
+	return sb.String()
+}
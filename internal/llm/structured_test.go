@@ -0,0 +1,148 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseStructuredAnalysis(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantErr      bool
+		wantSeverity string
+		wantErrors   int
+	}{
+		{
+			name:         "plain JSON",
+			content:      `{"severity":"warning","summary":"Slow queries","errors":[],"recommendations":["add index"]}`,
+			wantSeverity: "warning",
+		},
+		{
+			name:         "fenced JSON with uppercase severity",
+			content:      "```json\n{\"severity\":\"CRITICAL\",\"summary\":\"Crash\",\"errors\":[\"panic\"]}\n```",
+			wantSeverity: "critical",
+			wantErrors:   1,
+		},
+		{
+			name:    "prose",
+			content: "Everything looks fine.",
+			wantErr: true,
+		},
+		{
+			name:    "unknown severity",
+			content: `{"severity":"meh","summary":"?"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStructuredAnalysis(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.Severity != tt.wantSeverity {
+				t.Errorf("Expected severity %q, got %q", tt.wantSeverity, got.Severity)
+			}
+			if len(got.Errors) != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d", tt.wantErrors, len(got.Errors))
+			}
+		})
+	}
+}
+
+func TestStructuredAnalysis_Markdown(t *testing.T) {
+	healthy := &StructuredAnalysis{Severity: "healthy", Summary: "All good"}
+	md := healthy.Markdown()
+	if !strings.Contains(md, "**Summary**: All good") {
+		t.Errorf("Expected summary line, got:\n%s", md)
+	}
+	if strings.Contains(md, "**Errors**") {
+		t.Error("Empty errors section should be omitted")
+	}
+
+	critical := &StructuredAnalysis{
+		Severity:        "critical",
+		Summary:         "Crash loop",
+		Errors:          []string{"panic: nil map"},
+		Recommendations: []string{"roll back"},
+	}
+	md = critical.Markdown()
+	for _, want := range []string{"**Severity**: critical", "**Errors**:\n- panic: nil map", "**Recommendations**:\n- roll back"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected %q in:\n%s", want, md)
+		}
+	}
+}
+
+func TestClient_AnalyzeStructured(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantStructured bool
+	}{
+		{name: "JSON response", content: `{"severity":"warning","summary":"Disk almost full"}`, wantStructured: true},
+		{name: "prose response", content: "Disk almost full", wantStructured: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req ChatRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("Failed to decode request: %v", err)
+				}
+				if req.ResponseFormat == nil || req.ResponseFormat.Type != responseFormatJSON {
+					t.Errorf("Expected response_format json_object, got %+v", req.ResponseFormat)
+				}
+				if !strings.Contains(req.Messages[0].Content, "JSON") {
+					t.Error("Expected system prompt to mention JSON")
+				}
+
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(ChatResponse{
+					Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: tt.content}}},
+					Usage:   TokenUsage{TotalTokens: 42},
+				})
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "test-model").(*clientImpl)
+			content, structured, usage, err := client.AnalyzeStructured(context.Background(), "test", "system", "user")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if content != tt.content {
+				t.Errorf("Expected raw content %q, got %q", tt.content, content)
+			}
+			if (structured != nil) != tt.wantStructured {
+				t.Errorf("Expected structured=%v, got %+v", tt.wantStructured, structured)
+			}
+			if usage.TotalTokens != 42 {
+				t.Errorf("Expected 42 tokens, got %d", usage.TotalTokens)
+			}
+		})
+	}
+}
+
+func TestChatRequest_OmitsResponseFormatByDefault(t *testing.T) {
+	body, err := json.Marshal(ChatRequest{Model: "m"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(body), "response_format") {
+		t.Errorf("response_format should be omitted when nil: %s", body)
+	}
+}
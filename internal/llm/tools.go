@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// analysisToolName is the function the model calls to report its analysis.
+const analysisToolName = "report_analysis"
+
+// toolOutputInstructions is appended to the system prompt in tools mode.
+const toolOutputInstructions = `
+
+Report your analysis by calling the ` + analysisToolName + ` function. Use empty arrays when there are no errors or recommendations.`
+
+// analysisTool is the function schema matching StructuredAnalysis.
+var analysisTool = Tool{
+	Type: "function",
+	Function: ToolFunction{
+		Name:        analysisToolName,
+		Description: "Report the result of the container log analysis.",
+		Parameters: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "severity": {"type": "string", "enum": ["healthy", "warning", "critical"]},
+    "summary": {"type": "string", "description": "one or two sentence overview"},
+    "errors": {"type": "array", "items": {"type": "string"}, "description": "each distinct error or problem found"},
+    "recommendations": {"type": "array", "items": {"type": "string"}, "description": "each suggested action"}
+  },
+  "required": ["severity", "summary", "errors"]
+}`),
+	},
+}
+
+// ToolAnalyzer is implemented by clients that can request structured output through
+// the tools (function calling) API, for gateways that do not support JSON mode.
+type ToolAnalyzer interface {
+	// AnalyzeWithTool performs analysis with a forced report_analysis function call.
+	// Returns the raw response content, the parsed analysis (nil if the model did not
+	// call the function with valid arguments), and token usage.
+	AnalyzeWithTool(ctx context.Context, containerName, systemPrompt, userPrompt string) (string, *StructuredAnalysis, *TokenUsage, error)
+}
+
+// Compile-time verification that clientImpl implements ToolAnalyzer
+var _ ToolAnalyzer = (*clientImpl)(nil)
+
+func (c *clientImpl) AnalyzeWithTool(ctx context.Context, containerName, systemPrompt, userPrompt string) (string, *StructuredAnalysis, *TokenUsage, error) {
+	req := c.newChatRequest([]ChatMessage{
+		{Role: "system", Content: systemPrompt + toolOutputInstructions},
+		{Role: "user", Content: userPrompt},
+	}, c.analysisTemperature, c.analysisMaxTokens)
+	req.Tools = []Tool{analysisTool}
+	req.ToolChoice = &ToolChoice{Type: "function", Function: ToolChoiceFunction{Name: analysisToolName}}
+
+	resp, err := c.sendChatRequest(ctx, req)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", nil, nil, fmt.Errorf("no choices in response for container %s from model %s", containerName, c.model)
+	}
+
+	// Log the interaction if logger is configured
+	if c.logger != nil {
+		if logErr := c.logger.LogInteraction(containerName, userPrompt, req, resp); logErr != nil {
+			// Log error but don't fail the analysis
+			slog.Warn("failed to log LLM interaction", "container", containerName, "error", logErr)
+		}
+	}
+
+	message := resp.Choices[0].Message
+	for _, call := range message.ToolCalls {
+		if call.Function.Name != analysisToolName {
+			continue
+		}
+		parsed, parseErr := ParseStructuredAnalysis(call.Function.Arguments)
+		if parseErr != nil {
+			break
+		}
+		return call.Function.Arguments, parsed, &resp.Usage, nil
+	}
+
+	// Model did not call the function; callers fall back to the prose content
+	return message.Content, nil, &resp.Usage, nil
+}
@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_AnalyzeWithTool(t *testing.T) {
+	tests := []struct {
+		name           string
+		message        ChatMessage
+		wantContent    string
+		wantStructured bool
+	}{
+		{
+			name: "function call",
+			message: ChatMessage{Role: "assistant", ToolCalls: []ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: ToolCallFunction{Name: analysisToolName, Arguments: `{"severity":"critical","summary":"OOM","errors":["killed"]}`},
+			}}},
+			wantContent:    `{"severity":"critical","summary":"OOM","errors":["killed"]}`,
+			wantStructured: true,
+		},
+		{
+			name:        "prose response",
+			message:     ChatMessage{Role: "assistant", Content: "Disk almost full"},
+			wantContent: "Disk almost full",
+		},
+		{
+			name: "invalid arguments",
+			message: ChatMessage{Role: "assistant", Content: "fallback", ToolCalls: []ToolCall{{
+				Function: ToolCallFunction{Name: analysisToolName, Arguments: `{"severity":"unknown"}`},
+			}}},
+			wantContent: "fallback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req ChatRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("Failed to decode request: %v", err)
+				}
+				if len(req.Tools) != 1 || req.Tools[0].Function.Name != analysisToolName {
+					t.Errorf("Expected the %s tool, got %+v", analysisToolName, req.Tools)
+				}
+				if req.ToolChoice == nil || req.ToolChoice.Function.Name != analysisToolName {
+					t.Errorf("Expected tool_choice to force %s, got %+v", analysisToolName, req.ToolChoice)
+				}
+				if req.ResponseFormat != nil {
+					t.Errorf("Expected no response_format in tools mode, got %+v", req.ResponseFormat)
+				}
+
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(ChatResponse{
+					Choices: []Choice{{Message: tt.message}},
+					Usage:   TokenUsage{TotalTokens: 7},
+				})
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "test-model").(*clientImpl)
+			content, structured, usage, err := client.AnalyzeWithTool(context.Background(), "test", "system", "user")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if content != tt.wantContent {
+				t.Errorf("Expected content %q, got %q", tt.wantContent, content)
+			}
+			if (structured != nil) != tt.wantStructured {
+				t.Errorf("Expected structured=%v, got %+v", tt.wantStructured, structured)
+			}
+			if structured != nil && structured.Severity != "critical" {
+				t.Errorf("Expected critical severity, got %q", structured.Severity)
+			}
+			if usage.TotalTokens != 7 {
+				t.Errorf("Expected 7 tokens, got %d", usage.TotalTokens)
+			}
+		})
+	}
+}
+
+func TestAnalysisTool_SchemaIsValidJSON(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(analysisTool.Function.Parameters, &schema); err != nil {
+		t.Fatalf("tool parameters are not valid JSON: %v", err)
+	}
+	if !strings.Contains(string(analysisTool.Function.Parameters), `"severity"`) {
+		t.Error("schema should describe severity")
+	}
+}
+
+func TestChatRequest_OmitsToolsByDefault(t *testing.T) {
+	data, err := json.Marshal(ChatRequest{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tools") || strings.Contains(string(data), "tool_choice") {
+		t.Errorf("Expected no tool fields, got %s", data)
+	}
+}
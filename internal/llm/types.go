@@ -1,6 +1,10 @@
 package llm
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
 
 // ClientInterface defines the interface for LLM client operations
 type ClientInterface interface {
@@ -13,15 +17,66 @@ type ClientInterface interface {
 type ChatMessage struct {
 	Role    string `json:"role"`    // system, user, assistant
 	Content string `json:"content"` // message content
+	// ToolCalls holds the function calls of an assistant message (tools API only)
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
 // ChatRequest represents a request to the chat completion API
 type ChatRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	Temperature float64       `json:"temperature,omitempty"`
-	MaxTokens   int           `json:"max_tokens,omitempty"`
-	TopP        float64       `json:"top_p,omitempty"`
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	// Temperature is always sent: 0 selects deterministic sampling, not the API default
+	Temperature      float64 `json:"temperature"`
+	MaxTokens        int     `json:"max_tokens,omitempty"`
+	TopP             float64 `json:"top_p,omitempty"`
+	FrequencyPenalty float64 `json:"frequency_penalty,omitempty"`
+	// ResponseFormat requests JSON mode from OpenAI-compatible APIs (nil = freeform text)
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	// Tools and ToolChoice offer functions the model can call (nil = no tools)
+	Tools      []Tool      `json:"tools,omitempty"`
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+}
+
+// Tool describes a function the model may call
+type Tool struct {
+	Type     string       `json:"type"` // always "function"
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is the name, description and JSON schema of a callable function
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ToolChoice forces the model to call a specific function
+type ToolChoice struct {
+	Type     string             `json:"type"` // always "function"
+	Function ToolChoiceFunction `json:"function"`
+}
+
+// ToolChoiceFunction names the function a ToolChoice forces
+type ToolChoiceFunction struct {
+	Name string `json:"name"`
+}
+
+// ToolCall is a function call returned by the model
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction holds the called function and its JSON-encoded arguments
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ResponseFormat selects the output format of a chat completion
+type ResponseFormat struct {
+	Type string `json:"type"` // "text" or "json_object"
 }
 
 // ChatResponse represents the API response
@@ -56,6 +111,38 @@ type APIError struct {
 	Code    string `json:"code"`
 }
 
+// quotaErrorCodes are APIError codes/types that signal an exhausted quota or rate limit.
+var quotaErrorCodes = map[string]bool{
+	"insufficient_quota":  true,
+	"rate_limit_exceeded": true,
+	"quota_exceeded":      true,
+}
+
+// nonRetryableErrorCodes are APIError codes/types that fail the same way on every
+// attempt, even with a retryable HTTP status: malformed or oversized requests, an
+// unknown model or key, and an exhausted (as opposed to rate-limited) quota.
+var nonRetryableErrorCodes = map[string]bool{
+	"invalid_request_error":   true,
+	"context_length_exceeded": true,
+	"invalid_api_key":         true,
+	"model_not_found":         true,
+	"insufficient_quota":      true,
+	"quota_exceeded":          true,
+}
+
+// ErrQuotaExceeded is wrapped into errors for HTTP 429 responses.
+var ErrQuotaExceeded = errors.New("LLM quota or rate limit exhausted")
+
+// IsQuotaError reports whether err means the LLM quota or rate limit is exhausted,
+// either via an HTTP 429 response or an API error with a quota/rate-limit code.
+func IsQuotaError(err error) bool {
+	if errors.Is(err, ErrQuotaExceeded) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (quotaErrorCodes[apiErr.Code] || quotaErrorCodes[apiErr.Type])
+}
+
 // Error implements the error interface
 func (e *APIError) Error() string {
 	if e.Code != "" {
@@ -1,6 +1,10 @@
 package llm
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
 
 func TestAPIError_Error(t *testing.T) {
 	withCode := &APIError{Code: "rate_limit_exceeded", Message: "too many requests"}
@@ -13,3 +17,27 @@ func TestAPIError_Error(t *testing.T) {
 		t.Errorf("unexpected: %s", got)
 	}
 }
+
+func TestIsQuotaError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"quota sentinel", fmt.Errorf("%w: status 429", ErrQuotaExceeded), true},
+		{"insufficient quota code", &APIError{Code: "insufficient_quota"}, true},
+		{"rate limit type", &APIError{Type: "rate_limit_exceeded"}, true},
+		{"wrapped api error", fmt.Errorf("analysis failed: %w", &APIError{Code: "insufficient_quota"}), true},
+		{"other api error", &APIError{Code: "invalid_api_key"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsQuotaError(tt.err); got != tt.want {
+				t.Errorf("IsQuotaError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
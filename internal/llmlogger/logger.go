@@ -9,12 +9,17 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/privacy"
+	"github.com/zorak1103/dlia/internal/sanitize"
 )
 
 // Logger handles logging of LLM interactions to Markdown files.
 type Logger struct {
 	baseDir string
 	enabled bool
+	privacy config.PrivacyConfig
 }
 
 // NewLogger creates a new Logger instance.
@@ -26,6 +31,12 @@ func NewLogger(baseDir string, enabled bool) *Logger {
 	}
 }
 
+// SetPrivacy makes the logger redact the categories enabled in cfg from every log
+// file, including the raw LLM response, which may quote log lines back.
+func (l *Logger) SetPrivacy(cfg config.PrivacyConfig) {
+	l.privacy = cfg
+}
+
 // IsEnabled returns whether logging is enabled.
 func (l *Logger) IsEnabled() bool {
 	return l != nil && l.enabled
@@ -63,6 +74,7 @@ func (l *Logger) LogInteraction(containerName, originalInput string, request, re
 
 	// Generate Markdown content
 	content := formatMarkdown(containerName, timestamp, originalInput, requestJSON, responseJSON)
+	content = privacy.Scrub(content, l.privacy)
 
 	// Write to file with secure permissions (0600 = owner read/write only)
 	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
@@ -98,10 +110,11 @@ func formatMarkdown(containerName string, timestamp time.Time, originalInput str
 }
 
 // sanitizeFilename removes or replaces characters that are invalid in filenames.
+// "/" is mapped by sanitize.Name so the directory matches the one cleanup expects.
 func sanitizeFilename(name string) string {
-	// Replace common invalid characters with underscores
-	invalid := []rune{'/', '\\', ':', '*', '?', '"', '<', '>', '|'}
-	result := []rune(name)
+	// Replace remaining invalid characters with underscores
+	invalid := []rune{'\\', ':', '*', '?', '"', '<', '>', '|'}
+	result := []rune(sanitize.Name(name))
 	for i, r := range result {
 		for _, inv := range invalid {
 			if r == inv {
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -75,6 +77,27 @@ func TestLogInteraction(t *testing.T) {
 		}
 	})
 
+	t.Run("privacy settings redact the log file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		logger := NewLogger(tmpDir, true)
+		logger.SetPrivacy(config.PrivacyConfig{AnonymizeSecrets: true})
+
+		secret := "sk-abcdefghijklmnopqrstuvwxyz123456"
+		err := logger.LogInteraction("my-container", "input", map[string]string{"prompt": "key " + secret}, map[string]string{"reply": "found " + secret})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		files, _ := filepath.Glob(filepath.Join(tmpDir, "my-container", "*.md"))
+		if len(files) != 1 {
+			t.Fatalf("expected 1 log file, found %d", len(files))
+		}
+		content, _ := os.ReadFile(files[0])
+		if strings.Contains(string(content), secret) {
+			t.Errorf("expected the secret to be redacted from the log file, got:\n%s", content)
+		}
+	})
+
 	t.Run("enabled logger creates file with correct content", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		logger := NewLogger(tmpDir, true)
@@ -168,7 +191,7 @@ func TestSanitizeFilename(t *testing.T) {
 		expected string
 	}{
 		{"simple", "simple"},
-		{"with/slash", "with_slash"},
+		{"with/slash", "with~slash"},
 		{"with\\backslash", "with_backslash"},
 		{"with:colon", "with_colon"},
 		{"with*asterisk", "with_asterisk"},
@@ -177,7 +200,7 @@ func TestSanitizeFilename(t *testing.T) {
 		{"with<less", "with_less"},
 		{"with>greater", "with_greater"},
 		{"with|pipe", "with_pipe"},
-		{"multiple/invalid\\chars", "multiple_invalid_chars"},
+		{"multiple/invalid\\chars", "multiple~invalid_chars"},
 		{"already_valid_name", "already_valid_name"},
 	}
 
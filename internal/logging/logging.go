@@ -0,0 +1,61 @@
+// Package logging configures the structured operational log (log/slog).
+//
+// User-facing command output stays on stdout; the operational log carries
+// diagnostics such as Docker connections, state loading and LLM retries, and is
+// written to stderr so it can be collected separately, e.g. as JSON.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// ParseLevel converts a logging.level value to a slog level. An empty value is warn.
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "", "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelWarn, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// New creates a logger writing to w in the given format ("text" or "json", empty =
+// text) that drops records below level.
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// Setup installs the logger for level and format as the slog default. With verbose
+// the level is lowered to debug.
+func Setup(w io.Writer, level, format string, verbose bool) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if verbose {
+		lvl = min(lvl, slog.LevelDebug)
+	}
+	logger, err := New(w, lvl, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
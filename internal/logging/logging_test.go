@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelWarn, false},
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelWarn, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelInfo, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Debug("hidden")
+	logger.Info("loaded state", "containers", 3)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one record above the level, got %q", buf.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("record is not JSON: %v", err)
+	}
+	if record["msg"] != "loaded state" || record["containers"] != float64(3) {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, slog.LevelInfo, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestSetup_VerboseLowersLevel(t *testing.T) {
+	original := slog.Default()
+	defer slog.SetDefault(original)
+
+	var buf bytes.Buffer
+	if err := Setup(&buf, "error", "text", true); err != nil {
+		t.Fatal(err)
+	}
+	slog.Debug("connecting to Docker", "socket", "unix:///var/run/docker.sock")
+
+	if !strings.Contains(buf.String(), "msg=\"connecting to Docker\"") {
+		t.Errorf("expected debug record with --verbose, got %q", buf.String())
+	}
+}
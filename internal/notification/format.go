@@ -0,0 +1,211 @@
+package notification
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+// scanSummaryTitle is the title of run-level notifications.
+const scanSummaryTitle = "DLIA Scan Complete"
+
+// ContainerStatus is one entry of the per-container status list shown by rich formatters.
+type ContainerStatus struct {
+	Name       string
+	Severity   knowledge.Severity
+	Analysis   string
+	TokensUsed int // LLM tokens spent on this container; 0 if unknown
+}
+
+// ContainerStatuses classifies each free-text container analysis by keywords, most
+// severe first and then by name.
+func ContainerStatuses(containerAnalyses map[string]string) []ContainerStatus {
+	statuses := make([]ContainerStatus, 0, len(containerAnalyses))
+	for name, analysis := range containerAnalyses {
+		statuses = append(statuses, ContainerStatus{Name: name, Severity: knowledge.ClassifySeverity(analysis), Analysis: analysis})
+	}
+	SortContainerStatuses(statuses)
+	return statuses
+}
+
+// SortContainerStatuses orders statuses most severe first and then by name.
+func SortContainerStatuses(statuses []ContainerStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Severity != statuses[j].Severity {
+			return statuses[i].Severity > statuses[j].Severity
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+}
+
+// formatter renders notification messages for one kind of Shoutrrr service. The
+// returned params are passed to the sender, so formatters can set service-specific
+// options such as title or color. Formatters whose output Shoutrrr cannot deliver
+// as-is also implement directSender.
+type formatter interface {
+	scanSummary(summary string, containerCount int, severity knowledge.Severity, containers []ContainerStatus, timestamp time.Time) (string, types.Params)
+	containerAlert(containerName, analysis string, severity knowledge.Severity, timestamp time.Time) (string, types.Params)
+}
+
+// directSender is implemented by formatters that deliver their own payload instead
+// of going through Shoutrrr, e.g. because Shoutrrr only sends plain text.
+type directSender interface {
+	send(shoutrrrURL, message string, params types.Params) error
+}
+
+// formatterFor returns the formatter for a Shoutrrr service type (the URL scheme).
+// Services without a dedicated formatter get plain text.
+func formatterFor(service string) formatter {
+	switch service {
+	case "teams":
+		return teamsFormatter{}
+	case "slack":
+		return slackFormatter{}
+	default:
+		return plainFormatter{}
+	}
+}
+
+// plainFormatter renders emoji-prefixed plain text that reads well in any service.
+// Scan summaries are rendered from the notification prompt template when a loader
+// is set; a template that fails to render falls back to the built-in format.
+type plainFormatter struct {
+	prompts *prompts.PromptLoader
+}
+
+func (f plainFormatter) scanSummary(summary string, containerCount int, severity knowledge.Severity, containers []ContainerStatus, timestamp time.Time) (string, types.Params) {
+	if f.prompts == nil {
+		return formatScanSummary(summary, containerCount, severity, timestamp), types.Params{}
+	}
+
+	message, err := f.prompts.NotificationPrompt(notificationData(summary, containerCount, severity, containers, timestamp))
+	if err != nil {
+		slog.Warn("Notification prompt failed, using the built-in message format", "error", err)
+		return formatScanSummary(summary, containerCount, severity, timestamp), types.Params{}
+	}
+	return message, types.Params{}
+}
+
+func (plainFormatter) containerAlert(containerName, analysis string, severity knowledge.Severity, timestamp time.Time) (string, types.Params) {
+	title := containerAlertTitle(containerName, severity)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🐳 %s\n", title)
+	fmt.Fprintf(&sb, "📅 Time: %s\n", timestamp.Format("2006-01-02 15:04:05"))
+	sb.WriteString(severityLine(severity))
+	sb.WriteString("\n")
+	sb.WriteString(analysis)
+
+	params := types.Params{}
+	params.SetTitle(title)
+	return sb.String(), params
+}
+
+// teamsFormatter renders a Microsoft Teams message card: the title and a severity
+// theme color are set through params, and the body uses markdown with a
+// per-container status list. Shoutrrr turns every line into a card section.
+type teamsFormatter struct{}
+
+func (teamsFormatter) scanSummary(summary string, containerCount int, severity knowledge.Severity, containers []ContainerStatus, timestamp time.Time) (string, types.Params) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**Time:** %s\n", timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&sb, "**Containers:** %d\n", containerCount)
+	fmt.Fprintf(&sb, "**Status:** %s\n", strings.TrimSuffix(severityLine(severity), "\n"))
+	if len(containers) > 0 {
+		sb.WriteString("\n")
+		for _, c := range containers {
+			fmt.Fprintf(&sb, "%s **%s**: %s\n", severityIcon(c.Severity), c.Name, severityLabel(c.Severity))
+		}
+	}
+	sb.WriteString("\n")
+	sb.WriteString(summary)
+
+	return sb.String(), teamsParams(scanSummaryTitle, severity)
+}
+
+func (teamsFormatter) containerAlert(containerName, analysis string, severity knowledge.Severity, timestamp time.Time) (string, types.Params) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**Time:** %s\n", timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&sb, "**Status:** %s\n", strings.TrimSuffix(severityLine(severity), "\n"))
+	sb.WriteString("\n")
+	sb.WriteString(analysis)
+
+	return sb.String(), teamsParams(containerAlertTitle(containerName, severity), severity)
+}
+
+// teamsParams sets the card title and theme color for a severity.
+func teamsParams(title string, severity knowledge.Severity) types.Params {
+	params := types.Params{}
+	params.SetTitle(title)
+	params["color"] = teamsColor(severity)
+	return params
+}
+
+// teamsColor returns the card theme color (hex without '#') for a severity.
+func teamsColor(severity knowledge.Severity) string {
+	switch severity {
+	case knowledge.SeverityCritical:
+		return "D13438"
+	case knowledge.SeverityWarning:
+		return "FFB900"
+	default:
+		return "107C10"
+	}
+}
+
+// containerAlertTitle returns the title of a per-container alert.
+func containerAlertTitle(containerName string, severity knowledge.Severity) string {
+	return fmt.Sprintf("DLIA %s: %s", severityLabel(severity), containerName)
+}
+
+// severityIcon returns the status emoji for a severity, matching the knowledge base markers.
+func severityIcon(severity knowledge.Severity) string {
+	switch severity {
+	case knowledge.SeverityCritical:
+		return "🔴"
+	case knowledge.SeverityWarning:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+// notificationData collects the variables of the notification prompt template.
+func notificationData(summary string, containerCount int, severity knowledge.Severity, containers []ContainerStatus, timestamp time.Time) prompts.NotificationData {
+	data := prompts.NotificationData{
+		Title:          scanSummaryTitle,
+		Time:           timestamp.Format("2006-01-02 15:04:05"),
+		Summary:        summary,
+		ContainerCount: containerCount,
+		Severity:       severityLabel(severity),
+		Status:         strings.TrimSuffix(severityLine(severity), "\n"),
+		TokensUsed:     totalTokens(containers),
+		Containers:     make([]prompts.NotificationContainer, 0, len(containers)),
+	}
+	for _, c := range containers {
+		if c.Severity >= knowledge.SeverityWarning {
+			data.IssueCount++
+		}
+		data.Containers = append(data.Containers, prompts.NotificationContainer{
+			Name:       c.Name,
+			Severity:   severityLabel(c.Severity),
+			TokensUsed: c.TokensUsed,
+		})
+	}
+	return data
+}
+
+// totalTokens sums the tokens used by all containers.
+func totalTokens(containers []ContainerStatus) int {
+	total := 0
+	for _, c := range containers {
+		total += c.TokensUsed
+	}
+	return total
+}
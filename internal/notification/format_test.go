@@ -0,0 +1,164 @@
+package notification
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/prompts"
+)
+
+func TestFormatterFor(t *testing.T) {
+	if _, ok := formatterFor("teams").(teamsFormatter); !ok {
+		t.Error("formatterFor(teams) should return the Teams formatter")
+	}
+	if _, ok := formatterFor("slack").(slackFormatter); !ok {
+		t.Error("formatterFor(slack) should return the Slack formatter")
+	}
+	for _, service := range []string{"discord", "smtp", "unknown"} {
+		if _, ok := formatterFor(service).(plainFormatter); !ok {
+			t.Errorf("formatterFor(%s) should return the plain text formatter", service)
+		}
+	}
+}
+
+func TestContainerStatuses(t *testing.T) {
+	got := ContainerStatuses(map[string]string{
+		"web":   "All good",
+		"db":    "Critical: connection pool exhausted",
+		"cache": "Warning: eviction rate rising",
+		"api":   "Nothing notable",
+	})
+
+	want := []struct {
+		name     string
+		severity knowledge.Severity
+	}{
+		{"db", knowledge.SeverityCritical},
+		{"cache", knowledge.SeverityWarning},
+		{"api", knowledge.SeverityHealthy},
+		{"web", knowledge.SeverityHealthy},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ContainerStatuses() returned %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Name != want[i].name || got[i].Severity != want[i].severity {
+			t.Errorf("entry %d = %s/%s, want %s/%s", i, got[i].Name, got[i].Severity, want[i].name, want[i].severity)
+		}
+	}
+	if got[0].Analysis != "Critical: connection pool exhausted" {
+		t.Errorf("Analysis not carried over: %q", got[0].Analysis)
+	}
+}
+
+func TestTeamsFormatter_ScanSummary(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	containers := []ContainerStatus{
+		{Name: "db", Severity: knowledge.SeverityCritical},
+		{Name: "web", Severity: knowledge.SeverityHealthy},
+	}
+
+	message, params := teamsFormatter{}.scanSummary("Executive summary", 2, knowledge.SeverityCritical, containers, timestamp)
+
+	for _, want := range []string{"**Time:** 2025-01-02 03:04:05", "**Containers:** 2", "🔴 **db**: Critical", "🟢 **web**: Healthy", "Executive summary"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message should contain %q, got: %q", want, message)
+		}
+	}
+	if title, _ := params.Title(); title != scanSummaryTitle {
+		t.Errorf("title = %q, want %q", title, scanSummaryTitle)
+	}
+	if params["color"] != "D13438" {
+		t.Errorf("color = %q, want critical red", params["color"])
+	}
+}
+
+func TestTeamsFormatter_ContainerAlert(t *testing.T) {
+	message, params := teamsFormatter{}.containerAlert("cache", "Eviction rate rising", knowledge.SeverityWarning, time.Now())
+
+	if !strings.Contains(message, "Eviction rate rising") {
+		t.Errorf("message should contain the analysis, got: %q", message)
+	}
+	if title, _ := params.Title(); title != "DLIA Warning: cache" {
+		t.Errorf("title = %q, want %q", title, "DLIA Warning: cache")
+	}
+	if params["color"] != "FFB900" {
+		t.Errorf("color = %q, want warning amber", params["color"])
+	}
+}
+
+func TestPlainFormatter_MatchesFormatScanSummary(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	containers := []ContainerStatus{{Name: "db", Severity: knowledge.SeverityCritical}}
+
+	message, params := plainFormatter{}.scanSummary("All good", 3, knowledge.SeverityWarning, containers, timestamp)
+
+	if want := formatScanSummary("All good", 3, knowledge.SeverityWarning, timestamp); message != want {
+		t.Errorf("scanSummary() = %q, want %q", message, want)
+	}
+	if len(params) != 0 {
+		t.Errorf("plain scan summary should not set params, got %v", params)
+	}
+}
+
+func TestPlainFormatter_NotificationPrompt(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	containers := []ContainerStatus{
+		{Name: "db", Severity: knowledge.SeverityCritical, TokensUsed: 100},
+		{Name: "web", Severity: knowledge.SeverityHealthy, TokensUsed: 50},
+	}
+
+	t.Run("default template matches built-in format", func(t *testing.T) {
+		f := plainFormatter{prompts: prompts.NewPromptLoader(&config.Config{})}
+
+		message, _ := f.scanSummary("Database down", 2, knowledge.SeverityCritical, containers, timestamp)
+
+		if want := formatScanSummary("Database down", 2, knowledge.SeverityCritical, timestamp); message != want {
+			t.Errorf("scanSummary() = %q, want %q", message, want)
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notification.md")
+		content := "{{.Severity}} {{.IssueCount}}/{{.ContainerCount}} tokens={{.TokensUsed}}{{range .Containers}} {{.Name}}={{.Severity}}{{end}}"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write template: %v", err)
+		}
+		f := plainFormatter{prompts: prompts.NewPromptLoader(&config.Config{Prompts: config.PromptsConfig{NotificationPrompt: path}})}
+
+		message, _ := f.scanSummary("Database down", 2, knowledge.SeverityCritical, containers, timestamp)
+
+		if want := "Critical 1/2 tokens=150 db=Critical web=Healthy"; message != want {
+			t.Errorf("scanSummary() = %q, want %q", message, want)
+		}
+	})
+
+	t.Run("broken template falls back", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notification.md")
+		if err := os.WriteFile(path, []byte("{{.Unknown}}"), 0o600); err != nil {
+			t.Fatalf("Failed to write template: %v", err)
+		}
+		f := plainFormatter{prompts: prompts.NewPromptLoader(&config.Config{Prompts: config.PromptsConfig{NotificationPrompt: path}})}
+
+		message, _ := f.scanSummary("Database down", 2, knowledge.SeverityCritical, containers, timestamp)
+
+		if want := formatScanSummary("Database down", 2, knowledge.SeverityCritical, timestamp); message != want {
+			t.Errorf("scanSummary() = %q, want %q", message, want)
+		}
+	})
+}
+
+func TestNotifier_Preview_Teams(t *testing.T) {
+	notifier := &Notifier{enabled: true, shoutrrrURL: "teams://group@tenant/altId/groupOwner?host=example.webhook.office.com"}
+
+	preview := notifier.Preview("Executive summary", 1, knowledge.SeverityWarning, ContainerStatus{Name: "cache", Severity: knowledge.SeverityWarning})
+
+	if !strings.Contains(preview, "🟡 **cache**: Warning") {
+		t.Errorf("Teams preview should list container statuses, got: %q", preview)
+	}
+}
@@ -2,18 +2,26 @@
 package notification
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/containrrr/shoutrrr"
+	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/knowledge"
+	"github.com/zorak1103/dlia/internal/prompts"
 )
 
 // Notifier handles sending notifications via Shoutrrr
 type Notifier struct {
 	enabled     bool
 	shoutrrrURL string
+	minSeverity knowledge.Severity
+	pendingDir  string // Failed notifications are queued here for a retry; empty disables the queue
+	maxPending  int
+	prompts     *prompts.PromptLoader // Renders plain text scan summaries; nil uses the built-in format
 }
 
 // NewNotifier initializes a Shoutrrr-based notification client from config.
@@ -27,49 +35,198 @@ func NewNotifier(cfg *config.Config) (*Notifier, error) {
 		return &Notifier{enabled: false}, fmt.Errorf("notification enabled but shoutrrr_url not configured: provide URL in format 'service://credentials' (e.g., slack://token@channel, discord://token@webhookid)")
 	}
 
+	minSeverity, err := knowledge.ParseSeverity(cfg.Notification.MinSeverity)
+	if err != nil {
+		return &Notifier{enabled: false}, fmt.Errorf("invalid notification.min_severity: %w", err)
+	}
+
 	return &Notifier{
 		enabled:     true,
 		shoutrrrURL: cfg.Notification.ShoutrrURL,
+		minSeverity: minSeverity,
+		pendingDir:  cfg.Notification.PendingDir,
+		maxPending:  cfg.Notification.MaxPending,
+		prompts:     prompts.NewPromptLoader(cfg),
 	}, nil
 }
 
+// ShouldNotify reports whether a scan with the given severity meets the configured threshold.
+func (n *Notifier) ShouldNotify(severity knowledge.Severity) bool {
+	return n.enabled && severity >= n.minSeverity
+}
+
 // SendScanSummary delivers scan results via the configured notification channel.
-func (n *Notifier) SendScanSummary(summary string, containerCount int, issuesFound bool) error {
-	if !n.enabled {
-		return nil // Notifications disabled
+// Scans whose severity is below the configured minimum are silently skipped.
+// containers feeds the per-container status list of rich formats such as Teams
+// cards; plain text messages do not use it.
+func (n *Notifier) SendScanSummary(summary string, containerCount int, severity knowledge.Severity, containers ...ContainerStatus) error {
+	if !n.ShouldNotify(severity) {
+		return nil // Notifications disabled or below threshold
+	}
+
+	message, params := n.formatter().scanSummary(summary, containerCount, severity, containers, time.Now())
+
+	if err := n.send(message, params); err != nil {
+		return n.queueFailed(message, params, fmt.Errorf("notification failed to send via %s (containers: %d, severity: %s): %w", n.serviceType(), containerCount, severity, err))
 	}
 
-	// Format the notification message
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	return nil
+}
+
+// Preview returns the exact message body SendScanSummary would send, without dispatching it.
+func (n *Notifier) Preview(summary string, containerCount int, severity knowledge.Severity, containers ...ContainerStatus) string {
+	message, _ := n.formatter().scanSummary(summary, containerCount, severity, containers, time.Now())
+	return message
+}
 
+// formatScanSummary renders the run-level plain text notification message.
+func formatScanSummary(summary string, containerCount int, severity knowledge.Severity, timestamp time.Time) string {
 	var sb strings.Builder
-	sb.WriteString("🐳 DLIA Scan Complete\n")
-	fmt.Fprintf(&sb, "📅 Time: %s\n", timestamp)
+	fmt.Fprintf(&sb, "🐳 %s\n", scanSummaryTitle)
+	fmt.Fprintf(&sb, "📅 Time: %s\n", timestamp.Format("2006-01-02 15:04:05"))
 	fmt.Fprintf(&sb, "📦 Containers: %d\n", containerCount)
+	sb.WriteString(severityLine(severity))
+	sb.WriteString("\n")
+	sb.WriteString(summary)
+
+	return sb.String()
+}
 
-	if issuesFound {
-		sb.WriteString("⚠️  Issues detected\n")
-	} else {
-		sb.WriteString("✅ No critical issues\n")
+// SendContainerAlert delivers a dedicated alert for a single container.
+// The container name is used as the notification title so alerts can be routed per service.
+// Alerts whose severity is below the configured minimum are silently skipped.
+func (n *Notifier) SendContainerAlert(containerName, analysis string, severity knowledge.Severity) error {
+	if !n.ShouldNotify(severity) {
+		return nil // Notifications disabled or below threshold
 	}
 
-	sb.WriteString("\n")
-	sb.WriteString(summary)
+	message, params := n.formatter().containerAlert(containerName, analysis, severity, time.Now())
+
+	if err := n.send(message, params); err != nil {
+		return n.queueFailed(message, params, fmt.Errorf("notification failed to send via %s (container: %s, severity: %s): %w", n.serviceType(), containerName, severity, err))
+	}
+
+	return nil
+}
+
+// testAlertName is the "container" named in the title of test notifications.
+const testAlertName = "test notification"
+
+// SendTest delivers message as a test notification, regardless of the configured
+// minimum severity, so the notification URL can be verified without a scan. It is
+// formatted like a healthy container alert and never queued when delivery fails.
+func (n *Notifier) SendTest(message string) error {
+	if !n.enabled {
+		return errors.New("notifications are disabled")
+	}
+
+	formatted, params := n.formatter().containerAlert(testAlertName, message, knowledge.SeverityHealthy, time.Now())
+
+	if err := n.send(formatted, params); err != nil {
+		return fmt.Errorf("notification failed to send via %s (test message): %w", n.serviceType(), err)
+	}
+
+	return nil
+}
+
+// send dispatches a formatted message with its params to the configured URL.
+func (n *Notifier) send(message string, params types.Params) error {
+	return deliver(n.shoutrrrURL, message, params)
+}
 
-	// Send notification using shoutrrr
-	err := shoutrrr.Send(n.shoutrrrURL, sb.String())
+// Pending returns the rendered message and target URL as a PendingNotification,
+// ready to be queued and replayed later.
+func (n *Notifier) Pending(message string, params types.Params) PendingNotification {
+	return PendingNotification{URL: n.shoutrrrURL, Message: message, Params: params, CreatedAt: time.Now()}
+}
+
+// queueFailed queues a notification that failed to send, if a queue is configured,
+// and returns sendErr annotated with the outcome.
+func (n *Notifier) queueFailed(message string, params types.Params, sendErr error) error {
+	if n.pendingDir == "" || n.maxPending <= 0 {
+		return sendErr
+	}
+	dropped, err := Enqueue(n.pendingDir, n.maxPending, n.Pending(message, params))
 	if err != nil {
-		// Extract service type from URL (e.g., "slack://..." -> "slack")
-		serviceType := "unknown"
-		if idx := strings.Index(n.shoutrrrURL, "://"); idx > 0 {
-			serviceType = n.shoutrrrURL[:idx]
-		}
-		return fmt.Errorf("notification failed to send via %s (containers: %d, issues: %t): %w", serviceType, containerCount, issuesFound, err)
+		return errors.Join(sendErr, err)
+	}
+	if dropped > 0 {
+		return fmt.Errorf("%w (queued for retry in %s, dropped %d oldest queued notifications)", sendErr, n.pendingDir, dropped)
+	}
+	return fmt.Errorf("%w (queued for retry in %s)", sendErr, n.pendingDir)
+}
+
+// deliver dispatches a formatted message with its params through Shoutrrr, or through
+// the formatter itself when it sends its own payload.
+func deliver(shoutrrrURL, message string, params types.Params) error {
+	if direct, ok := formatterFor(serviceTypeOf(shoutrrrURL)).(directSender); ok {
+		return direct.send(shoutrrrURL, message, params)
+	}
+
+	sender, err := shoutrrr.CreateSender(shoutrrrURL)
+	if err != nil {
+		return err
+	}
+	return firstError(sender.Send(message, &params))
+}
+
+// formatter returns the message formatter for the configured service. Plain text
+// scan summaries are rendered from the notification prompt template.
+func (n *Notifier) formatter() formatter {
+	f := formatterFor(n.serviceType())
+	if plain, ok := f.(plainFormatter); ok {
+		plain.prompts = n.prompts
+		return plain
+	}
+	return f
+}
+
+// serviceType extracts the service type from the Shoutrrr URL (e.g., "slack://..." -> "slack").
+func (n *Notifier) serviceType() string {
+	return serviceTypeOf(n.shoutrrrURL)
+}
+
+func serviceTypeOf(shoutrrrURL string) string {
+	if idx := strings.Index(shoutrrrURL, "://"); idx > 0 {
+		return shoutrrrURL[:idx]
 	}
+	return "unknown"
+}
 
+// firstError returns the first non-nil error reported by a Shoutrrr sender.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// severityLabel returns a short title-case label for a severity.
+func severityLabel(severity knowledge.Severity) string {
+	switch severity {
+	case knowledge.SeverityCritical:
+		return "Critical"
+	case knowledge.SeverityWarning:
+		return "Warning"
+	default:
+		return "Healthy"
+	}
+}
+
+// severityLine returns the headline status line for a scan severity.
+func severityLine(severity knowledge.Severity) string {
+	switch severity {
+	case knowledge.SeverityCritical:
+		return "🔴 Critical issues detected\n"
+	case knowledge.SeverityWarning:
+		return "🟡 Warnings detected\n"
+	default:
+		return "✅ No critical issues\n"
+	}
+}
+
 // IsEnabled reports whether notifications are configured and active.
 func (n *Notifier) IsEnabled() bool {
 	return n.enabled
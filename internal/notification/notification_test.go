@@ -3,9 +3,12 @@ package notification
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/knowledge"
 )
 
 func TestNewNotifier(t *testing.T) {
@@ -131,7 +134,7 @@ func TestNotifier_SendScanSummary_Disabled(t *testing.T) {
 	}
 
 	// When notifications are disabled, SendScanSummary should return nil without error
-	err := notifier.SendScanSummary("test summary", 5, true)
+	err := notifier.SendScanSummary("test summary", 5, knowledge.SeverityCritical)
 	if err != nil {
 		t.Errorf("SendScanSummary() with disabled notifications should return nil, got error: %v", err)
 	}
@@ -144,7 +147,7 @@ func TestNotifier_SendScanSummary_DisabledWithIssues(t *testing.T) {
 	}
 
 	// Test with issues found
-	err := notifier.SendScanSummary("critical issues found", 10, true)
+	err := notifier.SendScanSummary("critical issues found", 10, knowledge.SeverityCritical)
 	if err != nil {
 		t.Errorf("SendScanSummary() with disabled notifications should return nil, got error: %v", err)
 	}
@@ -157,7 +160,7 @@ func TestNotifier_SendScanSummary_DisabledNoIssues(t *testing.T) {
 	}
 
 	// Test without issues found
-	err := notifier.SendScanSummary("all clear", 3, false)
+	err := notifier.SendScanSummary("all clear", 3, knowledge.SeverityHealthy)
 	if err != nil {
 		t.Errorf("SendScanSummary() with disabled notifications should return nil, got error: %v", err)
 	}
@@ -211,7 +214,7 @@ func TestNotifier_ZeroValue(t *testing.T) {
 	}
 
 	// SendScanSummary should not error on zero value notifier
-	err := notifier.SendScanSummary("test", 1, false)
+	err := notifier.SendScanSummary("test", 1, knowledge.SeverityHealthy)
 	if err != nil {
 		t.Errorf("SendScanSummary() on zero value notifier should return nil, got: %v", err)
 	}
@@ -237,63 +240,63 @@ func TestNotifier_SendScanSummary_EdgeCases(t *testing.T) {
 		name           string
 		summary        string
 		containerCount int
-		issuesFound    bool
+		severity       knowledge.Severity
 		wantErr        bool
 	}{
 		{
 			name:           "empty summary",
 			summary:        "",
 			containerCount: 5,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			wantErr:        false,
 		},
 		{
 			name:           "zero containers with issues",
 			summary:        "test summary",
 			containerCount: 0,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			wantErr:        false,
 		},
 		{
 			name:           "zero containers without issues",
 			summary:        "test summary",
 			containerCount: 0,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			wantErr:        false,
 		},
 		{
 			name:           "large container count",
 			summary:        "test summary",
 			containerCount: 10000,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			wantErr:        false,
 		},
 		{
 			name:           "negative container count",
 			summary:        "test summary",
 			containerCount: -1,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			wantErr:        false,
 		},
 		{
 			name:           "very long summary",
 			summary:        string(make([]byte, 10000)),
 			containerCount: 5,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			wantErr:        false,
 		},
 		{
 			name:           "summary with special characters",
 			summary:        "Test 🐳 with émojis and spëcial çharacters: \n\t\r",
 			containerCount: 3,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			wantErr:        false,
 		},
 		{
 			name:           "summary with newlines",
 			summary:        "Line 1\nLine 2\nLine 3",
 			containerCount: 7,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			wantErr:        false,
 		},
 	}
@@ -302,7 +305,7 @@ func TestNotifier_SendScanSummary_EdgeCases(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Test with disabled notifier - should always succeed
 			notifier := &Notifier{enabled: false}
-			err := notifier.SendScanSummary(tt.summary, tt.containerCount, tt.issuesFound)
+			err := notifier.SendScanSummary(tt.summary, tt.containerCount, tt.severity)
 			if err != nil {
 				t.Errorf("SendScanSummary() with disabled notifier should not error, got: %v", err)
 			}
@@ -315,31 +318,31 @@ func TestNotifier_SendScanSummary_IssuesFlagVariations(t *testing.T) {
 	tests := []struct {
 		name           string
 		containerCount int
-		issuesFound    bool
+		severity       knowledge.Severity
 		description    string
 	}{
 		{
 			name:           "single container with issues",
 			containerCount: 1,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			description:    "should handle singular container with issues",
 		},
 		{
 			name:           "single container without issues",
 			containerCount: 1,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			description:    "should handle singular container without issues",
 		},
 		{
 			name:           "multiple containers with issues",
 			containerCount: 100,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			description:    "should handle multiple containers with issues",
 		},
 		{
 			name:           "multiple containers without issues",
 			containerCount: 100,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			description:    "should handle multiple containers without issues",
 		},
 	}
@@ -347,7 +350,7 @@ func TestNotifier_SendScanSummary_IssuesFlagVariations(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			notifier := &Notifier{enabled: false}
-			err := notifier.SendScanSummary("test summary", tt.containerCount, tt.issuesFound)
+			err := notifier.SendScanSummary("test summary", tt.containerCount, tt.severity)
 			if err != nil {
 				t.Errorf("SendScanSummary() %s, got error: %v", tt.description, err)
 			}
@@ -419,17 +422,17 @@ func TestNotifier_SendScanSummary_MultipleInvocations(t *testing.T) {
 	testCases := []struct {
 		summary        string
 		containerCount int
-		issuesFound    bool
+		severity       knowledge.Severity
 	}{
-		{"first summary", 5, true},
-		{"second summary", 3, false},
-		{"third summary", 10, true},
-		{"", 0, false},
-		{"final summary", 1, false},
+		{"first summary", 5, knowledge.SeverityCritical},
+		{"second summary", 3, knowledge.SeverityHealthy},
+		{"third summary", 10, knowledge.SeverityCritical},
+		{"", 0, knowledge.SeverityHealthy},
+		{"final summary", 1, knowledge.SeverityHealthy},
 	}
 
 	for i, tc := range testCases {
-		err := notifier.SendScanSummary(tc.summary, tc.containerCount, tc.issuesFound)
+		err := notifier.SendScanSummary(tc.summary, tc.containerCount, tc.severity)
 		if err != nil {
 			t.Errorf("SendScanSummary() invocation %d returned error: %v", i, err)
 		}
@@ -532,56 +535,56 @@ func TestNotifier_SendScanSummary_MessageFormat(t *testing.T) {
 		name           string
 		summary        string
 		containerCount int
-		issuesFound    bool
+		severity       knowledge.Severity
 		expectError    bool
 	}{
 		{
 			name:           "with issues found",
 			summary:        "Critical vulnerabilities detected",
 			containerCount: 5,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			expectError:    true, // Will fail because URL is invalid, but tests the formatting path
 		},
 		{
 			name:           "without issues",
 			summary:        "All containers are healthy",
 			containerCount: 3,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			expectError:    true, // Will fail because URL is invalid, but tests the formatting path
 		},
 		{
 			name:           "empty summary with issues",
 			summary:        "",
 			containerCount: 10,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			expectError:    true,
 		},
 		{
 			name:           "single container",
 			summary:        "Container checked",
 			containerCount: 1,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			expectError:    true,
 		},
 		{
 			name:           "many containers",
 			summary:        "Large scale scan completed",
 			containerCount: 500,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			expectError:    true,
 		},
 		{
 			name:           "summary with newlines",
 			summary:        "Line 1\nLine 2\nLine 3",
 			containerCount: 2,
-			issuesFound:    false,
+			severity:       knowledge.SeverityHealthy,
 			expectError:    true,
 		},
 		{
 			name:           "summary with special characters",
 			summary:        "Test 🔥 émojis and spëcial çhars",
 			containerCount: 7,
-			issuesFound:    true,
+			severity:       knowledge.SeverityCritical,
 			expectError:    true,
 		},
 	}
@@ -594,7 +597,7 @@ func TestNotifier_SendScanSummary_MessageFormat(t *testing.T) {
 				shoutrrrURL: "invalid://test",
 			}
 
-			err := notifier.SendScanSummary(tt.summary, tt.containerCount, tt.issuesFound)
+			err := notifier.SendScanSummary(tt.summary, tt.containerCount, tt.severity)
 
 			// We expect an error because the URL is invalid, but this still tests
 			// that the message formatting code path is executed
@@ -618,7 +621,7 @@ func TestNotifier_SendScanSummary_ErrorWrapping(t *testing.T) {
 		shoutrrrURL: "totally-invalid-url-format",
 	}
 
-	err := notifier.SendScanSummary("test", 1, false)
+	err := notifier.SendScanSummary("test", 1, knowledge.SeverityHealthy)
 	if err == nil {
 		t.Fatal("SendScanSummary() with invalid URL should return error")
 	}
@@ -637,8 +640,8 @@ func TestNotifier_SendScanSummary_IssuesDetectedPath(t *testing.T) {
 		shoutrrrURL: "generic://invalid-but-exercises-code-path",
 	}
 
-	// Test with issues found - this exercises the issuesFound == true branch
-	err := notifier.SendScanSummary("Security issues found", 5, true)
+	// Test with issues found - this exercises the critical severity branch
+	err := notifier.SendScanSummary("Security issues found", 5, knowledge.SeverityCritical)
 	if err == nil {
 		t.Error("Expected error with invalid URL")
 	}
@@ -651,8 +654,8 @@ func TestNotifier_SendScanSummary_NoIssuesPath(t *testing.T) {
 		shoutrrrURL: "generic://invalid-but-exercises-code-path",
 	}
 
-	// Test without issues - this exercises the issuesFound == false branch
-	err := notifier.SendScanSummary("All clear", 3, false)
+	// Test without issues - this exercises the healthy severity branch
+	err := notifier.SendScanSummary("All clear", 3, knowledge.SeverityHealthy)
 	if err == nil {
 		t.Error("Expected error with invalid URL")
 	}
@@ -669,7 +672,7 @@ func TestNotifier_SendScanSummary_ContainerCountFormatting(t *testing.T) {
 				shoutrrrURL: "invalid://url",
 			}
 
-			err := notifier.SendScanSummary("test", count, false)
+			err := notifier.SendScanSummary("test", count, knowledge.SeverityHealthy)
 			// We expect an error due to invalid URL, but the formatting code is exercised
 			if err == nil {
 				t.Error("Expected error with invalid URL")
@@ -697,7 +700,7 @@ func TestNotifier_SendScanSummary_SummaryVariations(t *testing.T) {
 				shoutrrrURL: "invalid://url",
 			}
 
-			err := notifier.SendScanSummary(summary, 5, false)
+			err := notifier.SendScanSummary(summary, 5, knowledge.SeverityHealthy)
 			if err == nil {
 				t.Error("Expected error with invalid URL")
 			}
@@ -705,21 +708,21 @@ func TestNotifier_SendScanSummary_SummaryVariations(t *testing.T) {
 	}
 }
 
-// TestNotifier_SendScanSummary_BothBranches tests both branches of issuesFound
+// TestNotifier_SendScanSummary_BothBranches tests both ends of the severity range
 func TestNotifier_SendScanSummary_BothBranches(t *testing.T) {
 	notifier := &Notifier{
 		enabled:     true,
 		shoutrrrURL: "invalid://url",
 	}
 
-	// Test issuesFound = true branch
-	err1 := notifier.SendScanSummary("Issues", 5, true)
+	// Test critical severity branch
+	err1 := notifier.SendScanSummary("Issues", 5, knowledge.SeverityCritical)
 	if err1 == nil {
 		t.Error("Expected error for true branch")
 	}
 
-	// Test issuesFound = false branch
-	err2 := notifier.SendScanSummary("No issues", 5, false)
+	// Test healthy severity branch
+	err2 := notifier.SendScanSummary("No issues", 5, knowledge.SeverityHealthy)
 	if err2 == nil {
 		t.Error("Expected error for false branch")
 	}
@@ -827,3 +830,176 @@ func TestNewNotifier_ConfigVariations(t *testing.T) {
 		})
 	}
 }
+
+func TestNewNotifier_MinSeverity(t *testing.T) {
+	cfg := &config.Config{
+		Notification: config.NotificationConfig{
+			Enabled:     true,
+			ShoutrrURL:  "slack://token@channel",
+			MinSeverity: "critical",
+		},
+	}
+
+	notifier, err := NewNotifier(cfg)
+	if err != nil {
+		t.Fatalf("NewNotifier() unexpected error: %v", err)
+	}
+
+	if notifier.minSeverity != knowledge.SeverityCritical {
+		t.Errorf("NewNotifier() minSeverity = %v, want %v", notifier.minSeverity, knowledge.SeverityCritical)
+	}
+
+	cfg.Notification.MinSeverity = "loud"
+	if _, err := NewNotifier(cfg); err == nil {
+		t.Error("NewNotifier() expected error for unknown min_severity")
+	}
+}
+
+func TestNotifier_ShouldNotify(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier *Notifier
+		severity knowledge.Severity
+		want     bool
+	}{
+		{"disabled", &Notifier{enabled: false}, knowledge.SeverityCritical, false},
+		{"default threshold healthy", &Notifier{enabled: true}, knowledge.SeverityHealthy, true},
+		{"critical only, healthy scan", &Notifier{enabled: true, minSeverity: knowledge.SeverityCritical}, knowledge.SeverityHealthy, false},
+		{"critical only, warning scan", &Notifier{enabled: true, minSeverity: knowledge.SeverityCritical}, knowledge.SeverityWarning, false},
+		{"critical only, critical scan", &Notifier{enabled: true, minSeverity: knowledge.SeverityCritical}, knowledge.SeverityCritical, true},
+		{"warning threshold, critical scan", &Notifier{enabled: true, minSeverity: knowledge.SeverityWarning}, knowledge.SeverityCritical, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.notifier.ShouldNotify(tt.severity); got != tt.want {
+				t.Errorf("ShouldNotify(%v) = %v, want %v", tt.severity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifier_SendScanSummary_BelowThreshold(t *testing.T) {
+	notifier := &Notifier{
+		enabled:     true,
+		shoutrrrURL: "invalid://url",
+		minSeverity: knowledge.SeverityCritical,
+	}
+
+	// The invalid URL would fail if a send were attempted
+	if err := notifier.SendScanSummary("just warnings", 2, knowledge.SeverityWarning); err != nil {
+		t.Errorf("SendScanSummary() below threshold should be skipped, got error: %v", err)
+	}
+}
+
+func TestSeverityLine(t *testing.T) {
+	tests := []struct {
+		severity knowledge.Severity
+		want     string
+	}{
+		{knowledge.SeverityHealthy, "✅ No critical issues\n"},
+		{knowledge.SeverityWarning, "🟡 Warnings detected\n"},
+		{knowledge.SeverityCritical, "🔴 Critical issues detected\n"},
+	}
+
+	for _, tt := range tests {
+		if got := severityLine(tt.severity); got != tt.want {
+			t.Errorf("severityLine(%v) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestNotifier_SendContainerAlert_Disabled(t *testing.T) {
+	notifier := &Notifier{enabled: false}
+
+	if err := notifier.SendContainerAlert("web", "Critical failure", knowledge.SeverityCritical); err != nil {
+		t.Errorf("SendContainerAlert() with disabled notifications should return nil, got error: %v", err)
+	}
+}
+
+func TestNotifier_SendContainerAlert_BelowThreshold(t *testing.T) {
+	notifier := &Notifier{
+		enabled:     true,
+		shoutrrrURL: "invalid://url",
+		minSeverity: knowledge.SeverityCritical,
+	}
+
+	if err := notifier.SendContainerAlert("web", "Warning: slow", knowledge.SeverityWarning); err != nil {
+		t.Errorf("SendContainerAlert() below threshold should be skipped, got error: %v", err)
+	}
+}
+
+func TestNotifier_SendContainerAlert_ErrorWrapping(t *testing.T) {
+	notifier := &Notifier{
+		enabled:     true,
+		shoutrrrURL: "invalid://url",
+	}
+
+	err := notifier.SendContainerAlert("web-frontend", "Error: crash", knowledge.SeverityCritical)
+	if err == nil {
+		t.Fatal("SendContainerAlert() with invalid URL should return error")
+	}
+
+	for _, want := range []string{"notification failed", "invalid", "web-frontend", "critical"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("SendContainerAlert() error should contain %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestFormatScanSummary(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	got := formatScanSummary("All good", 3, knowledge.SeverityWarning, timestamp)
+	want := "🐳 DLIA Scan Complete\n" +
+		"📅 Time: 2025-01-02 03:04:05\n" +
+		"📦 Containers: 3\n" +
+		"🟡 Warnings detected\n" +
+		"\n" +
+		"All good"
+
+	if got != want {
+		t.Errorf("formatScanSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestNotifier_Preview(t *testing.T) {
+	// Preview must not dispatch anything, so an invalid URL is fine
+	notifier := &Notifier{enabled: true, shoutrrrURL: "invalid://url"}
+
+	preview := notifier.Preview("Executive summary", 7, knowledge.SeverityCritical)
+
+	for _, want := range []string{"DLIA Scan Complete", "Containers: 7", "Critical issues detected", "Executive summary"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Preview() should contain %q, got: %q", want, preview)
+		}
+	}
+}
+
+func TestNotifier_SendTest(t *testing.T) {
+	disabled := &Notifier{enabled: false}
+	if err := disabled.SendTest("hello"); err == nil {
+		t.Error("SendTest() on a disabled notifier should return an error")
+	}
+
+	// The minimum severity does not apply to test messages
+	notifier := &Notifier{
+		enabled:     true,
+		shoutrrrURL: "invalid://url",
+		minSeverity: knowledge.SeverityCritical,
+		pendingDir:  t.TempDir(),
+		maxPending:  10,
+	}
+	err := notifier.SendTest("hello")
+	if err == nil {
+		t.Fatal("SendTest() with invalid URL should return error")
+	}
+	for _, want := range []string{"notification failed", "invalid", "test message"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("SendTest() error should contain %q, got: %v", want, err)
+		}
+	}
+	if paths, _ := ListPending(notifier.pendingDir); len(paths) != 0 {
+		t.Errorf("A failed test message must not be queued, got %d queued", len(paths))
+	}
+}
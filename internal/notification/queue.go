@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/zorak1103/dlia/internal/fsutil"
+)
+
+// pendingExtension is the file extension of queued notifications.
+const pendingExtension = ".json"
+
+// Suffixes appended to queue files that FlushPending takes out of the queue without
+// deleting them. ListPending ignores them because they no longer end in pendingExtension.
+const (
+	corruptSuffix = ".corrupt" // Unreadable; kept for inspection
+	sentSuffix    = ".sent"    // Delivered, but the file could not be deleted
+)
+
+// removePending deletes a delivered notification file. Tests replace it to simulate
+// a file that cannot be deleted.
+var removePending = os.Remove
+
+// PendingNotification is a rendered notification that failed to send and is kept on
+// disk until FlushPending delivers it. It holds the exact payload and target URL, so
+// a replay does not depend on the current config.
+type PendingNotification struct {
+	URL       string       `json:"url"`
+	Message   string       `json:"message"`
+	Params    types.Params `json:"params,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// FlushResult reports the outcome of FlushPending.
+type FlushResult struct {
+	Sent      int // Notifications delivered and removed from the queue
+	Remaining int // Notifications still queued
+	Discarded int // Unreadable files moved aside (renamed to *.json.corrupt)
+}
+
+// Enqueue writes a pending notification to dir. When the queue already holds
+// maxPending notifications, the oldest ones are dropped to make room, so the queue
+// cannot grow without bound. It returns the number of dropped notifications.
+func Enqueue(dir string, maxPending int, pending PendingNotification) (int, error) {
+	if maxPending <= 0 {
+		return 0, fmt.Errorf("notification queue is disabled (max pending is %d)", maxPending)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return 0, fmt.Errorf("failed to create notification queue directory %s: %w", dir, err)
+	}
+
+	existing, err := ListPending(dir)
+	if err != nil {
+		return 0, err
+	}
+	dropped := 0
+	for len(existing)-dropped >= maxPending {
+		if err := os.Remove(existing[dropped]); err != nil && !os.IsNotExist(err) {
+			return dropped, fmt.Errorf("failed to drop queued notification %s: %w", existing[dropped], err)
+		}
+		dropped++
+	}
+
+	data, err := json.MarshalIndent(pending, "", "  ")
+	if err != nil {
+		return dropped, fmt.Errorf("failed to encode queued notification: %w", err)
+	}
+	// The file may contain credentials from the Shoutrrr URL
+	if err := fsutil.WriteFileAtomic(pendingPath(dir, pending.CreatedAt), data, 0o600); err != nil {
+		return dropped, fmt.Errorf("failed to queue notification: %w", err)
+	}
+	return dropped, nil
+}
+
+// ListPending returns the paths of the queued notifications in dir, oldest first.
+// A missing directory is an empty queue.
+func ListPending(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification queue %s: %w", dir, err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), pendingExtension) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	// File names start with a sortable timestamp
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// FlushPending retries the queued notifications in dir, oldest first, and deletes
+// each one that is delivered. It stops at the first delivery failure, since the
+// remaining ones would most likely fail the same way. Unreadable files are moved
+// aside so they do not stay in the queue forever; they are reported in the returned
+// error. A delivered notification whose file cannot be deleted is renamed instead,
+// so it counts as sent and is not delivered again by the next flush.
+func FlushPending(dir string) (FlushResult, error) {
+	paths, err := ListPending(dir)
+	if err != nil {
+		return FlushResult{}, err
+	}
+
+	var result FlushResult
+	var errs []error
+	for i, path := range paths {
+		pending, err := readPending(path)
+		if err != nil {
+			if renameErr := os.Rename(path, path+corruptSuffix); renameErr != nil {
+				errs = append(errs, err, fmt.Errorf("failed to move aside unreadable queued notification: %w", renameErr))
+				result.Remaining++
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%w (moved aside to %s)", err, filepath.Base(path)+corruptSuffix))
+			result.Discarded++
+			continue
+		}
+		if err := deliver(pending.URL, pending.Message, pending.Params); err != nil {
+			errs = append(errs, fmt.Errorf("failed to resend notification queued at %s via %s: %w",
+				pending.CreatedAt.Format(time.RFC3339), serviceTypeOf(pending.URL), err))
+			result.Remaining += len(paths) - i
+			break
+		}
+		result.Sent++
+		if err := removePending(path); err != nil && !os.IsNotExist(err) {
+			if renameErr := os.Rename(path, path+sentSuffix); renameErr != nil {
+				errs = append(errs, fmt.Errorf("notification sent but could not be removed from the queue, it may be sent again: %w",
+					errors.Join(err, renameErr)))
+			}
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// readPending decodes a queued notification file.
+func readPending(path string) (PendingNotification, error) {
+	var pending PendingNotification
+	data, err := os.ReadFile(path) //nolint:gosec // Path comes from listing the configured queue directory
+	if err != nil {
+		return pending, fmt.Errorf("failed to read queued notification %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return pending, fmt.Errorf("failed to decode queued notification %s: %w", path, err)
+	}
+	if pending.URL == "" {
+		return pending, fmt.Errorf("queued notification %s has no target URL", path)
+	}
+	return pending, nil
+}
+
+// pendingPath returns an unused file name for a notification created at t.
+func pendingPath(dir string, t time.Time) string {
+	base := t.UTC().Format("20060102T150405.000000000Z")
+	path := filepath.Join(dir, base+pendingExtension)
+	for i := 1; fileExists(path); i++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, i, pendingExtension))
+	}
+	return path
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,211 @@
+package notification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/knowledge"
+)
+
+// unreachableURL points at a closed local port, so sending fails immediately.
+const unreachableURL = "generic+http://127.0.0.1:1/webhook"
+
+func TestEnqueue_DropsOldest(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for i := range 4 {
+		pending := PendingNotification{URL: unreachableURL, Message: string(rune('a' + i)), CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if _, err := Enqueue(dir, 3, pending); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	paths, err := ListPending(dir)
+	if err != nil {
+		t.Fatalf("ListPending() error = %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected queue bounded to 3, got %d", len(paths))
+	}
+	first, err := readPending(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Message != "b" {
+		t.Errorf("oldest remaining message = %q, want b", first.Message)
+	}
+
+	info, err := os.Stat(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("queued file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestEnqueue_SameTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for range 2 {
+		if _, err := Enqueue(dir, 10, PendingNotification{URL: unreachableURL, CreatedAt: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paths, err := ListPending(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Errorf("expected 2 queued files, got %d", len(paths))
+	}
+}
+
+func TestListPending_MissingDir(t *testing.T) {
+	paths, err := ListPending(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(paths) != 0 {
+		t.Errorf("ListPending() = %v, %v; want empty queue", paths, err)
+	}
+}
+
+func TestNotifier_QueuesFailedNotification(t *testing.T) {
+	dir := t.TempDir()
+	notifier := &Notifier{enabled: true, shoutrrrURL: unreachableURL, pendingDir: dir, maxPending: 10}
+
+	err := notifier.SendScanSummary("summary", 2, knowledge.SeverityWarning)
+	if err == nil || !strings.Contains(err.Error(), "queued for retry") {
+		t.Fatalf("expected queued send error, got %v", err)
+	}
+
+	paths, err := ListPending(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 queued notification, got %d", len(paths))
+	}
+	pending, err := readPending(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.URL != unreachableURL || !strings.Contains(pending.Message, "summary") {
+		t.Errorf("unexpected queued notification: %+v", pending)
+	}
+}
+
+func TestNotifier_NoQueueConfigured(t *testing.T) {
+	notifier := &Notifier{enabled: true, shoutrrrURL: unreachableURL}
+	err := notifier.SendContainerAlert("web", "crash", knowledge.SeverityCritical)
+	if err == nil || strings.Contains(err.Error(), "queued") {
+		t.Errorf("expected plain send error, got %v", err)
+	}
+}
+
+func TestFlushPending(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		received++
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	original := slackAPIURL
+	slackAPIURL = server.URL
+	defer func() { slackAPIURL = original }()
+
+	dir := t.TempDir()
+	base := time.Now()
+	for i := range 2 {
+		if _, err := Enqueue(dir, 10, PendingNotification{URL: testSlackAPIURL, Message: `{"text":"hi"}`, CreatedAt: base.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := FlushPending(dir)
+	if err != nil {
+		t.Fatalf("FlushPending() error = %v", err)
+	}
+	if result.Sent != 2 || result.Remaining != 0 || received != 2 {
+		t.Errorf("result = %+v, received = %d; want 2 sent, 0 remaining", result, received)
+	}
+	if paths, _ := ListPending(dir); len(paths) != 0 {
+		t.Errorf("delivered notifications should be deleted, %d left", len(paths))
+	}
+}
+
+func TestFlushPending_StopsAtFailure(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now()
+	for i := range 3 {
+		if _, err := Enqueue(dir, 10, PendingNotification{URL: unreachableURL, Message: "m", CreatedAt: base.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := FlushPending(dir)
+	if err == nil {
+		t.Fatal("expected delivery error")
+	}
+	if result.Sent != 0 || result.Remaining != 3 {
+		t.Errorf("result = %+v, want 0 sent, 3 remaining", result)
+	}
+	if paths, _ := ListPending(dir); len(paths) != 3 {
+		t.Errorf("failed notifications must stay queued, got %d", len(paths))
+	}
+}
+
+func TestFlushPending_MovesUnreadableAside(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "20250101T000000.000000000Z.json")
+	if err := os.WriteFile(corrupt, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := FlushPending(dir)
+	if err == nil || !strings.Contains(err.Error(), "moved aside") {
+		t.Errorf("expected error reporting the moved file, got %v", err)
+	}
+	if result.Discarded != 1 || result.Remaining != 0 {
+		t.Errorf("result = %+v, want 1 discarded, 0 remaining", result)
+	}
+	if paths, _ := ListPending(dir); len(paths) != 0 {
+		t.Errorf("unreadable file should leave the queue, %d left", len(paths))
+	}
+	if _, err := os.Stat(corrupt + corruptSuffix); err != nil {
+		t.Errorf("expected unreadable file kept as %s: %v", corrupt+corruptSuffix, err)
+	}
+}
+
+func TestFlushPending_SentButNotRemoved(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		received++
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	original, originalRemove := slackAPIURL, removePending
+	slackAPIURL = server.URL
+	removePending = func(string) error { return os.ErrPermission }
+	defer func() { slackAPIURL, removePending = original, originalRemove }()
+
+	dir := t.TempDir()
+	if _, err := Enqueue(dir, 10, PendingNotification{URL: testSlackAPIURL, Message: `{"text":"hi"}`, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if _, err := FlushPending(dir); err != nil {
+			t.Fatalf("FlushPending() error = %v", err)
+		}
+	}
+	if received != 1 {
+		t.Errorf("notification delivered %d times, want once", received)
+	}
+}
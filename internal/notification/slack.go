@@ -0,0 +1,190 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/containrrr/shoutrrr/pkg/services/slack"
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/zorak1103/dlia/internal/knowledge"
+)
+
+// Slack Block Kit limits, see https://api.slack.com/reference/block-kit/blocks
+const (
+	slackMaxBlocks      = 50
+	slackMaxHeaderChars = 150
+	slackMaxTextChars   = 3000
+)
+
+// slackAPIURL is the chat.postMessage endpoint used for bot and user tokens.
+// Webhook tokens post to their own webhook URL instead.
+var slackAPIURL = "https://slack.com/api/chat.postMessage"
+
+// slackHTTPClient sends Block Kit payloads; Shoutrrr's Slack service only sends text attachments.
+var slackHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// slackMessage is a Block Kit message payload.
+type slackMessage struct {
+	Channel string       `json:"channel,omitempty"`
+	Text    string       `json:"text"` // Fallback for notifications and clients without block support
+	Blocks  []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// slackFormatter renders Slack Block Kit messages: a header with the run status, a
+// section per container with warnings or issues, and a context block with totals.
+type slackFormatter struct{}
+
+func (slackFormatter) scanSummary(summary string, containerCount int, severity knowledge.Severity, containers []ContainerStatus, timestamp time.Time) (string, types.Params) {
+	title := fmt.Sprintf("%s %s: %s", severityIcon(severity), scanSummaryTitle, severityLabel(severity))
+	blocks := []slackBlock{slackHeader(title), slackSection(summary)}
+
+	var flagged []ContainerStatus
+	for _, c := range containers {
+		if c.Severity != knowledge.SeverityHealthy {
+			flagged = append(flagged, c)
+		}
+	}
+
+	// Header, summary, footer and totals leave the rest of the block budget for containers
+	room := slackMaxBlocks - 4
+	for i, c := range flagged {
+		if i == room {
+			blocks = append(blocks, slackContext(fmt.Sprintf("(%d more containers)", len(flagged)-room)))
+			break
+		}
+		blocks = append(blocks, slackSection(fmt.Sprintf("*%s %s* (%s)\n%s", severityIcon(c.Severity), c.Name, severityLabel(c.Severity), c.Analysis)))
+	}
+
+	totals := fmt.Sprintf("📅 %s | 📦 %d containers", timestamp.Format("2006-01-02 15:04:05"), containerCount)
+	if tokens := totalTokens(containers); tokens > 0 {
+		totals += fmt.Sprintf(" | 🔢 %d tokens", tokens)
+	}
+	blocks = append(blocks, slackContext(totals))
+
+	return marshalSlackMessage(title, blocks), types.Params{}
+}
+
+func (slackFormatter) containerAlert(containerName, analysis string, severity knowledge.Severity, timestamp time.Time) (string, types.Params) {
+	title := fmt.Sprintf("%s %s", severityIcon(severity), containerAlertTitle(containerName, severity))
+	blocks := []slackBlock{
+		slackHeader(title),
+		slackSection(analysis),
+		slackContext("📅 " + timestamp.Format("2006-01-02 15:04:05")),
+	}
+	return marshalSlackMessage(title, blocks), types.Params{}
+}
+
+// send posts the Block Kit payload to the webhook or, for API tokens, to chat.postMessage.
+func (slackFormatter) send(shoutrrrURL, message string, _ types.Params) error {
+	serviceURL, err := url.Parse(shoutrrrURL)
+	if err != nil {
+		return fmt.Errorf("invalid slack URL: %w", err)
+	}
+	cfg, err := slack.CreateConfigFromURL(serviceURL)
+	if err != nil {
+		return fmt.Errorf("invalid slack URL: %w", err)
+	}
+
+	if !cfg.Token.IsAPIToken() {
+		return postSlack(cfg.Token.WebhookURL(), "", []byte(message))
+	}
+
+	var payload slackMessage
+	if err := json.Unmarshal([]byte(message), &payload); err != nil {
+		return fmt.Errorf("invalid slack payload: %w", err)
+	}
+	payload.Channel = cfg.Channel
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode slack payload: %w", err)
+	}
+	return postSlack(slackAPIURL, cfg.Token.Authorization(), body)
+}
+
+// postSlack posts a JSON payload. Webhooks answer "ok"; the Web API answers with a
+// JSON object whose "ok" field reports success.
+func postSlack(endpoint, authorization string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := slackHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send slack notification: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Close error not actionable in defer context
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024)) //nolint:errcheck // Body is only used for error details
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack returned status %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	if authorization == "" {
+		return nil
+	}
+
+	var apiResp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("invalid slack API response: %w", err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("slack API error: %s", apiResp.Error)
+	}
+	return nil
+}
+
+func marshalSlackMessage(fallback string, blocks []slackBlock) string {
+	// Marshaling plain structs of strings cannot fail
+	data, _ := json.Marshal(slackMessage{Text: fallback, Blocks: blocks}) //nolint:errcheck // See above
+	return string(data)
+}
+
+func slackHeader(text string) slackBlock {
+	return slackBlock{Type: "header", Text: &slackText{Type: "plain_text", Text: truncateRunes(text, slackMaxHeaderChars), Emoji: true}}
+}
+
+func slackSection(markdown string) slackBlock {
+	if strings.TrimSpace(markdown) == "" {
+		markdown = "_(empty)_"
+	}
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncateRunes(markdown, slackMaxTextChars)}}
+}
+
+func slackContext(markdown string) slackBlock {
+	return slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: truncateRunes(markdown, slackMaxTextChars)}}}
+}
+
+// truncateRunes shortens s to at most limit characters, marking the cut with an ellipsis.
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,191 @@
+// Package privacy redacts personal data and secrets from log text before it is sent to the LLM.
+package privacy
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+// Replacement markers inserted for redacted values
+const (
+	RedactedIP     = "[IP]"
+	RedactedEmail  = "[EMAIL]"
+	RedactedSecret = "[REDACTED]"
+	RedactedCard   = "[CARD]"
+)
+
+var (
+	ipv4Pattern  = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Pattern  = regexp.MustCompile(`(?i)[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}(?:%[0-9a-z]+)?`)
+	emailPattern = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,15}\b`)
+
+	// secretValuePatterns keep the key name (group 1) and redact only the value
+	secretValuePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(\bbearer\s+)[a-z0-9._~+/=-]{8,}`),
+		regexp.MustCompile(`(?i)(\bbasic\s+)[a-z0-9+/=]{8,}`),
+		regexp.MustCompile(`(?i)(\b(?:api[_-]?key|access[_-]?token|auth[_-]?token|secret|token|password|passwd|pwd)["']?\s*[:=]\s*["']?)[^\s"',;&]+`),
+	}
+
+	// secretTokenPatterns match well-known standalone credential formats
+	secretTokenPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}\b`),        // OpenAI-style API keys
+		regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),             // AWS access key IDs
+		regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),   // GitHub tokens
+		regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`), // Slack tokens
+	}
+)
+
+// Stats counts redactions by category.
+type Stats struct {
+	IPs         int
+	Emails      int
+	Secrets     int
+	CardNumbers int
+}
+
+// Total returns the number of redactions across all categories.
+func (s Stats) Total() int {
+	return s.IPs + s.Emails + s.Secrets + s.CardNumbers
+}
+
+// Add accumulates other into s.
+func (s *Stats) Add(other Stats) {
+	s.IPs += other.IPs
+	s.Emails += other.Emails
+	s.Secrets += other.Secrets
+	s.CardNumbers += other.CardNumbers
+}
+
+// Scrub redacts the categories enabled in cfg from text:
+// AnonymizeIPs covers IPv4/IPv6 addresses, AnonymizeSecrets covers bearer tokens,
+// API keys and key=value credentials, AnonymizeEmails covers email addresses and
+// AnonymizeCardNumbers covers Luhn-valid 13–16 digit card numbers.
+func Scrub(text string, cfg config.PrivacyConfig) string {
+	scrubbed, _ := ScrubWithStats(text, cfg)
+	return scrubbed
+}
+
+// ScrubWithStats is like Scrub and also reports how many values were redacted.
+func ScrubWithStats(text string, cfg config.PrivacyConfig) (string, Stats) {
+	var stats Stats
+
+	// Secrets first so tokens containing '@' or digits are not partially redacted as emails/cards
+	if cfg.AnonymizeSecrets {
+		for _, re := range secretValuePatterns {
+			text = re.ReplaceAllStringFunc(text, func(match string) string {
+				stats.Secrets++
+				return re.ReplaceAllString(match, "${1}"+RedactedSecret)
+			})
+		}
+		for _, re := range secretTokenPatterns {
+			text = re.ReplaceAllStringFunc(text, func(string) string {
+				stats.Secrets++
+				return RedactedSecret
+			})
+		}
+	}
+
+	if cfg.AnonymizeEmails {
+		text = emailPattern.ReplaceAllStringFunc(text, func(string) string {
+			stats.Emails++
+			return RedactedEmail
+		})
+	}
+
+	if cfg.AnonymizeIPs {
+		text = ipv4Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if net.ParseIP(match) == nil {
+				return match
+			}
+			stats.IPs++
+			return RedactedIP
+		})
+		text = redactIPv6(text, &stats)
+	}
+
+	if cfg.AnonymizeCardNumbers {
+		text = cardPattern.ReplaceAllStringFunc(text, func(match string) string {
+			if !luhnValid(match) {
+				return match
+			}
+			stats.CardNumbers++
+			return RedactedCard
+		})
+	}
+
+	return text, stats
+}
+
+// redactIPv6 replaces IPv6 addresses in text with RedactedIP. Candidates must stand
+// alone (no adjacent word characters or colons), contain a hex digit and parse as an
+// address, so times like 10:00:01 and scope operators like std::io::Error are kept.
+func redactIPv6(text string, stats *Stats) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range ipv6Pattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if !isIPv6Boundary(text, start-1) || !isIPv6Boundary(text, end) {
+			continue
+		}
+
+		addr, _, _ := strings.Cut(text[start:end], "%")
+		if !strings.Contains(addr, "::") && strings.Count(addr, ":") < 7 {
+			continue
+		}
+		if strings.Trim(addr, ":") == "" || net.ParseIP(addr) == nil {
+			continue
+		}
+
+		sb.WriteString(text[last:start])
+		sb.WriteString(RedactedIP)
+		last = end
+		stats.IPs++
+	}
+	sb.WriteString(text[last:])
+
+	return sb.String()
+}
+
+// isIPv6Boundary reports whether the byte at i (out of range counts) can border an IPv6 address.
+func isIPv6Boundary(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	c := text[i]
+
+	return c != ':' && c != '_' && !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z')
+}
+
+// luhnValid reports whether the digits in s (separators ignored) form a
+// 13–16 digit number passing the Luhn checksum.
+func luhnValid(s string) bool {
+	digits := make([]int, 0, len(s))
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, int(r-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 16 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
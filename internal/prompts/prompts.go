@@ -12,6 +12,7 @@ import (
 	"text/template"
 
 	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/ui"
 )
 
 //go:embed defaults/*.md
@@ -28,8 +29,8 @@ type PromptLoader struct {
 func NewPromptLoader(cfg *config.Config) *PromptLoader {
 	return &PromptLoader{
 		cfg: cfg,
-		// Typical: 5 prompt types (system, analysis, chunk_summary, synthesis, executive_summary)
-		promptSources: make(map[string]string, 5),
+		// Typical: 6 prompt types (system, analysis, chunk_summary, synthesis, executive_summary, notification)
+		promptSources: make(map[string]string, 6),
 	}
 }
 
@@ -47,8 +48,8 @@ func (pl *PromptLoader) loadPrompt(name, embeddedPath, externalPath string) (str
 			return string(content), nil
 		}
 		// Log warning but fall back to embedded
-		fmt.Printf("⚠️  Warning: Could not read %s from %s: %v\n", name, cleanPath, err)
-		fmt.Printf("   Falling back to built-in default\n")
+		ui.Printf("⚠️  Warning: Could not read %s from %s: %v\n", name, cleanPath, err)
+		ui.Printf("   Falling back to built-in default\n")
 	}
 
 	// Use embedded default
@@ -105,6 +106,14 @@ func (pl *PromptLoader) SystemPrompt(ignoreInstructions string) (string, error)
 
 // AnalysisPrompt renders the log analysis template with container context.
 func (pl *PromptLoader) AnalysisPrompt(containerName, logs string, logCount int) (string, error) {
+	return pl.AnalysisPromptWithHistory(containerName, logs, logCount, "")
+}
+
+// AnalysisPromptWithHistory is AnalysisPrompt with the container's previous analysis
+// as the PreviousAnalysis template variable, so the model can point out new,
+// recurring and resolved issues. An empty previousAnalysis (e.g. on the first scan)
+// renders the template without history.
+func (pl *PromptLoader) AnalysisPromptWithHistory(containerName, logs string, logCount int, previousAnalysis string) (string, error) {
 	templateContent, err := pl.loadPrompt(
 		"analysis_prompt",
 		"defaults/analysis_prompt.md",
@@ -120,9 +129,10 @@ func (pl *PromptLoader) AnalysisPrompt(containerName, logs string, logCount int)
 	}
 
 	data := map[string]interface{}{
-		"ContainerName": containerName,
-		"Logs":          logs,
-		"LogCount":      logCount,
+		"ContainerName":    containerName,
+		"Logs":             logs,
+		"LogCount":         logCount,
+		"PreviousAnalysis": previousAnalysis,
 	}
 
 	var buf bytes.Buffer
@@ -166,6 +176,13 @@ func (pl *PromptLoader) ChunkSummaryPrompt(containerName string, chunkNum, total
 
 // SynthesisPrompt renders the template for combining multiple chunk summaries.
 func (pl *PromptLoader) SynthesisPrompt(containerName string, summaries []string) (string, error) {
+	return pl.SynthesisPromptWithLines(containerName, summaries, "")
+}
+
+// SynthesisPromptWithLines is SynthesisPrompt with raw log lines from the chunks as
+// the CriticalLines template variable, so the final analysis can quote exact errors.
+// Empty criticalLines renders the template without them.
+func (pl *PromptLoader) SynthesisPromptWithLines(containerName string, summaries []string, criticalLines string) (string, error) {
 	templateContent, err := pl.loadPrompt(
 		"synthesis_prompt",
 		"defaults/synthesis_prompt.md",
@@ -189,6 +206,7 @@ func (pl *PromptLoader) SynthesisPrompt(containerName string, summaries []string
 	data := map[string]interface{}{
 		"ContainerName": containerName,
 		"Summaries":     combined,
+		"CriticalLines": criticalLines,
 	}
 
 	var buf bytes.Buffer
@@ -234,6 +252,50 @@ func (pl *PromptLoader) ExecutiveSummaryPrompt(containerResults map[string]strin
 	return buf.String(), nil
 }
 
+// NotificationData holds the variables of the notification prompt template.
+type NotificationData struct {
+	Title          string                  // Notification title, e.g. "DLIA Scan Complete"
+	Time           string                  // Scan time, YYYY-MM-DD HH:MM:SS
+	Summary        string                  // Executive summary of the scan
+	ContainerCount int                     // Number of analyzed containers
+	IssueCount     int                     // Containers with warnings or critical issues
+	Severity       string                  // Overall severity: Healthy, Warning or Critical
+	Status         string                  // Headline status line, e.g. "🔴 Critical issues detected"
+	TokensUsed     int                     // LLM tokens spent on the scan; 0 if unknown
+	Containers     []NotificationContainer // Per-container status, most severe first
+}
+
+// NotificationContainer is one entry of NotificationData.Containers.
+type NotificationContainer struct {
+	Name       string
+	Severity   string // Healthy, Warning or Critical
+	TokensUsed int
+}
+
+// NotificationPrompt renders the message body of run-level notifications.
+func (pl *PromptLoader) NotificationPrompt(data NotificationData) (string, error) {
+	templateContent, err := pl.loadPrompt(
+		"notification_prompt",
+		"defaults/notification_prompt.md",
+		pl.cfg.Prompts.NotificationPrompt,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New("notification").Option("missingkey=error").Parse(templateContent)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse notification template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute notification template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
 // Legacy wrapper functions for backward compatibility
 // These maintain the original API but use the loader internally
 
@@ -278,7 +340,7 @@ Be concise and specific in your findings.`
 
 	prompt, err := loader.SystemPrompt(ignoreInstructions)
 	if err != nil {
-		fmt.Printf("⚠️  Error loading system prompt: %v\n", err)
+		ui.Printf("⚠️  Error loading system prompt: %v\n", err)
 		return "You are a log analysis assistant."
 	}
 	return prompt
@@ -303,7 +365,7 @@ If logs are routine with no issues, state "No significant issues detected."`, lo
 
 	prompt, err := loader.AnalysisPrompt(containerName, logs, logCount)
 	if err != nil {
-		fmt.Printf("⚠️  Error loading analysis prompt: %v\n", err)
+		ui.Printf("⚠️  Error loading analysis prompt: %v\n", err)
 		return fmt.Sprintf("Analyze these logs from %s", containerName)
 	}
 	return prompt
@@ -322,7 +384,7 @@ Be concise:
 
 	prompt, err := loader.ChunkSummaryPrompt(containerName, chunkNum, totalChunks, logs)
 	if err != nil {
-		fmt.Printf("⚠️  Error loading chunk summary prompt: %v\n", err)
+		ui.Printf("⚠️  Error loading chunk summary prompt: %v\n", err)
 		return fmt.Sprintf("Summarize chunk %d of %d", chunkNum, totalChunks)
 	}
 	return prompt
@@ -350,7 +412,7 @@ Final Analysis:
 
 	prompt, err := loader.SynthesisPrompt(containerName, summaries)
 	if err != nil {
-		fmt.Printf("⚠️  Error loading synthesis prompt: %v\n", err)
+		ui.Printf("⚠️  Error loading synthesis prompt: %v\n", err)
 		return fmt.Sprintf("Synthesize summaries for %s", containerName)
 	}
 	return prompt
@@ -379,7 +441,7 @@ Keep it concise and actionable. Focus on what needs immediate attention.`)
 
 	prompt, err := loader.ExecutiveSummaryPrompt(containerResults)
 	if err != nil {
-		fmt.Printf("⚠️  Error loading executive summary prompt: %v\n", err)
+		ui.Printf("⚠️  Error loading executive summary prompt: %v\n", err)
 		return "Generate executive summary"
 	}
 	return prompt
@@ -151,6 +151,55 @@ func TestPromptLoader_AnalysisPrompt(t *testing.T) {
 	}
 }
 
+func TestPromptLoader_AnalysisPromptWithHistory(t *testing.T) {
+	loader := NewPromptLoader(&config.Config{})
+
+	withHistory, err := loader.AnalysisPromptWithHistory("web", "log line", 1, "Errors: database timeouts")
+	if err != nil {
+		t.Fatalf("AnalysisPromptWithHistory() error = %v", err)
+	}
+	if !strings.Contains(withHistory, "Errors: database timeouts") || !strings.Contains(withHistory, "recurring") {
+		t.Errorf("Expected the previous analysis and trend instructions in the prompt, got:\n%s", withHistory)
+	}
+
+	withoutHistory, err := loader.AnalysisPromptWithHistory("web", "log line", 1, "")
+	if err != nil {
+		t.Fatalf("AnalysisPromptWithHistory() error = %v", err)
+	}
+	plain, err := loader.AnalysisPrompt("web", "log line", 1)
+	if err != nil {
+		t.Fatalf("AnalysisPrompt() error = %v", err)
+	}
+	if withoutHistory != plain || strings.Contains(plain, "Previous analysis") {
+		t.Errorf("Expected no history section without a previous analysis, got:\n%s", withoutHistory)
+	}
+}
+
+func TestPromptLoader_SynthesisPromptWithLines(t *testing.T) {
+	loader := NewPromptLoader(&config.Config{})
+	summaries := []string{"Summary 1", "Summary 2"}
+
+	withLines, err := loader.SynthesisPromptWithLines("web", summaries, "[2025-01-01T00:00:00Z] FATAL disk full\n")
+	if err != nil {
+		t.Fatalf("SynthesisPromptWithLines() error = %v", err)
+	}
+	if !strings.Contains(withLines, "FATAL disk full") || !strings.Contains(withLines, "Quote the exact error messages") {
+		t.Errorf("Expected the critical lines and quoting instructions in the prompt, got:\n%s", withLines)
+	}
+
+	withoutLines, err := loader.SynthesisPromptWithLines("web", summaries, "")
+	if err != nil {
+		t.Fatalf("SynthesisPromptWithLines() error = %v", err)
+	}
+	plain, err := loader.SynthesisPrompt("web", summaries)
+	if err != nil {
+		t.Fatalf("SynthesisPrompt() error = %v", err)
+	}
+	if withoutLines != plain || strings.Contains(plain, "raw log lines") {
+		t.Errorf("Expected no critical lines section without lines, got:\n%s", withoutLines)
+	}
+}
+
 func TestPromptLoader_ChunkSummaryPrompt(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -314,6 +363,56 @@ func TestPromptLoader_ExecutiveSummaryPrompt(t *testing.T) {
 	}
 }
 
+func TestPromptLoader_NotificationPrompt(t *testing.T) {
+	data := NotificationData{
+		Title:          "DLIA Scan Complete",
+		Time:           "2025-01-15 08:30:00",
+		Summary:        "Database connection errors in api.",
+		ContainerCount: 2,
+		IssueCount:     1,
+		Severity:       "Critical",
+		Status:         "🔴 Critical issues detected",
+		Containers: []NotificationContainer{
+			{Name: "api", Severity: "Critical"},
+			{Name: "web", Severity: "Healthy"},
+		},
+	}
+
+	t.Run("default template", func(t *testing.T) {
+		loader := NewPromptLoader(&config.Config{})
+
+		got, err := loader.NotificationPrompt(data)
+		if err != nil {
+			t.Fatalf("NotificationPrompt() error = %v", err)
+		}
+
+		want := "🐳 DLIA Scan Complete\n📅 Time: 2025-01-15 08:30:00\n📦 Containers: 2\n🔴 Critical issues detected\n\nDatabase connection errors in api."
+		if got != want {
+			t.Errorf("NotificationPrompt() = %q, want %q", got, want)
+		}
+		if source := loader.GetPromptSource("notification_prompt"); source != "INTERNAL DEFAULT" {
+			t.Errorf("source = %q, want INTERNAL DEFAULT", source)
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notification.md")
+		content := "{{.Severity}}: {{.IssueCount}}/{{.ContainerCount}}{{range .Containers}} {{.Name}}={{.Severity}}{{end}}"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write template: %v", err)
+		}
+		loader := NewPromptLoader(&config.Config{Prompts: config.PromptsConfig{NotificationPrompt: path}})
+
+		got, err := loader.NotificationPrompt(data)
+		if err != nil {
+			t.Fatalf("NotificationPrompt() error = %v", err)
+		}
+		if want := "Critical: 1/2 api=Critical web=Healthy"; got != want {
+			t.Errorf("NotificationPrompt() = %q, want %q", got, want)
+		}
+	})
+}
+
 func TestPromptLoader_GetPromptSource(t *testing.T) {
 	cfg := &config.Config{}
 	loader := NewPromptLoader(cfg)
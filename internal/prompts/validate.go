@@ -0,0 +1,94 @@
+package prompts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidationResult describes whether one prompt loaded and rendered successfully.
+type ValidationResult struct {
+	Name   string // Prompt name as used in the prompts config section, e.g. "analysis_prompt"
+	Source string // Resolved source, see GetPromptSource
+	Err    error  // nil when the prompt is valid
+	// Line is the 1-based template line the error points at (0 if unknown) and
+	// LineText its content, to show the user where to look.
+	Line     int
+	LineText string
+}
+
+// templateErrorLine matches the line number in text/template parse and execution
+// errors, e.g. "template: analysis:3:14: executing ...".
+var templateErrorLine = regexp.MustCompile(`template: [^:]+:(\d+)`)
+
+// Validate loads every prompt and renders it with placeholder data, catching parse
+// errors as well as execution errors such as references to undefined fields.
+// Results are returned in a fixed order. A configured external file that cannot be
+// read is reported as an error even though scans would fall back to the default.
+func (pl *PromptLoader) Validate() []ValidationResult {
+	checks := []struct {
+		name, embeddedPath, externalPath string
+		render                           func() error
+	}{
+		{"system_prompt", "defaults/system_prompt.md", pl.cfg.Prompts.SystemPrompt, func() error {
+			_, err := pl.SystemPrompt("")
+			return err
+		}},
+		{"analysis_prompt", "defaults/analysis_prompt.md", pl.cfg.Prompts.AnalysisPrompt, func() error {
+			_, err := pl.AnalysisPromptWithHistory("example-container", "2025-01-01T00:00:00Z example log line", 1, "No significant issues detected.")
+			return err
+		}},
+		{"chunk_summary_prompt", "defaults/chunk_summary_prompt.md", pl.cfg.Prompts.ChunkSummaryPrompt, func() error {
+			_, err := pl.ChunkSummaryPrompt("example-container", 1, 2, "2025-01-01T00:00:00Z example log line")
+			return err
+		}},
+		{"synthesis_prompt", "defaults/synthesis_prompt.md", pl.cfg.Prompts.SynthesisPrompt, func() error {
+			_, err := pl.SynthesisPromptWithLines("example-container", []string{"example summary 1", "example summary 2"},
+				"\n--- Chunk 1 Critical Lines ---\n[2025-01-01T00:00:00Z] ERROR example log line\n")
+			return err
+		}},
+		{"executive_summary_prompt", "defaults/executive_summary_prompt.md", pl.cfg.Prompts.ExecutiveSummaryPrompt, func() error {
+			_, err := pl.ExecutiveSummaryPrompt(map[string]string{"example-container": "No significant issues detected."})
+			return err
+		}},
+		{"notification_prompt", "defaults/notification_prompt.md", pl.cfg.Prompts.NotificationPrompt, func() error {
+			_, err := pl.NotificationPrompt(NotificationData{
+				Title:          "DLIA Scan Complete",
+				Time:           "2025-01-01 08:00:00",
+				Summary:        "No significant issues detected.",
+				ContainerCount: 1,
+				Severity:       "Healthy",
+				Status:         "✅ No critical issues",
+				Containers:     []NotificationContainer{{Name: "example-container", Severity: "Healthy"}},
+			})
+			return err
+		}},
+	}
+
+	results := make([]ValidationResult, 0, len(checks))
+	for _, check := range checks {
+		result := ValidationResult{Name: check.name, Err: check.render()}
+		result.Source = pl.GetPromptSource(check.name)
+
+		if result.Err == nil && check.externalPath != "" && !strings.HasPrefix(result.Source, "EXTERNAL") {
+			result.Err = fmt.Errorf("external file %s could not be read; scans fall back to the built-in default", check.externalPath)
+		}
+
+		if result.Err != nil {
+			if m := templateErrorLine.FindStringSubmatch(result.Err.Error()); m != nil {
+				result.Line, _ = strconv.Atoi(m[1]) //nolint:errcheck // Regex guarantees digits
+				if content, err := pl.loadPrompt(check.name, check.embeddedPath, check.externalPath); err == nil {
+					lines := strings.Split(content, "\n")
+					if result.Line >= 1 && result.Line <= len(lines) {
+						result.LineText = strings.TrimRight(lines[result.Line-1], "\r")
+					}
+				}
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
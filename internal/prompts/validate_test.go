@@ -0,0 +1,93 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+func TestPromptLoader_Validate_Defaults(t *testing.T) {
+	loader := NewPromptLoader(&config.Config{})
+
+	results := loader.Validate()
+	if len(results) != 6 {
+		t.Fatalf("Validate() returned %d results, want 6", len(results))
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: unexpected error %v", r.Name, r.Err)
+		}
+		if r.Source != "INTERNAL DEFAULT" {
+			t.Errorf("%s: source = %q, want INTERNAL DEFAULT", r.Name, r.Source)
+		}
+	}
+}
+
+func TestPromptLoader_Validate_BrokenTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	cfg := &config.Config{
+		Prompts: config.PromptsConfig{
+			AnalysisPrompt:     write("analysis.md", "Analyze {{.ContainerName}}\n\n{{.UndefinedField}}\n{{.Logs}}"),
+			ChunkSummaryPrompt: write("chunk.md", "Chunk {{.ChunkNum}}\n{{.Logs"),
+			SynthesisPrompt:    write("synthesis.md", "Combine for {{.ContainerName}}:\n{{.Summaries}}"),
+			SystemPrompt:       filepath.Join(tmpDir, "missing.md"),
+			NotificationPrompt: write("notification.md", "{{.Title}}\n{{.Unknown}}"),
+		},
+	}
+
+	results := NewPromptLoader(cfg).Validate()
+	byName := make(map[string]ValidationResult, len(results))
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+
+	analysis := byName["analysis_prompt"]
+	if analysis.Err == nil {
+		t.Fatal("analysis_prompt: expected execution error for undefined field")
+	}
+	if analysis.Line != 3 || analysis.LineText != "{{.UndefinedField}}" {
+		t.Errorf("analysis_prompt: line %d %q, want 3 %q", analysis.Line, analysis.LineText, "{{.UndefinedField}}")
+	}
+
+	chunk := byName["chunk_summary_prompt"]
+	if chunk.Err == nil {
+		t.Fatal("chunk_summary_prompt: expected parse error")
+	}
+	if chunk.Line != 2 {
+		t.Errorf("chunk_summary_prompt: line = %d, want 2", chunk.Line)
+	}
+
+	synthesis := byName["synthesis_prompt"]
+	if synthesis.Err != nil {
+		t.Errorf("synthesis_prompt: unexpected error %v", synthesis.Err)
+	}
+	if !strings.HasPrefix(synthesis.Source, "EXTERNAL") {
+		t.Errorf("synthesis_prompt: source = %q, want EXTERNAL", synthesis.Source)
+	}
+
+	notification := byName["notification_prompt"]
+	if notification.Err == nil {
+		t.Fatal("notification_prompt: expected execution error for unknown field")
+	}
+	if notification.Line != 2 {
+		t.Errorf("notification_prompt: line = %d, want 2", notification.Line)
+	}
+
+	system := byName["system_prompt"]
+	if system.Err == nil || !strings.Contains(system.Err.Error(), "could not be read") {
+		t.Errorf("system_prompt: error = %v, want unreadable file error", system.Err)
+	}
+}
@@ -0,0 +1,166 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/chunking"
+	"github.com/zorak1103/dlia/internal/docker"
+)
+
+// Supported report formats (config: output.report_format)
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// htmlReportTemplate renders a self-contained HTML report. The LLM analysis is
+// inserted as preformatted text so html/template escapes it safely.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}: {{.ContainerName}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #222; }
+h1 { border-bottom: 2px solid #ddd; padding-bottom: 0.3em; }
+h2 { margin-top: 1.5em; }
+code { background: #f3f3f3; padding: 0.1em 0.3em; border-radius: 3px; }
+pre.analysis { background: #f8f8f8; border: 1px solid #e1e1e1; border-radius: 4px; padding: 1em; white-space: pre-wrap; word-wrap: break-word; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ddd; padding: 0.4em 0.8em; text-align: left; }
+th { background: #f3f3f3; }
+.meta { color: #555; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}: {{.ContainerName}}</h1>
+<p class="meta">
+<strong>Date:</strong> {{.Date}}<br>
+<strong>Container:</strong> <code>{{.ContainerName}}</code><br>
+{{- if .Analysis.ReanalyzedFrom}}
+<strong>Re-analysis of:</strong> <code>{{.Analysis.ReanalyzedFrom}}</code> (saved logs, current prompts and model)<br>
+{{- end}}
+{{- if .Stopped}}
+<strong>Container State:</strong> ⏹️ {{.Analysis.ContainerState}} (no longer running)<br>
+{{- end}}
+<strong>Log Entries:</strong> {{.Analysis.OriginalCount}}<br>
+<strong>Tokens Used:</strong> {{.Analysis.TokensUsed}}
+</p>
+
+<h2>🤖 AI Analysis</h2>
+<pre class="analysis">{{.Analysis.Analysis}}</pre>
+{{if gt .Analysis.FilterStats.LinesTotal 0}}
+<h2>🔍 Pre-Processing Statistics</h2>
+<table>
+<tr><th>Metric</th><th>Value</th></tr>
+{{- if gt .Analysis.FilterStats.PhysicalLines 0}}
+<tr><td>Physical Log Lines</td><td>{{.Analysis.FilterStats.PhysicalLines}}</td></tr>
+{{- end}}
+<tr><td>Total Log Lines</td><td>{{.Analysis.FilterStats.LinesTotal}}</td></tr>
+<tr><td>Lines Filtered (Regexp)</td><td>{{.Analysis.FilterStats.LinesFiltered}}</td></tr>
+<tr><td>Lines Kept</td><td>{{.Analysis.FilterStats.LinesKept}}</td></tr>
+{{- if gt .Analysis.FilterStats.SanitizedLines 0}}
+<tr><td>Lines Sanitized (Invalid UTF-8)</td><td>{{.Analysis.FilterStats.SanitizedLines}}</td></tr>
+{{- end}}
+<tr><td>Filter Reduction</td><td>{{printf "%.1f" .FilterPercentage}}%</td></tr>
+<tr><td>Est. Tokens Saved</td><td>~{{.EstimatedTokensSaved}}</td></tr>
+</table>
+<p><strong>Cost Impact:</strong> By filtering log lines before LLM processing, approximately {{.EstimatedTokensSaved}} tokens were saved. This reduces API costs and improves processing speed.</p>
+{{end}}
+<h2>📊 Statistics</h2>
+<table>
+<tr><th>Metric</th><th>Value</th></tr>
+<tr><td>Original Logs</td><td>{{.Analysis.OriginalCount}}</td></tr>
+<tr><td>Processed Logs</td><td>{{.Analysis.ProcessedCount}}</td></tr>
+{{- if gt .Analysis.TruncatedLines 0}}
+<tr><td>Truncated Lines</td><td>{{.Analysis.TruncatedLines}}</td></tr>
+{{- end}}
+{{- if .Analysis.Deduplicated}}
+<tr><td>Deduplication</td><td>{{printf "%.1f" .DedupPercentage}}%</td></tr>
+{{- end}}
+<tr><td>Tokens</td><td>{{.Analysis.TokensUsed}}</td></tr>
+<tr><td>Chunks</td><td>{{.Analysis.ChunksUsed}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// htmlReportData is the view model passed to htmlReportTemplate.
+type htmlReportData struct {
+	Title                string
+	ContainerName        string
+	Date                 string
+	Analysis             *chunking.AnalyzeResult
+	FilterPercentage     float64
+	EstimatedTokensSaved int
+	DedupPercentage      float64
+	Stopped              bool
+}
+
+// GenerateHTMLScanReport formats analysis results as a self-contained HTML report.
+// The report date is shown in loc (nil = local time).
+func GenerateHTMLScanReport(containerName string, analysis *chunking.AnalyzeResult, _ []docker.LogEntry, loc *time.Location) (string, error) {
+	data := htmlReportData{
+		Title:                reportTitle(analysis),
+		ContainerName:        containerName,
+		Date:                 displayNow(loc).Format(time.RFC1123),
+		Analysis:             analysis,
+		FilterPercentage:     calculateSavings(analysis.FilterStats.LinesTotal, analysis.FilterStats.LinesKept),
+		EstimatedTokensSaved: analysis.FilterStats.LinesFiltered * 20,
+		DedupPercentage:      calculateSavings(analysis.OriginalCount, analysis.ProcessedCount),
+		Stopped:              isStopped(analysis.ContainerState),
+	}
+
+	var buf bytes.Buffer
+	if err := htmlReportTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateReport formats analysis results in the requested format ("md" or "html").
+// An empty format falls back to Markdown. Dates are shown in loc (nil = local time).
+func GenerateReport(containerName string, analysis *chunking.AnalyzeResult, logs []docker.LogEntry, format string, loc *time.Location) (string, error) {
+	if format == FormatHTML {
+		return GenerateHTMLScanReport(containerName, analysis, logs, loc)
+	}
+
+	return GenerateScanReport(containerName, analysis, logs, loc), nil
+}
+
+// GenerateCappedReport is GenerateReport for reports of at most maxBytes bytes
+// (output.max_report_bytes, 0 = unlimited). Only the analysis section is truncated,
+// so the header and statistics stay intact; a report whose metadata alone exceeds
+// the limit keeps just the truncation marker as its analysis.
+func GenerateCappedReport(containerName string, analysis *chunking.AnalyzeResult, logs []docker.LogEntry, format string, loc *time.Location, maxBytes int) (string, error) {
+	content, err := GenerateReport(containerName, analysis, logs, format, loc)
+	if err != nil || maxBytes <= 0 || len(content) <= maxBytes {
+		return content, err
+	}
+
+	// HTML escaping makes the rendered size of the analysis differ from its length,
+	// so shrink the budget by the remaining overflow until the report fits
+	capped := *analysis
+	budget := len(analysis.Analysis)
+	for {
+		budget = max(budget-(len(content)-maxBytes), 1)
+		capped.Analysis = chunking.TruncateAnalysis(analysis.Analysis, budget)
+		content, err = GenerateReport(containerName, &capped, logs, format, loc)
+		if err != nil || len(content) <= maxBytes || budget == 1 {
+			return content, err
+		}
+	}
+}
+
+// reportExtension returns the file extension (including the dot) for a report format.
+func reportExtension(format string) string {
+	if format == FormatHTML {
+		return ".html"
+	}
+
+	return ".md"
+}
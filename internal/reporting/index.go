@@ -0,0 +1,129 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/dlia/internal/config"
+	"github.com/zorak1103/dlia/internal/fsutil"
+)
+
+// Report index files written to reports_dir after each scan run.
+const (
+	IndexFile     = "index.md"
+	IndexJSONFile = "index.json"
+)
+
+// IndexEntry is one container of the per-run report index.
+type IndexEntry struct {
+	Container  string `json:"container"`
+	Status     string `json:"status"` // healthy, warning or critical
+	TokensUsed int    `json:"tokens_used"`
+	Report     string `json:"report"` // Report path relative to reports_dir, with forward slashes
+}
+
+// IndexActivity lists the containers of a scan run that produced new log lines
+// (active) and those that stayed quiet (idle).
+type IndexActivity struct {
+	Active []string `json:"active"`
+	Idle   []string `json:"idle"`
+}
+
+// reportIndex is the document written to index.json.
+type reportIndex struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Containers  []IndexEntry   `json:"containers"`
+	Activity    *IndexActivity `json:"activity,omitempty"`
+}
+
+// WriteIndex writes index.md, and index.json when withJSON is set, to reports_dir,
+// replacing the index of the previous run. Entries are listed in the given order;
+// their Report holds the path returned by SaveReport and is rewritten relative to
+// reports_dir so the links work from the index. A non-nil activity is added as an
+// active/idle breakdown. Returns the written file paths.
+func WriteIndex(entries []IndexEntry, activity *IndexActivity, withJSON bool, cfg *config.Config) ([]string, error) {
+	linked := make([]IndexEntry, len(entries))
+	for i, entry := range entries {
+		entry.Report = relativeReportPath(cfg.Output.ReportsDir, entry.Report)
+		linked[i] = entry
+	}
+
+	if err := os.MkdirAll(cfg.Output.ReportsDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create reports directory: %w", err)
+	}
+
+	generatedAt := displayNow(cfg.DisplayLocation())
+	indexPath := filepath.Join(cfg.Output.ReportsDir, IndexFile)
+	if err := fsutil.WriteFileAtomic(indexPath, []byte(formatIndex(linked, activity, generatedAt)), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write report index: %w", err)
+	}
+	written := []string{indexPath}
+
+	if withJSON {
+		data, err := json.MarshalIndent(reportIndex{GeneratedAt: generatedAt, Containers: linked, Activity: activity}, "", "  ")
+		if err != nil {
+			return written, fmt.Errorf("failed to encode report index: %w", err)
+		}
+		jsonPath := filepath.Join(cfg.Output.ReportsDir, IndexJSONFile)
+		if err := fsutil.WriteFileAtomic(jsonPath, append(data, '\n'), 0o600); err != nil {
+			return written, fmt.Errorf("failed to write report index: %w", err)
+		}
+		written = append(written, jsonPath)
+	}
+
+	return written, nil
+}
+
+// formatIndex renders the markdown triage table of a scan run.
+func formatIndex(entries []IndexEntry, activity *IndexActivity, generatedAt time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("# DLIA Scan Index\n\n")
+	fmt.Fprintf(&sb, "**Date:** %s  \n", generatedAt.Format(time.RFC1123))
+	fmt.Fprintf(&sb, "**Reports:** %d\n\n", len(entries))
+	sb.WriteString("| Container | Status | Tokens | Report |\n")
+	sb.WriteString("|-----------|--------|--------|--------|\n")
+	for _, entry := range entries {
+		fmt.Fprintf(&sb, "| %s | %s | %d | [%s](%s) |\n",
+			entry.Container, indexStatus(entry.Status), entry.TokensUsed, filepath.Base(entry.Report), entry.Report)
+	}
+	if activity != nil {
+		sb.WriteString("\n## Activity\n\n")
+		fmt.Fprintf(&sb, "- **Active (%d):** %s\n", len(activity.Active), activityList(activity.Active))
+		fmt.Fprintf(&sb, "- **Idle (%d):** %s\n", len(activity.Idle), activityList(activity.Idle))
+	}
+	return sb.String()
+}
+
+// activityList joins container names for the activity section.
+func activityList(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+// indexStatus returns the status cell for a severity name.
+func indexStatus(status string) string {
+	switch status {
+	case "critical":
+		return "🔴 Critical"
+	case "warning":
+		return "🟡 Warning"
+	default:
+		return "🟢 Healthy"
+	}
+}
+
+// relativeReportPath returns reportPath relative to reportsDir with forward slashes,
+// or reportPath unchanged if it is not below reportsDir.
+func relativeReportPath(reportsDir, reportPath string) string {
+	rel, err := filepath.Rel(reportsDir, reportPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(reportPath)
+	}
+	return filepath.ToSlash(rel)
+}
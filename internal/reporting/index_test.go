@@ -0,0 +1,127 @@
+package reporting
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/dlia/internal/config"
+)
+
+func TestWriteIndex(t *testing.T) {
+	t.Parallel()
+
+	reportsDir := t.TempDir()
+	cfg := &config.Config{Output: config.OutputConfig{ReportsDir: reportsDir}}
+	entries := []IndexEntry{
+		{Container: "db", Status: "critical", TokensUsed: 1200, Report: filepath.Join(reportsDir, "shop", "db", "2025-01-15_08-30-00.md")},
+		{Container: "web", Status: "healthy", TokensUsed: 300, Report: filepath.Join(reportsDir, "web", "2025-01-15_08-30-01.md")},
+	}
+
+	paths, err := WriteIndex(entries, nil, false, cfg)
+	if err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+	if len(paths) != 1 || paths[0] != filepath.Join(reportsDir, IndexFile) {
+		t.Fatalf("WriteIndex() paths = %v, want only %s", paths, IndexFile)
+	}
+	if _, err := os.Stat(filepath.Join(reportsDir, IndexJSONFile)); !os.IsNotExist(err) {
+		t.Error("index.json must only be written when requested")
+	}
+
+	content, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	index := string(content)
+	for _, want := range []string{
+		"| db | 🔴 Critical | 1200 | [2025-01-15_08-30-00.md](shop/db/2025-01-15_08-30-00.md) |",
+		"| web | 🟢 Healthy | 300 | [2025-01-15_08-30-01.md](web/2025-01-15_08-30-01.md) |",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("Index missing %q:\n%s", want, index)
+		}
+	}
+	if strings.Index(index, "| db |") > strings.Index(index, "| web |") {
+		t.Error("Index must keep the given order")
+	}
+	if entries[0].Report != filepath.Join(reportsDir, "shop", "db", "2025-01-15_08-30-00.md") {
+		t.Error("WriteIndex() must not modify its input")
+	}
+}
+
+func TestWriteIndex_JSON(t *testing.T) {
+	t.Parallel()
+
+	reportsDir := t.TempDir()
+	cfg := &config.Config{Output: config.OutputConfig{ReportsDir: reportsDir}}
+	entries := []IndexEntry{
+		{Container: "api", Status: "warning", TokensUsed: 42, Report: filepath.Join(reportsDir, "api", "2025-01-15_08-30-00.md")},
+	}
+
+	paths, err := WriteIndex(entries, nil, true, cfg)
+	if err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("WriteIndex() paths = %v, want index.md and index.json", paths)
+	}
+
+	data, err := os.ReadFile(filepath.Join(reportsDir, IndexJSONFile))
+	if err != nil {
+		t.Fatalf("Failed to read index.json: %v", err)
+	}
+	var index reportIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("index.json is not valid JSON: %v", err)
+	}
+	if index.GeneratedAt.IsZero() {
+		t.Error("index.json should record when it was generated")
+	}
+	want := IndexEntry{Container: "api", Status: "warning", TokensUsed: 42, Report: "api/2025-01-15_08-30-00.md"}
+	if len(index.Containers) != 1 || index.Containers[0] != want {
+		t.Errorf("index.json containers = %+v, want [%+v]", index.Containers, want)
+	}
+	if index.Activity != nil {
+		t.Errorf("index.json activity = %+v, want none without an activity breakdown", index.Activity)
+	}
+}
+
+func TestWriteIndex_Activity(t *testing.T) {
+	t.Parallel()
+
+	reportsDir := t.TempDir()
+	cfg := &config.Config{Output: config.OutputConfig{ReportsDir: reportsDir}}
+	entries := []IndexEntry{
+		{Container: "api", Status: "healthy", TokensUsed: 42, Report: filepath.Join(reportsDir, "api", "2025-01-15_08-30-00.md")},
+	}
+	activity := &IndexActivity{Active: []string{"api"}, Idle: []string{"cron", "db"}}
+
+	if _, err := WriteIndex(entries, activity, true, cfg); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(reportsDir, IndexFile))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	for _, want := range []string{"## Activity", "- **Active (1):** api", "- **Idle (2):** cron, db"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Index missing %q:\n%s", want, content)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(reportsDir, IndexJSONFile))
+	if err != nil {
+		t.Fatalf("Failed to read index.json: %v", err)
+	}
+	var index reportIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("index.json is not valid JSON: %v", err)
+	}
+	if index.Activity == nil || strings.Join(index.Activity.Idle, ",") != "cron,db" || strings.Join(index.Activity.Active, ",") != "api" {
+		t.Errorf("index.json activity = %+v, want active [api], idle [cron db]", index.Activity)
+	}
+}
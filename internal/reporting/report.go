@@ -5,25 +5,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/zorak1103/dlia/internal/chunking"
 	"github.com/zorak1103/dlia/internal/config"
 	"github.com/zorak1103/dlia/internal/docker"
+	"github.com/zorak1103/dlia/internal/fsutil"
 	"github.com/zorak1103/dlia/internal/sanitize"
 )
 
-// GenerateScanReport formats analysis results as a markdown report.
-func GenerateScanReport(containerName string, analysis *chunking.AnalyzeResult, _ []docker.LogEntry) string {
+// GenerateScanReport formats analysis results as a markdown report. The report date
+// is shown in loc (nil = local time).
+func GenerateScanReport(containerName string, analysis *chunking.AnalyzeResult, _ []docker.LogEntry, loc *time.Location) string {
 	var sb strings.Builder
 
-	timestamp := time.Now().Format(time.RFC1123)
+	timestamp := displayNow(loc).Format(time.RFC1123)
 
 	// Header
-	fmt.Fprintf(&sb, "# Scan Report: %s\n\n", containerName)
+	fmt.Fprintf(&sb, "# %s: %s\n\n", reportTitle(analysis), containerName)
 	fmt.Fprintf(&sb, "**Date:** %s  \n", timestamp)
 	fmt.Fprintf(&sb, "**Container:** `%s`  \n", containerName)
+	if analysis.ReanalyzedFrom != "" {
+		fmt.Fprintf(&sb, "**Re-analysis of:** `%s` (saved logs, current prompts and model)  \n", analysis.ReanalyzedFrom)
+	}
+	if isStopped(analysis.ContainerState) {
+		fmt.Fprintf(&sb, "**Container State:** ⏹️ %s (no longer running)  \n", analysis.ContainerState)
+	}
 	fmt.Fprintf(&sb, "**Log Entries:** %d  \n", analysis.OriginalCount)
 	fmt.Fprintf(&sb, "**Tokens Used:** %d\n\n", analysis.TokensUsed)
 
@@ -37,9 +47,15 @@ func GenerateScanReport(containerName string, analysis *chunking.AnalyzeResult,
 		sb.WriteString("## 🔍 Pre-Processing Statistics\n\n")
 		sb.WriteString("| Metric | Value |\n")
 		sb.WriteString("|--------|-------|\n")
+		if analysis.FilterStats.PhysicalLines > 0 {
+			fmt.Fprintf(&sb, "| Physical Log Lines | %d |\n", analysis.FilterStats.PhysicalLines)
+		}
 		fmt.Fprintf(&sb, "| Total Log Lines | %d |\n", analysis.FilterStats.LinesTotal)
 		fmt.Fprintf(&sb, "| Lines Filtered (Regexp) | %d |\n", analysis.FilterStats.LinesFiltered)
 		fmt.Fprintf(&sb, "| Lines Kept | %d |\n", analysis.FilterStats.LinesKept)
+		if analysis.FilterStats.SanitizedLines > 0 {
+			fmt.Fprintf(&sb, "| Lines Sanitized (Invalid UTF-8) | %d |\n", analysis.FilterStats.SanitizedLines)
+		}
 
 		filterPercentage := calculateSavings(analysis.FilterStats.LinesTotal, analysis.FilterStats.LinesKept)
 		fmt.Fprintf(&sb, "| Filter Reduction | %.1f%% |\n", filterPercentage)
@@ -60,6 +76,9 @@ func GenerateScanReport(containerName string, analysis *chunking.AnalyzeResult,
 	sb.WriteString("|--------|-------|\n")
 	fmt.Fprintf(&sb, "| Original Logs | %d |\n", analysis.OriginalCount)
 	fmt.Fprintf(&sb, "| Processed Logs | %d |\n", analysis.ProcessedCount)
+	if analysis.TruncatedLines > 0 {
+		fmt.Fprintf(&sb, "| Truncated Lines | %d |\n", analysis.TruncatedLines)
+	}
 	if analysis.Deduplicated {
 		fmt.Fprintf(&sb, "| Deduplication | %.1f%% |\n", calculateSavings(analysis.OriginalCount, analysis.ProcessedCount))
 	}
@@ -69,29 +88,120 @@ func GenerateScanReport(containerName string, analysis *chunking.AnalyzeResult,
 	return sb.String()
 }
 
-// SaveReport writes a report to the container's directory and returns the file path.
-func SaveReport(containerName, content string, cfg *config.Config) (string, error) {
+// SaveReport writes a report to the directory output.report_path_template selects for
+// the container and compose project (empty for standalone containers) and returns
+// the file path.
+func SaveReport(containerName, project, content string, cfg *config.Config) (string, error) {
+	subdir, err := cfg.Output.ReportSubdir(config.ReportPathData{
+		Container: sanitize.Name(containerName),
+		Project:   sanitize.Name(project),
+		Date:      time.Now().Format("2006-01-02"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute report directory: %w", err)
+	}
+
 	// Create container directory inside reports dir
-	containerDir := filepath.Join(cfg.Output.ReportsDir, sanitize.Name(containerName))
+	containerDir := filepath.Join(cfg.Output.ReportsDir, subdir)
 	if err := os.MkdirAll(containerDir, 0o750); err != nil {
 		return "", fmt.Errorf("failed to create report directory: %w", err)
 	}
 
-	// Generate filename: YYYY-MM-DD_HH-MM-SS.md
-	filename := time.Now().Format("2006-01-02_15-04-05") + ".md"
+	// Generate filename: YYYY-MM-DD_HH-MM-SS.<md|html>
+	filename := time.Now().Format("2006-01-02_15-04-05") + reportExtension(cfg.Output.ReportFormat)
 	filePath := filepath.Join(containerDir, filename)
 
-	// Write file
-	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+	// Write atomically so an interrupted run never leaves a truncated report
+	if err := fsutil.WriteFileAtomic(filePath, []byte(content), 0o600); err != nil {
 		return "", fmt.Errorf("failed to write report file: %w", err)
 	}
 
 	return filePath, nil
 }
 
+// rawLogsExtension replaces the report extension for the raw log file of a report.
+const rawLogsExtension = ".logs.txt"
+
+// SaveRawLogs writes the log text an analysis was based on next to its report,
+// named after the report with a ".logs.txt" extension, and returns the file path.
+func SaveRawLogs(reportPath, logsText string) (string, error) {
+	filePath := strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + rawLogsExtension
+	if err := fsutil.WriteFileAtomic(filePath, []byte(logsText), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write raw logs file: %w", err)
+	}
+	return filePath, nil
+}
+
+// FindRawLogs returns the raw log file saved next to a report of containerName. scan
+// selects the report by its timestamp (YYYY-MM-DD_HH-MM-SS); empty selects the latest.
+// The date of output.report_path_template matches any directory, and the project
+// matches any compose project as well as none, since SaveReport drops the empty
+// project segment of standalone containers.
+func FindRawLogs(containerName, scan string, cfg *config.Config) (string, error) {
+	var containerDirs []string
+	for _, project := range []string{"*", ""} {
+		subdir, err := cfg.Output.ReportSubdir(config.ReportPathData{
+			Container: sanitize.Name(containerName),
+			Project:   project,
+			Date:      "*",
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to compute report directory: %w", err)
+		}
+		if dir := filepath.Join(cfg.Output.ReportsDir, subdir); !slices.Contains(containerDirs, dir) {
+			containerDirs = append(containerDirs, dir)
+		}
+	}
+
+	name := "*" + rawLogsExtension
+	if scan != "" {
+		name = scan + rawLogsExtension
+	}
+	var matches []string
+	for _, dir := range containerDirs {
+		found, err := filepath.Glob(filepath.Join(dir, name))
+		if err != nil {
+			return "", fmt.Errorf("failed to list raw logs for %s: %w", containerName, err)
+		}
+		matches = append(matches, found...)
+	}
+	if len(matches) == 0 {
+		searched := strings.Join(containerDirs, " or ")
+		if scan != "" {
+			return "", fmt.Errorf("no saved raw logs for %s from scan %s in %s", containerName, scan, searched)
+		}
+		return "", fmt.Errorf("no saved raw logs for %s in %s (enable output.save_raw_logs)", containerName, searched)
+	}
+
+	// Timestamped names sort chronologically, whatever directory they are in
+	sort.Slice(matches, func(i, j int) bool { return filepath.Base(matches[i]) < filepath.Base(matches[j]) })
+	return matches[len(matches)-1], nil
+}
+
+// reportTitle distinguishes re-analyses of saved logs from regular scan reports.
+func reportTitle(analysis *chunking.AnalyzeResult) string {
+	if analysis.ReanalyzedFrom != "" {
+		return "Re-analysis Report"
+	}
+	return "Scan Report"
+}
+
+// isStopped reports whether a container state means the container is no longer running.
+func isStopped(state string) bool {
+	return state != "" && state != "running"
+}
+
 func calculateSavings(original, processed int) float64 {
 	if original == 0 {
 		return 0
 	}
 	return float64(original-processed) / float64(original) * 100
 }
+
+// displayNow returns the current time in loc, or local time when loc is nil.
+func displayNow(loc *time.Location) time.Time {
+	if loc == nil {
+		return time.Now()
+	}
+	return time.Now().In(loc)
+}
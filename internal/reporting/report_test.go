@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/zorak1103/dlia/internal/chunking"
 	"github.com/zorak1103/dlia/internal/config"
@@ -111,7 +112,7 @@ func TestGenerateScanReport(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := GenerateScanReport(tt.containerName, tt.analysis, tt.logs)
+			result := GenerateScanReport(tt.containerName, tt.analysis, tt.logs, time.UTC)
 
 			for _, want := range tt.wantContains {
 				if !strings.Contains(result, want) {
@@ -131,7 +132,7 @@ func TestGenerateScanReport_HasDateHeader(t *testing.T) {
 		ProcessedCount: 10,
 	}
 
-	result := GenerateScanReport("test", analysis, nil)
+	result := GenerateScanReport("test", analysis, nil, time.UTC)
 
 	if !strings.Contains(result, "**Date:**") {
 		t.Error("GenerateScanReport() missing date header")
@@ -180,7 +181,7 @@ func TestSaveReport(t *testing.T) {
 				},
 			}
 
-			filePath, err := SaveReport(tt.containerName, tt.content, cfg)
+			filePath, err := SaveReport(tt.containerName, "", tt.content, cfg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SaveReport() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -218,6 +219,11 @@ func TestSaveReport(t *testing.T) {
 			if !strings.HasSuffix(filename, ".md") {
 				t.Errorf("SaveReport() filename should end with .md, got: %s", filename)
 			}
+
+			// Verify the atomic write left no temp file behind
+			if _, err := os.Stat(filePath + ".tmp"); !os.IsNotExist(err) {
+				t.Errorf("SaveReport() left a temp file at %s.tmp", filePath)
+			}
 		})
 	}
 }
@@ -234,7 +240,7 @@ func TestSaveReport_DirectoryCreation(t *testing.T) {
 		},
 	}
 
-	filePath, err := SaveReport("test-container", "test content", cfg)
+	filePath, err := SaveReport("test-container", "", "test content", cfg)
 	if err != nil {
 		t.Errorf("SaveReport() failed to create nested directories: %v", err)
 		return
@@ -245,6 +251,108 @@ func TestSaveReport_DirectoryCreation(t *testing.T) {
 	}
 }
 
+func TestSaveRawLogs(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			ReportsDir:   t.TempDir(),
+			ReportFormat: FormatHTML,
+		},
+	}
+
+	reportPath, err := SaveReport("ns/web", "", "<html></html>", cfg)
+	if err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+
+	logsPath, err := SaveRawLogs(reportPath, "[2025-01-01T10:00:00Z] boom\n")
+	if err != nil {
+		t.Fatalf("SaveRawLogs() error = %v", err)
+	}
+
+	if filepath.Dir(logsPath) != filepath.Dir(reportPath) {
+		t.Errorf("raw logs %s should be next to report %s", logsPath, reportPath)
+	}
+	if want := strings.TrimSuffix(reportPath, ".html") + ".logs.txt"; logsPath != want {
+		t.Errorf("SaveRawLogs() path = %s, want %s", logsPath, want)
+	}
+	content, err := os.ReadFile(logsPath) //nolint:gosec // test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "[2025-01-01T10:00:00Z] boom\n" {
+		t.Errorf("unexpected raw logs content: %q", content)
+	}
+}
+
+func TestFindRawLogs(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Output: config.OutputConfig{ReportsDir: t.TempDir()}}
+	containerDir := filepath.Join(cfg.Output.ReportsDir, "web")
+	if err := os.MkdirAll(containerDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FindRawLogs("web", "", cfg); err == nil || !strings.Contains(err.Error(), "output.save_raw_logs") {
+		t.Errorf("FindRawLogs() without saved logs error = %v", err)
+	}
+
+	for _, name := range []string{"2025-01-01_10-00-00.logs.txt", "2025-01-02_10-00-00.logs.txt", "2025-01-03_10-00-00.md"} {
+		if err := os.WriteFile(filepath.Join(containerDir, name), []byte("x\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := FindRawLogs("web", "", cfg)
+	if err != nil {
+		t.Fatalf("FindRawLogs() error = %v", err)
+	}
+	if filepath.Base(latest) != "2025-01-02_10-00-00.logs.txt" {
+		t.Errorf("FindRawLogs() latest = %s", latest)
+	}
+
+	selected, err := FindRawLogs("web", "2025-01-01_10-00-00", cfg)
+	if err != nil {
+		t.Fatalf("FindRawLogs() error = %v", err)
+	}
+	if filepath.Base(selected) != "2025-01-01_10-00-00.logs.txt" {
+		t.Errorf("FindRawLogs() selected = %s", selected)
+	}
+
+	if _, err := FindRawLogs("web", "2025-01-03_10-00-00", cfg); err == nil {
+		t.Error("FindRawLogs() should fail for a report without saved logs")
+	}
+}
+
+func TestGenerateScanReport_Reanalysis(t *testing.T) {
+	t.Parallel()
+
+	analysis := &chunking.AnalyzeResult{Analysis: "Test", ReanalyzedFrom: "reports/web/2025-01-01_10-00-00.logs.txt"}
+
+	md := GenerateScanReport("web", analysis, nil, time.UTC)
+	if !strings.HasPrefix(md, "# Re-analysis Report: web") {
+		t.Errorf("Markdown report should be titled as a re-analysis:\n%s", md)
+	}
+	if !strings.Contains(md, "**Re-analysis of:** `reports/web/2025-01-01_10-00-00.logs.txt`") {
+		t.Error("Markdown report should name the re-analyzed logs")
+	}
+
+	html, err := GenerateHTMLScanReport("web", analysis, nil, time.UTC)
+	if err != nil {
+		t.Fatalf("GenerateHTMLScanReport() error = %v", err)
+	}
+	if !strings.Contains(html, "<h1>Re-analysis Report: web</h1>") || !strings.Contains(html, "Re-analysis of:") {
+		t.Error("HTML report should be marked as a re-analysis")
+	}
+
+	md = GenerateScanReport("web", &chunking.AnalyzeResult{Analysis: "Test"}, nil, time.UTC)
+	if !strings.HasPrefix(md, "# Scan Report: web") || strings.Contains(md, "Re-analysis") {
+		t.Error("regular reports must not be marked as re-analyses")
+	}
+}
+
 func TestSaveReport_FilePermissions(t *testing.T) {
 	t.Parallel()
 
@@ -261,7 +369,7 @@ func TestSaveReport_FilePermissions(t *testing.T) {
 		},
 	}
 
-	filePath, err := SaveReport("test-container", "test content", cfg)
+	filePath, err := SaveReport("test-container", "", "test content", cfg)
 	if err != nil {
 		t.Fatalf("SaveReport() failed: %v", err)
 	}
@@ -353,12 +461,12 @@ func TestSanitizeName(t *testing.T) {
 		{
 			name:  "name with slash",
 			input: "namespace/container",
-			want:  "namespace_container",
+			want:  "namespace~container",
 		},
 		{
 			name:  "multiple slashes",
 			input: "a/b/c/d",
-			want:  "a_b_c_d",
+			want:  "a~b~c~d",
 		},
 		{
 			name:  "no slashes",
@@ -373,7 +481,7 @@ func TestSanitizeName(t *testing.T) {
 		{
 			name:  "only slashes",
 			input: "///",
-			want:  "___",
+			want:  "~~~",
 		},
 	}
 
@@ -423,7 +531,7 @@ func TestGenerateScanReport_DeduplicationPercentage(t *testing.T) {
 				ProcessedCount: tt.processed,
 			}
 
-			result := GenerateScanReport("test", analysis, nil)
+			result := GenerateScanReport("test", analysis, nil, time.UTC)
 
 			if !strings.Contains(result, tt.wantPercentage) {
 				t.Errorf("GenerateScanReport() deduplication percentage not found\nWant: %s\nGot:\n%s", tt.wantPercentage, result)
@@ -442,13 +550,64 @@ func TestGenerateScanReport_NoDeduplicationRow(t *testing.T) {
 		ProcessedCount: 100,
 	}
 
-	result := GenerateScanReport("test", analysis, nil)
+	result := GenerateScanReport("test", analysis, nil, time.UTC)
 
 	if strings.Contains(result, "| Deduplication |") {
 		t.Error("GenerateScanReport() should not include deduplication row when Deduplicated is false")
 	}
 }
 
+func TestGenerateScanReport_TruncatedLinesRow(t *testing.T) {
+	t.Parallel()
+
+	analysis := &chunking.AnalyzeResult{
+		Analysis:       "Test",
+		OriginalCount:  100,
+		ProcessedCount: 40,
+		TruncatedLines: 60,
+	}
+
+	if result := GenerateScanReport("test", analysis, nil, time.UTC); !strings.Contains(result, "| Truncated Lines | 60 |") {
+		t.Error("GenerateScanReport() should include truncated lines row when lines were dropped")
+	}
+
+	analysis.TruncatedLines = 0
+	if result := GenerateScanReport("test", analysis, nil, time.UTC); strings.Contains(result, "Truncated Lines") {
+		t.Error("GenerateScanReport() should not include truncated lines row when nothing was dropped")
+	}
+}
+
+func TestGenerateScanReport_StoppedContainer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		state       string
+		wantFlagged bool
+	}{
+		{state: "exited", wantFlagged: true},
+		{state: "dead", wantFlagged: true},
+		{state: "running", wantFlagged: false},
+		{state: "", wantFlagged: false},
+	}
+
+	for _, tt := range tests {
+		analysis := &chunking.AnalyzeResult{Analysis: "Test", ContainerState: tt.state}
+
+		md := GenerateScanReport("test", analysis, nil, time.UTC)
+		if got := strings.Contains(md, "**Container State:**"); got != tt.wantFlagged {
+			t.Errorf("GenerateScanReport() state %q flagged = %v, want %v", tt.state, got, tt.wantFlagged)
+		}
+
+		html, err := GenerateHTMLScanReport("test", analysis, nil, time.UTC)
+		if err != nil {
+			t.Fatalf("GenerateHTMLScanReport() error = %v", err)
+		}
+		if got := strings.Contains(html, "no longer running"); got != tt.wantFlagged {
+			t.Errorf("GenerateHTMLScanReport() state %q flagged = %v, want %v", tt.state, got, tt.wantFlagged)
+		}
+	}
+}
+
 func TestGenerateScanReport_WithFilterStats(t *testing.T) {
 	t.Parallel()
 
@@ -465,7 +624,7 @@ func TestGenerateScanReport_WithFilterStats(t *testing.T) {
 		},
 	}
 
-	result := GenerateScanReport("mycontainer", analysis, nil)
+	result := GenerateScanReport("mycontainer", analysis, nil, time.UTC)
 
 	wantContains := []string{
 		"## 🔍 Pre-Processing Statistics",
@@ -482,3 +641,186 @@ func TestGenerateScanReport_WithFilterStats(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateHTMLScanReport(t *testing.T) {
+	t.Parallel()
+
+	analysis := &chunking.AnalyzeResult{
+		Analysis:       "Found <script>alert('x')</script> & errors",
+		OriginalCount:  200,
+		ProcessedCount: 100,
+		TokensUsed:     750,
+		ChunksUsed:     2,
+		Deduplicated:   true,
+		FilterStats: chunking.FilterStats{
+			LinesTotal:    200,
+			LinesFiltered: 50,
+			LinesKept:     150,
+		},
+	}
+
+	result, err := GenerateHTMLScanReport("web<app>", analysis, nil, time.UTC)
+	if err != nil {
+		t.Fatalf("GenerateHTMLScanReport() error = %v", err)
+	}
+
+	wantContains := []string{
+		"<!DOCTYPE html>",
+		"<title>Scan Report: web&lt;app&gt;</title>",
+		"&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt; &amp; errors",
+		"<td>Deduplication</td><td>50.0%</td>",
+		"<td>Lines Filtered (Regexp)</td><td>50</td>",
+		"<td>Tokens</td><td>750</td>",
+		"<td>Chunks</td><td>2</td>",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(result, want) {
+			t.Errorf("GenerateHTMLScanReport() missing %q\nGot:\n%s", want, result)
+		}
+	}
+
+	if strings.Contains(result, "<script>") {
+		t.Error("GenerateHTMLScanReport() must escape LLM output")
+	}
+}
+
+func TestGenerateReport_Format(t *testing.T) {
+	t.Parallel()
+
+	analysis := &chunking.AnalyzeResult{Analysis: "ok"}
+
+	md, err := GenerateReport("c", analysis, nil, FormatMarkdown, time.UTC)
+	if err != nil || !strings.HasPrefix(md, "# Scan Report: c") {
+		t.Errorf("GenerateReport(md) = %q, %v; want markdown report", md, err)
+	}
+
+	defaulted, err := GenerateReport("c", analysis, nil, "", time.UTC)
+	if err != nil || !strings.HasPrefix(defaulted, "# Scan Report: c") {
+		t.Errorf("GenerateReport(\"\") = %q, %v; want markdown report", defaulted, err)
+	}
+
+	html, err := GenerateReport("c", analysis, nil, FormatHTML, time.UTC)
+	if err != nil || !strings.HasPrefix(html, "<!DOCTYPE html>") {
+		t.Errorf("GenerateReport(html) = %q, %v; want HTML report", html, err)
+	}
+}
+
+func TestGenerateCappedReport(t *testing.T) {
+	t.Parallel()
+
+	analysis := &chunking.AnalyzeResult{
+		Analysis:      strings.Repeat("<error> & \"retry\" ", 500),
+		OriginalCount: 42,
+		TokensUsed:    777,
+	}
+
+	for _, format := range []string{FormatMarkdown, FormatHTML} {
+		full, err := GenerateReport("c", analysis, nil, format, time.UTC)
+		if err != nil {
+			t.Fatalf("GenerateReport(%s) error = %v", format, err)
+		}
+
+		unlimited, err := GenerateCappedReport("c", analysis, nil, format, time.UTC, 0)
+		if err != nil || len(unlimited) != len(full) {
+			t.Errorf("GenerateCappedReport(%s, 0) should not truncate, got %d bytes (%v), want %d", format, len(unlimited), err, len(full))
+		}
+
+		const limit = 3000
+		capped, err := GenerateCappedReport("c", analysis, nil, format, time.UTC, limit)
+		if err != nil {
+			t.Fatalf("GenerateCappedReport(%s) error = %v", format, err)
+		}
+		if len(capped) > limit {
+			t.Errorf("GenerateCappedReport(%s) = %d bytes, want at most %d", format, len(capped), limit)
+		}
+		if !strings.Contains(capped, "[truncated]") || !strings.Contains(capped, "777") {
+			t.Errorf("GenerateCappedReport(%s) should mark the cut and keep the token count:\n%s", format, capped)
+		}
+	}
+}
+
+func TestSaveReport_HTMLExtension(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			ReportsDir:   t.TempDir(),
+			ReportFormat: FormatHTML,
+		},
+	}
+
+	filePath, err := SaveReport("test-container", "", "<html></html>", cfg)
+	if err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+
+	if filepath.Ext(filePath) != ".html" {
+		t.Errorf("SaveReport() filename should end with .html, got: %s", filePath)
+	}
+}
+
+func TestSaveReport_PathTemplate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Output: config.OutputConfig{
+			ReportsDir:         t.TempDir(),
+			ReportPathTemplate: "{{.Project}}/{{.Container}}",
+		},
+	}
+
+	filePath, err := SaveReport("ns/web", "shop", "report", cfg)
+	if err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+	if want := filepath.Join(cfg.Output.ReportsDir, "shop", sanitize.Name("ns/web")); filepath.Dir(filePath) != want {
+		t.Errorf("SaveReport() dir = %s, want %s", filepath.Dir(filePath), want)
+	}
+
+	logsPath, err := SaveRawLogs(filePath, "boom\n")
+	if err != nil {
+		t.Fatalf("SaveRawLogs() error = %v", err)
+	}
+	found, err := FindRawLogs("ns/web", "", cfg)
+	if err != nil {
+		t.Fatalf("FindRawLogs() error = %v", err)
+	}
+	if found != logsPath {
+		t.Errorf("FindRawLogs() = %s, want %s", found, logsPath)
+	}
+
+	// Standalone containers have no project directory
+	standalone, err := SaveReport("db", "", "report", cfg)
+	if err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+	standaloneLogs, err := SaveRawLogs(standalone, "boom\n")
+	if err != nil {
+		t.Fatalf("SaveRawLogs() error = %v", err)
+	}
+	if found, err := FindRawLogs("db", "", cfg); err != nil || found != standaloneLogs {
+		t.Errorf("FindRawLogs() standalone = %s, %v; want %s", found, err, standaloneLogs)
+	}
+
+	if _, err := SaveReport("web", "..", "report", cfg); err == nil {
+		t.Error("SaveReport() must reject a project that leaves the reports directory")
+	}
+}
+
+func TestGenerateReport_DisplayTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	analysis := &chunking.AnalyzeResult{Analysis: "ok"}
+
+	for _, format := range []string{FormatMarkdown, FormatHTML} {
+		report, err := GenerateReport("c", analysis, nil, format, loc)
+		if err != nil {
+			t.Fatalf("GenerateReport(%s) error = %v", format, err)
+		}
+		if !strings.Contains(report, "JST") {
+			t.Errorf("GenerateReport(%s) date should be shown in JST:\n%s", format, report)
+		}
+	}
+}
@@ -3,10 +3,32 @@ package sanitize
 
 import "strings"
 
+// separatorReplacement stands in for "/" in file names. Docker container names can
+// only contain [a-zA-Z0-9][a-zA-Z0-9_.-]* plus "/" as separator, so "~" never
+// appears in a real name: the mapping is reversible and "org/app" and "org_app"
+// get distinct files. It is also safe in URLs and on every supported filesystem.
+const separatorReplacement = "~"
+
+// legacySeparatorReplacement stood in for "/" before separatorReplacement; files
+// written by older versions still carry it.
+const legacySeparatorReplacement = "_"
+
 // Name converts container names to filesystem-safe names.
-// Currently only handles "/" -> "_" substitution since Docker container names
-// can only contain [a-zA-Z0-9][a-zA-Z0-9_.-]* plus "/" as separator.
-// If future Docker versions allow additional special characters, extend this function.
+// Currently only handles "/" -> "~" substitution; see separatorReplacement.
+// If future Docker versions allow additional special characters, extend this function
+// together with Original.
 func Name(name string) string {
-	return strings.ReplaceAll(name, "/", "_")
+	return strings.ReplaceAll(name, "/", separatorReplacement)
+}
+
+// Original reverses Name, returning the container name a sanitized file name was
+// derived from. Name(Original(s)) == s for every s.
+func Original(sanitized string) string {
+	return strings.ReplaceAll(sanitized, separatorReplacement, "/")
+}
+
+// LegacyName returns the file name older versions derived from name, mapping "/"
+// to "_". Callers use it to find and migrate files written before the switch to "~".
+func LegacyName(name string) string {
+	return strings.ReplaceAll(name, "/", legacySeparatorReplacement)
 }
@@ -8,10 +8,10 @@ func TestName(t *testing.T) {
 		want  string
 	}{
 		{"mycontainer", "mycontainer"},
-		{"my/container", "my_container"},
-		{"a/b/c", "a_b_c"},
-		{"/leading", "_leading"},
-		{"trailing/", "trailing_"},
+		{"my/container", "my~container"},
+		{"a/b/c", "a~b~c"},
+		{"/leading", "~leading"},
+		{"trailing/", "trailing~"},
 		{"", ""},
 		{"no-slash_here.ok", "no-slash_here.ok"},
 	}
@@ -21,3 +21,40 @@ func TestName(t *testing.T) {
 		}
 	}
 }
+
+func TestName_NoCollisions(t *testing.T) {
+	names := []string{"org/app", "org_app", "org-app", "org.app", "org/app/x", "org_app/x"}
+
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		sanitized := Name(name)
+		if other, exists := seen[sanitized]; exists {
+			t.Errorf("Name(%q) and Name(%q) both map to %q", name, other, sanitized)
+		}
+		seen[sanitized] = name
+	}
+}
+
+func TestOriginal_RoundTrip(t *testing.T) {
+	for _, name := range []string{"mycontainer", "org/app", "org_app", "/leading", "a/b_c/d", ""} {
+		if got := Original(Name(name)); got != name {
+			t.Errorf("Original(Name(%q)) = %q", name, got)
+		}
+	}
+
+	// Legacy or foreign file names must survive a reverse-and-sanitize cycle unchanged
+	for _, sanitized := range []string{"org_app", "org~app", "plain"} {
+		if got := Name(Original(sanitized)); got != sanitized {
+			t.Errorf("Name(Original(%q)) = %q", sanitized, got)
+		}
+	}
+}
+
+func TestLegacyName(t *testing.T) {
+	if got := LegacyName("org/app/x"); got != "org_app_x" {
+		t.Errorf("LegacyName(%q) = %q, want %q", "org/app/x", got, "org_app_x")
+	}
+	if got := LegacyName("plain"); got != Name("plain") {
+		t.Errorf("LegacyName(%q) = %q, want it to match Name", "plain", got)
+	}
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Supported state backends (config: output.state_backend)
+const (
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+)
+
+// Backend is the storage-agnostic interface for container scan state.
+// The JSON file implementation is State; SQLiteState stores state in a SQLite database.
+type Backend interface {
+	// GetLastScan returns the last scan time for a container and whether it is tracked.
+	GetLastScan(containerID string) (time.Time, bool)
+	// GetLogCursor returns the stored log cursor for a container and whether one exists.
+	GetLogCursor(containerID string) (string, bool)
+	// UpdateContainer records new scan information for a container (persisted on Save).
+	UpdateContainer(containerID, name string, lastScan time.Time, cursor string)
+	// SeenFingerprints returns the line fingerprints analyzed in recent scans of a container.
+	SeenFingerprints(containerID string) map[string]bool
+	// RecordFingerprints stores one scan's fingerprints for a tracked container,
+	// keeping the most recent keepScans scans (persisted on Save).
+	RecordFingerprints(containerID string, fingerprints []string, keepScans int)
+	// GetLastNotified returns when the last per-container alert was sent, its severity
+	// and whether the container was notified before.
+	GetLastNotified(containerID string) (time.Time, string, bool)
+	// RecordNotification stores a sent per-container alert for a tracked container (persisted on Save).
+	RecordNotification(containerID string, notifiedAt time.Time, severity string)
+	// RemoveContainer removes a container and reports whether it was tracked (persisted on Save).
+	RemoveContainer(containerID string) bool
+	// ResetFiltered removes and immediately persists containers whose name or ID matches pattern.
+	ResetFiltered(pattern string) (int, error)
+	// GetAllContainers returns a copy of all tracked containers keyed by ID.
+	GetAllContainers() map[string]*Container
+	// Count returns the number of tracked containers.
+	Count() int
+	// UpdatedAt returns when the state was last persisted (zero if never).
+	UpdatedAt() time.Time
+	// Save persists pending changes.
+	Save() error
+	// Delete removes all persisted state.
+	Delete() error
+	// Close releases resources held by the backend.
+	Close() error
+}
+
+// Compile-time interface checks
+var (
+	_ Backend = (*State)(nil)
+	_ Backend = (*SQLiteState)(nil)
+)
+
+// Open loads the state stored at path using the named backend.
+// An empty backend name selects the JSON backend.
+func Open(backend, path string) (Backend, error) {
+	// Return untyped nil on error so callers can compare the interface against nil
+	switch backend {
+	case "", BackendJSON:
+		s, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case BackendSQLite:
+		s, err := OpenSQLite(path)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown state backend %q (expected %s or %s)", backend, BackendJSON, BackendSQLite)
+	}
+}
+
+// OpenReadOnly loads the state stored at path like Open, but never creates, upgrades
+// or writes it, e.g. for dry runs. Changes made to the returned state cannot be saved
+// with the SQLite backend; the JSON backend only writes on Save anyway.
+func OpenReadOnly(backend, path string) (Backend, error) {
+	if backend != BackendSQLite {
+		return Open(backend, path)
+	}
+
+	s, err := OpenSQLiteReadOnly(path)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,54 @@
+package state
+
+// MaxFingerprintsPerScan bounds how many line fingerprints are stored for a single
+// scan so a container emitting many distinct lines cannot grow the state unbounded.
+const MaxFingerprintsPerScan = 1000
+
+// appendFingerprintScan adds one scan's fingerprints to history (oldest first) and
+// keeps only the most recent keepScans scans. Fingerprints beyond
+// MaxFingerprintsPerScan are dropped.
+func appendFingerprintScan(history [][]string, fingerprints []string, keepScans int) [][]string {
+	if keepScans <= 0 {
+		return nil
+	}
+
+	if len(fingerprints) > MaxFingerprintsPerScan {
+		fingerprints = fingerprints[:MaxFingerprintsPerScan]
+	}
+	scan := make([]string, len(fingerprints))
+	copy(scan, fingerprints)
+
+	history = append(history, scan)
+	if len(history) > keepScans {
+		history = history[len(history)-keepScans:]
+	}
+
+	// Copy into a fresh slice so trimmed scans can be garbage collected
+	result := make([][]string, len(history))
+	copy(result, history)
+	return result
+}
+
+// fingerprintSet flattens a fingerprint history into a lookup set.
+func fingerprintSet(history [][]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, scan := range history {
+		for _, fp := range scan {
+			set[fp] = true
+		}
+	}
+	return set
+}
+
+// copyFingerprints returns a deep copy of a fingerprint history.
+func copyFingerprints(history [][]string) [][]string {
+	if history == nil {
+		return nil
+	}
+
+	result := make([][]string, len(history))
+	for i, scan := range history {
+		result[i] = append([]string(nil), scan...)
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendFingerprintScan(t *testing.T) {
+	var history [][]string
+	for i := 0; i < 5; i++ {
+		history = appendFingerprintScan(history, []string{fmt.Sprintf("fp%d", i)}, 3)
+	}
+
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 scans kept, got %d", len(history))
+	}
+	if history[0][0] != "fp2" || history[2][0] != "fp4" {
+		t.Errorf("Expected oldest scans trimmed, got %v", history)
+	}
+
+	if got := appendFingerprintScan(history, []string{"x"}, 0); got != nil {
+		t.Errorf("keepScans 0 should clear history, got %v", got)
+	}
+}
+
+func TestAppendFingerprintScan_CapsPerScan(t *testing.T) {
+	fps := make([]string, MaxFingerprintsPerScan+10)
+	for i := range fps {
+		fps[i] = fmt.Sprintf("fp%d", i)
+	}
+
+	history := appendFingerprintScan(nil, fps, 1)
+	if len(history[0]) != MaxFingerprintsPerScan {
+		t.Errorf("Expected scan capped at %d fingerprints, got %d", MaxFingerprintsPerScan, len(history[0]))
+	}
+}
+
+func TestState_RecordFingerprints(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Untracked containers are ignored
+	s.RecordFingerprints("abc123", []string{"a"}, 2)
+	if s.Count() != 0 {
+		t.Fatal("RecordFingerprints should not create container entries")
+	}
+
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	s.RecordFingerprints("abc123", []string{"a", "b"}, 2)
+
+	// UpdateContainer keeps previously recorded fingerprints
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	s.RecordFingerprints("abc123", []string{"c"}, 2)
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	seen := reloaded.SeenFingerprints("abc123")
+	for _, fp := range []string{"a", "b", "c"} {
+		if !seen[fp] {
+			t.Errorf("Expected fingerprint %q to be seen", fp)
+		}
+	}
+
+	if len(reloaded.SeenFingerprints("missing")) != 0 {
+		t.Error("Untracked container should have no fingerprints")
+	}
+}
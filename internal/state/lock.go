@@ -0,0 +1,88 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrLocked is returned by AcquireLock when another process holds the scan lock.
+var ErrLocked = errors.New("another scan is in progress")
+
+// errWouldBlock is returned by tryLock when the lock is held elsewhere.
+var errWouldBlock = errors.New("lock is held by another process")
+
+// Lock is an exclusive OS-level advisory lock on a file beside the state file. It
+// serializes scans across processes; the mutex in State only protects one process.
+// The operating system releases the lock when the process exits, even if it crashes.
+type Lock struct {
+	file *os.File
+}
+
+// LockPath returns the path of the lockfile guarding stateFile.
+func LockPath(stateFile string) string {
+	return stateFile + ".lock"
+}
+
+// AcquireLock takes the scan lock for stateFile without waiting. If another process
+// holds it, the returned error wraps ErrLocked.
+func AcquireLock(stateFile string) (*Lock, error) {
+	path := LockPath(stateFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory for %s: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600) // #nosec G304 -- path is derived from the configured state file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lockfile %s: %w", path, err)
+	}
+
+	if err := tryLock(f); err != nil {
+		holder := lockHolder(f)
+		f.Close() //nolint:errcheck,gosec // Lock was not acquired, close error not actionable
+		if errors.Is(err, errWouldBlock) {
+			if holder != "" {
+				return nil, fmt.Errorf("%w (lock %s held by PID %s)", ErrLocked, path, holder)
+			}
+			return nil, fmt.Errorf("%w (lock %s is held)", ErrLocked, path)
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	// Record the owner for the error message of competing scans; failures are harmless
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+
+	return &Lock{file: f}, nil
+}
+
+// Release unlocks and closes the lockfile. The file itself is kept: removing it
+// would let a waiting process lock a file that a new process recreates.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	f := l.file
+	l.file = nil
+	unlockErr := unlock(f)
+	closeErr := f.Close()
+	if unlockErr != nil {
+		return fmt.Errorf("failed to unlock %s: %w", f.Name(), unlockErr)
+	}
+	return closeErr
+}
+
+// lockHolder returns the PID recorded in a lockfile, or "" if unknown.
+func lockHolder(f *os.File) string {
+	buf := make([]byte, 32)
+	n, _ := f.ReadAt(buf, 0) //nolint:errcheck // EOF is expected for short files
+	pid := strings.TrimSpace(string(buf[:n]))
+	if _, err := strconv.Atoi(pid); err != nil {
+		return ""
+	}
+	return pid
+}
@@ -0,0 +1,50 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAcquireLock(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "data", "state.json")
+
+	lock, err := AcquireLock(stateFile)
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+
+	content, err := os.ReadFile(LockPath(stateFile))
+	if err != nil {
+		t.Fatalf("Failed to read lockfile: %v", err)
+	}
+	if got := strings.TrimSpace(string(content)); got != strconv.Itoa(os.Getpid()) {
+		t.Errorf("Lockfile content = %q, want the PID", got)
+	}
+
+	_, err = AcquireLock(stateFile)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("Second AcquireLock() error = %v, want ErrLocked", err)
+	}
+	if !strings.Contains(err.Error(), "PID "+strconv.Itoa(os.Getpid())) {
+		t.Errorf("Error should name the holding PID, got: %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Errorf("Second Release() should be a no-op, got %v", err)
+	}
+
+	relocked, err := AcquireLock(stateFile)
+	if err != nil {
+		t.Fatalf("AcquireLock() after release error = %v", err)
+	}
+	if err := relocked.Release(); err != nil {
+		t.Errorf("Release() error = %v", err)
+	}
+}
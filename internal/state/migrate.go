@@ -0,0 +1,80 @@
+package state
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CurrentVersion is the state file schema version written by this build.
+const CurrentVersion = 1
+
+// migrations maps a schema version to the step that upgrades it to the next version.
+// To introduce a new schema, bump CurrentVersion and register the step from the previous version.
+var migrations = map[int]func(*State) error{
+	0: migrateV0ToV1,
+}
+
+// migrate upgrades a freshly loaded state to CurrentVersion in place.
+// Upgraded states are marked modified so the new format is written back on the next Save.
+// Returns an error for versions that are unknown or newer than this build supports.
+func migrate(s *State) error {
+	version, err := parseVersion(s.Version)
+	if err != nil {
+		return fmt.Errorf("unsupported state version %q in %s: %w", s.Version, s.filePath, err)
+	}
+
+	if version > CurrentVersion {
+		return fmt.Errorf("state file %s has version %d but this build supports up to version %d: state written by a newer DLIA, please upgrade",
+			s.filePath, version, CurrentVersion)
+	}
+
+	for version < CurrentVersion {
+		step, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration path from state version %d in %s", version, s.filePath)
+		}
+		if err := step(s); err != nil {
+			return fmt.Errorf("failed to migrate state %s from version %d: %w", s.filePath, version, err)
+		}
+
+		version++
+		s.Version = strconv.Itoa(version)
+		s.modified = true
+	}
+
+	return nil
+}
+
+// parseVersion converts the stored version string to a number.
+// Files written before versioning was introduced have no version and are treated as version 0.
+func parseVersion(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	if version < 0 {
+		return 0, fmt.Errorf("negative version")
+	}
+
+	return version, nil
+}
+
+// migrateV0ToV1 upgrades unversioned state files: ensures the containers map
+// exists and drops null container entries.
+func migrateV0ToV1(s *State) error {
+	if s.Containers == nil {
+		s.Containers = make(map[string]*Container)
+	}
+
+	for id, ctr := range s.Containers {
+		if ctr == nil {
+			delete(s.Containers, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStateFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+	return path
+}
+
+func TestLoad_MigratesUnversionedState(t *testing.T) {
+	// Crafted pre-versioning file: no version field and a null container entry
+	path := writeStateFile(t, `{
+  "last_updated": "2024-01-01T00:00:00Z",
+  "containers": {
+    "abc123": {"name": "web", "last_scan": "2024-01-01T00:00:00Z"},
+    "dead00": null
+  }
+}`)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if s.Version != "1" {
+		t.Errorf("Version = %q, want upgraded to \"1\"", s.Version)
+	}
+	if !s.modified {
+		t.Error("Migrated state should be marked modified so it is written back on Save")
+	}
+	if s.Count() != 1 {
+		t.Errorf("Count() = %d, want 1 (null entry dropped)", s.Count())
+	}
+	if _, exists := s.GetLastScan("abc123"); !exists {
+		t.Error("Existing container should survive migration")
+	}
+
+	// The upgraded format is persisted on the next Save
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read saved state: %v", err)
+	}
+	if !strings.Contains(string(data), `"version": "1"`) {
+		t.Errorf("Saved state should contain version 1, got: %s", data)
+	}
+}
+
+func TestLoad_MigratesMissingContainers(t *testing.T) {
+	path := writeStateFile(t, `{"last_updated": "2024-01-01T00:00:00Z"}`)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if s.Containers == nil {
+		t.Fatal("Containers map should be initialized after migration")
+	}
+
+	// Must be safe to use after migration
+	s.UpdateContainer("abc", "web", s.LastUpdated, "")
+}
+
+func TestLoad_CurrentVersionNotModified(t *testing.T) {
+	path := writeStateFile(t, `{"version": "1", "containers": {}}`)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if s.modified {
+		t.Error("Current-version state should not be marked modified")
+	}
+}
+
+func TestLoad_NewerVersion(t *testing.T) {
+	path := writeStateFile(t, `{"version": "99", "containers": {}}`)
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("Load() expected error for newer state version")
+	}
+	if !strings.Contains(err.Error(), "newer DLIA, please upgrade") {
+		t.Errorf("Error should ask the user to upgrade, got: %v", err)
+	}
+}
+
+func TestLoad_UnknownVersion(t *testing.T) {
+	for _, version := range []string{"v2", "-1"} {
+		path := writeStateFile(t, `{"version": "`+version+`", "containers": {}}`)
+
+		_, err := Load(path)
+		if err == nil {
+			t.Errorf("Load() expected error for version %q", version)
+			continue
+		}
+		if !strings.Contains(err.Error(), "unsupported state version") {
+			t.Errorf("Unexpected error for version %q: %v", version, err)
+		}
+	}
+}
+
+func TestMigrate_MissingStep(t *testing.T) {
+	original := migrations
+	migrations = map[int]func(*State) error{}
+	defer func() { migrations = original }()
+
+	s := &State{Version: "0", filePath: "/tmp/test.json"}
+	if err := migrate(s); err == nil || !strings.Contains(err.Error(), "no migration path") {
+		t.Errorf("migrate() error = %v, want missing migration path error", err)
+	}
+}
@@ -0,0 +1,540 @@
+package state
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the pure-Go "sqlite" database/sql driver
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS containers (
+	id         TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	last_scan  TEXT NOT NULL,
+	log_cursor TEXT NOT NULL DEFAULT '',
+	fingerprints TEXT NOT NULL DEFAULT '',
+	last_notified     TEXT NOT NULL DEFAULT '',
+	notified_severity TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);`
+
+const sqliteUpsert = `
+INSERT INTO containers (id, name, last_scan, log_cursor, fingerprints, last_notified, notified_severity)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET name = excluded.name, last_scan = excluded.last_scan,
+	log_cursor = excluded.log_cursor, fingerprints = excluded.fingerprints,
+	last_notified = excluded.last_notified, notified_severity = excluded.notified_severity`
+
+const sqliteSetMeta = `
+INSERT INTO meta (key, value) VALUES (?, ?)
+ON CONFLICT(key) DO UPDATE SET value = excluded.value`
+
+// SQLiteState stores container scan state in a SQLite database.
+// Containers are cached in memory; Save writes only the containers changed since
+// the last save as individual upserts instead of rewriting the whole state.
+type SQLiteState struct {
+	db          *sql.DB
+	filePath    string
+	mu          sync.RWMutex
+	containers  map[string]*Container
+	dirty       map[string]bool // Containers with pending upserts
+	removed     map[string]bool // Containers with pending deletes
+	lastUpdated time.Time
+	readOnly    bool // Opened by OpenSQLiteReadOnly; db is nil when the database does not exist
+}
+
+// newSQLiteState returns an empty state for the database at filePath.
+func newSQLiteState(filePath string) *SQLiteState {
+	return &SQLiteState{
+		filePath:   filePath,
+		containers: make(map[string]*Container),
+		dirty:      make(map[string]bool),
+		removed:    make(map[string]bool),
+	}
+}
+
+// OpenSQLite opens (or creates) the SQLite state database at filePath and loads all containers.
+func OpenSQLite(filePath string) (*SQLiteState, error) {
+	db, err := sql.Open("sqlite", filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database %s: %w", filePath, err)
+	}
+	// A single connection serializes writers and avoids SQLITE_BUSY between our own connections
+	db.SetMaxOpenConns(1)
+
+	s := newSQLiteState(filePath)
+	s.db = db
+	if err := s.init(); err != nil {
+		_ = db.Close() // Best effort cleanup
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// OpenSQLiteReadOnly loads the SQLite state database at filePath without creating,
+// upgrading or writing it, e.g. for dry runs. A missing database yields an empty
+// state. Save and Delete fail once the returned state has changes.
+func OpenSQLiteReadOnly(filePath string) (*SQLiteState, error) {
+	s := newSQLiteState(filePath)
+	s.readOnly = true
+	if _, err := os.Stat(filePath); errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+
+	db, err := sql.Open("sqlite", "file:"+(&url.URL{Path: filePath}).EscapedPath()+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database %s: %w", filePath, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	s.db = db
+	if _, err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+		_ = db.Close() // Best effort cleanup
+		return nil, fmt.Errorf("failed to configure state database %s: %w", filePath, err)
+	}
+	if err := s.checkVersion(); err != nil {
+		_ = db.Close() // Best effort cleanup
+		return nil, err
+	}
+	if err := s.load(); err != nil {
+		_ = db.Close() // Best effort cleanup
+		return nil, err
+	}
+	return s, nil
+}
+
+// init creates the schema, checks the schema version and loads all containers into memory.
+func (s *SQLiteState) init() error {
+	if _, err := s.db.Exec("PRAGMA busy_timeout = 5000; PRAGMA journal_mode = WAL;"); err != nil {
+		return fmt.Errorf("failed to configure state database %s: %w", s.filePath, err)
+	}
+	if _, err := s.db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create schema in state database %s: %w", s.filePath, err)
+	}
+
+	if err := s.checkVersion(); err != nil {
+		return err
+	}
+
+	if err := s.addMissingColumns(); err != nil {
+		return err
+	}
+
+	return s.load()
+}
+
+// load reads all containers and the metadata into memory. Columns of addedColumns
+// missing from a database not yet upgraded (read-only) read as empty.
+func (s *SQLiteState) load() error {
+	columns := "id, name, last_scan, log_cursor"
+	for _, column := range addedColumns {
+		exists, err := s.hasColumn(column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			columns += ", " + column
+		} else {
+			columns += ", '' AS " + column
+		}
+	}
+
+	// columns come from addedColumns, not from input
+	rows, err := s.db.Query("SELECT " + columns + " FROM containers")
+	if err != nil {
+		return fmt.Errorf("failed to read containers from state database %s: %w", s.filePath, err)
+	}
+	defer rows.Close() //nolint:errcheck // Close error not actionable after iteration
+
+	for rows.Next() {
+		var id, lastScan, fingerprints, lastNotified string
+		ctr := &Container{}
+		if err := rows.Scan(&id, &ctr.Name, &lastScan, &ctr.LogCursor, &fingerprints, &lastNotified, &ctr.NotifiedSeverity); err != nil {
+			return fmt.Errorf("failed to scan container row in state database %s: %w", s.filePath, err)
+		}
+		if ctr.LastScan, err = time.Parse(time.RFC3339Nano, lastScan); err != nil {
+			return fmt.Errorf("invalid last_scan %q for container %s in state database %s: %w", lastScan, id, s.filePath, err)
+		}
+		if fingerprints != "" {
+			if err := json.Unmarshal([]byte(fingerprints), &ctr.Fingerprints); err != nil {
+				return fmt.Errorf("invalid fingerprints for container %s in state database %s: %w", id, s.filePath, err)
+			}
+		}
+		if lastNotified != "" {
+			if ctr.LastNotified, err = time.Parse(time.RFC3339Nano, lastNotified); err != nil {
+				return fmt.Errorf("invalid last_notified %q for container %s in state database %s: %w", lastNotified, id, s.filePath, err)
+			}
+		}
+		s.containers[id] = ctr
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read containers from state database %s: %w", s.filePath, err)
+	}
+
+	var updated string
+	err = s.db.QueryRow("SELECT value FROM meta WHERE key = 'last_updated'").Scan(&updated)
+	if err == nil {
+		s.lastUpdated, _ = time.Parse(time.RFC3339Nano, updated) //nolint:errcheck // Zero time is an acceptable fallback
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read metadata from state database %s: %w", s.filePath, err)
+	}
+
+	return nil
+}
+
+// checkVersion records the schema version in a new database and rejects databases
+// written by a newer DLIA.
+func (s *SQLiteState) checkVersion() error {
+	var stored string
+	err := s.db.QueryRow("SELECT value FROM meta WHERE key = 'version'").Scan(&stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		if s.readOnly {
+			return nil
+		}
+		if _, err := s.db.Exec(sqliteSetMeta, "version", strconv.Itoa(CurrentVersion)); err != nil {
+			return fmt.Errorf("failed to write version to state database %s: %w", s.filePath, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read version from state database %s: %w", s.filePath, err)
+	}
+
+	version, err := parseVersion(stored)
+	if err != nil {
+		return fmt.Errorf("unsupported state version %q in %s: %w", stored, s.filePath, err)
+	}
+	if version > CurrentVersion {
+		return fmt.Errorf("state database %s has version %d but this build supports up to version %d: state written by a newer DLIA, please upgrade",
+			s.filePath, version, CurrentVersion)
+	}
+
+	return nil
+}
+
+// addedColumns lists the containers columns added after the initial schema, in the order
+// they were introduced.
+var addedColumns = []string{"fingerprints", "last_notified", "notified_severity"}
+
+// addMissingColumns upgrades databases created before all columns of addedColumns existed.
+func (s *SQLiteState) addMissingColumns() error {
+	for _, column := range addedColumns {
+		exists, err := s.hasColumn(column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		// column comes from addedColumns, not from input
+		if _, err := s.db.Exec("ALTER TABLE containers ADD COLUMN " + column + " TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add %s column to state database %s: %w", column, s.filePath, err)
+		}
+	}
+	return nil
+}
+
+// hasColumn reports whether the containers table has column.
+func (s *SQLiteState) hasColumn(column string) (bool, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('containers') WHERE name = ?", column).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect schema of state database %s: %w", s.filePath, err)
+	}
+	return count > 0, nil
+}
+
+// GetLastScan returns the last scan time for a container.
+func (s *SQLiteState) GetLastScan(containerID string) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ctr, exists := s.containers[containerID]; exists {
+		return ctr.LastScan, true
+	}
+	return time.Time{}, false
+}
+
+// GetLogCursor returns the stored log cursor for a container.
+func (s *SQLiteState) GetLogCursor(containerID string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ctr, exists := s.containers[containerID]; exists && ctr.LogCursor != "" {
+		return ctr.LogCursor, true
+	}
+	return "", false
+}
+
+// UpdateContainer records new scan information, preserving stored fingerprints and
+// notification times; the upsert is written on Save.
+func (s *SQLiteState) UpdateContainer(containerID, name string, lastScan time.Time, cursor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctr := &Container{
+		Name:      name,
+		LastScan:  lastScan,
+		LogCursor: cursor,
+	}
+	if existing, exists := s.containers[containerID]; exists {
+		ctr.Fingerprints = existing.Fingerprints
+		ctr.LastNotified = existing.LastNotified
+		ctr.NotifiedSeverity = existing.NotifiedSeverity
+	}
+	s.containers[containerID] = ctr
+	s.dirty[containerID] = true
+	delete(s.removed, containerID)
+}
+
+// SeenFingerprints returns the set of line fingerprints analyzed in the stored recent scans.
+func (s *SQLiteState) SeenFingerprints(containerID string) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ctr, exists := s.containers[containerID]; exists {
+		return fingerprintSet(ctr.Fingerprints)
+	}
+	return map[string]bool{}
+}
+
+// RecordFingerprints stores the current scan's fingerprints for a tracked container,
+// keeping the most recent keepScans scans; the upsert is written on Save.
+func (s *SQLiteState) RecordFingerprints(containerID string, fingerprints []string, keepScans int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctr, exists := s.containers[containerID]
+	if !exists {
+		return
+	}
+
+	ctr.Fingerprints = appendFingerprintScan(ctr.Fingerprints, fingerprints, keepScans)
+	s.dirty[containerID] = true
+}
+
+// GetLastNotified returns when the last per-container alert was sent and its severity.
+func (s *SQLiteState) GetLastNotified(containerID string) (time.Time, string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ctr, exists := s.containers[containerID]; exists && !ctr.LastNotified.IsZero() {
+		return ctr.LastNotified, ctr.NotifiedSeverity, true
+	}
+	return time.Time{}, "", false
+}
+
+// RecordNotification stores a sent per-container alert for a tracked container;
+// the upsert is written on Save.
+func (s *SQLiteState) RecordNotification(containerID string, notifiedAt time.Time, severity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctr, exists := s.containers[containerID]
+	if !exists {
+		return
+	}
+
+	ctr.LastNotified = notifiedAt
+	ctr.NotifiedSeverity = severity
+	s.dirty[containerID] = true
+}
+
+// RemoveContainer removes a container; the delete is written on Save.
+func (s *SQLiteState) RemoveContainer(containerID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.containers[containerID]; !exists {
+		return false
+	}
+
+	delete(s.containers, containerID)
+	delete(s.dirty, containerID)
+	s.removed[containerID] = true
+	return true
+}
+
+// ResetFiltered removes containers whose name or ID matches pattern and persists immediately.
+func (s *SQLiteState) ResetFiltered(pattern string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pattern == "" {
+		return 0, fmt.Errorf("pattern cannot be empty for ResetFiltered operation on state %s", s.filePath)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pattern %q for ResetFiltered operation on state %s: %w", pattern, s.filePath, err)
+	}
+
+	count := 0
+	for id, ctr := range s.containers {
+		if re.MatchString(ctr.Name) || re.MatchString(id) {
+			delete(s.containers, id)
+			delete(s.dirty, id)
+			s.removed[id] = true
+			count++
+		}
+	}
+
+	if count > 0 {
+		if err := s.saveUnlocked(); err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+// GetAllContainers returns a deep copy of all container states.
+func (s *SQLiteState) GetAllContainers() map[string]*Container {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]*Container, len(s.containers))
+	for id, ctr := range s.containers {
+		result[id] = &Container{
+			Name:             ctr.Name,
+			LastScan:         ctr.LastScan,
+			LogCursor:        ctr.LogCursor,
+			Fingerprints:     copyFingerprints(ctr.Fingerprints),
+			LastNotified:     ctr.LastNotified,
+			NotifiedSeverity: ctr.NotifiedSeverity,
+		}
+	}
+	return result
+}
+
+// Count returns the number of containers currently tracked.
+func (s *SQLiteState) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.containers)
+}
+
+// UpdatedAt returns when pending changes were last written.
+func (s *SQLiteState) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastUpdated
+}
+
+// Save writes pending upserts and deletes in a single transaction.
+// Only saves if there are changes since the last save.
+func (s *SQLiteState) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.saveUnlocked()
+}
+
+// saveUnlocked performs the save operation without acquiring the lock.
+// Caller must hold the lock.
+func (s *SQLiteState) saveUnlocked() error {
+	if len(s.dirty) == 0 && len(s.removed) == 0 {
+		return nil // No changes to save
+	}
+	if s.readOnly {
+		return fmt.Errorf("state database %s is open read-only", s.filePath)
+	}
+
+	now := time.Now()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction on state database %s: %w", s.filePath, err)
+	}
+	defer tx.Rollback() //nolint:errcheck // Rollback after Commit is a no-op
+
+	for id := range s.removed {
+		if _, err := tx.Exec("DELETE FROM containers WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete container %s from state database %s: %w", id, s.filePath, err)
+		}
+	}
+
+	for id := range s.dirty {
+		ctr := s.containers[id]
+		fingerprints, err := encodeFingerprints(ctr.Fingerprints)
+		if err != nil {
+			return fmt.Errorf("failed to encode fingerprints for container %s in state database %s: %w", id, s.filePath, err)
+		}
+		lastNotified := ""
+		if !ctr.LastNotified.IsZero() {
+			lastNotified = ctr.LastNotified.Format(time.RFC3339Nano)
+		}
+		if _, err := tx.Exec(sqliteUpsert, id, ctr.Name, ctr.LastScan.Format(time.RFC3339Nano), ctr.LogCursor, fingerprints,
+			lastNotified, ctr.NotifiedSeverity); err != nil {
+			return fmt.Errorf("failed to upsert container %s in state database %s: %w", id, s.filePath, err)
+		}
+	}
+
+	if _, err := tx.Exec(sqliteSetMeta, "last_updated", now.Format(time.RFC3339Nano)); err != nil {
+		return fmt.Errorf("failed to update metadata in state database %s: %w", s.filePath, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit state database %s: %w", s.filePath, err)
+	}
+
+	s.dirty = make(map[string]bool)
+	s.removed = make(map[string]bool)
+	s.lastUpdated = now
+	return nil
+}
+
+// encodeFingerprints serializes a fingerprint history as JSON ("" when empty).
+func encodeFingerprints(history [][]string) (string, error) {
+	if len(history) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Delete removes all containers from the database and clears in-memory state.
+func (s *SQLiteState) Delete() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.readOnly {
+		return fmt.Errorf("state database %s is open read-only", s.filePath)
+	}
+	if _, err := s.db.Exec("DELETE FROM containers; DELETE FROM meta WHERE key = 'last_updated';"); err != nil {
+		return fmt.Errorf("failed to clear state database %s: %w", s.filePath, err)
+	}
+
+	s.containers = make(map[string]*Container)
+	s.dirty = make(map[string]bool)
+	s.removed = make(map[string]bool)
+	s.lastUpdated = time.Time{}
+	return nil
+}
+
+// Close closes the underlying database.
+func (s *SQLiteState) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
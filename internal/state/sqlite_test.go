@@ -0,0 +1,320 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestSQLite(t *testing.T, path string) *SQLiteState {
+	t.Helper()
+
+	s, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("OpenSQLite() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestSQLiteState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	scanTime := time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	s := openTestSQLite(t, path)
+	if s.Count() != 0 {
+		t.Fatalf("New database should be empty, got %d containers", s.Count())
+	}
+
+	s.UpdateContainer("abc123", "web", scanTime, "cursor-1")
+	s.UpdateContainer("def456", "db", scanTime, "")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if s.UpdatedAt().IsZero() {
+		t.Error("UpdatedAt() should be set after Save")
+	}
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	if reopened.Count() != 2 {
+		t.Fatalf("Count() after reopen = %d, want 2", reopened.Count())
+	}
+
+	lastScan, exists := reopened.GetLastScan("abc123")
+	if !exists || !lastScan.Equal(scanTime) {
+		t.Errorf("GetLastScan() = %v, %v; want %v, true", lastScan, exists, scanTime)
+	}
+	if cursor, ok := reopened.GetLogCursor("abc123"); !ok || cursor != "cursor-1" {
+		t.Errorf("GetLogCursor() = %q, %v; want cursor-1, true", cursor, ok)
+	}
+	if _, ok := reopened.GetLogCursor("def456"); ok {
+		t.Error("Container without cursor should report no cursor")
+	}
+	if reopened.UpdatedAt().IsZero() {
+		t.Error("UpdatedAt() should be loaded from metadata")
+	}
+}
+
+func TestSQLiteState_UnsavedChangesNotPersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	if reopened.Count() != 0 {
+		t.Errorf("Unsaved changes should not be persisted, got %d containers", reopened.Count())
+	}
+}
+
+func TestSQLiteState_RemoveContainer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	s.UpdateContainer("def456", "db", time.Now(), "")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if !s.RemoveContainer("abc123") {
+		t.Error("RemoveContainer() should return true for existing container")
+	}
+	if s.RemoveContainer("missing") {
+		t.Error("RemoveContainer() should return false for missing container")
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	if _, exists := reopened.GetLastScan("abc123"); exists {
+		t.Error("Removed container should not be persisted")
+	}
+	if reopened.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", reopened.Count())
+	}
+}
+
+func TestSQLiteState_ResetFiltered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	s.UpdateContainer("abc123", "nginx-1", time.Now(), "")
+	s.UpdateContainer("def456", "nginx-2", time.Now(), "")
+	s.UpdateContainer("ghi789", "postgres", time.Now(), "")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	count, err := s.ResetFiltered("nginx")
+	if err != nil {
+		t.Fatalf("ResetFiltered() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("ResetFiltered() = %d, want 2", count)
+	}
+
+	if _, err := s.ResetFiltered(""); err == nil {
+		t.Error("ResetFiltered() should reject empty pattern")
+	}
+	if _, err := s.ResetFiltered("[invalid"); err == nil {
+		t.Error("ResetFiltered() should reject invalid regex")
+	}
+	_ = s.Close()
+
+	// ResetFiltered persists immediately
+	reopened := openTestSQLite(t, path)
+	if reopened.Count() != 1 {
+		t.Errorf("Count() after reopen = %d, want 1", reopened.Count())
+	}
+}
+
+func TestSQLiteState_Delete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := s.Delete(); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if s.Count() != 0 || !s.UpdatedAt().IsZero() {
+		t.Error("Delete() should clear in-memory state")
+	}
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	if reopened.Count() != 0 {
+		t.Errorf("Delete() should clear persisted state, got %d containers", reopened.Count())
+	}
+}
+
+func TestSQLiteState_Fingerprints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	s.RecordFingerprints("abc123", []string{"a", "b"}, 1)
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	s.RecordFingerprints("abc123", []string{"c"}, 1)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	seen := reopened.SeenFingerprints("abc123")
+	if !seen["c"] || seen["a"] {
+		t.Errorf("Expected only the most recent scan to be kept, got %v", seen)
+	}
+}
+
+func TestSQLiteState_Notifications(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	notifiedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	s := openTestSQLite(t, path)
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	s.RecordNotification("abc123", notifiedAt, "warning")
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	at, severity, ok := reopened.GetLastNotified("abc123")
+	if !ok || !at.Equal(notifiedAt) || severity != "warning" {
+		t.Errorf("GetLastNotified() = %v, %q, %v; want %v, warning, true", at, severity, ok, notifiedAt)
+	}
+}
+
+func TestSQLiteState_AddsMissingColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	if _, err := s.db.Exec("ALTER TABLE containers DROP COLUMN last_notified; ALTER TABLE containers DROP COLUMN notified_severity"); err != nil {
+		t.Fatalf("Failed to simulate old schema: %v", err)
+	}
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	reopened.UpdateContainer("abc123", "web", time.Now(), "")
+	reopened.RecordNotification("abc123", time.Now(), "critical")
+	if err := reopened.Save(); err != nil {
+		t.Errorf("Save() after schema upgrade error = %v", err)
+	}
+}
+
+func TestSQLiteState_AddsFingerprintsColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	if _, err := s.db.Exec("ALTER TABLE containers DROP COLUMN fingerprints"); err != nil {
+		t.Fatalf("Failed to simulate old schema: %v", err)
+	}
+	_ = s.Close()
+
+	reopened := openTestSQLite(t, path)
+	reopened.UpdateContainer("abc123", "web", time.Now(), "")
+	reopened.RecordFingerprints("abc123", []string{"a"}, 1)
+	if err := reopened.Save(); err != nil {
+		t.Errorf("Save() after schema upgrade error = %v", err)
+	}
+}
+
+func TestSQLiteState_NewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s := openTestSQLite(t, path)
+	if _, err := s.db.Exec(sqliteSetMeta, "version", "99"); err != nil {
+		t.Fatalf("Failed to write version: %v", err)
+	}
+	_ = s.Close()
+
+	_, err := OpenSQLite(path)
+	if err == nil || !strings.Contains(err.Error(), "newer DLIA, please upgrade") {
+		t.Errorf("OpenSQLite() error = %v, want newer-version error", err)
+	}
+}
+
+func TestOpen_Backends(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonBackend, err := Open("", filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatalf("Open(json) error = %v", err)
+	}
+	if _, ok := jsonBackend.(*State); !ok {
+		t.Errorf("Open(\"\") = %T, want *State", jsonBackend)
+	}
+
+	sqliteBackend, err := Open(BackendSQLite, filepath.Join(dir, "state.db"))
+	if err != nil {
+		t.Fatalf("Open(sqlite) error = %v", err)
+	}
+	defer sqliteBackend.Close() //nolint:errcheck // Test cleanup
+	if _, ok := sqliteBackend.(*SQLiteState); !ok {
+		t.Errorf("Open(sqlite) = %T, want *SQLiteState", sqliteBackend)
+	}
+
+	backend, err := Open("redis", filepath.Join(dir, "state"))
+	if err == nil {
+		t.Error("Open() should reject unknown backend")
+	}
+	if backend != nil {
+		t.Error("Open() should return a nil interface on error")
+	}
+}
+
+func TestOpenSQLiteReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	empty, err := OpenSQLiteReadOnly(path)
+	if err != nil {
+		t.Fatalf("OpenSQLiteReadOnly() on a missing database error = %v", err)
+	}
+	if empty.Count() != 0 {
+		t.Errorf("Count() = %d, want 0", empty.Count())
+	}
+	_ = empty.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected no database to be created, stat error = %v", err)
+	}
+
+	s := openTestSQLite(t, path)
+	s.UpdateContainer("abc123", "web", time.Now(), "cursor-1")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := s.db.Exec("ALTER TABLE containers DROP COLUMN notified_severity"); err != nil {
+		t.Fatalf("Failed to simulate old schema: %v", err)
+	}
+	_ = s.Close()
+
+	ro, err := OpenSQLiteReadOnly(path)
+	if err != nil {
+		t.Fatalf("OpenSQLiteReadOnly() error = %v", err)
+	}
+	defer ro.Close() //nolint:errcheck // test cleanup
+	if cursor, ok := ro.GetLogCursor("abc123"); !ok || cursor != "cursor-1" {
+		t.Errorf("GetLogCursor() = %q, %v; want cursor-1", cursor, ok)
+	}
+	if exists, err := ro.hasColumn("notified_severity"); err != nil || exists {
+		t.Errorf("Expected the old schema to stay unchanged, hasColumn() = %v, %v", exists, err)
+	}
+
+	ro.UpdateContainer("def456", "db", time.Now(), "")
+	if err := ro.Save(); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("Save() error = %v, want read-only error", err)
+	}
+}
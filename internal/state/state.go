@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -26,6 +27,12 @@ type Container struct {
 	Name      string    `json:"name"`
 	LastScan  time.Time `json:"last_scan"`
 	LogCursor string    `json:"log_cursor,omitempty"`
+	// Fingerprints holds normalized line hashes analyzed in recent scans, oldest scan first
+	Fingerprints [][]string `json:"fingerprints,omitempty"`
+	// LastNotified is when the last per-container alert was sent and NotifiedSeverity
+	// the severity it reported, used for notification.cooldown_per_container
+	LastNotified     time.Time `json:"last_notified,omitzero"`
+	NotifiedSeverity string    `json:"notified_severity,omitempty"`
 }
 
 // Load loads the state from a JSON file at the specified path.
@@ -33,7 +40,7 @@ type Container struct {
 // Returns error if the file cannot be read or parsed.
 func Load(filePath string) (*State, error) {
 	s := &State{
-		Version:    "1",
+		Version:    strconv.Itoa(CurrentVersion),
 		Containers: make(map[string]*Container),
 		filePath:   filePath,
 	}
@@ -49,12 +56,19 @@ func Load(filePath string) (*State, error) {
 		return nil, fmt.Errorf("failed to read state file from %s: %w", filePath, err)
 	}
 
-	// Unmarshal JSON
+	// Unmarshal JSON; a missing version field identifies a pre-versioning file
+	s.Version = ""
 	if err := json.Unmarshal(data, s); err != nil {
 		return nil, fmt.Errorf("failed to parse state file %s: %w", filePath, err)
 	}
 
 	s.filePath = filePath
+
+	// Upgrade older schema versions in memory; persisted on next Save
+	if err := migrate(s); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
@@ -131,18 +145,93 @@ func (s *State) GetLastScan(containerID string) (time.Time, bool) {
 	return time.Time{}, false
 }
 
+// GetLogCursor returns the stored log cursor for a container.
+// Returns the encoded cursor and true if the container exists in state and has a cursor.
+// Returns an empty string and false otherwise.
+func (s *State) GetLogCursor(containerID string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ctr, exists := s.Containers[containerID]; exists && ctr.LogCursor != "" {
+		return ctr.LogCursor, true
+	}
+	return "", false
+}
+
 // UpdateContainer updates the state for a container with new scan information.
 // Creates a new container entry if it doesn't exist, or updates the existing one.
-// Marks the state as modified requiring a save operation.
+// Stored fingerprints and notification times are preserved. Marks the state as modified
+// requiring a save operation.
 func (s *State) UpdateContainer(containerID, name string, lastScan time.Time, cursor string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.Containers[containerID] = &Container{
+	ctr := &Container{
 		Name:      name,
 		LastScan:  lastScan,
 		LogCursor: cursor,
 	}
+	if existing, exists := s.Containers[containerID]; exists {
+		ctr.Fingerprints = existing.Fingerprints
+		ctr.LastNotified = existing.LastNotified
+		ctr.NotifiedSeverity = existing.NotifiedSeverity
+	}
+	s.Containers[containerID] = ctr
+	s.modified = true
+}
+
+// SeenFingerprints returns the set of line fingerprints analyzed in the stored recent scans.
+func (s *State) SeenFingerprints(containerID string) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ctr, exists := s.Containers[containerID]; exists {
+		return fingerprintSet(ctr.Fingerprints)
+	}
+	return map[string]bool{}
+}
+
+// RecordFingerprints stores the fingerprints analyzed in the current scan, keeping only
+// the most recent keepScans scans. Untracked containers are ignored, so call this after
+// UpdateContainer. Marks the state as modified requiring a save operation.
+func (s *State) RecordFingerprints(containerID string, fingerprints []string, keepScans int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctr, exists := s.Containers[containerID]
+	if !exists {
+		return
+	}
+
+	ctr.Fingerprints = appendFingerprintScan(ctr.Fingerprints, fingerprints, keepScans)
+	s.modified = true
+}
+
+// GetLastNotified returns when the last per-container alert was sent and its severity.
+// Returns zero values and false if the container is untracked or was never notified.
+func (s *State) GetLastNotified(containerID string) (time.Time, string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ctr, exists := s.Containers[containerID]; exists && !ctr.LastNotified.IsZero() {
+		return ctr.LastNotified, ctr.NotifiedSeverity, true
+	}
+	return time.Time{}, "", false
+}
+
+// RecordNotification stores when a per-container alert of the given severity was sent.
+// Untracked containers are ignored. Marks the state as modified requiring a save operation.
+func (s *State) RecordNotification(containerID string, notifiedAt time.Time, severity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctr, exists := s.Containers[containerID]
+	if !exists {
+		return
+	}
+
+	ctr.LastNotified = notifiedAt
+	ctr.NotifiedSeverity = severity
 	s.modified = true
 }
 
@@ -220,9 +309,12 @@ func (s *State) GetAllContainers() map[string]*Container {
 	for id, ctr := range s.Containers {
 		// Deep copy
 		result[id] = &Container{
-			Name:      ctr.Name,
-			LastScan:  ctr.LastScan,
-			LogCursor: ctr.LogCursor,
+			Name:             ctr.Name,
+			LastScan:         ctr.LastScan,
+			LogCursor:        ctr.LogCursor,
+			Fingerprints:     copyFingerprints(ctr.Fingerprints),
+			LastNotified:     ctr.LastNotified,
+			NotifiedSeverity: ctr.NotifiedSeverity,
 		}
 	}
 	return result
@@ -236,6 +328,18 @@ func (s *State) Count() int {
 	return len(s.Containers)
 }
 
+// UpdatedAt returns when the state file was last saved.
+func (s *State) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.LastUpdated
+}
+
+// Close is a no-op for the JSON backend; the file is only open during Load and Save.
+func (s *State) Close() error {
+	return nil
+}
+
 // Delete removes the state file from disk and clears in-memory state.
 // Resets the containers map to empty and marks state as unmodified.
 // Returns error if the file cannot be deleted (except if it doesn't exist).
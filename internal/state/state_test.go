@@ -230,6 +230,67 @@ func TestState_GetLastScan(t *testing.T) {
 	})
 }
 
+func TestState_GetLogCursor(t *testing.T) {
+	s := &State{
+		Version:    "1",
+		Containers: make(map[string]*Container),
+		filePath:   "/tmp/test.json",
+	}
+
+	s.Containers["abc123"] = &Container{Name: "with-cursor", LogCursor: "2025-01-01T10:00:00Z|deadbeef"}
+	s.Containers["def456"] = &Container{Name: "without-cursor"}
+
+	cursor, exists := s.GetLogCursor("abc123")
+	if !exists || cursor != "2025-01-01T10:00:00Z|deadbeef" {
+		t.Errorf("GetLogCursor() = %q, %v; want stored cursor, true", cursor, exists)
+	}
+
+	if _, exists := s.GetLogCursor("def456"); exists {
+		t.Error("Container without cursor should report no cursor")
+	}
+
+	if _, exists := s.GetLogCursor("nonexistent"); exists {
+		t.Error("Non-existent container should report no cursor")
+	}
+}
+
+func TestState_RecordNotification(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "state.json")
+	notifiedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	s, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Untracked containers are ignored
+	s.RecordNotification("abc123", notifiedAt, "warning")
+	if _, _, ok := s.GetLastNotified("abc123"); ok || s.Count() != 0 {
+		t.Fatal("RecordNotification should not create container entries")
+	}
+
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	if _, _, ok := s.GetLastNotified("abc123"); ok {
+		t.Error("A container that was never notified should report no notification")
+	}
+	s.RecordNotification("abc123", notifiedAt, "warning")
+
+	// UpdateContainer keeps the recorded notification
+	s.UpdateContainer("abc123", "web", time.Now(), "")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	at, severity, ok := reloaded.GetLastNotified("abc123")
+	if !ok || !at.Equal(notifiedAt) || severity != "warning" {
+		t.Errorf("GetLastNotified() = %v, %q, %v; want %v, warning, true", at, severity, ok, notifiedAt)
+	}
+}
+
 func TestState_RemoveContainer(t *testing.T) {
 	s := &State{
 		Version:    "1",
@@ -0,0 +1,143 @@
+// Package ui renders the user-facing output of the commands on stdout.
+//
+// By default output passes through unchanged. In plain mode (--no-color or NO_COLOR),
+// e.g. for CI logs and files, ANSI escape sequences are stripped, status emoji become
+// ASCII markers like "[OK]" and other emoji are dropped.
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// NoColorEnvVar enables plain mode like --no-color when set to a non-empty value
+// (https://no-color.org).
+const NoColorEnvVar = "NO_COLOR"
+
+var plain atomic.Bool
+
+// SetPlain switches plain mode on or off.
+func SetPlain(on bool) {
+	plain.Store(on)
+}
+
+// Plain reports whether plain mode is on.
+func Plain() bool {
+	return plain.Load()
+}
+
+// Printf prints to stdout.
+func Printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(Writer(os.Stdout), format, args...)
+}
+
+// Println prints to stdout.
+func Println(args ...any) {
+	_, _ = fmt.Fprintln(Writer(os.Stdout), args...)
+}
+
+// Writer returns w, rendering everything written to it in plain mode.
+func Writer(w io.Writer) io.Writer {
+	if !Plain() {
+		return w
+	}
+	if _, ok := w.(plainWriter); ok {
+		return w
+	}
+	return plainWriter{w: w}
+}
+
+// plainWriter renders writes with plainText. fmt writes every formatted call at once,
+// so escape sequences and emoji are never split across writes.
+type plainWriter struct {
+	w io.Writer
+}
+
+func (p plainWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(p.w, plainText(string(b))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// ansiEscape matches ANSI CSI escape sequences, e.g. colors and "erase line".
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// plainMarkers maps status emoji to ASCII markers that keep their meaning.
+var plainMarkers = map[rune]string{
+	'✅': "[OK]",
+	'❌': "[ERROR]",
+	'⚠': "[WARN]",
+	'🛑': "[STOP]",
+	'🔴': "[CRITICAL]",
+	'🟡': "[WARNING]",
+	'🟢': "[OK]",
+}
+
+// plainChars maps arrows, check marks and box-drawing characters to ASCII.
+var plainChars = map[rune]string{
+	'✓': "+",
+	'✗': "x",
+	'→': "->",
+	'─': "-",
+	'═': "=",
+	'│': "|",
+	'┌': "+",
+	'└': "+",
+}
+
+// plainText strips ANSI escape sequences from s and replaces symbols with ASCII:
+// status emoji with their plainMarkers marker, the plainChars characters with their
+// alternative. Other emoji are dropped along with the spaces following them.
+func plainText(s string) string {
+	s = ansiEscape.ReplaceAllString(s, "")
+
+	var sb strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if alt, ok := plainChars[r]; ok {
+			sb.WriteString(alt)
+			continue
+		}
+		marker, ok := plainMarkers[r]
+		if !ok && !isEmoji(r) {
+			sb.WriteRune(r)
+			continue
+		}
+
+		// Skip the variation selectors and joiners of the emoji and the spaces after it,
+		// which are often doubled to align wide emoji
+		for i+1 < len(runes) && (runes[i+1] == variationSelector || runes[i+1] == zeroWidthJoiner) {
+			i++
+		}
+		spaced := false
+		for i+1 < len(runes) && runes[i+1] == ' ' {
+			i++
+			spaced = true
+		}
+		if marker != "" {
+			sb.WriteString(marker)
+			if spaced {
+				sb.WriteByte(' ')
+			}
+		}
+	}
+	return sb.String()
+}
+
+const (
+	variationSelector = '\uFE0F' // Requests the emoji presentation of the preceding symbol
+	zeroWidthJoiner   = '\u200D' // Combines emoji into one, e.g. in flags
+)
+
+// isEmoji reports whether r is a pictographic symbol such as "📊" or "⏭". Letters of
+// any script are kept.
+func isEmoji(r rune) bool {
+	return r == variationSelector || (r >= 0x2190 && unicode.Is(unicode.So, r))
+}
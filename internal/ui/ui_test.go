@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"status emoji become markers", "✅ Scan complete\n", "[OK] Scan complete\n"},
+		{"variation selector and wide spacing", "⚠️  Warning: disk full\n", "[WARN] Warning: disk full\n"},
+		{"other emoji are dropped", "📊 Scanning 3 containers 🐳\n", "Scanning 3 containers \n"},
+		{"emoji with variation selector dropped", "⏭️  Skipping web\n", "Skipping web\n"},
+		{"ANSI escape sequences", "\r\033[K\033[1;31mfailed\033[0m", "\rfailed"},
+		{"box drawing and arrows", "═══ web → db │ ✓", "=== web -> db | +"},
+		{"letters of any script are kept", "Fehler: Größe überschritten, 错误", "Fehler: Größe überschritten, 错误"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plainText(tt.in); got != tt.want {
+				t.Errorf("plainText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	defer SetPlain(false)
+
+	var buf bytes.Buffer
+	if Writer(&buf) != &buf {
+		t.Error("Writer() should return w unchanged outside plain mode")
+	}
+
+	SetPlain(true)
+	w := Writer(&buf)
+	if Writer(w) != w {
+		t.Error("Writer() should not wrap a plain writer twice")
+	}
+	n, err := fmt.Fprintf(w, "❌ %s\n", "failed")
+	if err != nil || n != len("❌ failed\n") {
+		t.Errorf("Fprintf() = %d, %v; want the length of the unrendered text", n, err)
+	}
+	if got := buf.String(); got != "[ERROR] failed\n" {
+		t.Errorf("plain output = %q", got)
+	}
+}
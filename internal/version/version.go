@@ -1,6 +1,12 @@
 // Package version contains version information.
 package version
 
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
 // Version information for DLIA
 var (
 	Version   = "dev"
@@ -8,12 +14,77 @@ var (
 	GitCommit = "unknown"
 )
 
+// readBuildInfo is replaced in tests
+var readBuildInfo = debug.ReadBuildInfo
+
+// Info is the version and build metadata of the running binary.
+type Info struct {
+	Version   string
+	GitCommit string
+	BuildDate string
+	GoVersion string
+	Platform  string // OS/architecture, e.g. linux/amd64
+}
+
+// Get returns the build metadata. Values injected via -ldflags take precedence; when
+// they are not set (e.g. for go install builds), the module version and VCS
+// information embedded by the Go toolchain are used instead.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	bi, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+
+	var modified bool
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.GitCommit == "unknown" {
+				info.GitCommit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "unknown" {
+				info.BuildDate = setting.Value
+			}
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if modified && GitCommit == "unknown" && info.GitCommit != "unknown" {
+		info.GitCommit += "-dirty"
+	}
+	return info
+}
+
+// String formats the build metadata for dlia version and --version.
+func (i Info) String() string {
+	var b strings.Builder
+	b.WriteString("dlia version " + i.Version + "\n")
+	b.WriteString("  Commit:     " + i.GitCommit + "\n")
+	b.WriteString("  Built:      " + i.BuildDate + "\n")
+	b.WriteString("  Go version: " + i.GoVersion + "\n")
+	b.WriteString("  OS/Arch:    " + i.Platform + "\n")
+	return b.String()
+}
+
 // GetVersion returns the full version string
 func GetVersion() string {
-	return Version
+	return Get().Version
 }
 
 // GetFullVersion returns version with build metadata
 func GetFullVersion() string {
-	return Version + " (build: " + BuildDate + ", commit: " + GitCommit + ")"
+	info := Get()
+	return info.Version + " (build: " + info.BuildDate + ", commit: " + info.GitCommit + ")"
 }
@@ -2,6 +2,7 @@
 package version
 
 import (
+	"runtime/debug"
 	"strings"
 	"testing"
 )
@@ -215,3 +216,59 @@ func TestGetFullVersion_Format(t *testing.T) {
 		t.Errorf("GetFullVersion() = %q, should contain opening parenthesis", got)
 	}
 }
+
+func TestGet_BuildInfoFallback(t *testing.T) {
+	originalVersion, originalBuildDate, originalGitCommit := Version, BuildDate, GitCommit
+	originalRead := readBuildInfo
+	defer func() {
+		Version, BuildDate, GitCommit = originalVersion, originalBuildDate, originalGitCommit
+		readBuildInfo = originalRead
+	}()
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Version: "v1.4.0"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "0123abcd"},
+				{Key: "vcs.time", Value: "2025-05-06T07:08:09Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	t.Run("without ldflags", func(t *testing.T) {
+		Version, BuildDate, GitCommit = "dev", "unknown", "unknown"
+		got := Get()
+		if got.Version != "v1.4.0" || got.GitCommit != "0123abcd-dirty" || got.BuildDate != "2025-05-06T07:08:09Z" {
+			t.Errorf("Get() = %+v, want build info values", got)
+		}
+	})
+
+	t.Run("ldflags take precedence", func(t *testing.T) {
+		Version, BuildDate, GitCommit = "1.5.0", "2025-06-01", "feedbeef"
+		got := Get()
+		if got.Version != "1.5.0" || got.GitCommit != "feedbeef" || got.BuildDate != "2025-06-01" {
+			t.Errorf("Get() = %+v, want ldflags values", got)
+		}
+	})
+
+	t.Run("devel module version", func(t *testing.T) {
+		readBuildInfo = func() (*debug.BuildInfo, bool) {
+			return &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, true
+		}
+		Version, BuildDate, GitCommit = "dev", "unknown", "unknown"
+		if got := Get(); got.Version != "dev" || got.GitCommit != "unknown" {
+			t.Errorf("Get() = %+v, want dev defaults", got)
+		}
+	})
+}
+
+func TestInfo_String(t *testing.T) {
+	info := Info{Version: "1.0.0", GitCommit: "abc", BuildDate: "2025-01-01", GoVersion: "go1.26.0", Platform: "linux/amd64"}
+	got := info.String()
+	for _, want := range []string{"dlia version 1.0.0", "Commit:     abc", "Built:      2025-01-01", "Go version: go1.26.0", "OS/Arch:    linux/amd64"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+}
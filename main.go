@@ -13,7 +13,10 @@ import (
 func main() {
 	// Panic recovery for production hardening. Catches unhandled panics and logs
 	// the stack trace before terminating gracefully with exit code 1.
-	// Exit code semantics: 0 = success, 1 = general error/panic, 2 = config error
+	// Exit code semantics: 0 = success, 1 = general error/panic, 2 = config error,
+	// 3 = scan found issues (only with scan --fail-on-issues),
+	// 4 = another scan is in progress (scan lock held),
+	// 75 = LLM quota exhausted mid-scan (retry later)
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Fprintf(os.Stderr, "\n❌ PANIC: %v\n", r)